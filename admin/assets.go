@@ -0,0 +1,23 @@
+// Package admin embeds the admin UI's templates, static files and web app
+// manifest.
+package admin
+
+import (
+	"embed"
+	"io/fs"
+	"os"
+)
+
+//go:embed templates static manifest.webmanifest
+var embedded embed.FS
+
+// FS returns the templates/ and static/ trees and manifest.webmanifest. They
+// are embedded in the binary, except in local development, where they are
+// read from ./admin so that edits and rebuilt bundles show up without a
+// rebuild.
+func FS(localDev bool) fs.FS {
+	if localDev {
+		return os.DirFS("admin")
+	}
+	return embedded
+}
@@ -33,6 +33,13 @@ const bundles = [
         jsx: true,
         templatePath: 'templates/login.html',
     },
+    {
+        entryPoint: 'src/account/index.jsx',
+        prefix: 'account-app',
+        format: 'esm',
+        jsx: true,
+        templatePath: 'templates/account.html',
+    },
 ];
 
 for (const bundle of bundles) {
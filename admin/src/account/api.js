@@ -0,0 +1,71 @@
+import { csrfHeaders } from '../csrf.js';
+
+async function postJSON(url, body) {
+    const res = await fetch(url, {
+        method: 'POST',
+        headers: csrfHeaders({ 'Content-Type': 'application/json' }),
+        body: JSON.stringify(body),
+    });
+    return res.json();
+}
+
+export async function fetchTOTPStatus() {
+    const res = await fetch('/admin/api/account/totp');
+    if (!res.ok) throw new Error('Failed to fetch two-factor settings');
+    return res.json();
+}
+
+export async function setupTOTP() {
+    const res = await fetch('/admin/api/account/totp/setup', { method: 'POST', headers: csrfHeaders() });
+    if (!res.ok) throw new Error('Failed to start two-factor setup');
+    return res.json();
+}
+
+export async function enableTOTP(code) {
+    return postJSON('/admin/api/account/totp/enable', { code });
+}
+
+export async function disableTOTP(code) {
+    return postJSON('/admin/api/account/totp/disable', { code });
+}
+
+export const passkeysSupported =
+    typeof window.PublicKeyCredential?.parseCreationOptionsFromJSON === 'function';
+
+export async function fetchPasskeys() {
+    const res = await fetch('/admin/api/webauthn/credentials');
+    if (!res.ok) throw new Error('Failed to fetch passkeys');
+    return res.json();
+}
+
+export async function registerPasskey(name) {
+    const begin = await fetch('/admin/api/webauthn/register/begin', { method: 'POST', headers: csrfHeaders() });
+    if (!begin.ok) throw new Error('Failed to start passkey registration');
+    const { token, options } = await begin.json();
+
+    const credential = await navigator.credentials.create({
+        publicKey: PublicKeyCredential.parseCreationOptionsFromJSON(options.publicKey),
+    });
+
+    return postJSON('/admin/api/webauthn/register/finish', {
+        token,
+        name,
+        credential: credential.toJSON(),
+    });
+}
+
+export async function deletePasskey(id) {
+    const res = await fetch(`/admin/api/webauthn/credentials?id=${id}`, { method: 'DELETE', headers: csrfHeaders() });
+    return res.json();
+}
+
+export async function fetchSessions() {
+    const res = await fetch('/admin/api/account/sessions');
+    if (!res.ok) throw new Error('Failed to fetch sessions');
+    return res.json();
+}
+
+export async function revokeSession(id) {
+    const res = await fetch(`/admin/api/account/sessions?id=${id}`, { method: 'DELETE', headers: csrfHeaders() });
+    return res.json();
+}
@@ -0,0 +1,14 @@
+import { PasskeySettings } from './components/PasskeySettings.jsx';
+import { SessionList } from './components/SessionList.jsx';
+import { TwoFactorSettings } from './components/TwoFactorSettings.jsx';
+
+export function App() {
+    return (
+        <>
+            <h1>Account</h1>
+            <PasskeySettings />
+            <TwoFactorSettings />
+            <SessionList />
+        </>
+    );
+}
@@ -0,0 +1,107 @@
+import { useCallback, useEffect, useState } from 'preact/hooks';
+import { deletePasskey, fetchPasskeys, passkeysSupported, registerPasskey } from '../api.js';
+
+function formatDate(value) {
+    return value ? new Date(value).toLocaleString() : 'never';
+}
+
+// PasskeySettings lists the signed-in user's passkeys and adds new ones, so
+// that the admin can be signed in to without typing the password.
+export function PasskeySettings() {
+    const [passkeys, setPasskeys] = useState([]);
+    const [name, setName] = useState('');
+    const [busy, setBusy] = useState(false);
+    const [error, setError] = useState('');
+
+    const load = useCallback(async () => {
+        try {
+            const data = await fetchPasskeys();
+            setPasskeys(data.passkeys);
+        } catch (e) {
+            setError(e.message);
+        }
+    }, []);
+
+    useEffect(() => {
+        load();
+    }, [load]);
+
+    const handleAdd = async (e) => {
+        e.preventDefault();
+        if (busy) return;
+        setError('');
+        setBusy(true);
+        try {
+            const data = await registerPasskey(name);
+            if (data.ok) {
+                setName('');
+                load();
+            } else {
+                setError(data.error || 'Failed to add passkey');
+            }
+        } catch (e) {
+            setError(e.name === 'NotAllowedError' ? 'Passkey registration was cancelled.' : e.message);
+        }
+        setBusy(false);
+    };
+
+    const handleDelete = async (passkey) => {
+        if (!confirm(`Delete passkey "${passkey.name}"?`)) return;
+        setError('');
+        const data = await deletePasskey(passkey.id);
+        if (!data.ok) {
+            setError(data.error || 'Failed to delete passkey');
+        }
+        load();
+    };
+
+    return (
+        <section class="account-section">
+            <h2>Passkeys</h2>
+            {error && <p class="account-error">{error}</p>}
+
+            {passkeys.length === 0 ? (
+                <p class="account-status">No passkeys yet.</p>
+            ) : (
+                <ul class="passkey-list">
+                    {passkeys.map((passkey) => (
+                        <li key={passkey.id}>
+                            <span>
+                                {passkey.name}
+                                <span class="passkey-meta">
+                                    Added {formatDate(passkey.created_at)}, last used{' '}
+                                    {formatDate(passkey.last_used_at)}
+                                </span>
+                            </span>
+                            <button
+                                type="button"
+                                class="btn btn-danger"
+                                onClick={() => handleDelete(passkey)}
+                            >
+                                Delete
+                            </button>
+                        </li>
+                    ))}
+                </ul>
+            )}
+
+            {passkeysSupported ? (
+                <form class="account-form" onSubmit={handleAdd}>
+                    <input
+                        type="text"
+                        required
+                        placeholder="Name, e.g. iPhone"
+                        aria-label="Passkey name"
+                        value={name}
+                        onInput={(e) => setName(e.currentTarget.value)}
+                    />
+                    <button type="submit" class="btn btn-secondary" disabled={busy}>
+                        Add passkey
+                    </button>
+                </form>
+            ) : (
+                <p class="account-status">This browser cannot register passkeys.</p>
+            )}
+        </section>
+    );
+}
@@ -0,0 +1,69 @@
+import { useCallback, useEffect, useState } from 'preact/hooks';
+import { fetchSessions, revokeSession } from '../api.js';
+
+function formatDate(value) {
+    return value ? new Date(value).toLocaleString() : 'never';
+}
+
+// SessionList shows the browsers signed in as the current user and signs
+// the other ones out, e.g. after losing a device.
+export function SessionList() {
+    const [sessions, setSessions] = useState([]);
+    const [error, setError] = useState('');
+
+    const load = useCallback(async () => {
+        try {
+            const data = await fetchSessions();
+            setSessions(data.sessions);
+        } catch (e) {
+            setError(e.message);
+        }
+    }, []);
+
+    useEffect(() => {
+        load();
+    }, [load]);
+
+    const handleRevoke = async (session) => {
+        if (!confirm('Sign this session out?')) return;
+        setError('');
+        const data = await revokeSession(session.id);
+        if (!data.ok) {
+            setError(data.error || 'Failed to revoke session');
+        }
+        load();
+    };
+
+    return (
+        <section class="account-section">
+            <h2>Sessions</h2>
+            {error && <p class="account-error">{error}</p>}
+
+            <ul class="session-list">
+                {sessions.map((session) => (
+                    <li key={session.id}>
+                        <span>
+                            <span class="session-agent">{session.user_agent || 'Unknown browser'}</span>
+                            <span class="session-meta">
+                                {session.ip_address || 'unknown address'}, signed in{' '}
+                                {formatDate(session.created_at)}, last active{' '}
+                                {formatDate(session.last_accessed_at)}
+                            </span>
+                        </span>
+                        {session.current ? (
+                            <span class="session-current">This browser</span>
+                        ) : (
+                            <button
+                                type="button"
+                                class="btn btn-danger"
+                                onClick={() => handleRevoke(session)}
+                            >
+                                Revoke
+                            </button>
+                        )}
+                    </li>
+                ))}
+            </ul>
+        </section>
+    );
+}
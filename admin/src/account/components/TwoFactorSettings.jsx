@@ -0,0 +1,154 @@
+import { useCallback, useEffect, useState } from 'preact/hooks';
+import { disableTOTP, enableTOTP, fetchTOTPStatus, setupTOTP } from '../api.js';
+
+function CodeForm({ label, submitLabel, danger, onSubmit }) {
+    const [code, setCode] = useState('');
+    const [submitting, setSubmitting] = useState(false);
+
+    const handleSubmit = async (e) => {
+        e.preventDefault();
+        if (submitting) return;
+        setSubmitting(true);
+        await onSubmit(code);
+        setSubmitting(false);
+        setCode('');
+    };
+
+    return (
+        <form class="account-form" onSubmit={handleSubmit}>
+            <input
+                type="text"
+                required
+                autoComplete="one-time-code"
+                placeholder={label}
+                aria-label={label}
+                value={code}
+                onInput={(e) => setCode(e.currentTarget.value)}
+            />
+            <button
+                type="submit"
+                class={`btn ${danger ? 'btn-danger' : 'btn-secondary'}`}
+                disabled={submitting}
+            >
+                {submitLabel}
+            </button>
+        </form>
+    );
+}
+
+// TwoFactorSettings enrols the signed-in user in TOTP: it shows a QR code
+// for the authenticator app, confirms a code from it and then shows the
+// recovery codes, which cannot be retrieved again afterwards.
+export function TwoFactorSettings() {
+    const [status, setStatus] = useState(null);
+    const [setup, setSetup] = useState(null);
+    const [recoveryCodes, setRecoveryCodes] = useState(null);
+    const [error, setError] = useState('');
+
+    const load = useCallback(async () => {
+        try {
+            setStatus(await fetchTOTPStatus());
+        } catch (e) {
+            setError(e.message);
+        }
+    }, []);
+
+    useEffect(() => {
+        load();
+    }, [load]);
+
+    const handleSetup = async () => {
+        setError('');
+        try {
+            setSetup(await setupTOTP());
+        } catch (e) {
+            setError(e.message);
+        }
+    };
+
+    const handleEnable = async (code) => {
+        setError('');
+        const data = await enableTOTP(code);
+        if (!data.ok) {
+            setError(data.error || 'Failed to enable two-factor authentication');
+            return;
+        }
+        setSetup(null);
+        setRecoveryCodes(data.recovery_codes);
+        load();
+    };
+
+    const handleDisable = async (code) => {
+        setError('');
+        const data = await disableTOTP(code);
+        if (!data.ok) {
+            setError(data.error || 'Failed to disable two-factor authentication');
+            return;
+        }
+        load();
+    };
+
+    return (
+        <section class="account-section">
+            <h2>Two-factor authentication</h2>
+            {error && <p class="account-error">{error}</p>}
+
+            {recoveryCodes && (
+                <div>
+                    <p>
+                        Save these recovery codes somewhere safe. Each one signs you in once if
+                        you lose your authenticator; they will not be shown again.
+                    </p>
+                    <ul class="recovery-codes">
+                        {recoveryCodes.map((code) => (
+                            <li key={code}>{code}</li>
+                        ))}
+                    </ul>
+                    <button
+                        type="button"
+                        class="btn btn-secondary"
+                        onClick={() => setRecoveryCodes(null)}
+                    >
+                        I have saved them
+                    </button>
+                </div>
+            )}
+
+            {!recoveryCodes && status && status.enabled && (
+                <div>
+                    <p class="account-status">
+                        Enabled. {status.recovery_codes_left} recovery codes left.
+                    </p>
+                    <CodeForm
+                        label="Code or recovery code"
+                        submitLabel="Disable"
+                        danger
+                        onSubmit={handleDisable}
+                    />
+                </div>
+            )}
+
+            {!recoveryCodes && status && !status.enabled && !setup && (
+                <div>
+                    <p class="account-status">
+                        Disabled. Signing in only needs your password.
+                    </p>
+                    <button type="button" class="btn btn-secondary" onClick={handleSetup}>
+                        Set up
+                    </button>
+                </div>
+            )}
+
+            {setup && (
+                <div>
+                    <p>Scan this QR code with your authenticator app, then enter a code from it.</p>
+                    <img class="totp-qr" src={setup.qr_code} alt="TOTP QR code" />
+                    <p>
+                        Or enter the key manually: <span class="totp-secret">{setup.secret}</span>
+                    </p>
+                    <CodeForm label="6-digit code" submitLabel="Enable" onSubmit={handleEnable} />
+                </div>
+            )}
+        </section>
+    );
+}
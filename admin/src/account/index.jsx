@@ -0,0 +1,5 @@
+import { render } from 'preact';
+import { App } from './app.jsx';
+
+const mountEl = document.getElementById('account-app');
+render(<App />, mountEl);
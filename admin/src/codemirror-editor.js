@@ -16,7 +16,7 @@ import {
 } from '@codemirror/language';
 import { languages } from '@codemirror/language-data';
 import { highlightSelectionMatches, searchKeymap } from '@codemirror/search';
-import { EditorState } from '@codemirror/state';
+import { Annotation, EditorState } from '@codemirror/state';
 import {
     EditorView,
     crosshairCursor,
@@ -53,6 +53,9 @@ const editorSetup = [
     ]),
 ];
 
+// Marks changes made by setContent, which are not reported to onUpdate
+const externalChange = Annotation.define();
+
 /**
  * Initialize CodeMirror editor
  * @param {HTMLElement} container - The container element for the editor
@@ -65,7 +68,10 @@ function createEditor(container, initialValue, onUpdate) {
     const debounceDelay = 800; // Match the original textarea delay
 
     const updateListener = EditorView.updateListener.of((update) => {
-        if (update.docChanged) {
+        if (update.docChanged && update.transactions.some((tr) => tr.annotation(externalChange))) {
+            // The pending content was replaced, so it must not be saved
+            clearTimeout(debounceTimer);
+        } else if (update.docChanged) {
             clearTimeout(debounceTimer);
             debounceTimer = setTimeout(() => {
                 onUpdate(update.state.doc.toString());
@@ -130,5 +136,20 @@ function insertAtCursor(editor, text) {
     editor.focus();
 }
 
+/**
+ * Replace the whole content, e.g. with a body merged on the server.
+ * The change is not reported to the editor's onUpdate callback.
+ * @param {EditorView} editor
+ * @param {string} text
+ */
+function setContent(editor, text) {
+    const cursor = Math.min(editor.state.selection.main.head, text.length);
+    editor.dispatch({
+        changes: { from: 0, to: editor.state.doc.length, insert: text },
+        selection: { anchor: cursor },
+        annotations: externalChange.of(true),
+    });
+}
+
 // Export functions
-export { createEditor, getContent, insertAtCursor };
+export { createEditor, getContent, insertAtCursor, setContent };
@@ -0,0 +1,7 @@
+// The admin pages carry the session's CSRF token in a meta tag, and every
+// request that changes something sends it back in the X-CSRF-Token header.
+const token = document.querySelector('meta[name="csrf-token"]')?.content ?? '';
+
+export function csrfHeaders(headers = {}) {
+    return { ...headers, 'X-CSRF-Token': token };
+}
@@ -1,17 +1,24 @@
-export async function updateTitle(path, title, updatedAt) {
+import { csrfHeaders } from '../csrf.js';
+
+export async function updateTitle(path, title, updatedAt, editSession) {
     const res = await fetch(`/admin/api/entries/title?path=${encodeURIComponent(path)}`, {
         method: 'PUT',
-        headers: { 'Content-Type': 'application/json' },
-        body: JSON.stringify({ title, updated_at: updatedAt }),
+        headers: csrfHeaders({ 'Content-Type': 'application/json' }),
+        body: JSON.stringify({ title, updated_at: updatedAt, edit_session: editSession }),
     });
     return res.json();
 }
 
-export async function updateBody(path, body, updatedAt) {
+export async function updateBody(path, body, updatedAt, baseRevision, editSession) {
     const res = await fetch(`/admin/api/entries/body?path=${encodeURIComponent(path)}`, {
         method: 'PUT',
-        headers: { 'Content-Type': 'application/json' },
-        body: JSON.stringify({ body, updated_at: updatedAt }),
+        headers: csrfHeaders({ 'Content-Type': 'application/json' }),
+        body: JSON.stringify({
+            body,
+            updated_at: updatedAt,
+            base_revision: baseRevision,
+            edit_session: editSession,
+        }),
     });
     return res.json();
 }
@@ -19,15 +26,80 @@ export async function updateBody(path, body, updatedAt) {
 export async function updateVisibility(path, visibility) {
     const res = await fetch(`/admin/api/entries/visibility?path=${encodeURIComponent(path)}`, {
         method: 'PUT',
-        headers: { 'Content-Type': 'application/json' },
+        headers: csrfHeaders({ 'Content-Type': 'application/json' }),
         body: JSON.stringify({ visibility }),
     });
     return res.json();
 }
 
+export async function scheduleEntry(path, publishAt) {
+    const res = await fetch(`/admin/api/entries/schedule?path=${encodeURIComponent(path)}`, {
+        method: 'PUT',
+        headers: csrfHeaders({ 'Content-Type': 'application/json' }),
+        body: JSON.stringify({ publish_at: publishAt }),
+    });
+    return res.json();
+}
+
+export async function listPreviewLinks(path) {
+    const res = await fetch(`/admin/api/entries/previews?path=${encodeURIComponent(path)}`);
+    return res.json();
+}
+
+export async function createPreviewLink(path, expiresInHours) {
+    const res = await fetch(`/admin/api/entries/previews?path=${encodeURIComponent(path)}`, {
+        method: 'POST',
+        headers: csrfHeaders({ 'Content-Type': 'application/json' }),
+        body: JSON.stringify({ expires_in_hours: expiresInHours }),
+    });
+    return res.json();
+}
+
+export async function revokePreviewLink(path, id) {
+    const res = await fetch(`/admin/api/entries/previews?path=${encodeURIComponent(path)}&id=${encodeURIComponent(id)}`, {
+        method: 'DELETE',
+        headers: csrfHeaders(),
+    });
+    return res.json();
+}
+
+export async function listRevisions(path) {
+    const res = await fetch(`/admin/api/entries/revisions?path=${encodeURIComponent(path)}`);
+    return res.json();
+}
+
+export async function diffRevisions(path, from, to) {
+    const res = await fetch(`/admin/api/entries/revisions/diff?path=${encodeURIComponent(path)}&from=${from}&to=${to}`);
+    return res.json();
+}
+
+export async function restoreRevision(path, id, updatedAt) {
+    const res = await fetch(`/admin/api/entries/revisions/restore?path=${encodeURIComponent(path)}&id=${id}`, {
+        method: 'POST',
+        headers: csrfHeaders({ 'Content-Type': 'application/json' }),
+        body: JSON.stringify({ updated_at: updatedAt }),
+    });
+    return res.json();
+}
+
+export async function updateTags(path, tags) {
+    const res = await fetch(`/admin/api/entries/tags?path=${encodeURIComponent(path)}`, {
+        method: 'PUT',
+        headers: csrfHeaders({ 'Content-Type': 'application/json' }),
+        body: JSON.stringify({ tags }),
+    });
+    return res.json();
+}
+
+export async function searchTags(q) {
+    const res = await fetch(`/admin/api/tags?q=${encodeURIComponent(q)}`);
+    return res.json();
+}
+
 export async function deleteEntry(path) {
     const res = await fetch(`/admin/api/entries/delete?path=${encodeURIComponent(path)}`, {
         method: 'DELETE',
+        headers: csrfHeaders(),
     });
     return res.json();
 }
@@ -35,15 +107,21 @@ export async function deleteEntry(path) {
 export async function regenerateImage(path) {
     const res = await fetch(`/admin/api/entries/image/regenerate?path=${encodeURIComponent(path)}`, {
         method: 'POST',
+        headers: csrfHeaders(),
     });
     return res.json();
 }
 
-export async function previewMarkdown(body) {
+export async function getLinks(path) {
+    const res = await fetch(`/admin/api/entries/links?path=${encodeURIComponent(path)}`);
+    return res.json();
+}
+
+export async function previewMarkdown(body, title) {
     const res = await fetch('/admin/api/entries/preview', {
         method: 'POST',
-        headers: { 'Content-Type': 'application/json' },
-        body: JSON.stringify({ body }),
+        headers: csrfHeaders({ 'Content-Type': 'application/json' }),
+        body: JSON.stringify({ body, title }),
     });
     return res.json();
 }
@@ -53,6 +131,7 @@ export async function uploadImage(file) {
     formData.append('file', file);
     const res = await fetch('/admin/api/entries/upload', {
         method: 'POST',
+        headers: csrfHeaders(),
         body: formData,
     });
     return res.json();
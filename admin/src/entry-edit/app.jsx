@@ -2,6 +2,9 @@ import { useReducer, useCallback, useRef } from 'preact/hooks';
 import { TitleInput } from './components/TitleInput.jsx';
 import { BodyEditor } from './components/BodyEditor.jsx';
 import { Sidebar } from './components/Sidebar.jsx';
+import { RelatedLinks } from './components/RelatedLinks.jsx';
+import { RevisionHistory } from './components/RevisionHistory.jsx';
+import { MergeConflict } from './components/MergeConflict.jsx';
 import { useAutoSave } from './hooks/useAutoSave.js';
 import * as api from './api.js';
 
@@ -13,8 +16,14 @@ function reducer(state, action) {
             return { ...state, body: action.value };
         case 'SET_VISIBILITY':
             return { ...state, visibility: action.value };
+        case 'SET_SCHEDULE':
+            return { ...state, visibility: 'scheduled', publishedAt: action.value };
+        case 'SET_TAGS':
+            return { ...state, tags: action.value };
         case 'SET_UPDATED_AT':
             return { ...state, updatedAt: action.value };
+        case 'SET_CONFLICT':
+            return { ...state, conflict: action.value };
         case 'SET_FEEDBACK':
             return { ...state, feedback: action.value };
         case 'CLEAR_FEEDBACK':
@@ -29,11 +38,18 @@ export function App({ initData }) {
         title: initData.title,
         body: initData.body,
         visibility: initData.visibility,
+        publishedAt: initData.published_at,
+        tags: initData.tags || [],
         updatedAt: initData.updated_at,
+        conflict: null,
         feedback: null,
     });
 
     const updatedAtRef = useRef(state.updatedAt);
+    // The revision the editor's body derives from; the server merges
+    // concurrent edits against it
+    const baseRevisionRef = useRef(initData.revision_id);
+    const editorApiRef = useRef(null);
     const feedbackTimerRef = useRef(null);
 
     const showFeedback = useCallback((feedback) => {
@@ -72,21 +88,57 @@ export function App({ initData }) {
 
     const saveTitle = useCallback(async (title) => {
         try {
-            const data = await api.updateTitle(initData.path, title, updatedAtRef.current);
-            handleApiResponse(data);
+            const data = await api.updateTitle(
+                initData.path,
+                title,
+                updatedAtRef.current,
+                initData.edit_session,
+            );
+            if (handleApiResponse(data) && data.revision_id) {
+                baseRevisionRef.current = data.revision_id;
+            }
         } catch (err) {
             showFeedback({ type: 'error', message: `Failed to save title: ${err.message}` });
         }
-    }, [initData.path, handleApiResponse, showFeedback]);
+    }, [initData.path, initData.edit_session, handleApiResponse, showFeedback]);
 
     const saveBody = useCallback(async (body) => {
+        const editor = editorApiRef.current;
+        if (editor && editor.getContent() !== body) {
+            // Typed over or replaced by a merge since; a newer save is pending
+            return;
+        }
         try {
-            const data = await api.updateBody(initData.path, body, updatedAtRef.current);
-            handleApiResponse(data);
+            const data = await api.updateBody(
+                initData.path,
+                body,
+                updatedAtRef.current,
+                baseRevisionRef.current,
+                initData.edit_session,
+            );
+            if (data.conflict) {
+                dispatch({ type: 'SET_CONFLICT', value: data.conflict });
+                showFeedback({ type: 'error', message: data.error });
+                return;
+            }
+            if (data.merged) {
+                if (!editor || editor.getContent() !== body) {
+                    // Typing went on while the merge was saved. Keep the old
+                    // base so that the next save merges it again.
+                    return;
+                }
+                editor.setContent(data.body);
+                dispatch({ type: 'SET_BODY', value: data.body });
+                showFeedback({ type: 'success', message: data.message });
+            }
+            if (handleApiResponse(data)) {
+                if (data.revision_id) baseRevisionRef.current = data.revision_id;
+                dispatch({ type: 'SET_CONFLICT', value: null });
+            }
         } catch (err) {
             showFeedback({ type: 'error', message: `Failed to save body: ${err.message}` });
         }
-    }, [initData.path, handleApiResponse, showFeedback]);
+    }, [initData.path, initData.edit_session, handleApiResponse, showFeedback]);
 
     const debouncedSaveTitle = useAutoSave(saveTitle, 500);
     const debouncedSaveBody = useAutoSave(saveBody, 800);
@@ -101,6 +153,18 @@ export function App({ initData }) {
         debouncedSaveBody(body);
     }, [debouncedSaveBody]);
 
+    const handleResolve = useCallback((body) => {
+        const conflict = state.conflict;
+        // The resolution is made on top of what the other tab stored
+        updatedAtRef.current = conflict.updated_at;
+        dispatch({ type: 'SET_UPDATED_AT', value: conflict.updated_at });
+        baseRevisionRef.current = conflict.revision_id;
+        if (editorApiRef.current) editorApiRef.current.setContent(body);
+        dispatch({ type: 'SET_BODY', value: body });
+        dispatch({ type: 'SET_CONFLICT', value: null });
+        saveBody(body);
+    }, [state.conflict, saveBody]);
+
     const handleVisibilityChange = useCallback(async (visibility) => {
         try {
             const data = await api.updateVisibility(initData.path, visibility);
@@ -112,6 +176,40 @@ export function App({ initData }) {
         }
     }, [initData.path, handleApiResponse, showFeedback]);
 
+    const handleSchedule = useCallback(async (publishAt) => {
+        try {
+            const data = await api.scheduleEntry(initData.path, publishAt);
+            if (handleApiResponse(data)) {
+                dispatch({ type: 'SET_SCHEDULE', value: publishAt });
+            }
+        } catch (err) {
+            showFeedback({ type: 'error', message: `Failed to schedule entry: ${err.message}` });
+        }
+    }, [initData.path, handleApiResponse, showFeedback]);
+
+    const handleRestore = useCallback(async (id) => {
+        try {
+            const data = await api.restoreRevision(initData.path, id, updatedAtRef.current);
+            if (handleApiResponse(data)) {
+                // The editor only takes its initial body, so start over from the restored one
+                window.location.reload();
+            }
+        } catch (err) {
+            showFeedback({ type: 'error', message: `Failed to restore revision: ${err.message}` });
+        }
+    }, [initData.path, handleApiResponse, showFeedback]);
+
+    const handleTagsChange = useCallback(async (tags) => {
+        try {
+            const data = await api.updateTags(initData.path, tags);
+            if (handleApiResponse(data)) {
+                dispatch({ type: 'SET_TAGS', value: data.tags });
+            }
+        } catch (err) {
+            showFeedback({ type: 'error', message: `Failed to update tags: ${err.message}` });
+        }
+    }, [initData.path, handleApiResponse, showFeedback]);
+
     const handleDelete = useCallback(async () => {
         try {
             const data = await api.deleteEntry(initData.path);
@@ -139,19 +237,30 @@ export function App({ initData }) {
             <div class="edit-main">
                 <TitleInput value={state.title} onChange={handleTitleChange} />
                 <BodyEditor
+                    title={state.title}
                     initialBody={initData.body}
                     currentBody={state.body}
                     onBodyChange={handleBodyChange}
                     onFeedback={reportFeedback}
+                    apiRef={editorApiRef}
                 />
+                {state.conflict && <MergeConflict conflict={state.conflict} onResolve={handleResolve} />}
+                <RevisionHistory path={initData.path} updatedAt={state.updatedAt} onRestore={handleRestore} />
+                <RelatedLinks path={initData.path} updatedAt={state.updatedAt} />
             </div>
             <Sidebar
                 feedback={state.feedback}
                 visibility={state.visibility}
+                publishedAt={state.publishedAt}
+                tags={state.tags}
                 path={initData.path}
+                author={initData.author}
                 onVisibilityChange={handleVisibilityChange}
+                onSchedule={handleSchedule}
+                onTagsChange={handleTagsChange}
                 onDelete={handleDelete}
                 onRegenerateImage={handleRegenerateImage}
+                onFeedback={showFeedback}
             />
         </div>
     );
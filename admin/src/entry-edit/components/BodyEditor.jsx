@@ -1,8 +1,8 @@
 import { useRef, useEffect, useState, useCallback } from 'preact/hooks';
-import { createEditor, getContent, insertAtCursor } from '../../codemirror-editor.js';
+import { createEditor, getContent, insertAtCursor, setContent } from '../../codemirror-editor.js';
 import { uploadImage, previewMarkdown } from '../api.js';
 
-export function BodyEditor({ initialBody, currentBody, onBodyChange, onFeedback }) {
+export function BodyEditor({ title, initialBody, currentBody, onBodyChange, onFeedback, apiRef }) {
     const containerRef = useRef(null);
     const editorRef = useRef(null);
     const [activeTab, setActiveTab] = useState('edit');
@@ -16,6 +16,12 @@ export function BodyEditor({ initialBody, currentBody, onBodyChange, onFeedback
             onBodyChange(content);
         });
         editorRef.current = editor;
+        if (apiRef) {
+            apiRef.current = {
+                getContent: () => getContent(editor),
+                setContent: (text) => setContent(editor, text),
+            };
+        }
 
         containerRef.current.addEventListener('paste', async (event) => {
             const items = event.clipboardData?.items || [];
@@ -56,7 +62,7 @@ export function BodyEditor({ initialBody, currentBody, onBodyChange, onFeedback
         setPreviewLoading(true);
         try {
             const body = editorRef.current ? getContent(editorRef.current) : (currentBody || '');
-            const data = await previewMarkdown(body);
+            const data = await previewMarkdown(body, title);
             if (data.error) {
                 onFeedback({ type: 'error', message: data.error });
                 setPreviewHtml('<p>Failed to load preview.</p>');
@@ -69,7 +75,7 @@ export function BodyEditor({ initialBody, currentBody, onBodyChange, onFeedback
         } finally {
             setPreviewLoading(false);
         }
-    }, [currentBody, onFeedback]);
+    }, [title, currentBody, onFeedback]);
 
     const handleEditClick = useCallback(() => {
         setActiveTab('edit');
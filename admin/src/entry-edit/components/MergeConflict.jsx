@@ -0,0 +1,108 @@
+import { useState, useEffect } from 'preact/hooks';
+
+// Lines of unchanged text shown above each conflict
+const CONTEXT_LINES = 2;
+
+function resolveBody(conflict, choices) {
+    const lines = [];
+    conflict.chunks.forEach((chunk, i) => {
+        if (!chunk.conflict) {
+            lines.push(...(chunk.lines || []));
+            return;
+        }
+        const current = chunk.current || [];
+        const incoming = chunk.incoming || [];
+        switch (choices[i] || 'incoming') {
+            case 'current':
+                lines.push(...current);
+                break;
+            case 'both':
+                lines.push(...current, ...incoming);
+                break;
+            default:
+                lines.push(...incoming);
+        }
+    });
+    if (lines.length === 0) return '';
+    return lines.join('\n') + (conflict.trailing_newline ? '\n' : '');
+}
+
+function Lines({ lines }) {
+    return (
+        <pre>
+            {lines.length === 0 ? <span class="merge-empty">(nothing)</span> : lines.join('\n')}
+        </pre>
+    );
+}
+
+// MergeConflict shows the parts of the body that were changed differently in
+// this tab and in another one, and saves the body with the chosen side of
+// each. Autosave keeps running meanwhile, so the conflict follows the editor.
+export function MergeConflict({ conflict, onResolve }) {
+    const [choices, setChoices] = useState({});
+
+    useEffect(() => {
+        setChoices({});
+    }, [conflict]);
+
+    const choose = (i, choice) => setChoices((prev) => ({ ...prev, [i]: choice }));
+
+    return (
+        <div class="merge-conflict">
+            <p class="merge-conflict-help">
+                The entry was changed in another tab too. Choose what to keep for each conflict,
+                then save.
+            </p>
+            {conflict.chunks.map((chunk, i) => {
+                if (!chunk.conflict) return null;
+                const before = conflict.chunks[i - 1];
+                const context =
+                    before && !before.conflict ? (before.lines || []).slice(-CONTEXT_LINES) : [];
+                const choice = choices[i] || 'incoming';
+                return (
+                    <div key={i} class="merge-chunk">
+                        {context.length > 0 && <pre class="merge-context">{context.join('\n')}</pre>}
+                        <div class="merge-sides">
+                            <label class={`merge-side${choice === 'current' ? ' selected' : ''}`}>
+                                <input
+                                    type="radio"
+                                    name={`merge-${i}`}
+                                    checked={choice === 'current'}
+                                    onChange={() => choose(i, 'current')}
+                                />
+                                Other tab
+                                <Lines lines={chunk.current || []} />
+                            </label>
+                            <label class={`merge-side${choice === 'incoming' ? ' selected' : ''}`}>
+                                <input
+                                    type="radio"
+                                    name={`merge-${i}`}
+                                    checked={choice === 'incoming'}
+                                    onChange={() => choose(i, 'incoming')}
+                                />
+                                This tab
+                                <Lines lines={chunk.incoming || []} />
+                            </label>
+                        </div>
+                        <label class="merge-both">
+                            <input
+                                type="radio"
+                                name={`merge-${i}`}
+                                checked={choice === 'both'}
+                                onChange={() => choose(i, 'both')}
+                            />
+                            Keep both (other tab first)
+                        </label>
+                    </div>
+                );
+            })}
+            <button
+                type="button"
+                class="btn btn-secondary"
+                onClick={() => onResolve(resolveBody(conflict, choices))}
+            >
+                Save Resolution
+            </button>
+        </div>
+    );
+}
@@ -0,0 +1,85 @@
+import { useState, useEffect } from 'preact/hooks';
+import * as api from '../api.js';
+
+const EXPIRY_OPTIONS = [
+    { hours: 24, label: '1 day' },
+    { hours: 72, label: '3 days' },
+    { hours: 168, label: '7 days' },
+    { hours: 720, label: '30 days' },
+];
+
+// PreviewLinks mints, lists and revokes signed links that let someone without
+// an admin session read the entry before it is public.
+export function PreviewLinks({ path, onFeedback }) {
+    const [links, setLinks] = useState([]);
+    const [hours, setHours] = useState(72);
+
+    useEffect(() => {
+        let cancelled = false;
+        api.listPreviewLinks(path)
+            .then((data) => {
+                if (!cancelled && !data.error) setLinks(data.links);
+            })
+            .catch((err) => console.error('Failed to load preview links:', err));
+        return () => { cancelled = true; };
+    }, [path]);
+
+    const handleCreate = async () => {
+        try {
+            const data = await api.createPreviewLink(path, hours);
+            if (data.error) {
+                onFeedback({ type: 'error', message: data.error });
+                return;
+            }
+            setLinks([data.link, ...links]);
+            if (navigator.clipboard) {
+                navigator.clipboard.writeText(data.link.url).catch(() => {});
+            }
+        } catch (err) {
+            onFeedback({ type: 'error', message: `Failed to create preview link: ${err.message}` });
+        }
+    };
+
+    const handleRevoke = async (id) => {
+        if (!confirm('Revoke this preview link? Anyone holding it will lose access.')) return;
+        try {
+            const data = await api.revokePreviewLink(path, id);
+            if (data.error) {
+                onFeedback({ type: 'error', message: data.error });
+                return;
+            }
+            setLinks(links.filter((link) => link.id !== id));
+        } catch (err) {
+            onFeedback({ type: 'error', message: `Failed to revoke preview link: ${err.message}` });
+        }
+    };
+
+    return (
+        <div class="control-panel">
+            <h3>Preview Links</h3>
+            <div class="preview-create">
+                <select value={hours} onChange={(e) => setHours(Number(e.target.value))}>
+                    {EXPIRY_OPTIONS.map((o) => <option key={o.hours} value={o.hours}>{o.label}</option>)}
+                </select>
+                <button type="button" class="btn btn-secondary" onClick={handleCreate}>
+                    Create Link
+                </button>
+            </div>
+            {links.length > 0 && (
+                <ul class="preview-links">
+                    {links.map((link) => (
+                        <li key={link.id}>
+                            <input type="text" readOnly value={link.url} onFocus={(e) => e.target.select()} />
+                            <div class="preview-link-meta">
+                                <span>Expires {new Date(link.expires_at).toLocaleString()}</span>
+                                <button type="button" class="preview-revoke" onClick={() => handleRevoke(link.id)}>
+                                    Revoke
+                                </button>
+                            </div>
+                        </li>
+                    ))}
+                </ul>
+            )}
+        </div>
+    );
+}
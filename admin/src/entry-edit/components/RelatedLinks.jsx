@@ -0,0 +1,64 @@
+import { useState, useEffect } from 'preact/hooks';
+import * as api from '../api.js';
+
+function entryHref(entry) {
+    if (entry.missing) {
+        return `/admin/entries/new?title=${encodeURIComponent(entry.title)}`;
+    }
+    return `/admin/entries/edit?path=${encodeURIComponent(entry.path)}`;
+}
+
+function LinkCard({ entry }) {
+    return (
+        <a href={entryHref(entry)} class={`related-card${entry.missing ? ' missing' : ''}${entry.visibility === 'private' || entry.visibility === 'scheduled' ? ' private' : ''}`}>
+            <div class="related-card-title">{entry.title}</div>
+            {entry.image_url
+                ? <img class="related-card-image" src={entry.image_url} alt="" loading="lazy" />
+                : entry.body_preview && <div class="related-card-body">{entry.body_preview}</div>}
+        </a>
+    );
+}
+
+function LinkGroup({ heading, entries }) {
+    if (!entries || entries.length === 0) return null;
+    return (
+        <div class="related-group">
+            <h3>{heading}</h3>
+            <div class="related-grid">
+                {entries.map((entry) => <LinkCard key={entry.path || entry.title} entry={entry} />)}
+            </div>
+        </div>
+    );
+}
+
+// RelatedLinks shows outgoing links, backlinks and two-hop links below the editor.
+// It reloads whenever the entry is saved so new [[links]] show up while writing.
+export function RelatedLinks({ path, updatedAt }) {
+    const [links, setLinks] = useState(null);
+
+    useEffect(() => {
+        let cancelled = false;
+        api.getLinks(path)
+            .then((data) => {
+                if (!cancelled && !data.error) setLinks(data);
+            })
+            .catch((err) => console.error('Failed to load links:', err));
+        return () => { cancelled = true; };
+    }, [path, updatedAt]);
+
+    if (!links) return null;
+
+    return (
+        <div class="related-links">
+            <LinkGroup heading="Links" entries={links.links} />
+            <LinkGroup heading="Backlinks" entries={links.backlinks} />
+            {links.two_hops.map((hop) => (
+                <LinkGroup
+                    key={hop.target.path || hop.target.title}
+                    heading={`→ ${hop.target.title}`}
+                    entries={hop.entries}
+                />
+            ))}
+        </div>
+    );
+}
@@ -0,0 +1,91 @@
+import { useState, useEffect } from 'preact/hooks';
+import * as api from '../api.js';
+
+function diffLineClass(line) {
+    if (line.startsWith('+++') || line.startsWith('---')) return 'diff-file';
+    if (line.startsWith('@@')) return 'diff-hunk';
+    if (line.startsWith('+')) return 'diff-add';
+    if (line.startsWith('-')) return 'diff-del';
+    return '';
+}
+
+// RevisionHistory lists the saved revisions of the entry below the editor. A
+// selected revision is compared with the latest one and can be restored.
+// It only loads while open, and reloads when the entry is saved.
+export function RevisionHistory({ path, updatedAt, onRestore }) {
+    const [open, setOpen] = useState(false);
+    const [revisions, setRevisions] = useState([]);
+    const [selected, setSelected] = useState(null);
+    const [diff, setDiff] = useState(null);
+
+    useEffect(() => {
+        if (!open) return;
+        let cancelled = false;
+        api.listRevisions(path)
+            .then((data) => {
+                if (!cancelled && !data.error) setRevisions(data.revisions);
+            })
+            .catch((err) => console.error('Failed to load revisions:', err));
+        return () => { cancelled = true; };
+    }, [path, updatedAt, open]);
+
+    const latest = revisions[0];
+
+    useEffect(() => {
+        if (!selected || !latest || selected === latest.id) {
+            setDiff(null);
+            return;
+        }
+        let cancelled = false;
+        api.diffRevisions(path, selected, latest.id)
+            .then((data) => {
+                if (!cancelled && !data.error) setDiff(data);
+            })
+            .catch((err) => console.error('Failed to load diff:', err));
+        return () => { cancelled = true; };
+    }, [path, selected, latest && latest.id]);
+
+    const handleRestore = () => {
+        const rev = revisions.find((r) => r.id === selected);
+        if (!rev) return;
+        if (!confirm(`Restore the revision saved at ${new Date(rev.saved_at).toLocaleString()}?`)) return;
+        onRestore(rev.id);
+    };
+
+    return (
+        <details class="revision-history" onToggle={(e) => setOpen(e.currentTarget.open)}>
+            <summary>History</summary>
+            <ul class="revision-list">
+                {revisions.map((rev) => (
+                    <li key={rev.id}>
+                        <button
+                            type="button"
+                            class={`revision-item${rev.id === selected ? ' selected' : ''}`}
+                            onClick={() => setSelected(rev.id)}
+                        >
+                            <span class="revision-time">{new Date(rev.saved_at).toLocaleString()}</span>
+                            <span class="revision-title">{rev.title}</span>
+                            <span class="revision-size">{rev.body_length} chars</span>
+                            {rev === latest && <span class="revision-current">current</span>}
+                        </button>
+                    </li>
+                ))}
+            </ul>
+            {diff && (
+                <div class="revision-diff">
+                    {diff.from_title !== diff.to_title && (
+                        <p class="revision-title-change">Title: {diff.from_title} → {diff.to_title}</p>
+                    )}
+                    <pre>
+                        {diff.diff.split('\n').map((line, i) => (
+                            <div key={i} class={diffLineClass(line)}>{line}</div>
+                        ))}
+                    </pre>
+                    <button type="button" class="btn btn-secondary" onClick={handleRestore}>
+                        Restore This Revision
+                    </button>
+                </div>
+            )}
+        </details>
+    );
+}
@@ -1,8 +1,10 @@
 import { SaveFeedback } from './SaveFeedback.jsx';
 import { VisibilityControl } from './VisibilityControl.jsx';
+import { PreviewLinks } from './PreviewLinks.jsx';
+import { TagEditor } from './TagEditor.jsx';
 import { ActionButtons } from './ActionButtons.jsx';
 
-export function Sidebar({ feedback, visibility, path, onVisibilityChange, onDelete, onRegenerateImage }) {
+export function Sidebar({ feedback, visibility, publishedAt, tags, path, author, onVisibilityChange, onSchedule, onTagsChange, onDelete, onRegenerateImage, onFeedback }) {
     return (
         <div class="edit-sidebar">
             <SaveFeedback feedback={feedback} />
@@ -13,8 +15,16 @@ export function Sidebar({ feedback, visibility, path, onVisibilityChange, onDele
                     </a>
                 </div>
             )}
-            <VisibilityControl visibility={visibility} onVisibilityChange={onVisibilityChange} />
+            <VisibilityControl
+                visibility={visibility}
+                publishedAt={publishedAt}
+                onVisibilityChange={onVisibilityChange}
+                onSchedule={onSchedule}
+            />
+            {visibility !== 'public' && <PreviewLinks path={path} onFeedback={onFeedback} />}
+            <TagEditor tags={tags} onTagsChange={onTagsChange} />
             <ActionButtons onDelete={onDelete} onRegenerateImage={onRegenerateImage} />
+            {author && <p class="entry-author">Written by {author}</p>}
         </div>
     );
 }
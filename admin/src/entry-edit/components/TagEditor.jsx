@@ -0,0 +1,92 @@
+import { useState, useEffect } from 'preact/hooks';
+import * as api from '../api.js';
+
+// TagEditor edits the entry's tags. Enter or comma adds the typed tag,
+// Backspace on an empty input removes the last one. Existing tags are
+// suggested while typing. #hashtags in the body are tagged on save without
+// showing up here.
+export function TagEditor({ tags, onTagsChange }) {
+    const [input, setInput] = useState('');
+    const [suggestions, setSuggestions] = useState([]);
+
+    useEffect(() => {
+        const q = input.trim().replace(/^#/, '');
+        if (!q) {
+            setSuggestions([]);
+            return;
+        }
+        let cancelled = false;
+        const timer = setTimeout(() => {
+            api.searchTags(q)
+                .then((data) => {
+                    if (cancelled || !Array.isArray(data)) return;
+                    const current = new Set(tags.map((t) => t.toLowerCase()));
+                    setSuggestions(data.filter((s) => !current.has(s.tag.toLowerCase())));
+                })
+                .catch(() => {});
+        }, 200);
+        return () => {
+            cancelled = true;
+            clearTimeout(timer);
+        };
+    }, [input, tags]);
+
+    const addTag = (value) => {
+        const tag = value.trim().replace(/^#/, '');
+        setInput('');
+        setSuggestions([]);
+        if (!tag || tags.some((t) => t.toLowerCase() === tag.toLowerCase())) return;
+        onTagsChange([...tags, tag]);
+    };
+
+    const removeTag = (tag) => {
+        onTagsChange(tags.filter((t) => t !== tag));
+    };
+
+    const handleKeyDown = (e) => {
+        if (e.isComposing) return;
+        if (e.key === 'Enter' || e.key === ',') {
+            e.preventDefault();
+            addTag(input);
+        } else if (e.key === 'Backspace' && input === '' && tags.length > 0) {
+            removeTag(tags[tags.length - 1]);
+        }
+    };
+
+    return (
+        <div class="control-panel">
+            <h3>Tags</h3>
+            <div class="tag-editor">
+                {tags.map((tag) => (
+                    <span class="tag-chip" key={tag}>
+                        #{tag}
+                        <button type="button" class="tag-chip-remove" onClick={() => removeTag(tag)} aria-label={`Remove ${tag}`}>
+                            ×
+                        </button>
+                    </span>
+                ))}
+                <input
+                    type="text"
+                    class="tag-input"
+                    value={input}
+                    placeholder="Add tag"
+                    onInput={(e) => setInput(e.currentTarget.value)}
+                    onKeyDown={handleKeyDown}
+                    onBlur={() => addTag(input)}
+                />
+            </div>
+            <p class="tag-hint">#hashtags in the body are added automatically.</p>
+            {suggestions.length > 0 && (
+                <ul class="tag-suggestions">
+                    {suggestions.map((s) => (
+                        <li key={s.tag}>
+                            <button type="button" onMouseDown={(e) => { e.preventDefault(); addTag(s.tag); }}>
+                                #{s.tag} <span class="tag-count">{s.count}</span>
+                            </button>
+                        </li>
+                    ))}
+                </ul>
+            )}
+        </div>
+    );
+}
@@ -1,4 +1,17 @@
-export function VisibilityControl({ visibility, onVisibilityChange }) {
+import { useState } from 'preact/hooks';
+
+// toDateTimeLocal formats an RFC 3339 timestamp for a datetime-local input,
+// which works in the browser's time zone.
+function toDateTimeLocal(value) {
+    if (!value) return '';
+    const d = new Date(value);
+    const pad = (n) => String(n).padStart(2, '0');
+    return `${d.getFullYear()}-${pad(d.getMonth() + 1)}-${pad(d.getDate())}T${pad(d.getHours())}:${pad(d.getMinutes())}`;
+}
+
+export function VisibilityControl({ visibility, publishedAt, onVisibilityChange, onSchedule }) {
+    const [publishAt, setPublishAt] = useState(visibility === 'scheduled' ? toDateTimeLocal(publishedAt) : '');
+
     const handleChange = (newVisibility) => {
         if (newVisibility === visibility) return;
         const msg = `Change visibility to ${newVisibility}?`;
@@ -6,6 +19,13 @@ export function VisibilityControl({ visibility, onVisibilityChange }) {
         onVisibilityChange(newVisibility);
     };
 
+    const handleSchedule = () => {
+        if (!publishAt) return;
+        const when = new Date(publishAt);
+        if (!confirm(`Publish this entry at ${when.toLocaleString()}?`)) return;
+        onSchedule(when.toISOString());
+    };
+
     return (
         <div class="control-panel">
             <h3>Visibility</h3>
@@ -31,6 +51,21 @@ export function VisibilityControl({ visibility, onVisibilityChange }) {
                     <span>Public</span>
                 </label>
             </div>
+            {visibility !== 'public' && (
+                <div class="schedule-control">
+                    {visibility === 'scheduled' && (
+                        <p class="schedule-status">Scheduled for {new Date(publishedAt).toLocaleString()}</p>
+                    )}
+                    <input
+                        type="datetime-local"
+                        value={publishAt}
+                        onInput={(e) => setPublishAt(e.target.value)}
+                    />
+                    <button type="button" class="btn btn-secondary" disabled={!publishAt} onClick={handleSchedule}>
+                        {visibility === 'scheduled' ? 'Reschedule' : 'Schedule'}
+                    </button>
+                </div>
+            )}
         </div>
     );
 }
@@ -1,5 +1,8 @@
-export async function fetchAllEntries() {
-    const res = await fetch('/admin/api/entries');
+import { csrfHeaders } from '../csrf.js';
+
+export async function fetchAllEntries(tag) {
+    const url = tag ? `/admin/api/entries?tag=${encodeURIComponent(tag)}` : '/admin/api/entries';
+    const res = await fetch(url);
     if (!res.ok) throw new Error('Failed to fetch entries');
     return res.json();
 }
@@ -7,7 +10,7 @@ export async function fetchAllEntries() {
 export async function createEntry(title) {
     const res = await fetch('/admin/api/entries/create', {
         method: 'POST',
-        headers: { 'Content-Type': 'application/json' },
+        headers: csrfHeaders({ 'Content-Type': 'application/json' }),
         body: JSON.stringify({ title }),
     });
     if (!res.ok) throw new Error('Failed to create entry');
@@ -6,12 +6,14 @@ import { SearchBox } from './components/SearchBox.jsx';
 import { searchEntries } from './search.js';
 
 export function App() {
+    // ?tag= narrows the list to one tag; the server does the filtering.
+    const tag = new URLSearchParams(window.location.search).get('tag') || '';
     const [allEntries, setAllEntries] = useState([]);
     const [query, setQuery] = useState('');
     const [status, setStatus] = useState('loading'); // 'loading' | 'ready' | 'error'
 
     useEffect(() => {
-        fetchAllEntries()
+        fetchAllEntries(tag)
             .then((entries) => {
                 setAllEntries(entries);
                 setStatus('ready');
@@ -19,7 +21,7 @@ export function App() {
             .catch(() => {
                 setStatus('error');
             });
-    }, []);
+    }, [tag]);
 
     const visibleEntries = useMemo(() => searchEntries(allEntries, query), [allEntries, query]);
 
@@ -29,6 +31,11 @@ export function App() {
                 <SearchBox onSearch={setQuery} />
                 <NewEntryButton />
             </div>
+            {tag && (
+                <p class="entry-list-tag-filter">
+                    Tagged <strong>#{tag}</strong> <a href="/admin/entries/search">Show all</a>
+                </p>
+            )}
             {status === 'loading' && <p class="entry-list-status">Loading...</p>}
             {status === 'error' && <p class="entry-list-status">Failed to load entries.</p>}
             {status === 'ready' && <EntryGrid entries={visibleEntries} />}
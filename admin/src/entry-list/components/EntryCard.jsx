@@ -1,12 +1,29 @@
 export function EntryCard({ entry }) {
-    const className = `entry-card${entry.visibility === 'private' ? ' private' : ''}`;
+    const className = `entry-card${entry.visibility !== 'public' ? ` ${entry.visibility}` : ''}`;
 
     return (
         <a href={`/admin/entries/edit?path=${entry.path}`} class={className}>
             <div class="entry-card-content">
                 <h3 class="entry-title">{entry.title}</h3>
+                {entry.visibility === 'scheduled' && <span class="schedule-badge">Scheduled</span>}
                 {entry.image_url && <img src={entry.image_url} class="entry-image" alt="" />}
                 <p class="entry-body">{entry.body_preview}</p>
+                {entry.tags && entry.tags.length > 0 && (
+                    <div class="entry-tags">
+                        {entry.tags.map((tag) => (
+                            <span
+                                key={tag}
+                                class="tag-chip"
+                                onClick={(e) => {
+                                    e.preventDefault();
+                                    window.location.href = `/admin/entries/search?tag=${encodeURIComponent(tag)}`;
+                                }}
+                            >
+                                #{tag}
+                            </span>
+                        ))}
+                    </div>
+                )}
             </div>
         </a>
     );
@@ -6,3 +6,33 @@ export async function login(username, password, rememberMe) {
     });
     return res.json();
 }
+
+export async function loginTOTP(pendingToken, code) {
+    const res = await fetch('/admin/api/login/totp', {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ pending_token: pendingToken, code }),
+    });
+    return res.json();
+}
+
+// Browsers that can turn the server's JSON options into WebAuthn requests
+export const passkeysSupported =
+    typeof window.PublicKeyCredential?.parseRequestOptionsFromJSON === 'function';
+
+export async function loginWithPasskey() {
+    const begin = await fetch('/admin/api/webauthn/login/begin', { method: 'POST' });
+    if (!begin.ok) throw new Error('Failed to start passkey login');
+    const { token, options } = await begin.json();
+
+    const credential = await navigator.credentials.get({
+        publicKey: PublicKeyCredential.parseRequestOptionsFromJSON(options.publicKey),
+    });
+
+    const res = await fetch('/admin/api/webauthn/login/finish', {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ token, credential: credential.toJSON() }),
+    });
+    return res.json();
+}
@@ -1,5 +1,5 @@
 import { useCallback, useState } from 'preact/hooks';
-import { login } from './api.js';
+import { login, loginTOTP, loginWithPasskey, passkeysSupported } from './api.js';
 
 export function App() {
     const [username, setUsername] = useState('');
@@ -7,6 +7,9 @@ export function App() {
     const [rememberMe, setRememberMe] = useState(false);
     const [error, setError] = useState('');
     const [submitting, setSubmitting] = useState(false);
+    // Set once the password is accepted and a TOTP code is needed
+    const [pendingToken, setPendingToken] = useState('');
+    const [code, setCode] = useState('');
 
     const handleSubmit = useCallback(
         async (e) => {
@@ -16,19 +19,113 @@ export function App() {
             setSubmitting(true);
             try {
                 const data = await login(username, password, rememberMe);
+                if (data.ok && data.two_factor_required) {
+                    setPendingToken(data.pending_token);
+                    setCode('');
+                } else if (data.ok && data.redirect) {
+                    window.location.href = data.redirect;
+                    return;
+                } else {
+                    setError(data.error || 'Login failed');
+                }
+            } catch {
+                setError('Network error. Please try again.');
+            }
+            setSubmitting(false);
+        },
+        [username, password, rememberMe, submitting],
+    );
+
+    const handleCodeSubmit = useCallback(
+        async (e) => {
+            e.preventDefault();
+            if (submitting) return;
+            setError('');
+            setSubmitting(true);
+            try {
+                const data = await loginTOTP(pendingToken, code);
                 if (data.ok && data.redirect) {
                     window.location.href = data.redirect;
                     return;
                 }
+                if (data.restart) {
+                    setPendingToken('');
+                    setPassword('');
+                }
                 setError(data.error || 'Login failed');
             } catch {
                 setError('Network error. Please try again.');
             }
             setSubmitting(false);
         },
-        [username, password, rememberMe, submitting],
+        [pendingToken, code, submitting],
     );
 
+    const handlePasskey = useCallback(async () => {
+        if (submitting) return;
+        setError('');
+        setSubmitting(true);
+        try {
+            const data = await loginWithPasskey();
+            if (data.ok && data.redirect) {
+                window.location.href = data.redirect;
+                return;
+            }
+            setError(data.error || 'Login failed');
+        } catch (e) {
+            // NotAllowedError: the user closed the browser's passkey dialog
+            setError(e.name === 'NotAllowedError' ? 'Passkey login was cancelled.' : e.message);
+        }
+        setSubmitting(false);
+    }, [submitting]);
+
+    const handleBack = useCallback(() => {
+        setPendingToken('');
+        setPassword('');
+        setError('');
+    }, []);
+
+    if (pendingToken) {
+        return (
+            <div class="login-box">
+                <h1>Two-Factor Authentication</h1>
+
+                {error && (
+                    <div class="error-message">
+                        <p>{error}</p>
+                    </div>
+                )}
+
+                <form onSubmit={handleCodeSubmit}>
+                    <div class="form-group">
+                        <label for="code">Authentication code</label>
+                        <input
+                            type="text"
+                            id="code"
+                            name="code"
+                            required
+                            autoFocus
+                            autoComplete="one-time-code"
+                            value={code}
+                            onInput={(e) => setCode(e.currentTarget.value)}
+                        />
+                        <p class="form-hint">
+                            Enter the 6-digit code from your authenticator app, or one of your
+                            recovery codes.
+                        </p>
+                    </div>
+
+                    <button type="submit" class="btn btn-primary btn-login" disabled={submitting}>
+                        {submitting ? 'Verifying...' : 'Verify'}
+                    </button>
+                    <button type="button" class="btn-link login-back" onClick={handleBack}>
+                        Back to login
+                    </button>
+                </form>
+            </div>
+        );
+    }
+
     return (
         <div class="login-box">
             <h1>Admin Login</h1>
@@ -81,6 +178,17 @@ export function App() {
                     {submitting ? 'Logging in...' : 'Login'}
                 </button>
             </form>
+
+            {passkeysSupported && (
+                <button
+                    type="button"
+                    class="btn btn-passkey"
+                    disabled={submitting}
+                    onClick={handlePasskey}
+                >
+                    Sign in with a passkey
+                </button>
+            )}
         </div>
     );
 }
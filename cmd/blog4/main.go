@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log/slog"
@@ -8,17 +9,28 @@ import (
 	"net/http"
 	"os"
 	"strconv"
-	"time"
 
 	"github.com/caarlos0/env/v11"
 	"github.com/go-sql-driver/mysql"
 
+	"github.com/tokuhirom/blog4/db/admin/admindb"
 	"github.com/tokuhirom/blog4/internal"
+	"github.com/tokuhirom/blog4/internal/adminuser"
+	"github.com/tokuhirom/blog4/internal/revision"
 	"github.com/tokuhirom/blog4/internal/router"
+	"github.com/tokuhirom/blog4/internal/search"
 	"github.com/tokuhirom/blog4/internal/sobs"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "user" {
+		if err := DoUser(os.Args[2:], os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	if err := DoMain(); err != nil {
 		slog.Error("failed to start server", slog.Any("error", err))
 		os.Exit(1)
@@ -26,12 +38,8 @@ func main() {
 	os.Exit(0)
 }
 
-func DoMain() error {
-	cfg, err := env.ParseAs[internal.Config]()
-	if err != nil {
-		return fmt.Errorf("failed to parse Config: %w", err)
-	}
-
+// openDB connects to the database configured by cfg
+func openDB(cfg internal.Config) (*sql.DB, error) {
 	mysqlConfig := mysql.Config{
 		User:                 cfg.DBUser,
 		Passwd:               cfg.DBPassword,
@@ -40,19 +48,42 @@ func DoMain() error {
 		DBName:               cfg.DBName,
 		AllowNativePasswords: true,
 		ParseTime:            true,
-		Loc:                  time.FixedZone("Asia/Tokyo", cfg.TimeZoneOffset), // Set time zone to JST
+		Loc:                  cfg.Location(), // Set time zone to JST
 	}
 	// TiDB CR is only reachable over TLS. The local development MariaDB does not
-	// serve TLS, so keep it disabled there (same switch as the S3 client below).
+	// serve TLS, so keep it disabled there (same switch as the S3 client in DoMain).
 	if !cfg.LocalDev {
 		mysqlConfig.TLSConfig = "true"
 	}
 	sqlDB, err := sql.Open("mysql", mysqlConfig.FormatDSN())
 	if err != nil {
-		return fmt.Errorf("failed to open DB connection: %w", err)
+		return nil, fmt.Errorf("failed to open DB connection: %w", err)
 	}
 	if err := sqlDB.Ping(); err != nil {
-		return fmt.Errorf("failed to ping DB: %w", err)
+		return nil, fmt.Errorf("failed to ping DB: %w", err)
+	}
+	return sqlDB, nil
+}
+
+func DoMain() error {
+	cfg, err := env.ParseAs[internal.Config]()
+	if err != nil {
+		return fmt.Errorf("failed to parse Config: %w", err)
+	}
+
+	sqlDB, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+
+	// A deployment set up before admin_user signs in with ADMIN_USER/ADMIN_PW,
+	// so that pair becomes the first user
+	created, err := adminuser.NewService(admindb.New(sqlDB)).EnsureInitialUser(context.Background(), cfg.AdminUser, cfg.AdminPassword)
+	if err != nil {
+		return fmt.Errorf("failed to create the first admin user: %w", err)
+	}
+	if created {
+		slog.Info("created the first admin user from ADMIN_USER/ADMIN_PW", slog.String("username", cfg.AdminUser))
 	}
 
 	// Use SSL for S3 connections unless in local development mode
@@ -72,6 +103,30 @@ func DoMain() error {
 		internal.StartBackup(&cfg, sobsClient)
 	})()
 
+	go internal.StartAmazonCacheWorker(&cfg, admindb.New(sqlDB))
+
+	// Entries written before the search index existed, or restored from a
+	// dump, have no tokens yet.
+	go (func() {
+		indexed, err := search.NewService(sqlDB, admindb.New(sqlDB)).IndexMissing(context.Background())
+		if err != nil {
+			slog.Error("failed to index entries for search", slog.Any("error", err))
+			return
+		}
+		slog.Info("indexed entries for search", slog.Int("entries", indexed))
+	})()
+
+	// Entries written before revisions were kept, or restored from a dump,
+	// have no revision yet.
+	go (func() {
+		recorded, err := revision.NewService(admindb.New(sqlDB)).RecordMissing(context.Background())
+		if err != nil {
+			slog.Error("failed to record missing revisions", slog.Any("error", err))
+			return
+		}
+		slog.Info("recorded missing revisions", slog.Int64("entries", recorded))
+	})()
+
 	// Start the server
 	slog.Info("Starting server", slog.String("url", "http://localhost:8181/"))
 	err = http.ListenAndServe(":8181", r)
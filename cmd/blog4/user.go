@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/caarlos0/env/v11"
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/tokuhirom/blog4/db/admin/admindb"
+	"github.com/tokuhirom/blog4/internal"
+	"github.com/tokuhirom/blog4/internal/adminuser"
+)
+
+const userUsage = `usage:
+  blog4 user add [-password-stdin] NAME     create an admin user
+  blog4 user passwd [-password-stdin] NAME  reset the password and sign the user out
+  blog4 user reset-totp NAME                turn off two-factor authentication
+  blog4 user list                           list admin users
+
+Without -password-stdin a random password is generated and printed.`
+
+// DoUser runs `blog4 user`, which manages the accounts that can sign in to
+// the admin. It connects to the database configured by the environment, as
+// the server does.
+func DoUser(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(userUsage)
+	}
+	command := args[0]
+
+	fs := flag.NewFlagSet("blog4 user "+command, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	passwordStdin := fs.Bool("password-stdin", false, "read the password from stdin")
+	if err := fs.Parse(args[1:]); err != nil {
+		return fmt.Errorf("%w\n%s", err, userUsage)
+	}
+
+	cfg, err := env.ParseAs[internal.Config]()
+	if err != nil {
+		return fmt.Errorf("failed to parse Config: %w", err)
+	}
+
+	switch command {
+	case "add", "passwd":
+		if fs.NArg() != 1 {
+			return errors.New(userUsage)
+		}
+		username := fs.Arg(0)
+		password, generated, err := readPassword(*passwordStdin, stdin)
+		if err != nil {
+			return err
+		}
+
+		sqlDB, err := openDB(cfg)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = sqlDB.Close() }()
+		users := adminuser.NewService(admindb.New(sqlDB))
+
+		ctx := context.Background()
+		if command == "add" {
+			_, err = users.Create(ctx, username, password)
+			var mysqlErr *mysql.MySQLError
+			if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 { // ER_DUP_ENTRY
+				return fmt.Errorf("user %s already exists", username)
+			}
+		} else {
+			err = users.ResetPassword(ctx, username, password)
+		}
+		if err != nil {
+			return err
+		}
+
+		if generated {
+			_, _ = fmt.Fprintf(stdout, "%s's password: %s\n", username, password)
+		}
+		return nil
+	case "reset-totp":
+		if fs.NArg() != 1 {
+			return errors.New(userUsage)
+		}
+		sqlDB, err := openDB(cfg)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = sqlDB.Close() }()
+
+		return adminuser.NewService(admindb.New(sqlDB)).ResetTOTP(context.Background(), fs.Arg(0))
+	case "list":
+		sqlDB, err := openDB(cfg)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = sqlDB.Close() }()
+
+		rows, err := admindb.New(sqlDB).ListAdminUsers(context.Background())
+		if err != nil {
+			return fmt.Errorf("failed to list admin users: %w", err)
+		}
+		w := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "ID\tUSERNAME\t2FA\tCREATED")
+		for _, row := range rows {
+			twoFactor := "-"
+			if row.TotpEnabled {
+				twoFactor = "totp"
+			}
+			_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", row.ID, row.Username, twoFactor, row.CreatedAt.Time.Format(time.DateTime))
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf("unknown command %q\n%s", command, userUsage)
+	}
+}
+
+// readPassword reads the password from stdin, or generates one when
+// fromStdin is false.
+func readPassword(fromStdin bool, stdin io.Reader) (password string, generated bool, err error) {
+	if fromStdin {
+		b, err := io.ReadAll(stdin)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to read password: %w", err)
+		}
+		return strings.TrimRight(string(b), "\r\n"), false, nil
+	}
+
+	b := make([]byte, 18)
+	if _, err := rand.Read(b); err != nil {
+		return "", false, fmt.Errorf("failed to generate password: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), true, nil
+}
@@ -8,10 +8,11 @@ package admindb
 import (
 	"context"
 	"database/sql"
+	"strings"
 )
 
 const adminGetEntryByPath = `-- name: AdminGetEntryByPath :one
-SELECT entry.path, entry.title, entry.body, entry.visibility, entry.format, entry.published_at, entry.last_edited_at, entry.created_at, entry.updated_at, entry_image.url AS image_url
+SELECT entry.path, entry.title, entry.body, entry.visibility, entry.format, entry.published_at, entry.last_edited_at, entry.created_at, entry.updated_at, entry.author_id, entry_image.url AS image_url
 FROM entry
     LEFT JOIN entry_image ON (entry.path = entry_image.path)
 WHERE entry.path = ?
@@ -27,6 +28,7 @@ type AdminGetEntryByPathRow struct {
 	LastEditedAt sql.NullTime
 	CreatedAt    sql.NullTime
 	UpdatedAt    sql.NullTime
+	AuthorID     sql.NullInt64
 	ImageUrl     sql.NullString
 }
 
@@ -43,13 +45,14 @@ func (q *Queries) AdminGetEntryByPath(ctx context.Context, path string) (AdminGe
 		&i.LastEditedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AuthorID,
 		&i.ImageUrl,
 	)
 	return i, err
 }
 
 const adminListAllEntries = `-- name: AdminListAllEntries :many
-SELECT entry.path, entry.title, entry.body, entry.visibility, entry.format, entry.published_at, entry.last_edited_at, entry.created_at, entry.updated_at, entry_image.url AS image_url
+SELECT entry.path, entry.title, entry.body, entry.visibility, entry.format, entry.published_at, entry.last_edited_at, entry.created_at, entry.updated_at, entry.author_id, entry_image.url AS image_url
 FROM entry
     LEFT JOIN entry_image ON (entry.path = entry_image.path)
 ORDER BY
@@ -67,6 +70,7 @@ type AdminListAllEntriesRow struct {
 	LastEditedAt sql.NullTime
 	CreatedAt    sql.NullTime
 	UpdatedAt    sql.NullTime
+	AuthorID     sql.NullInt64
 	ImageUrl     sql.NullString
 }
 
@@ -89,6 +93,7 @@ func (q *Queries) AdminListAllEntries(ctx context.Context) ([]AdminListAllEntrie
 			&i.LastEditedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.AuthorID,
 			&i.ImageUrl,
 		); err != nil {
 			return nil, err
@@ -106,17 +111,18 @@ func (q *Queries) AdminListAllEntries(ctx context.Context) ([]AdminListAllEntrie
 
 const createEmptyEntry = `-- name: CreateEmptyEntry :execrows
 INSERT INTO entry
-           (path, title, body, visibility)
-    VALUES (?,        ?, '',    'private')
+           (path, title, body, visibility, author_id)
+    VALUES (?,        ?, '',    'private',  ?)
 `
 
 type CreateEmptyEntryParams struct {
-	Path  string
-	Title string
+	Path     string
+	Title    string
+	AuthorID sql.NullInt64
 }
 
 func (q *Queries) CreateEmptyEntry(ctx context.Context, arg CreateEmptyEntryParams) (int64, error) {
-	result, err := q.db.ExecContext(ctx, createEmptyEntry, arg.Path, arg.Title)
+	result, err := q.db.ExecContext(ctx, createEmptyEntry, arg.Path, arg.Title, arg.AuthorID)
 	if err != nil {
 		return 0, err
 	}
@@ -125,18 +131,36 @@ func (q *Queries) CreateEmptyEntry(ctx context.Context, arg CreateEmptyEntryPara
 
 const createEntryWithBody = `-- name: CreateEntryWithBody :execrows
 INSERT INTO entry
-           (path, title, body, visibility)
-    VALUES (?,    ?,     ?,    'private')
+           (path, title, body, visibility, author_id)
+    VALUES (?,    ?,     ?,    'private',  ?)
 `
 
 type CreateEntryWithBodyParams struct {
-	Path  string
-	Title string
-	Body  string
+	Path     string
+	Title    string
+	Body     string
+	AuthorID sql.NullInt64
 }
 
 func (q *Queries) CreateEntryWithBody(ctx context.Context, arg CreateEntryWithBodyParams) (int64, error) {
-	result, err := q.db.ExecContext(ctx, createEntryWithBody, arg.Path, arg.Title, arg.Body)
+	result, err := q.db.ExecContext(ctx, createEntryWithBody,
+		arg.Path,
+		arg.Title,
+		arg.Body,
+		arg.AuthorID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteBodyEntryTags = `-- name: DeleteBodyEntryTags :execrows
+DELETE FROM entry_tag WHERE path = ? AND source = 'body'
+`
+
+func (q *Queries) DeleteBodyEntryTags(ctx context.Context, path string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteBodyEntryTags, path)
 	if err != nil {
 		return 0, err
 	}
@@ -167,6 +191,30 @@ func (q *Queries) DeleteEntryLinkByPath(ctx context.Context, srcPath string) (in
 	return result.RowsAffected()
 }
 
+const deleteEntryTagsByPath = `-- name: DeleteEntryTagsByPath :execrows
+DELETE FROM entry_tag WHERE path = ? AND source = 'manual'
+`
+
+func (q *Queries) DeleteEntryTagsByPath(ctx context.Context, path string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteEntryTagsByPath, path)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteSearchTokensByPath = `-- name: DeleteSearchTokensByPath :execrows
+DELETE FROM entry_search_token WHERE path = ?
+`
+
+func (q *Queries) DeleteSearchTokensByPath(ctx context.Context, path string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteSearchTokensByPath, path)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getAllEntryTitles = `-- name: GetAllEntryTitles :many
 SELECT title
 FROM entry
@@ -197,21 +245,37 @@ func (q *Queries) GetAllEntryTitles(ctx context.Context) ([]string, error) {
 }
 
 const getEntriesByLinkedTitle = `-- name: GetEntriesByLinkedTitle :many
-SELECT DISTINCT entry.path, entry.title, entry.body, entry.visibility, entry.format, entry.published_at, entry.last_edited_at, entry.created_at, entry.updated_at
+SELECT DISTINCT entry.path, entry.title, entry.body, entry.visibility, entry.format, entry.published_at, entry.last_edited_at, entry.created_at, entry.updated_at, entry.author_id, entry_image.url AS image_url
 FROM entry_link
     INNER JOIN entry ON (entry.path = entry_link.src_path)
+    LEFT JOIN entry_image ON (entry.path = entry_image.path)
 WHERE entry_link.dst_title = ?
+ORDER BY entry.last_edited_at DESC, entry.path DESC
 `
 
-func (q *Queries) GetEntriesByLinkedTitle(ctx context.Context, dstTitle string) ([]Entry, error) {
+type GetEntriesByLinkedTitleRow struct {
+	Path         string
+	Title        string
+	Body         string
+	Visibility   EntryVisibility
+	Format       EntryFormat
+	PublishedAt  sql.NullTime
+	LastEditedAt sql.NullTime
+	CreatedAt    sql.NullTime
+	UpdatedAt    sql.NullTime
+	AuthorID     sql.NullInt64
+	ImageUrl     sql.NullString
+}
+
+func (q *Queries) GetEntriesByLinkedTitle(ctx context.Context, dstTitle string) ([]GetEntriesByLinkedTitleRow, error) {
 	rows, err := q.db.QueryContext(ctx, getEntriesByLinkedTitle, dstTitle)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Entry
+	var items []GetEntriesByLinkedTitleRow
 	for rows.Next() {
-		var i Entry
+		var i GetEntriesByLinkedTitleRow
 		if err := rows.Scan(
 			&i.Path,
 			&i.Title,
@@ -222,6 +286,8 @@ func (q *Queries) GetEntriesByLinkedTitle(ctx context.Context, dstTitle string)
 			&i.LastEditedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.AuthorID,
+			&i.ImageUrl,
 		); err != nil {
 			return nil, err
 		}
@@ -236,6 +302,108 @@ func (q *Queries) GetEntriesByLinkedTitle(ctx context.Context, dstTitle string)
 	return items, nil
 }
 
+const getEntryPathByTitle = `-- name: GetEntryPathByTitle :one
+SELECT path
+FROM entry
+WHERE title = ?
+`
+
+func (q *Queries) GetEntryPathByTitle(ctx context.Context, title string) (string, error) {
+	row := q.db.QueryRowContext(ctx, getEntryPathByTitle, title)
+	var path string
+	err := row.Scan(&path)
+	return path, err
+}
+
+const getEntryPathsByTitles = `-- name: GetEntryPathsByTitles :many
+SELECT path, title
+FROM entry
+WHERE title IN (/*SLICE:titles*/?)
+`
+
+type GetEntryPathsByTitlesRow struct {
+	Path  string
+	Title string
+}
+
+func (q *Queries) GetEntryPathsByTitles(ctx context.Context, titles []string) ([]GetEntryPathsByTitlesRow, error) {
+	query := getEntryPathsByTitles
+	var queryParams []interface{}
+	if len(titles) > 0 {
+		for _, v := range titles {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:titles*/?", strings.Repeat(",?", len(titles))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:titles*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetEntryPathsByTitlesRow
+	for rows.Next() {
+		var i GetEntryPathsByTitlesRow
+		if err := rows.Scan(&i.Path, &i.Title); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getEntryTags = `-- name: GetEntryTags :many
+SELECT tag
+FROM entry_tag
+WHERE path = ? AND source = 'manual'
+ORDER BY tag
+`
+
+// タグエディタで編集するタグ。本文の #hashtag 由来のものは含めない
+func (q *Queries) GetEntryTags(ctx context.Context, path string) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, getEntryTags, path)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, err
+		}
+		items = append(items, tag)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getEntryTitleForUpdate = `-- name: GetEntryTitleForUpdate :one
+SELECT title
+FROM entry
+WHERE path = ?
+FOR UPDATE
+`
+
+func (q *Queries) GetEntryTitleForUpdate(ctx context.Context, path string) (string, error) {
+	row := q.db.QueryRowContext(ctx, getEntryTitleForUpdate, path)
+	var title string
+	err := row.Scan(&title)
+	return title, err
+}
+
 const getLinkedEntries = `-- name: GetLinkedEntries :many
 SELECT DISTINCT
     entry_link.dst_title AS dst_title,
@@ -298,25 +466,285 @@ func (q *Queries) GetLinkedEntries(ctx context.Context, srcPath string) ([]GetLi
 	return items, nil
 }
 
-const insertEntryLink = `-- name: InsertEntryLink :execrows
-INSERT INTO entry_link (src_path, dst_title)
-VALUES (?, ?)
+const getPublicEntriesByTitles = `-- name: GetPublicEntriesByTitles :many
+SELECT path, title, body
+FROM entry
+WHERE title IN (/*SLICE:titles*/?) AND visibility = 'public'
 `
 
-type InsertEntryLinkParams struct {
-	SrcPath  string
-	DstTitle string
+type GetPublicEntriesByTitlesRow struct {
+	Path  string
+	Title string
+	Body  string
 }
 
-// TODO batch insert
-func (q *Queries) InsertEntryLink(ctx context.Context, arg InsertEntryLinkParams) (int64, error) {
-	result, err := q.db.ExecContext(ctx, insertEntryLink, arg.SrcPath, arg.DstTitle)
+func (q *Queries) GetPublicEntriesByTitles(ctx context.Context, titles []string) ([]GetPublicEntriesByTitlesRow, error) {
+	query := getPublicEntriesByTitles
+	var queryParams []interface{}
+	if len(titles) > 0 {
+		for _, v := range titles {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:titles*/?", strings.Repeat(",?", len(titles))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:titles*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPublicEntriesByTitlesRow
+	for rows.Next() {
+		var i GetPublicEntriesByTitlesRow
+		if err := rows.Scan(&i.Path, &i.Title, &i.Body); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTwoHopEntries = `-- name: GetTwoHopEntries :many
+SELECT
+    entry_link.dst_title AS dst_title,
+    entry.path,
+    entry.title,
+    entry.body,
+    entry.visibility,
+    entry_image.url AS image_url
+FROM entry_link
+    INNER JOIN entry ON (entry.path = entry_link.src_path)
+    LEFT JOIN entry_image ON (entry.path = entry_image.path)
+WHERE entry_link.dst_title IN (/*SLICE:dst_titles*/?)
+    AND entry_link.src_path != ?
+ORDER BY entry_link.dst_title, entry.last_edited_at DESC, entry.path DESC
+`
+
+type GetTwoHopEntriesParams struct {
+	DstTitles []string
+	SrcPath   string
+}
+
+type GetTwoHopEntriesRow struct {
+	DstTitle   string
+	Path       string
+	Title      string
+	Body       string
+	Visibility EntryVisibility
+	ImageUrl   sql.NullString
+}
+
+func (q *Queries) GetTwoHopEntries(ctx context.Context, arg GetTwoHopEntriesParams) ([]GetTwoHopEntriesRow, error) {
+	query := getTwoHopEntries
+	var queryParams []interface{}
+	if len(arg.DstTitles) > 0 {
+		for _, v := range arg.DstTitles {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:dst_titles*/?", strings.Repeat(",?", len(arg.DstTitles))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:dst_titles*/?", "NULL", 1)
+	}
+	queryParams = append(queryParams, arg.SrcPath)
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTwoHopEntriesRow
+	for rows.Next() {
+		var i GetTwoHopEntriesRow
+		if err := rows.Scan(
+			&i.DstTitle,
+			&i.Path,
+			&i.Title,
+			&i.Body,
+			&i.Visibility,
+			&i.ImageUrl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertBodyEntryTag = `-- name: InsertBodyEntryTag :execrows
+INSERT IGNORE INTO entry_tag (path, tag, source) VALUES (?, ?, 'body')
+`
+
+type InsertBodyEntryTagParams struct {
+	Path string
+	Tag  string
+}
+
+func (q *Queries) InsertBodyEntryTag(ctx context.Context, arg InsertBodyEntryTagParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, insertBodyEntryTag, arg.Path, arg.Tag)
 	if err != nil {
 		return 0, err
 	}
 	return result.RowsAffected()
 }
 
+const insertEntryTag = `-- name: InsertEntryTag :execrows
+INSERT INTO entry_tag (path, tag, source) VALUES (?, ?, 'manual')
+ON DUPLICATE KEY UPDATE source = 'manual'
+`
+
+type InsertEntryTagParams struct {
+	Path string
+	Tag  string
+}
+
+// 本文の #hashtag と同じタグでも、手で付けたものとして扱う
+func (q *Queries) InsertEntryTag(ctx context.Context, arg InsertEntryTagParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, insertEntryTag, arg.Path, arg.Tag)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const listAllEntryTags = `-- name: ListAllEntryTags :many
+SELECT path, tag
+FROM entry_tag
+ORDER BY path, tag
+`
+
+type ListAllEntryTagsRow struct {
+	Path string
+	Tag  string
+}
+
+func (q *Queries) ListAllEntryTags(ctx context.Context) ([]ListAllEntryTagsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listAllEntryTags)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAllEntryTagsRow
+	for rows.Next() {
+		var i ListAllEntryTagsRow
+		if err := rows.Scan(&i.Path, &i.Tag); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEntryPathsWithoutSearchTokens = `-- name: ListEntryPathsWithoutSearchTokens :many
+SELECT path
+FROM entry
+WHERE NOT EXISTS (SELECT 1 FROM entry_search_token WHERE entry_search_token.path = entry.path)
+ORDER BY path
+`
+
+func (q *Queries) ListEntryPathsWithoutSearchTokens(ctx context.Context) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listEntryPathsWithoutSearchTokens)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, err
+		}
+		items = append(items, path)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const rewriteEntryBody = `-- name: RewriteEntryBody :execrows
+UPDATE entry
+SET body = ?
+WHERE path = ? AND updated_at = ?
+`
+
+type RewriteEntryBodyParams struct {
+	Body      string
+	Path      string
+	UpdatedAt sql.NullTime
+}
+
+func (q *Queries) RewriteEntryBody(ctx context.Context, arg RewriteEntryBodyParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, rewriteEntryBody, arg.Body, arg.Path, arg.UpdatedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const searchTags = `-- name: SearchTags :many
+SELECT tag, COUNT(*) AS entry_count
+FROM entry_tag
+WHERE tag LIKE ?
+GROUP BY tag
+ORDER BY entry_count DESC, tag
+LIMIT ?
+`
+
+type SearchTagsParams struct {
+	Pattern string
+	Limit   int32
+}
+
+type SearchTagsRow struct {
+	Tag        string
+	EntryCount int64
+}
+
+// タグ入力の補完候補。よく使われているタグを先に出す
+func (q *Queries) SearchTags(ctx context.Context, arg SearchTagsParams) ([]SearchTagsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchTags, arg.Pattern, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchTagsRow
+	for rows.Next() {
+		var i SearchTagsRow
+		if err := rows.Scan(&i.Tag, &i.EntryCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateEntryBody = `-- name: UpdateEntryBody :execrows
 UPDATE entry
 SET body = ?, last_edited_at = NOW()
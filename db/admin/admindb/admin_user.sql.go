@@ -0,0 +1,149 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.31.1
+// source: admin_user.sql
+
+package admindb
+
+import (
+	"context"
+	"database/sql"
+)
+
+const countAdminUsers = `-- name: CountAdminUsers :one
+SELECT COUNT(*)
+FROM admin_user
+`
+
+func (q *Queries) CountAdminUsers(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countAdminUsers)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createAdminUser = `-- name: CreateAdminUser :execlastid
+INSERT INTO admin_user (username, password_hash)
+VALUES (?, ?)
+`
+
+type CreateAdminUserParams struct {
+	Username     string
+	PasswordHash string
+}
+
+func (q *Queries) CreateAdminUser(ctx context.Context, arg CreateAdminUserParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createAdminUser, arg.Username, arg.PasswordHash)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+const getAdminUser = `-- name: GetAdminUser :one
+SELECT id, username, password_hash, totp_secret, totp_pending_secret, totp_last_step, totp_failed_attempts, totp_locked_until, created_at, updated_at
+FROM admin_user
+WHERE id = ?
+`
+
+func (q *Queries) GetAdminUser(ctx context.Context, id int64) (AdminUser, error) {
+	row := q.db.QueryRowContext(ctx, getAdminUser, id)
+	var i AdminUser
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.PasswordHash,
+		&i.TotpSecret,
+		&i.TotpPendingSecret,
+		&i.TotpLastStep,
+		&i.TotpFailedAttempts,
+		&i.TotpLockedUntil,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getAdminUserByUsername = `-- name: GetAdminUserByUsername :one
+SELECT id, username, password_hash, totp_secret, totp_pending_secret, totp_last_step, totp_failed_attempts, totp_locked_until, created_at, updated_at
+FROM admin_user
+WHERE username = ?
+`
+
+func (q *Queries) GetAdminUserByUsername(ctx context.Context, username string) (AdminUser, error) {
+	row := q.db.QueryRowContext(ctx, getAdminUserByUsername, username)
+	var i AdminUser
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.PasswordHash,
+		&i.TotpSecret,
+		&i.TotpPendingSecret,
+		&i.TotpLastStep,
+		&i.TotpFailedAttempts,
+		&i.TotpLockedUntil,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listAdminUsers = `-- name: ListAdminUsers :many
+SELECT id, username, totp_secret IS NOT NULL AS totp_enabled, created_at
+FROM admin_user
+ORDER BY id
+`
+
+type ListAdminUsersRow struct {
+	ID          int64
+	Username    string
+	TotpEnabled bool
+	CreatedAt   sql.NullTime
+}
+
+func (q *Queries) ListAdminUsers(ctx context.Context) ([]ListAdminUsersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listAdminUsers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAdminUsersRow
+	for rows.Next() {
+		var i ListAdminUsersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.TotpEnabled,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateAdminUserPassword = `-- name: UpdateAdminUserPassword :execrows
+UPDATE admin_user
+SET password_hash = ?
+WHERE id = ?
+`
+
+type UpdateAdminUserPasswordParams struct {
+	PasswordHash string
+	ID           int64
+}
+
+func (q *Queries) UpdateAdminUserPassword(ctx context.Context, arg UpdateAdminUserPasswordParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateAdminUserPassword, arg.PasswordHash, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -8,12 +8,14 @@ package admindb
 import (
 	"context"
 	"database/sql"
+	"strings"
+	"time"
 )
 
 const countAmazonCacheByAsin = `-- name: CountAmazonCacheByAsin :one
 SELECT count(1)
 FROM amazon_cache
-WHERE asin = ?
+WHERE asin = ? AND NOT not_found
 `
 
 func (q *Queries) CountAmazonCacheByAsin(ctx context.Context, asin string) (int64, error) {
@@ -23,10 +25,54 @@ func (q *Queries) CountAmazonCacheByAsin(ctx context.Context, asin string) (int6
 	return count, err
 }
 
+const getAmazonCacheFetchedAt = `-- name: GetAmazonCacheFetchedAt :many
+SELECT asin, fetched_at
+FROM amazon_cache
+WHERE asin IN (/*SLICE:asins*/?)
+`
+
+type GetAmazonCacheFetchedAtRow struct {
+	Asin      string
+	FetchedAt time.Time
+}
+
+func (q *Queries) GetAmazonCacheFetchedAt(ctx context.Context, asins []string) ([]GetAmazonCacheFetchedAtRow, error) {
+	query := getAmazonCacheFetchedAt
+	var queryParams []interface{}
+	if len(asins) > 0 {
+		for _, v := range asins {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:asins*/?", strings.Repeat(",?", len(asins))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:asins*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAmazonCacheFetchedAtRow
+	for rows.Next() {
+		var i GetAmazonCacheFetchedAtRow
+		if err := rows.Scan(&i.Asin, &i.FetchedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getAmazonImageUrlByAsin = `-- name: GetAmazonImageUrlByAsin :one
 SELECT image_medium_url
 FROM amazon_cache
-WHERE asin = ?
+WHERE asin = ? AND NOT not_found
 `
 
 func (q *Queries) GetAmazonImageUrlByAsin(ctx context.Context, asin string) (sql.NullString, error) {
@@ -36,19 +82,71 @@ func (q *Queries) GetAmazonImageUrlByAsin(ctx context.Context, asin string) (sql
 	return image_medium_url, err
 }
 
-const insertAmazonProductDetail = `-- name: InsertAmazonProductDetail :execrows
+const listEntryBodiesWithAsin = `-- name: ListEntryBodiesWithAsin :many
+SELECT path, body
+FROM entry
+WHERE body LIKE '%[asin:%'
+`
+
+type ListEntryBodiesWithAsinRow struct {
+	Path string
+	Body string
+}
+
+func (q *Queries) ListEntryBodiesWithAsin(ctx context.Context) ([]ListEntryBodiesWithAsinRow, error) {
+	rows, err := q.db.QueryContext(ctx, listEntryBodiesWithAsin)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListEntryBodiesWithAsinRow
+	for rows.Next() {
+		var i ListEntryBodiesWithAsinRow
+		if err := rows.Scan(&i.Path, &i.Body); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markAmazonProductNotFound = `-- name: MarkAmazonProductNotFound :exec
+INSERT INTO amazon_cache (asin, link, not_found) VALUES (?, '', TRUE)
+ON DUPLICATE KEY UPDATE
+    fetched_at = NOW()
+`
+
+// A product cached before keeps its details; it is only asked for later.
+func (q *Queries) MarkAmazonProductNotFound(ctx context.Context, asin string) error {
+	_, err := q.db.ExecContext(ctx, markAmazonProductNotFound, asin)
+	return err
+}
+
+const upsertAmazonProductDetail = `-- name: UpsertAmazonProductDetail :execrows
 INSERT INTO amazon_cache (asin, title, image_medium_url, link) VALUES (?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE
+    title = VALUES(title),
+    image_medium_url = VALUES(image_medium_url),
+    link = VALUES(link),
+    fetched_at = NOW(),
+    not_found = FALSE
 `
 
-type InsertAmazonProductDetailParams struct {
+type UpsertAmazonProductDetailParams struct {
 	Asin           string
 	Title          sql.NullString
 	ImageMediumUrl sql.NullString
 	Link           string
 }
 
-func (q *Queries) InsertAmazonProductDetail(ctx context.Context, arg InsertAmazonProductDetailParams) (int64, error) {
-	result, err := q.db.ExecContext(ctx, insertAmazonProductDetail,
+func (q *Queries) UpsertAmazonProductDetail(ctx context.Context, arg UpsertAmazonProductDetailParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, upsertAmazonProductDetail,
 		arg.Asin,
 		arg.Title,
 		arg.ImageMediumUrl,
@@ -36,7 +36,7 @@ func (q *Queries) GetEntryImageByPath(ctx context.Context, path string) (EntryIm
 }
 
 const getEntryImageNotProcessedEntries = `-- name: GetEntryImageNotProcessedEntries :many
-SELECT entry.path, entry.title, entry.body, entry.visibility, entry.format, entry.published_at, entry.last_edited_at, entry.created_at, entry.updated_at
+SELECT entry.path, entry.title, entry.body, entry.visibility, entry.format, entry.published_at, entry.last_edited_at, entry.created_at, entry.updated_at, entry.author_id
 FROM entry
     LEFT JOIN entry_image ON (entry.path = entry_image.path)
 WHERE entry_image.path IS NULL
@@ -63,6 +63,7 @@ func (q *Queries) GetEntryImageNotProcessedEntries(ctx context.Context) ([]Entry
 			&i.LastEditedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.AuthorID,
 		); err != nil {
 			return nil, err
 		}
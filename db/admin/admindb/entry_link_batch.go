@@ -0,0 +1,47 @@
+package admindb
+
+import (
+	"context"
+	"strings"
+)
+
+// insertEntryLinksBatchSize caps the number of rows per INSERT statement so a
+// single entry with a huge number of links stays well below max_allowed_packet
+// and the placeholder limit.
+const insertEntryLinksBatchSize = 500
+
+// InsertEntryLinks inserts (srcPath, dstTitle) rows for every title using
+// multi-row INSERT statements. sqlc cannot express a variable-length VALUES
+// list for MySQL, so this query is written by hand.
+//
+// Duplicate titles (compared with the column's case-insensitive collation) are
+// ignored rather than failing the whole batch.
+func (q *Queries) InsertEntryLinks(ctx context.Context, srcPath string, dstTitles []string) (int64, error) {
+	var total int64
+	for start := 0; start < len(dstTitles); start += insertEntryLinksBatchSize {
+		end := min(start+insertEntryLinksBatchSize, len(dstTitles))
+		chunk := dstTitles[start:end]
+
+		var sb strings.Builder
+		sb.WriteString("INSERT IGNORE INTO entry_link (src_path, dst_title) VALUES ")
+		args := make([]interface{}, 0, len(chunk)*2)
+		for i, title := range chunk {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString("(?, ?)")
+			args = append(args, srcPath, title)
+		}
+
+		result, err := q.db.ExecContext(ctx, sb.String(), args...)
+		if err != nil {
+			return total, err
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return total, err
+		}
+		total += rows
+	}
+	return total, nil
+}
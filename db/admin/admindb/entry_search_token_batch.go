@@ -0,0 +1,43 @@
+package admindb
+
+import (
+	"context"
+	"strings"
+)
+
+// insertSearchTokensBatchSize caps the number of rows per INSERT statement; a
+// long entry yields thousands of distinct n-grams.
+const insertSearchTokensBatchSize = 500
+
+// InsertSearchTokens inserts (token, path) rows for every token using
+// multi-row INSERT statements, like InsertEntryLinks. Duplicate tokens are
+// ignored.
+func (q *Queries) InsertSearchTokens(ctx context.Context, path string, tokens []string) (int64, error) {
+	var total int64
+	for start := 0; start < len(tokens); start += insertSearchTokensBatchSize {
+		end := min(start+insertSearchTokensBatchSize, len(tokens))
+		chunk := tokens[start:end]
+
+		var sb strings.Builder
+		sb.WriteString("INSERT IGNORE INTO entry_search_token (token, path) VALUES ")
+		args := make([]interface{}, 0, len(chunk)*2)
+		for i, token := range chunk {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString("(?, ?)")
+			args = append(args, token, path)
+		}
+
+		result, err := q.db.ExecContext(ctx, sb.String(), args...)
+		if err != nil {
+			return total, err
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return total, err
+		}
+		total += rows
+	}
+	return total, nil
+}
@@ -41,6 +41,36 @@ func (m *MockQuerier) EXPECT() *MockQuerierMockRecorder {
 	return m.recorder
 }
 
+// AddAdminUserTOTPAttempt mocks base method.
+func (m *MockQuerier) AddAdminUserTOTPAttempt(ctx context.Context, arg AddAdminUserTOTPAttemptParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAdminUserTOTPAttempt", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddAdminUserTOTPAttempt indicates an expected call of AddAdminUserTOTPAttempt.
+func (mr *MockQuerierMockRecorder) AddAdminUserTOTPAttempt(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAdminUserTOTPAttempt", reflect.TypeOf((*MockQuerier)(nil).AddAdminUserTOTPAttempt), ctx, arg)
+}
+
+// AddPendingLoginAttempt mocks base method.
+func (m *MockQuerier) AddPendingLoginAttempt(ctx context.Context, arg AddPendingLoginAttemptParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddPendingLoginAttempt", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddPendingLoginAttempt indicates an expected call of AddPendingLoginAttempt.
+func (mr *MockQuerierMockRecorder) AddPendingLoginAttempt(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPendingLoginAttempt", reflect.TypeOf((*MockQuerier)(nil).AddPendingLoginAttempt), ctx, arg)
+}
+
 // AdminGetEntryByPath mocks base method.
 func (m *MockQuerier) AdminGetEntryByPath(ctx context.Context, path string) (AdminGetEntryByPathRow, error) {
 	m.ctrl.T.Helper()
@@ -71,6 +101,21 @@ func (mr *MockQuerierMockRecorder) AdminListAllEntries(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminListAllEntries", reflect.TypeOf((*MockQuerier)(nil).AdminListAllEntries), ctx)
 }
 
+// CountAdminUsers mocks base method.
+func (m *MockQuerier) CountAdminUsers(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAdminUsers", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAdminUsers indicates an expected call of CountAdminUsers.
+func (mr *MockQuerierMockRecorder) CountAdminUsers(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAdminUsers", reflect.TypeOf((*MockQuerier)(nil).CountAdminUsers), ctx)
+}
+
 // CountAmazonCacheByAsin mocks base method.
 func (m *MockQuerier) CountAmazonCacheByAsin(ctx context.Context, asin string) (int64, error) {
 	m.ctrl.T.Helper()
@@ -86,6 +131,36 @@ func (mr *MockQuerierMockRecorder) CountAmazonCacheByAsin(ctx, asin any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAmazonCacheByAsin", reflect.TypeOf((*MockQuerier)(nil).CountAmazonCacheByAsin), ctx, asin)
 }
 
+// CountUnusedRecoveryCodes mocks base method.
+func (m *MockQuerier) CountUnusedRecoveryCodes(ctx context.Context, userID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnusedRecoveryCodes", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnusedRecoveryCodes indicates an expected call of CountUnusedRecoveryCodes.
+func (mr *MockQuerierMockRecorder) CountUnusedRecoveryCodes(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnusedRecoveryCodes", reflect.TypeOf((*MockQuerier)(nil).CountUnusedRecoveryCodes), ctx, userID)
+}
+
+// CreateAdminUser mocks base method.
+func (m *MockQuerier) CreateAdminUser(ctx context.Context, arg CreateAdminUserParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAdminUser", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAdminUser indicates an expected call of CreateAdminUser.
+func (mr *MockQuerierMockRecorder) CreateAdminUser(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAdminUser", reflect.TypeOf((*MockQuerier)(nil).CreateAdminUser), ctx, arg)
+}
+
 // CreateEmptyEntry mocks base method.
 func (m *MockQuerier) CreateEmptyEntry(ctx context.Context, arg CreateEmptyEntryParams) (int64, error) {
 	m.ctrl.T.Helper()
@@ -116,6 +191,48 @@ func (mr *MockQuerierMockRecorder) CreateEntryWithBody(ctx, arg any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntryWithBody", reflect.TypeOf((*MockQuerier)(nil).CreateEntryWithBody), ctx, arg)
 }
 
+// CreatePendingLogin mocks base method.
+func (m *MockQuerier) CreatePendingLogin(ctx context.Context, arg CreatePendingLoginParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePendingLogin", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePendingLogin indicates an expected call of CreatePendingLogin.
+func (mr *MockQuerierMockRecorder) CreatePendingLogin(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePendingLogin", reflect.TypeOf((*MockQuerier)(nil).CreatePendingLogin), ctx, arg)
+}
+
+// CreatePreviewToken mocks base method.
+func (m *MockQuerier) CreatePreviewToken(ctx context.Context, arg CreatePreviewTokenParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePreviewToken", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePreviewToken indicates an expected call of CreatePreviewToken.
+func (mr *MockQuerierMockRecorder) CreatePreviewToken(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePreviewToken", reflect.TypeOf((*MockQuerier)(nil).CreatePreviewToken), ctx, arg)
+}
+
+// CreateRecoveryCode mocks base method.
+func (m *MockQuerier) CreateRecoveryCode(ctx context.Context, arg CreateRecoveryCodeParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRecoveryCode", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRecoveryCode indicates an expected call of CreateRecoveryCode.
+func (mr *MockQuerierMockRecorder) CreateRecoveryCode(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRecoveryCode", reflect.TypeOf((*MockQuerier)(nil).CreateRecoveryCode), ctx, arg)
+}
+
 // CreateSession mocks base method.
 func (m *MockQuerier) CreateSession(ctx context.Context, arg CreateSessionParams) error {
 	m.ctrl.T.Helper()
@@ -130,6 +247,49 @@ func (mr *MockQuerierMockRecorder) CreateSession(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockQuerier)(nil).CreateSession), ctx, arg)
 }
 
+// CreateWebauthnChallenge mocks base method.
+func (m *MockQuerier) CreateWebauthnChallenge(ctx context.Context, arg CreateWebauthnChallengeParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebauthnChallenge", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWebauthnChallenge indicates an expected call of CreateWebauthnChallenge.
+func (mr *MockQuerierMockRecorder) CreateWebauthnChallenge(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebauthnChallenge", reflect.TypeOf((*MockQuerier)(nil).CreateWebauthnChallenge), ctx, arg)
+}
+
+// CreateWebauthnCredential mocks base method.
+func (m *MockQuerier) CreateWebauthnCredential(ctx context.Context, arg CreateWebauthnCredentialParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebauthnCredential", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWebauthnCredential indicates an expected call of CreateWebauthnCredential.
+func (mr *MockQuerierMockRecorder) CreateWebauthnCredential(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebauthnCredential", reflect.TypeOf((*MockQuerier)(nil).CreateWebauthnCredential), ctx, arg)
+}
+
+// DeleteBodyEntryTags mocks base method.
+func (m *MockQuerier) DeleteBodyEntryTags(ctx context.Context, path string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBodyEntryTags", ctx, path)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteBodyEntryTags indicates an expected call of DeleteBodyEntryTags.
+func (mr *MockQuerierMockRecorder) DeleteBodyEntryTags(ctx, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBodyEntryTags", reflect.TypeOf((*MockQuerier)(nil).DeleteBodyEntryTags), ctx, path)
+}
+
 // DeleteEntry mocks base method.
 func (m *MockQuerier) DeleteEntry(ctx context.Context, path string) (int64, error) {
 	m.ctrl.T.Helper()
@@ -175,267 +335,1065 @@ func (mr *MockQuerierMockRecorder) DeleteEntryLinkByPath(ctx, srcPath any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEntryLinkByPath", reflect.TypeOf((*MockQuerier)(nil).DeleteEntryLinkByPath), ctx, srcPath)
 }
 
-// DeleteExpiredSessions mocks base method.
-func (m *MockQuerier) DeleteExpiredSessions(ctx context.Context) error {
+// DeleteEntryTagsByPath mocks base method.
+func (m *MockQuerier) DeleteEntryTagsByPath(ctx context.Context, path string) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteExpiredSessions", ctx)
+	ret := m.ctrl.Call(m, "DeleteEntryTagsByPath", ctx, path)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteEntryTagsByPath indicates an expected call of DeleteEntryTagsByPath.
+func (mr *MockQuerierMockRecorder) DeleteEntryTagsByPath(ctx, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEntryTagsByPath", reflect.TypeOf((*MockQuerier)(nil).DeleteEntryTagsByPath), ctx, path)
+}
+
+// DeleteExpiredPendingLogins mocks base method.
+func (m *MockQuerier) DeleteExpiredPendingLogins(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredPendingLogins", ctx)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteExpiredSessions indicates an expected call of DeleteExpiredSessions.
-func (mr *MockQuerierMockRecorder) DeleteExpiredSessions(ctx any) *gomock.Call {
+// DeleteExpiredPendingLogins indicates an expected call of DeleteExpiredPendingLogins.
+func (mr *MockQuerierMockRecorder) DeleteExpiredPendingLogins(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredSessions", reflect.TypeOf((*MockQuerier)(nil).DeleteExpiredSessions), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredPendingLogins", reflect.TypeOf((*MockQuerier)(nil).DeleteExpiredPendingLogins), ctx)
 }
 
-// DeleteSession mocks base method.
-func (m *MockQuerier) DeleteSession(ctx context.Context, sessionID string) error {
+// DeleteExpiredPreviewTokens mocks base method.
+func (m *MockQuerier) DeleteExpiredPreviewTokens(ctx context.Context) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteSession", ctx, sessionID)
+	ret := m.ctrl.Call(m, "DeleteExpiredPreviewTokens", ctx)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteSession indicates an expected call of DeleteSession.
-func (mr *MockQuerierMockRecorder) DeleteSession(ctx, sessionID any) *gomock.Call {
+// DeleteExpiredPreviewTokens indicates an expected call of DeleteExpiredPreviewTokens.
+func (mr *MockQuerierMockRecorder) DeleteExpiredPreviewTokens(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSession", reflect.TypeOf((*MockQuerier)(nil).DeleteSession), ctx, sessionID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredPreviewTokens", reflect.TypeOf((*MockQuerier)(nil).DeleteExpiredPreviewTokens), ctx)
 }
 
-// GetAllEntryTitles mocks base method.
-func (m *MockQuerier) GetAllEntryTitles(ctx context.Context) ([]string, error) {
+// DeleteExpiredSessions mocks base method.
+func (m *MockQuerier) DeleteExpiredSessions(ctx context.Context) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllEntryTitles", ctx)
-	ret0, _ := ret[0].([]string)
+	ret := m.ctrl.Call(m, "DeleteExpiredSessions", ctx)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAllEntryTitles indicates an expected call of GetAllEntryTitles.
-func (mr *MockQuerierMockRecorder) GetAllEntryTitles(ctx any) *gomock.Call {
+// DeleteExpiredSessions indicates an expected call of DeleteExpiredSessions.
+func (mr *MockQuerierMockRecorder) DeleteExpiredSessions(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllEntryTitles", reflect.TypeOf((*MockQuerier)(nil).GetAllEntryTitles), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredSessions", reflect.TypeOf((*MockQuerier)(nil).DeleteExpiredSessions), ctx)
 }
 
-// GetAmazonImageUrlByAsin mocks base method.
-func (m *MockQuerier) GetAmazonImageUrlByAsin(ctx context.Context, asin string) (sql.NullString, error) {
+// DeleteExpiredWebauthnChallenges mocks base method.
+func (m *MockQuerier) DeleteExpiredWebauthnChallenges(ctx context.Context) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAmazonImageUrlByAsin", ctx, asin)
-	ret0, _ := ret[0].(sql.NullString)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "DeleteExpiredWebauthnChallenges", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetAmazonImageUrlByAsin indicates an expected call of GetAmazonImageUrlByAsin.
-func (mr *MockQuerierMockRecorder) GetAmazonImageUrlByAsin(ctx, asin any) *gomock.Call {
+// DeleteExpiredWebauthnChallenges indicates an expected call of DeleteExpiredWebauthnChallenges.
+func (mr *MockQuerierMockRecorder) DeleteExpiredWebauthnChallenges(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAmazonImageUrlByAsin", reflect.TypeOf((*MockQuerier)(nil).GetAmazonImageUrlByAsin), ctx, asin)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredWebauthnChallenges", reflect.TypeOf((*MockQuerier)(nil).DeleteExpiredWebauthnChallenges), ctx)
 }
 
-// GetEntriesByLinkedTitle mocks base method.
-func (m *MockQuerier) GetEntriesByLinkedTitle(ctx context.Context, dstTitle string) ([]Entry, error) {
+// DeletePendingLogin mocks base method.
+func (m *MockQuerier) DeletePendingLogin(ctx context.Context, token string) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEntriesByLinkedTitle", ctx, dstTitle)
-	ret0, _ := ret[0].([]Entry)
+	ret := m.ctrl.Call(m, "DeletePendingLogin", ctx, token)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetEntriesByLinkedTitle indicates an expected call of GetEntriesByLinkedTitle.
-func (mr *MockQuerierMockRecorder) GetEntriesByLinkedTitle(ctx, dstTitle any) *gomock.Call {
+// DeletePendingLogin indicates an expected call of DeletePendingLogin.
+func (mr *MockQuerierMockRecorder) DeletePendingLogin(ctx, token any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntriesByLinkedTitle", reflect.TypeOf((*MockQuerier)(nil).GetEntriesByLinkedTitle), ctx, dstTitle)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePendingLogin", reflect.TypeOf((*MockQuerier)(nil).DeletePendingLogin), ctx, token)
 }
 
-// GetEntryImageByPath mocks base method.
-func (m *MockQuerier) GetEntryImageByPath(ctx context.Context, path string) (EntryImage, error) {
+// DeletePreviewToken mocks base method.
+func (m *MockQuerier) DeletePreviewToken(ctx context.Context, arg DeletePreviewTokenParams) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEntryImageByPath", ctx, path)
-	ret0, _ := ret[0].(EntryImage)
+	ret := m.ctrl.Call(m, "DeletePreviewToken", ctx, arg)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetEntryImageByPath indicates an expected call of GetEntryImageByPath.
-func (mr *MockQuerierMockRecorder) GetEntryImageByPath(ctx, path any) *gomock.Call {
+// DeletePreviewToken indicates an expected call of DeletePreviewToken.
+func (mr *MockQuerierMockRecorder) DeletePreviewToken(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntryImageByPath", reflect.TypeOf((*MockQuerier)(nil).GetEntryImageByPath), ctx, path)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePreviewToken", reflect.TypeOf((*MockQuerier)(nil).DeletePreviewToken), ctx, arg)
 }
 
-// GetEntryImageNotProcessedEntries mocks base method.
-func (m *MockQuerier) GetEntryImageNotProcessedEntries(ctx context.Context) ([]Entry, error) {
+// DeleteRecoveryCodes mocks base method.
+func (m *MockQuerier) DeleteRecoveryCodes(ctx context.Context, userID int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEntryImageNotProcessedEntries", ctx)
-	ret0, _ := ret[0].([]Entry)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "DeleteRecoveryCodes", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetEntryImageNotProcessedEntries indicates an expected call of GetEntryImageNotProcessedEntries.
-func (mr *MockQuerierMockRecorder) GetEntryImageNotProcessedEntries(ctx any) *gomock.Call {
+// DeleteRecoveryCodes indicates an expected call of DeleteRecoveryCodes.
+func (mr *MockQuerierMockRecorder) DeleteRecoveryCodes(ctx, userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntryImageNotProcessedEntries", reflect.TypeOf((*MockQuerier)(nil).GetEntryImageNotProcessedEntries), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRecoveryCodes", reflect.TypeOf((*MockQuerier)(nil).DeleteRecoveryCodes), ctx, userID)
 }
 
-// GetEntryVisibility mocks base method.
-func (m *MockQuerier) GetEntryVisibility(ctx context.Context, path string) (GetEntryVisibilityRow, error) {
+// DeleteSearchTokensByPath mocks base method.
+func (m *MockQuerier) DeleteSearchTokensByPath(ctx context.Context, path string) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEntryVisibility", ctx, path)
-	ret0, _ := ret[0].(GetEntryVisibilityRow)
+	ret := m.ctrl.Call(m, "DeleteSearchTokensByPath", ctx, path)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetEntryVisibility indicates an expected call of GetEntryVisibility.
-func (mr *MockQuerierMockRecorder) GetEntryVisibility(ctx, path any) *gomock.Call {
+// DeleteSearchTokensByPath indicates an expected call of DeleteSearchTokensByPath.
+func (mr *MockQuerierMockRecorder) DeleteSearchTokensByPath(ctx, path any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntryVisibility", reflect.TypeOf((*MockQuerier)(nil).GetEntryVisibility), ctx, path)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSearchTokensByPath", reflect.TypeOf((*MockQuerier)(nil).DeleteSearchTokensByPath), ctx, path)
 }
 
-// GetLinkedEntries mocks base method.
-func (m *MockQuerier) GetLinkedEntries(ctx context.Context, srcPath string) ([]GetLinkedEntriesRow, error) {
+// DeleteSession mocks base method.
+func (m *MockQuerier) DeleteSession(ctx context.Context, sessionID string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLinkedEntries", ctx, srcPath)
-	ret0, _ := ret[0].([]GetLinkedEntriesRow)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "DeleteSession", ctx, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetLinkedEntries indicates an expected call of GetLinkedEntries.
-func (mr *MockQuerierMockRecorder) GetLinkedEntries(ctx, srcPath any) *gomock.Call {
+// DeleteSession indicates an expected call of DeleteSession.
+func (mr *MockQuerierMockRecorder) DeleteSession(ctx, sessionID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLinkedEntries", reflect.TypeOf((*MockQuerier)(nil).GetLinkedEntries), ctx, srcPath)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSession", reflect.TypeOf((*MockQuerier)(nil).DeleteSession), ctx, sessionID)
 }
 
-// GetSession mocks base method.
-func (m *MockQuerier) GetSession(ctx context.Context, sessionID string) (AdminSession, error) {
+// DeleteUserSession mocks base method.
+func (m *MockQuerier) DeleteUserSession(ctx context.Context, arg DeleteUserSessionParams) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSession", ctx, sessionID)
-	ret0, _ := ret[0].(AdminSession)
+	ret := m.ctrl.Call(m, "DeleteUserSession", ctx, arg)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetSession indicates an expected call of GetSession.
-func (mr *MockQuerierMockRecorder) GetSession(ctx, sessionID any) *gomock.Call {
+// DeleteUserSession indicates an expected call of DeleteUserSession.
+func (mr *MockQuerierMockRecorder) DeleteUserSession(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockQuerier)(nil).GetSession), ctx, sessionID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserSession", reflect.TypeOf((*MockQuerier)(nil).DeleteUserSession), ctx, arg)
 }
 
-// InsertAmazonProductDetail mocks base method.
-func (m *MockQuerier) InsertAmazonProductDetail(ctx context.Context, arg InsertAmazonProductDetailParams) (int64, error) {
+// DeleteUserSessions mocks base method.
+func (m *MockQuerier) DeleteUserSessions(ctx context.Context, userID int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InsertAmazonProductDetail", ctx, arg)
-	ret0, _ := ret[0].(int64)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "DeleteUserSessions", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// InsertAmazonProductDetail indicates an expected call of InsertAmazonProductDetail.
-func (mr *MockQuerierMockRecorder) InsertAmazonProductDetail(ctx, arg any) *gomock.Call {
+// DeleteUserSessions indicates an expected call of DeleteUserSessions.
+func (mr *MockQuerierMockRecorder) DeleteUserSessions(ctx, userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAmazonProductDetail", reflect.TypeOf((*MockQuerier)(nil).InsertAmazonProductDetail), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserSessions", reflect.TypeOf((*MockQuerier)(nil).DeleteUserSessions), ctx, userID)
 }
 
-// InsertEntryImage mocks base method.
-func (m *MockQuerier) InsertEntryImage(ctx context.Context, arg InsertEntryImageParams) (int64, error) {
+// DeleteWebauthnChallenge mocks base method.
+func (m *MockQuerier) DeleteWebauthnChallenge(ctx context.Context, token string) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InsertEntryImage", ctx, arg)
+	ret := m.ctrl.Call(m, "DeleteWebauthnChallenge", ctx, token)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// InsertEntryImage indicates an expected call of InsertEntryImage.
-func (mr *MockQuerierMockRecorder) InsertEntryImage(ctx, arg any) *gomock.Call {
+// DeleteWebauthnChallenge indicates an expected call of DeleteWebauthnChallenge.
+func (mr *MockQuerierMockRecorder) DeleteWebauthnChallenge(ctx, token any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertEntryImage", reflect.TypeOf((*MockQuerier)(nil).InsertEntryImage), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebauthnChallenge", reflect.TypeOf((*MockQuerier)(nil).DeleteWebauthnChallenge), ctx, token)
 }
 
-// InsertEntryLink mocks base method.
-func (m *MockQuerier) InsertEntryLink(ctx context.Context, arg InsertEntryLinkParams) (int64, error) {
+// DeleteWebauthnCredential mocks base method.
+func (m *MockQuerier) DeleteWebauthnCredential(ctx context.Context, arg DeleteWebauthnCredentialParams) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InsertEntryLink", ctx, arg)
+	ret := m.ctrl.Call(m, "DeleteWebauthnCredential", ctx, arg)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// InsertEntryLink indicates an expected call of InsertEntryLink.
-func (mr *MockQuerierMockRecorder) InsertEntryLink(ctx, arg any) *gomock.Call {
+// DeleteWebauthnCredential indicates an expected call of DeleteWebauthnCredential.
+func (mr *MockQuerierMockRecorder) DeleteWebauthnCredential(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertEntryLink", reflect.TypeOf((*MockQuerier)(nil).InsertEntryLink), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebauthnCredential", reflect.TypeOf((*MockQuerier)(nil).DeleteWebauthnCredential), ctx, arg)
 }
 
-// UpdateEntryBody mocks base method.
-func (m *MockQuerier) UpdateEntryBody(ctx context.Context, arg UpdateEntryBodyParams) (int64, error) {
+// DisableAdminUserTOTP mocks base method.
+func (m *MockQuerier) DisableAdminUserTOTP(ctx context.Context, id int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateEntryBody", ctx, arg)
-	ret0, _ := ret[0].(int64)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "DisableAdminUserTOTP", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// UpdateEntryBody indicates an expected call of UpdateEntryBody.
-func (mr *MockQuerierMockRecorder) UpdateEntryBody(ctx, arg any) *gomock.Call {
+// DisableAdminUserTOTP indicates an expected call of DisableAdminUserTOTP.
+func (mr *MockQuerierMockRecorder) DisableAdminUserTOTP(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEntryBody", reflect.TypeOf((*MockQuerier)(nil).UpdateEntryBody), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableAdminUserTOTP", reflect.TypeOf((*MockQuerier)(nil).DisableAdminUserTOTP), ctx, id)
 }
 
-// UpdateEntryTitle mocks base method.
-func (m *MockQuerier) UpdateEntryTitle(ctx context.Context, arg UpdateEntryTitleParams) (int64, error) {
+// EnableAdminUserTOTP mocks base method.
+func (m *MockQuerier) EnableAdminUserTOTP(ctx context.Context, arg EnableAdminUserTOTPParams) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateEntryTitle", ctx, arg)
+	ret := m.ctrl.Call(m, "EnableAdminUserTOTP", ctx, arg)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdateEntryTitle indicates an expected call of UpdateEntryTitle.
-func (mr *MockQuerierMockRecorder) UpdateEntryTitle(ctx, arg any) *gomock.Call {
+// EnableAdminUserTOTP indicates an expected call of EnableAdminUserTOTP.
+func (mr *MockQuerierMockRecorder) EnableAdminUserTOTP(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEntryTitle", reflect.TypeOf((*MockQuerier)(nil).UpdateEntryTitle), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableAdminUserTOTP", reflect.TypeOf((*MockQuerier)(nil).EnableAdminUserTOTP), ctx, arg)
 }
 
-// UpdatePublishedAt mocks base method.
-func (m *MockQuerier) UpdatePublishedAt(ctx context.Context, path string) error {
+// GetAdminUser mocks base method.
+func (m *MockQuerier) GetAdminUser(ctx context.Context, id int64) (AdminUser, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdatePublishedAt", ctx, path)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "GetAdminUser", ctx, id)
+	ret0, _ := ret[0].(AdminUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// UpdatePublishedAt indicates an expected call of UpdatePublishedAt.
-func (mr *MockQuerierMockRecorder) UpdatePublishedAt(ctx, path any) *gomock.Call {
+// GetAdminUser indicates an expected call of GetAdminUser.
+func (mr *MockQuerierMockRecorder) GetAdminUser(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePublishedAt", reflect.TypeOf((*MockQuerier)(nil).UpdatePublishedAt), ctx, path)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdminUser", reflect.TypeOf((*MockQuerier)(nil).GetAdminUser), ctx, id)
 }
 
-// UpdateSessionLastAccessed mocks base method.
-func (m *MockQuerier) UpdateSessionLastAccessed(ctx context.Context, sessionID string) error {
+// GetAdminUserByUsername mocks base method.
+func (m *MockQuerier) GetAdminUserByUsername(ctx context.Context, username string) (AdminUser, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateSessionLastAccessed", ctx, sessionID)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "GetAdminUserByUsername", ctx, username)
+	ret0, _ := ret[0].(AdminUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// UpdateSessionLastAccessed indicates an expected call of UpdateSessionLastAccessed.
-func (mr *MockQuerierMockRecorder) UpdateSessionLastAccessed(ctx, sessionID any) *gomock.Call {
+// GetAdminUserByUsername indicates an expected call of GetAdminUserByUsername.
+func (mr *MockQuerierMockRecorder) GetAdminUserByUsername(ctx, username any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSessionLastAccessed", reflect.TypeOf((*MockQuerier)(nil).UpdateSessionLastAccessed), ctx, sessionID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdminUserByUsername", reflect.TypeOf((*MockQuerier)(nil).GetAdminUserByUsername), ctx, username)
 }
 
-// UpdateVisibility mocks base method.
-func (m *MockQuerier) UpdateVisibility(ctx context.Context, arg UpdateVisibilityParams) error {
+// GetAllEntryTitles mocks base method.
+func (m *MockQuerier) GetAllEntryTitles(ctx context.Context) ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateVisibility", ctx, arg)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "GetAllEntryTitles", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// UpdateVisibility indicates an expected call of UpdateVisibility.
-func (mr *MockQuerierMockRecorder) UpdateVisibility(ctx, arg any) *gomock.Call {
+// GetAllEntryTitles indicates an expected call of GetAllEntryTitles.
+func (mr *MockQuerierMockRecorder) GetAllEntryTitles(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllEntryTitles", reflect.TypeOf((*MockQuerier)(nil).GetAllEntryTitles), ctx)
+}
+
+// GetAmazonCacheFetchedAt mocks base method.
+func (m *MockQuerier) GetAmazonCacheFetchedAt(ctx context.Context, asins []string) ([]GetAmazonCacheFetchedAtRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAmazonCacheFetchedAt", ctx, asins)
+	ret0, _ := ret[0].([]GetAmazonCacheFetchedAtRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAmazonCacheFetchedAt indicates an expected call of GetAmazonCacheFetchedAt.
+func (mr *MockQuerierMockRecorder) GetAmazonCacheFetchedAt(ctx, asins any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAmazonCacheFetchedAt", reflect.TypeOf((*MockQuerier)(nil).GetAmazonCacheFetchedAt), ctx, asins)
+}
+
+// GetAmazonImageUrlByAsin mocks base method.
+func (m *MockQuerier) GetAmazonImageUrlByAsin(ctx context.Context, asin string) (sql.NullString, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAmazonImageUrlByAsin", ctx, asin)
+	ret0, _ := ret[0].(sql.NullString)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAmazonImageUrlByAsin indicates an expected call of GetAmazonImageUrlByAsin.
+func (mr *MockQuerierMockRecorder) GetAmazonImageUrlByAsin(ctx, asin any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAmazonImageUrlByAsin", reflect.TypeOf((*MockQuerier)(nil).GetAmazonImageUrlByAsin), ctx, asin)
+}
+
+// GetEntriesByLinkedTitle mocks base method.
+func (m *MockQuerier) GetEntriesByLinkedTitle(ctx context.Context, dstTitle string) ([]GetEntriesByLinkedTitleRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntriesByLinkedTitle", ctx, dstTitle)
+	ret0, _ := ret[0].([]GetEntriesByLinkedTitleRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntriesByLinkedTitle indicates an expected call of GetEntriesByLinkedTitle.
+func (mr *MockQuerierMockRecorder) GetEntriesByLinkedTitle(ctx, dstTitle any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntriesByLinkedTitle", reflect.TypeOf((*MockQuerier)(nil).GetEntriesByLinkedTitle), ctx, dstTitle)
+}
+
+// GetEntryImageByPath mocks base method.
+func (m *MockQuerier) GetEntryImageByPath(ctx context.Context, path string) (EntryImage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntryImageByPath", ctx, path)
+	ret0, _ := ret[0].(EntryImage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntryImageByPath indicates an expected call of GetEntryImageByPath.
+func (mr *MockQuerierMockRecorder) GetEntryImageByPath(ctx, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntryImageByPath", reflect.TypeOf((*MockQuerier)(nil).GetEntryImageByPath), ctx, path)
+}
+
+// GetEntryImageNotProcessedEntries mocks base method.
+func (m *MockQuerier) GetEntryImageNotProcessedEntries(ctx context.Context) ([]Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntryImageNotProcessedEntries", ctx)
+	ret0, _ := ret[0].([]Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntryImageNotProcessedEntries indicates an expected call of GetEntryImageNotProcessedEntries.
+func (mr *MockQuerierMockRecorder) GetEntryImageNotProcessedEntries(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntryImageNotProcessedEntries", reflect.TypeOf((*MockQuerier)(nil).GetEntryImageNotProcessedEntries), ctx)
+}
+
+// GetEntryPathByTitle mocks base method.
+func (m *MockQuerier) GetEntryPathByTitle(ctx context.Context, title string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntryPathByTitle", ctx, title)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntryPathByTitle indicates an expected call of GetEntryPathByTitle.
+func (mr *MockQuerierMockRecorder) GetEntryPathByTitle(ctx, title any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntryPathByTitle", reflect.TypeOf((*MockQuerier)(nil).GetEntryPathByTitle), ctx, title)
+}
+
+// GetEntryPathsByTitles mocks base method.
+func (m *MockQuerier) GetEntryPathsByTitles(ctx context.Context, titles []string) ([]GetEntryPathsByTitlesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntryPathsByTitles", ctx, titles)
+	ret0, _ := ret[0].([]GetEntryPathsByTitlesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntryPathsByTitles indicates an expected call of GetEntryPathsByTitles.
+func (mr *MockQuerierMockRecorder) GetEntryPathsByTitles(ctx, titles any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntryPathsByTitles", reflect.TypeOf((*MockQuerier)(nil).GetEntryPathsByTitles), ctx, titles)
+}
+
+// GetEntryRevision mocks base method.
+func (m *MockQuerier) GetEntryRevision(ctx context.Context, arg GetEntryRevisionParams) (EntryRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntryRevision", ctx, arg)
+	ret0, _ := ret[0].(EntryRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntryRevision indicates an expected call of GetEntryRevision.
+func (mr *MockQuerierMockRecorder) GetEntryRevision(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntryRevision", reflect.TypeOf((*MockQuerier)(nil).GetEntryRevision), ctx, arg)
+}
+
+// GetEntryTags mocks base method.
+func (m *MockQuerier) GetEntryTags(ctx context.Context, path string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntryTags", ctx, path)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntryTags indicates an expected call of GetEntryTags.
+func (mr *MockQuerierMockRecorder) GetEntryTags(ctx, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntryTags", reflect.TypeOf((*MockQuerier)(nil).GetEntryTags), ctx, path)
+}
+
+// GetEntryTitleForUpdate mocks base method.
+func (m *MockQuerier) GetEntryTitleForUpdate(ctx context.Context, path string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntryTitleForUpdate", ctx, path)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntryTitleForUpdate indicates an expected call of GetEntryTitleForUpdate.
+func (mr *MockQuerierMockRecorder) GetEntryTitleForUpdate(ctx, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntryTitleForUpdate", reflect.TypeOf((*MockQuerier)(nil).GetEntryTitleForUpdate), ctx, path)
+}
+
+// GetEntryVisibility mocks base method.
+func (m *MockQuerier) GetEntryVisibility(ctx context.Context, path string) (GetEntryVisibilityRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntryVisibility", ctx, path)
+	ret0, _ := ret[0].(GetEntryVisibilityRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntryVisibility indicates an expected call of GetEntryVisibility.
+func (mr *MockQuerierMockRecorder) GetEntryVisibility(ctx, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntryVisibility", reflect.TypeOf((*MockQuerier)(nil).GetEntryVisibility), ctx, path)
+}
+
+// GetLatestEntryRevision mocks base method.
+func (m *MockQuerier) GetLatestEntryRevision(ctx context.Context, path string) (EntryRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestEntryRevision", ctx, path)
+	ret0, _ := ret[0].(EntryRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestEntryRevision indicates an expected call of GetLatestEntryRevision.
+func (mr *MockQuerierMockRecorder) GetLatestEntryRevision(ctx, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestEntryRevision", reflect.TypeOf((*MockQuerier)(nil).GetLatestEntryRevision), ctx, path)
+}
+
+// GetLinkedEntries mocks base method.
+func (m *MockQuerier) GetLinkedEntries(ctx context.Context, srcPath string) ([]GetLinkedEntriesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLinkedEntries", ctx, srcPath)
+	ret0, _ := ret[0].([]GetLinkedEntriesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLinkedEntries indicates an expected call of GetLinkedEntries.
+func (mr *MockQuerierMockRecorder) GetLinkedEntries(ctx, srcPath any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLinkedEntries", reflect.TypeOf((*MockQuerier)(nil).GetLinkedEntries), ctx, srcPath)
+}
+
+// GetPendingLogin mocks base method.
+func (m *MockQuerier) GetPendingLogin(ctx context.Context, token string) (AdminPendingLogin, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingLogin", ctx, token)
+	ret0, _ := ret[0].(AdminPendingLogin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingLogin indicates an expected call of GetPendingLogin.
+func (mr *MockQuerierMockRecorder) GetPendingLogin(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingLogin", reflect.TypeOf((*MockQuerier)(nil).GetPendingLogin), ctx, token)
+}
+
+// GetPublicEntriesByTitles mocks base method.
+func (m *MockQuerier) GetPublicEntriesByTitles(ctx context.Context, titles []string) ([]GetPublicEntriesByTitlesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPublicEntriesByTitles", ctx, titles)
+	ret0, _ := ret[0].([]GetPublicEntriesByTitlesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPublicEntriesByTitles indicates an expected call of GetPublicEntriesByTitles.
+func (mr *MockQuerierMockRecorder) GetPublicEntriesByTitles(ctx, titles any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublicEntriesByTitles", reflect.TypeOf((*MockQuerier)(nil).GetPublicEntriesByTitles), ctx, titles)
+}
+
+// GetSession mocks base method.
+func (m *MockQuerier) GetSession(ctx context.Context, sessionID string) (GetSessionRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSession", ctx, sessionID)
+	ret0, _ := ret[0].(GetSessionRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSession indicates an expected call of GetSession.
+func (mr *MockQuerierMockRecorder) GetSession(ctx, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockQuerier)(nil).GetSession), ctx, sessionID)
+}
+
+// GetTwoHopEntries mocks base method.
+func (m *MockQuerier) GetTwoHopEntries(ctx context.Context, arg GetTwoHopEntriesParams) ([]GetTwoHopEntriesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTwoHopEntries", ctx, arg)
+	ret0, _ := ret[0].([]GetTwoHopEntriesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTwoHopEntries indicates an expected call of GetTwoHopEntries.
+func (mr *MockQuerierMockRecorder) GetTwoHopEntries(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTwoHopEntries", reflect.TypeOf((*MockQuerier)(nil).GetTwoHopEntries), ctx, arg)
+}
+
+// GetWebauthnChallenge mocks base method.
+func (m *MockQuerier) GetWebauthnChallenge(ctx context.Context, token string) (WebauthnChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebauthnChallenge", ctx, token)
+	ret0, _ := ret[0].(WebauthnChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebauthnChallenge indicates an expected call of GetWebauthnChallenge.
+func (mr *MockQuerierMockRecorder) GetWebauthnChallenge(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebauthnChallenge", reflect.TypeOf((*MockQuerier)(nil).GetWebauthnChallenge), ctx, token)
+}
+
+// GetWebauthnCredentialByCredentialID mocks base method.
+func (m *MockQuerier) GetWebauthnCredentialByCredentialID(ctx context.Context, credentialID []byte) (WebauthnCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebauthnCredentialByCredentialID", ctx, credentialID)
+	ret0, _ := ret[0].(WebauthnCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebauthnCredentialByCredentialID indicates an expected call of GetWebauthnCredentialByCredentialID.
+func (mr *MockQuerierMockRecorder) GetWebauthnCredentialByCredentialID(ctx, credentialID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebauthnCredentialByCredentialID", reflect.TypeOf((*MockQuerier)(nil).GetWebauthnCredentialByCredentialID), ctx, credentialID)
+}
+
+// InsertBodyEntryTag mocks base method.
+func (m *MockQuerier) InsertBodyEntryTag(ctx context.Context, arg InsertBodyEntryTagParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertBodyEntryTag", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertBodyEntryTag indicates an expected call of InsertBodyEntryTag.
+func (mr *MockQuerierMockRecorder) InsertBodyEntryTag(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertBodyEntryTag", reflect.TypeOf((*MockQuerier)(nil).InsertBodyEntryTag), ctx, arg)
+}
+
+// InsertEntryImage mocks base method.
+func (m *MockQuerier) InsertEntryImage(ctx context.Context, arg InsertEntryImageParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertEntryImage", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertEntryImage indicates an expected call of InsertEntryImage.
+func (mr *MockQuerierMockRecorder) InsertEntryImage(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertEntryImage", reflect.TypeOf((*MockQuerier)(nil).InsertEntryImage), ctx, arg)
+}
+
+// InsertEntryRevision mocks base method.
+func (m *MockQuerier) InsertEntryRevision(ctx context.Context, arg InsertEntryRevisionParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertEntryRevision", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertEntryRevision indicates an expected call of InsertEntryRevision.
+func (mr *MockQuerierMockRecorder) InsertEntryRevision(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertEntryRevision", reflect.TypeOf((*MockQuerier)(nil).InsertEntryRevision), ctx, arg)
+}
+
+// InsertEntryTag mocks base method.
+func (m *MockQuerier) InsertEntryTag(ctx context.Context, arg InsertEntryTagParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertEntryTag", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertEntryTag indicates an expected call of InsertEntryTag.
+func (mr *MockQuerierMockRecorder) InsertEntryTag(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertEntryTag", reflect.TypeOf((*MockQuerier)(nil).InsertEntryTag), ctx, arg)
+}
+
+// InsertMissingEntryRevisions mocks base method.
+func (m *MockQuerier) InsertMissingEntryRevisions(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertMissingEntryRevisions", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertMissingEntryRevisions indicates an expected call of InsertMissingEntryRevisions.
+func (mr *MockQuerierMockRecorder) InsertMissingEntryRevisions(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertMissingEntryRevisions", reflect.TypeOf((*MockQuerier)(nil).InsertMissingEntryRevisions), ctx)
+}
+
+// ListAdminUsers mocks base method.
+func (m *MockQuerier) ListAdminUsers(ctx context.Context) ([]ListAdminUsersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAdminUsers", ctx)
+	ret0, _ := ret[0].([]ListAdminUsersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAdminUsers indicates an expected call of ListAdminUsers.
+func (mr *MockQuerierMockRecorder) ListAdminUsers(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAdminUsers", reflect.TypeOf((*MockQuerier)(nil).ListAdminUsers), ctx)
+}
+
+// ListAllEntryTags mocks base method.
+func (m *MockQuerier) ListAllEntryTags(ctx context.Context) ([]ListAllEntryTagsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAllEntryTags", ctx)
+	ret0, _ := ret[0].([]ListAllEntryTagsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAllEntryTags indicates an expected call of ListAllEntryTags.
+func (mr *MockQuerierMockRecorder) ListAllEntryTags(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllEntryTags", reflect.TypeOf((*MockQuerier)(nil).ListAllEntryTags), ctx)
+}
+
+// ListDueScheduledEntries mocks base method.
+func (m *MockQuerier) ListDueScheduledEntries(ctx context.Context, now sql.NullTime) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDueScheduledEntries", ctx, now)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDueScheduledEntries indicates an expected call of ListDueScheduledEntries.
+func (mr *MockQuerierMockRecorder) ListDueScheduledEntries(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDueScheduledEntries", reflect.TypeOf((*MockQuerier)(nil).ListDueScheduledEntries), ctx, now)
+}
+
+// ListEntryBodiesWithAsin mocks base method.
+func (m *MockQuerier) ListEntryBodiesWithAsin(ctx context.Context) ([]ListEntryBodiesWithAsinRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntryBodiesWithAsin", ctx)
+	ret0, _ := ret[0].([]ListEntryBodiesWithAsinRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntryBodiesWithAsin indicates an expected call of ListEntryBodiesWithAsin.
+func (mr *MockQuerierMockRecorder) ListEntryBodiesWithAsin(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntryBodiesWithAsin", reflect.TypeOf((*MockQuerier)(nil).ListEntryBodiesWithAsin), ctx)
+}
+
+// ListEntryPathsWithoutSearchTokens mocks base method.
+func (m *MockQuerier) ListEntryPathsWithoutSearchTokens(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntryPathsWithoutSearchTokens", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntryPathsWithoutSearchTokens indicates an expected call of ListEntryPathsWithoutSearchTokens.
+func (mr *MockQuerierMockRecorder) ListEntryPathsWithoutSearchTokens(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntryPathsWithoutSearchTokens", reflect.TypeOf((*MockQuerier)(nil).ListEntryPathsWithoutSearchTokens), ctx)
+}
+
+// ListEntryRevisions mocks base method.
+func (m *MockQuerier) ListEntryRevisions(ctx context.Context, path string) ([]ListEntryRevisionsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntryRevisions", ctx, path)
+	ret0, _ := ret[0].([]ListEntryRevisionsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntryRevisions indicates an expected call of ListEntryRevisions.
+func (mr *MockQuerierMockRecorder) ListEntryRevisions(ctx, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntryRevisions", reflect.TypeOf((*MockQuerier)(nil).ListEntryRevisions), ctx, path)
+}
+
+// ListPreviewTokens mocks base method.
+func (m *MockQuerier) ListPreviewTokens(ctx context.Context, path string) ([]PreviewToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPreviewTokens", ctx, path)
+	ret0, _ := ret[0].([]PreviewToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPreviewTokens indicates an expected call of ListPreviewTokens.
+func (mr *MockQuerierMockRecorder) ListPreviewTokens(ctx, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPreviewTokens", reflect.TypeOf((*MockQuerier)(nil).ListPreviewTokens), ctx, path)
+}
+
+// ListUserSessions mocks base method.
+func (m *MockQuerier) ListUserSessions(ctx context.Context, userID int64) ([]ListUserSessionsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserSessions", ctx, userID)
+	ret0, _ := ret[0].([]ListUserSessionsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserSessions indicates an expected call of ListUserSessions.
+func (mr *MockQuerierMockRecorder) ListUserSessions(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserSessions", reflect.TypeOf((*MockQuerier)(nil).ListUserSessions), ctx, userID)
+}
+
+// ListWebauthnCredentials mocks base method.
+func (m *MockQuerier) ListWebauthnCredentials(ctx context.Context, userID int64) ([]WebauthnCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebauthnCredentials", ctx, userID)
+	ret0, _ := ret[0].([]WebauthnCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebauthnCredentials indicates an expected call of ListWebauthnCredentials.
+func (mr *MockQuerierMockRecorder) ListWebauthnCredentials(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebauthnCredentials", reflect.TypeOf((*MockQuerier)(nil).ListWebauthnCredentials), ctx, userID)
+}
+
+// MarkAmazonProductNotFound mocks base method.
+func (m *MockQuerier) MarkAmazonProductNotFound(ctx context.Context, asin string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAmazonProductNotFound", ctx, asin)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkAmazonProductNotFound indicates an expected call of MarkAmazonProductNotFound.
+func (mr *MockQuerierMockRecorder) MarkAmazonProductNotFound(ctx, asin any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAmazonProductNotFound", reflect.TypeOf((*MockQuerier)(nil).MarkAmazonProductNotFound), ctx, asin)
+}
+
+// PublishScheduledEntry mocks base method.
+func (m *MockQuerier) PublishScheduledEntry(ctx context.Context, path string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishScheduledEntry", ctx, path)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishScheduledEntry indicates an expected call of PublishScheduledEntry.
+func (mr *MockQuerierMockRecorder) PublishScheduledEntry(ctx, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishScheduledEntry", reflect.TypeOf((*MockQuerier)(nil).PublishScheduledEntry), ctx, path)
+}
+
+// ResetAdminUserTOTPAttempts mocks base method.
+func (m *MockQuerier) ResetAdminUserTOTPAttempts(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetAdminUserTOTPAttempts", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetAdminUserTOTPAttempts indicates an expected call of ResetAdminUserTOTPAttempts.
+func (mr *MockQuerierMockRecorder) ResetAdminUserTOTPAttempts(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetAdminUserTOTPAttempts", reflect.TypeOf((*MockQuerier)(nil).ResetAdminUserTOTPAttempts), ctx, id)
+}
+
+// RestoreEntryRevision mocks base method.
+func (m *MockQuerier) RestoreEntryRevision(ctx context.Context, arg RestoreEntryRevisionParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreEntryRevision", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreEntryRevision indicates an expected call of RestoreEntryRevision.
+func (mr *MockQuerierMockRecorder) RestoreEntryRevision(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreEntryRevision", reflect.TypeOf((*MockQuerier)(nil).RestoreEntryRevision), ctx, arg)
+}
+
+// RewriteEntryBody mocks base method.
+func (m *MockQuerier) RewriteEntryBody(ctx context.Context, arg RewriteEntryBodyParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RewriteEntryBody", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RewriteEntryBody indicates an expected call of RewriteEntryBody.
+func (mr *MockQuerierMockRecorder) RewriteEntryBody(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RewriteEntryBody", reflect.TypeOf((*MockQuerier)(nil).RewriteEntryBody), ctx, arg)
+}
+
+// ScheduleEntry mocks base method.
+func (m *MockQuerier) ScheduleEntry(ctx context.Context, arg ScheduleEntryParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScheduleEntry", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ScheduleEntry indicates an expected call of ScheduleEntry.
+func (mr *MockQuerierMockRecorder) ScheduleEntry(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduleEntry", reflect.TypeOf((*MockQuerier)(nil).ScheduleEntry), ctx, arg)
+}
+
+// SealEntryRevisions mocks base method.
+func (m *MockQuerier) SealEntryRevisions(ctx context.Context, path string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SealEntryRevisions", ctx, path)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SealEntryRevisions indicates an expected call of SealEntryRevisions.
+func (mr *MockQuerierMockRecorder) SealEntryRevisions(ctx, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SealEntryRevisions", reflect.TypeOf((*MockQuerier)(nil).SealEntryRevisions), ctx, path)
+}
+
+// SearchTags mocks base method.
+func (m *MockQuerier) SearchTags(ctx context.Context, arg SearchTagsParams) ([]SearchTagsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTags", ctx, arg)
+	ret0, _ := ret[0].([]SearchTagsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchTags indicates an expected call of SearchTags.
+func (mr *MockQuerierMockRecorder) SearchTags(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTags", reflect.TypeOf((*MockQuerier)(nil).SearchTags), ctx, arg)
+}
+
+// SetAdminUserPendingTOTPSecret mocks base method.
+func (m *MockQuerier) SetAdminUserPendingTOTPSecret(ctx context.Context, arg SetAdminUserPendingTOTPSecretParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAdminUserPendingTOTPSecret", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAdminUserPendingTOTPSecret indicates an expected call of SetAdminUserPendingTOTPSecret.
+func (mr *MockQuerierMockRecorder) SetAdminUserPendingTOTPSecret(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAdminUserPendingTOTPSecret", reflect.TypeOf((*MockQuerier)(nil).SetAdminUserPendingTOTPSecret), ctx, arg)
+}
+
+// UpdateAdminUserPassword mocks base method.
+func (m *MockQuerier) UpdateAdminUserPassword(ctx context.Context, arg UpdateAdminUserPasswordParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAdminUserPassword", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAdminUserPassword indicates an expected call of UpdateAdminUserPassword.
+func (mr *MockQuerierMockRecorder) UpdateAdminUserPassword(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAdminUserPassword", reflect.TypeOf((*MockQuerier)(nil).UpdateAdminUserPassword), ctx, arg)
+}
+
+// UpdateAdminUserTOTPLastStep mocks base method.
+func (m *MockQuerier) UpdateAdminUserTOTPLastStep(ctx context.Context, arg UpdateAdminUserTOTPLastStepParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAdminUserTOTPLastStep", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAdminUserTOTPLastStep indicates an expected call of UpdateAdminUserTOTPLastStep.
+func (mr *MockQuerierMockRecorder) UpdateAdminUserTOTPLastStep(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAdminUserTOTPLastStep", reflect.TypeOf((*MockQuerier)(nil).UpdateAdminUserTOTPLastStep), ctx, arg)
+}
+
+// UpdateEntryBody mocks base method.
+func (m *MockQuerier) UpdateEntryBody(ctx context.Context, arg UpdateEntryBodyParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEntryBody", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateEntryBody indicates an expected call of UpdateEntryBody.
+func (mr *MockQuerierMockRecorder) UpdateEntryBody(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEntryBody", reflect.TypeOf((*MockQuerier)(nil).UpdateEntryBody), ctx, arg)
+}
+
+// UpdateEntryRevision mocks base method.
+func (m *MockQuerier) UpdateEntryRevision(ctx context.Context, arg UpdateEntryRevisionParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEntryRevision", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateEntryRevision indicates an expected call of UpdateEntryRevision.
+func (mr *MockQuerierMockRecorder) UpdateEntryRevision(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEntryRevision", reflect.TypeOf((*MockQuerier)(nil).UpdateEntryRevision), ctx, arg)
+}
+
+// UpdateEntryTitle mocks base method.
+func (m *MockQuerier) UpdateEntryTitle(ctx context.Context, arg UpdateEntryTitleParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEntryTitle", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateEntryTitle indicates an expected call of UpdateEntryTitle.
+func (mr *MockQuerierMockRecorder) UpdateEntryTitle(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEntryTitle", reflect.TypeOf((*MockQuerier)(nil).UpdateEntryTitle), ctx, arg)
+}
+
+// UpdatePublishedAt mocks base method.
+func (m *MockQuerier) UpdatePublishedAt(ctx context.Context, path string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePublishedAt", ctx, path)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePublishedAt indicates an expected call of UpdatePublishedAt.
+func (mr *MockQuerierMockRecorder) UpdatePublishedAt(ctx, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePublishedAt", reflect.TypeOf((*MockQuerier)(nil).UpdatePublishedAt), ctx, path)
+}
+
+// UpdateSessionLastAccessed mocks base method.
+func (m *MockQuerier) UpdateSessionLastAccessed(ctx context.Context, sessionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSessionLastAccessed", ctx, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSessionLastAccessed indicates an expected call of UpdateSessionLastAccessed.
+func (mr *MockQuerierMockRecorder) UpdateSessionLastAccessed(ctx, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSessionLastAccessed", reflect.TypeOf((*MockQuerier)(nil).UpdateSessionLastAccessed), ctx, sessionID)
+}
+
+// UpdateVisibility mocks base method.
+func (m *MockQuerier) UpdateVisibility(ctx context.Context, arg UpdateVisibilityParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateVisibility", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateVisibility indicates an expected call of UpdateVisibility.
+func (mr *MockQuerierMockRecorder) UpdateVisibility(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVisibility", reflect.TypeOf((*MockQuerier)(nil).UpdateVisibility), ctx, arg)
 }
+
+// UpdateWebauthnCredentialUsage mocks base method.
+func (m *MockQuerier) UpdateWebauthnCredentialUsage(ctx context.Context, arg UpdateWebauthnCredentialUsageParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebauthnCredentialUsage", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWebauthnCredentialUsage indicates an expected call of UpdateWebauthnCredentialUsage.
+func (mr *MockQuerierMockRecorder) UpdateWebauthnCredentialUsage(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebauthnCredentialUsage", reflect.TypeOf((*MockQuerier)(nil).UpdateWebauthnCredentialUsage), ctx, arg)
+}
+
+// UpsertAmazonProductDetail mocks base method.
+func (m *MockQuerier) UpsertAmazonProductDetail(ctx context.Context, arg UpsertAmazonProductDetailParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertAmazonProductDetail", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertAmazonProductDetail indicates an expected call of UpsertAmazonProductDetail.
+func (mr *MockQuerierMockRecorder) UpsertAmazonProductDetail(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertAmazonProductDetail", reflect.TypeOf((*MockQuerier)(nil).UpsertAmazonProductDetail), ctx, arg)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockQuerier) UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockQuerierMockRecorder) UseRecoveryCode(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockQuerier)(nil).UseRecoveryCode), ctx, arg)
+}
@@ -0,0 +1,90 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.31.1
+// source: preview.sql
+
+package admindb
+
+import (
+	"context"
+	"time"
+)
+
+const createPreviewToken = `-- name: CreatePreviewToken :exec
+INSERT INTO preview_token (id, path, expires_at)
+VALUES (?, ?, ?)
+`
+
+type CreatePreviewTokenParams struct {
+	ID        string
+	Path      string
+	ExpiresAt time.Time
+}
+
+func (q *Queries) CreatePreviewToken(ctx context.Context, arg CreatePreviewTokenParams) error {
+	_, err := q.db.ExecContext(ctx, createPreviewToken, arg.ID, arg.Path, arg.ExpiresAt)
+	return err
+}
+
+const deleteExpiredPreviewTokens = `-- name: DeleteExpiredPreviewTokens :exec
+DELETE FROM preview_token
+WHERE expires_at < NOW()
+`
+
+func (q *Queries) DeleteExpiredPreviewTokens(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteExpiredPreviewTokens)
+	return err
+}
+
+const deletePreviewToken = `-- name: DeletePreviewToken :execrows
+DELETE FROM preview_token
+WHERE id = ? AND path = ?
+`
+
+type DeletePreviewTokenParams struct {
+	ID   string
+	Path string
+}
+
+func (q *Queries) DeletePreviewToken(ctx context.Context, arg DeletePreviewTokenParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deletePreviewToken, arg.ID, arg.Path)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const listPreviewTokens = `-- name: ListPreviewTokens :many
+SELECT id, path, expires_at, created_at
+FROM preview_token
+WHERE path = ? AND expires_at > NOW()
+ORDER BY created_at DESC, id
+`
+
+func (q *Queries) ListPreviewTokens(ctx context.Context, path string) ([]PreviewToken, error) {
+	rows, err := q.db.QueryContext(ctx, listPreviewTokens, path)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PreviewToken
+	for rows.Next() {
+		var i PreviewToken
+		if err := rows.Scan(
+			&i.ID,
+			&i.Path,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -10,34 +10,105 @@ import (
 )
 
 type Querier interface {
+	// Counts a sign-in code attempt unless the user is locked out. The attempt
+	// that reaches max_attempts locks the user until locked_until and starts the
+	// count over.
+	AddAdminUserTOTPAttempt(ctx context.Context, arg AddAdminUserTOTPAttemptParams) (int64, error)
+	AddPendingLoginAttempt(ctx context.Context, arg AddPendingLoginAttemptParams) (int64, error)
 	AdminGetEntryByPath(ctx context.Context, path string) (AdminGetEntryByPathRow, error)
 	AdminListAllEntries(ctx context.Context) ([]AdminListAllEntriesRow, error)
+	CountAdminUsers(ctx context.Context) (int64, error)
 	CountAmazonCacheByAsin(ctx context.Context, asin string) (int64, error)
+	CountUnusedRecoveryCodes(ctx context.Context, userID int64) (int64, error)
+	CreateAdminUser(ctx context.Context, arg CreateAdminUserParams) (int64, error)
 	CreateEmptyEntry(ctx context.Context, arg CreateEmptyEntryParams) (int64, error)
 	CreateEntryWithBody(ctx context.Context, arg CreateEntryWithBodyParams) (int64, error)
+	CreatePendingLogin(ctx context.Context, arg CreatePendingLoginParams) error
+	CreatePreviewToken(ctx context.Context, arg CreatePreviewTokenParams) error
+	CreateRecoveryCode(ctx context.Context, arg CreateRecoveryCodeParams) error
 	CreateSession(ctx context.Context, arg CreateSessionParams) error
+	CreateWebauthnChallenge(ctx context.Context, arg CreateWebauthnChallengeParams) error
+	CreateWebauthnCredential(ctx context.Context, arg CreateWebauthnCredentialParams) error
+	DeleteBodyEntryTags(ctx context.Context, path string) (int64, error)
 	DeleteEntry(ctx context.Context, path string) (int64, error)
 	DeleteEntryImageByPath(ctx context.Context, path string) (int64, error)
 	DeleteEntryLinkByPath(ctx context.Context, srcPath string) (int64, error)
-	DeleteExpiredSessions(ctx context.Context) error
+	DeleteEntryTagsByPath(ctx context.Context, path string) (int64, error)
+	DeleteExpiredPendingLogins(ctx context.Context) error
+	DeleteExpiredPreviewTokens(ctx context.Context) error
+	DeleteExpiredSessions(ctx context.Context) (int64, error)
+	DeleteExpiredWebauthnChallenges(ctx context.Context) error
+	DeletePendingLogin(ctx context.Context, token string) (int64, error)
+	DeletePreviewToken(ctx context.Context, arg DeletePreviewTokenParams) (int64, error)
+	DeleteRecoveryCodes(ctx context.Context, userID int64) error
+	DeleteSearchTokensByPath(ctx context.Context, path string) (int64, error)
 	DeleteSession(ctx context.Context, sessionID string) error
+	DeleteUserSession(ctx context.Context, arg DeleteUserSessionParams) (int64, error)
+	DeleteUserSessions(ctx context.Context, userID int64) error
+	DeleteWebauthnChallenge(ctx context.Context, token string) (int64, error)
+	DeleteWebauthnCredential(ctx context.Context, arg DeleteWebauthnCredentialParams) (int64, error)
+	DisableAdminUserTOTP(ctx context.Context, id int64) error
+	EnableAdminUserTOTP(ctx context.Context, arg EnableAdminUserTOTPParams) (int64, error)
+	GetAdminUser(ctx context.Context, id int64) (AdminUser, error)
+	GetAdminUserByUsername(ctx context.Context, username string) (AdminUser, error)
 	GetAllEntryTitles(ctx context.Context) ([]string, error)
+	GetAmazonCacheFetchedAt(ctx context.Context, asins []string) ([]GetAmazonCacheFetchedAtRow, error)
 	GetAmazonImageUrlByAsin(ctx context.Context, asin string) (sql.NullString, error)
-	GetEntriesByLinkedTitle(ctx context.Context, dstTitle string) ([]Entry, error)
+	GetEntriesByLinkedTitle(ctx context.Context, dstTitle string) ([]GetEntriesByLinkedTitleRow, error)
 	GetEntryImageByPath(ctx context.Context, path string) (EntryImage, error)
 	GetEntryImageNotProcessedEntries(ctx context.Context) ([]Entry, error)
+	GetEntryPathByTitle(ctx context.Context, title string) (string, error)
+	GetEntryPathsByTitles(ctx context.Context, titles []string) ([]GetEntryPathsByTitlesRow, error)
+	GetEntryRevision(ctx context.Context, arg GetEntryRevisionParams) (EntryRevision, error)
+	// タグエディタで編集するタグ。本文の #hashtag 由来のものは含めない
+	GetEntryTags(ctx context.Context, path string) ([]string, error)
+	GetEntryTitleForUpdate(ctx context.Context, path string) (string, error)
 	GetEntryVisibility(ctx context.Context, path string) (GetEntryVisibilityRow, error)
+	GetLatestEntryRevision(ctx context.Context, path string) (EntryRevision, error)
 	GetLinkedEntries(ctx context.Context, srcPath string) ([]GetLinkedEntriesRow, error)
-	GetSession(ctx context.Context, sessionID string) (AdminSession, error)
-	InsertAmazonProductDetail(ctx context.Context, arg InsertAmazonProductDetailParams) (int64, error)
+	GetPendingLogin(ctx context.Context, token string) (AdminPendingLogin, error)
+	GetPublicEntriesByTitles(ctx context.Context, titles []string) ([]GetPublicEntriesByTitlesRow, error)
+	GetSession(ctx context.Context, sessionID string) (GetSessionRow, error)
+	GetTwoHopEntries(ctx context.Context, arg GetTwoHopEntriesParams) ([]GetTwoHopEntriesRow, error)
+	GetWebauthnChallenge(ctx context.Context, token string) (WebauthnChallenge, error)
+	GetWebauthnCredentialByCredentialID(ctx context.Context, credentialID []byte) (WebauthnCredential, error)
+	InsertBodyEntryTag(ctx context.Context, arg InsertBodyEntryTagParams) (int64, error)
 	InsertEntryImage(ctx context.Context, arg InsertEntryImageParams) (int64, error)
-	// TODO batch insert
-	InsertEntryLink(ctx context.Context, arg InsertEntryLinkParams) (int64, error)
+	InsertEntryRevision(ctx context.Context, arg InsertEntryRevisionParams) (int64, error)
+	// 本文の #hashtag と同じタグでも、手で付けたものとして扱う
+	InsertEntryTag(ctx context.Context, arg InsertEntryTagParams) (int64, error)
+	InsertMissingEntryRevisions(ctx context.Context) (int64, error)
+	ListAdminUsers(ctx context.Context) ([]ListAdminUsersRow, error)
+	ListAllEntryTags(ctx context.Context) ([]ListAllEntryTagsRow, error)
+	ListDueScheduledEntries(ctx context.Context, now sql.NullTime) ([]string, error)
+	ListEntryBodiesWithAsin(ctx context.Context) ([]ListEntryBodiesWithAsinRow, error)
+	ListEntryPathsWithoutSearchTokens(ctx context.Context) ([]string, error)
+	ListEntryRevisions(ctx context.Context, path string) ([]ListEntryRevisionsRow, error)
+	ListPreviewTokens(ctx context.Context, path string) ([]PreviewToken, error)
+	ListUserSessions(ctx context.Context, userID int64) ([]ListUserSessionsRow, error)
+	ListWebauthnCredentials(ctx context.Context, userID int64) ([]WebauthnCredential, error)
+	// A product cached before keeps its details; it is only asked for later.
+	MarkAmazonProductNotFound(ctx context.Context, asin string) error
+	PublishScheduledEntry(ctx context.Context, path string) (int64, error)
+	ResetAdminUserTOTPAttempts(ctx context.Context, id int64) error
+	RestoreEntryRevision(ctx context.Context, arg RestoreEntryRevisionParams) (int64, error)
+	RewriteEntryBody(ctx context.Context, arg RewriteEntryBodyParams) (int64, error)
+	ScheduleEntry(ctx context.Context, arg ScheduleEntryParams) error
+	SealEntryRevisions(ctx context.Context, path string) error
+	// タグ入力の補完候補。よく使われているタグを先に出す
+	SearchTags(ctx context.Context, arg SearchTagsParams) ([]SearchTagsRow, error)
+	SetAdminUserPendingTOTPSecret(ctx context.Context, arg SetAdminUserPendingTOTPSecretParams) error
+	UpdateAdminUserPassword(ctx context.Context, arg UpdateAdminUserPasswordParams) (int64, error)
+	UpdateAdminUserTOTPLastStep(ctx context.Context, arg UpdateAdminUserTOTPLastStepParams) (int64, error)
 	UpdateEntryBody(ctx context.Context, arg UpdateEntryBodyParams) (int64, error)
+	UpdateEntryRevision(ctx context.Context, arg UpdateEntryRevisionParams) error
 	UpdateEntryTitle(ctx context.Context, arg UpdateEntryTitleParams) (int64, error)
 	UpdatePublishedAt(ctx context.Context, path string) error
 	UpdateSessionLastAccessed(ctx context.Context, sessionID string) error
 	UpdateVisibility(ctx context.Context, arg UpdateVisibilityParams) error
+	UpdateWebauthnCredentialUsage(ctx context.Context, arg UpdateWebauthnCredentialUsageParams) error
+	UpsertAmazonProductDetail(ctx context.Context, arg UpsertAmazonProductDetailParams) (int64, error)
+	UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (int64, error)
 }
 
 var _ Querier = (*Queries)(nil)
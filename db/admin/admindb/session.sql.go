@@ -7,33 +7,45 @@ package admindb
 
 import (
 	"context"
+	"database/sql"
 	"time"
 )
 
 const createSession = `-- name: CreateSession :exec
-INSERT INTO admin_session (session_id, username, expires_at)
-VALUES (?, ?, ?)
+INSERT INTO admin_session (session_id, user_id, user_agent, ip_address, expires_at)
+VALUES (?, ?, ?, ?, ?)
 `
 
 type CreateSessionParams struct {
 	SessionID string
-	Username  string
+	UserID    int64
+	UserAgent string
+	IpAddress string
 	ExpiresAt time.Time
 }
 
 func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) error {
-	_, err := q.db.ExecContext(ctx, createSession, arg.SessionID, arg.Username, arg.ExpiresAt)
+	_, err := q.db.ExecContext(ctx, createSession,
+		arg.SessionID,
+		arg.UserID,
+		arg.UserAgent,
+		arg.IpAddress,
+		arg.ExpiresAt,
+	)
 	return err
 }
 
-const deleteExpiredSessions = `-- name: DeleteExpiredSessions :exec
+const deleteExpiredSessions = `-- name: DeleteExpiredSessions :execrows
 DELETE FROM admin_session
 WHERE expires_at < NOW()
 `
 
-func (q *Queries) DeleteExpiredSessions(ctx context.Context) error {
-	_, err := q.db.ExecContext(ctx, deleteExpiredSessions)
-	return err
+func (q *Queries) DeleteExpiredSessions(ctx context.Context) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteExpiredSessions)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const deleteSession = `-- name: DeleteSession :exec
@@ -46,18 +58,60 @@ func (q *Queries) DeleteSession(ctx context.Context, sessionID string) error {
 	return err
 }
 
+const deleteUserSession = `-- name: DeleteUserSession :execrows
+DELETE FROM admin_session
+WHERE id = ? AND user_id = ?
+`
+
+type DeleteUserSessionParams struct {
+	ID     int64
+	UserID int64
+}
+
+func (q *Queries) DeleteUserSession(ctx context.Context, arg DeleteUserSessionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteUserSession, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteUserSessions = `-- name: DeleteUserSessions :exec
+DELETE FROM admin_session
+WHERE user_id = ?
+`
+
+func (q *Queries) DeleteUserSessions(ctx context.Context, userID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteUserSessions, userID)
+	return err
+}
+
 const getSession = `-- name: GetSession :one
-SELECT session_id, username, expires_at, created_at, last_accessed_at
+SELECT admin_session.id, admin_session.session_id, admin_session.user_id, admin_user.username,
+       admin_session.expires_at, admin_session.created_at, admin_session.last_accessed_at
 FROM admin_session
-WHERE session_id = ? AND expires_at > NOW()
+    INNER JOIN admin_user ON admin_user.id = admin_session.user_id
+WHERE admin_session.session_id = ? AND admin_session.expires_at > NOW()
 LIMIT 1
 `
 
-func (q *Queries) GetSession(ctx context.Context, sessionID string) (AdminSession, error) {
+type GetSessionRow struct {
+	ID             int64
+	SessionID      string
+	UserID         int64
+	Username       string
+	ExpiresAt      time.Time
+	CreatedAt      sql.NullTime
+	LastAccessedAt sql.NullTime
+}
+
+func (q *Queries) GetSession(ctx context.Context, sessionID string) (GetSessionRow, error) {
 	row := q.db.QueryRowContext(ctx, getSession, sessionID)
-	var i AdminSession
+	var i GetSessionRow
 	err := row.Scan(
+		&i.ID,
 		&i.SessionID,
+		&i.UserID,
 		&i.Username,
 		&i.ExpiresAt,
 		&i.CreatedAt,
@@ -66,6 +120,52 @@ func (q *Queries) GetSession(ctx context.Context, sessionID string) (AdminSessio
 	return i, err
 }
 
+const listUserSessions = `-- name: ListUserSessions :many
+SELECT id, user_agent, ip_address, expires_at, created_at, last_accessed_at
+FROM admin_session
+WHERE user_id = ? AND expires_at > NOW()
+ORDER BY last_accessed_at DESC, id DESC
+`
+
+type ListUserSessionsRow struct {
+	ID             int64
+	UserAgent      string
+	IpAddress      string
+	ExpiresAt      time.Time
+	CreatedAt      sql.NullTime
+	LastAccessedAt sql.NullTime
+}
+
+func (q *Queries) ListUserSessions(ctx context.Context, userID int64) ([]ListUserSessionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUserSessions, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUserSessionsRow
+	for rows.Next() {
+		var i ListUserSessionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserAgent,
+			&i.IpAddress,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.LastAccessedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateSessionLastAccessed = `-- name: UpdateSessionLastAccessed :exec
 UPDATE admin_session
 SET last_accessed_at = NOW()
@@ -28,10 +28,70 @@ func (q *Queries) GetEntryVisibility(ctx context.Context, path string) (GetEntry
 	return i, err
 }
 
+const listDueScheduledEntries = `-- name: ListDueScheduledEntries :many
+SELECT path
+FROM entry
+WHERE visibility = 'scheduled' AND published_at <= ?
+ORDER BY published_at, path
+`
+
+func (q *Queries) ListDueScheduledEntries(ctx context.Context, now sql.NullTime) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listDueScheduledEntries, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, err
+		}
+		items = append(items, path)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const publishScheduledEntry = `-- name: PublishScheduledEntry :execrows
+UPDATE entry
+SET visibility = 'public'
+WHERE path = ? AND visibility = 'scheduled'
+`
+
+func (q *Queries) PublishScheduledEntry(ctx context.Context, path string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, publishScheduledEntry, path)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const scheduleEntry = `-- name: ScheduleEntry :exec
+UPDATE entry
+SET visibility = 'scheduled', published_at = ?
+WHERE path = ?
+`
+
+type ScheduleEntryParams struct {
+	PublishedAt sql.NullTime
+	Path        string
+}
+
+func (q *Queries) ScheduleEntry(ctx context.Context, arg ScheduleEntryParams) error {
+	_, err := q.db.ExecContext(ctx, scheduleEntry, arg.PublishedAt, arg.Path)
+	return err
+}
+
 const updatePublishedAt = `-- name: UpdatePublishedAt :exec
 UPDATE entry
 SET published_at = NOW()
-WHERE path = ? AND published_at IS NULL
+WHERE path = ? AND (published_at IS NULL OR published_at > NOW())
 `
 
 func (q *Queries) UpdatePublishedAt(ctx context.Context, path string) error {
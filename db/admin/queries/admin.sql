@@ -17,6 +17,17 @@ UPDATE entry
 SET title = ?, last_edited_at = NOW()
 WHERE path = ? AND updated_at = ?;
 
+-- name: GetEntryTitleForUpdate :one
+SELECT title
+FROM entry
+WHERE path = ?
+FOR UPDATE;
+
+-- name: RewriteEntryBody :execrows
+UPDATE entry
+SET body = ?
+WHERE path = ? AND updated_at = ?;
+
 -- name: UpdateEntryBody :execrows
 UPDATE entry
 SET body = ?, last_edited_at = NOW()
@@ -25,11 +36,6 @@ WHERE path = ? AND updated_at = ?;
 -- name: DeleteEntryLinkByPath :execrows
 DELETE FROM entry_link WHERE src_path = ?;
 
--- name: InsertEntryLink :execrows
-/* TODO batch insert */
-INSERT INTO entry_link (src_path, dst_title)
-VALUES (?, ?);
-
 -- name: GetLinkedEntries :many
 SELECT DISTINCT
     entry_link.dst_title AS dst_title,
@@ -47,10 +53,42 @@ FROM entry_link
 WHERE entry_link.src_path = ?;
 
 -- name: GetEntriesByLinkedTitle :many
-SELECT DISTINCT entry.*
+SELECT DISTINCT entry.*, entry_image.url AS image_url
 FROM entry_link
     INNER JOIN entry ON (entry.path = entry_link.src_path)
-WHERE entry_link.dst_title = ?;
+    LEFT JOIN entry_image ON (entry.path = entry_image.path)
+WHERE entry_link.dst_title = ?
+ORDER BY entry.last_edited_at DESC, entry.path DESC;
+
+-- name: GetTwoHopEntries :many
+SELECT
+    entry_link.dst_title AS dst_title,
+    entry.path,
+    entry.title,
+    entry.body,
+    entry.visibility,
+    entry_image.url AS image_url
+FROM entry_link
+    INNER JOIN entry ON (entry.path = entry_link.src_path)
+    LEFT JOIN entry_image ON (entry.path = entry_image.path)
+WHERE entry_link.dst_title IN (sqlc.slice(dst_titles))
+    AND entry_link.src_path != sqlc.arg(src_path)
+ORDER BY entry_link.dst_title, entry.last_edited_at DESC, entry.path DESC;
+
+-- name: GetEntryPathsByTitles :many
+SELECT path, title
+FROM entry
+WHERE title IN (sqlc.slice(titles));
+
+-- name: GetPublicEntriesByTitles :many
+SELECT path, title, body
+FROM entry
+WHERE title IN (sqlc.slice(titles)) AND visibility = 'public';
+
+-- name: GetEntryPathByTitle :one
+SELECT path
+FROM entry
+WHERE title = ?;
 
 -- name: GetAllEntryTitles :many
 SELECT title
@@ -59,13 +97,57 @@ ORDER BY title ASC;
 
 -- name: CreateEmptyEntry :execrows
 INSERT INTO entry
-           (path, title, body, visibility)
-    VALUES (?,        ?, '',    'private');
+           (path, title, body, visibility, author_id)
+    VALUES (?,        ?, '',    'private',  ?);
 
 -- name: CreateEntryWithBody :execrows
 INSERT INTO entry
-           (path, title, body, visibility)
-    VALUES (?,    ?,     ?,    'private');
+           (path, title, body, visibility, author_id)
+    VALUES (?,    ?,     ?,    'private',  ?);
 
 -- name: DeleteEntry :execrows
 DELETE FROM entry WHERE path = ?;
+
+-- name: DeleteSearchTokensByPath :execrows
+DELETE FROM entry_search_token WHERE path = ?;
+
+-- name: ListEntryPathsWithoutSearchTokens :many
+SELECT path
+FROM entry
+WHERE NOT EXISTS (SELECT 1 FROM entry_search_token WHERE entry_search_token.path = entry.path)
+ORDER BY path;
+
+-- name: GetEntryTags :many
+/* タグエディタで編集するタグ。本文の #hashtag 由来のものは含めない */
+SELECT tag
+FROM entry_tag
+WHERE path = ? AND source = 'manual'
+ORDER BY tag;
+
+-- name: ListAllEntryTags :many
+SELECT path, tag
+FROM entry_tag
+ORDER BY path, tag;
+
+-- name: DeleteEntryTagsByPath :execrows
+DELETE FROM entry_tag WHERE path = ? AND source = 'manual';
+
+-- name: InsertEntryTag :execrows
+/* 本文の #hashtag と同じタグでも、手で付けたものとして扱う */
+INSERT INTO entry_tag (path, tag, source) VALUES (?, ?, 'manual')
+ON DUPLICATE KEY UPDATE source = 'manual';
+
+-- name: DeleteBodyEntryTags :execrows
+DELETE FROM entry_tag WHERE path = ? AND source = 'body';
+
+-- name: InsertBodyEntryTag :execrows
+INSERT IGNORE INTO entry_tag (path, tag, source) VALUES (?, ?, 'body');
+
+-- name: SearchTags :many
+/* タグ入力の補完候補。よく使われているタグを先に出す */
+SELECT tag, COUNT(*) AS entry_count
+FROM entry_tag
+WHERE tag LIKE sqlc.arg(pattern)
+GROUP BY tag
+ORDER BY entry_count DESC, tag
+LIMIT ?;
@@ -0,0 +1,27 @@
+-- name: CreateAdminUser :execlastid
+INSERT INTO admin_user (username, password_hash)
+VALUES (?, ?);
+
+-- name: GetAdminUserByUsername :one
+SELECT *
+FROM admin_user
+WHERE username = ?;
+
+-- name: GetAdminUser :one
+SELECT *
+FROM admin_user
+WHERE id = ?;
+
+-- name: UpdateAdminUserPassword :execrows
+UPDATE admin_user
+SET password_hash = ?
+WHERE id = ?;
+
+-- name: ListAdminUsers :many
+SELECT id, username, totp_secret IS NOT NULL AS totp_enabled, created_at
+FROM admin_user
+ORDER BY id;
+
+-- name: CountAdminUsers :one
+SELECT COUNT(*)
+FROM admin_user;
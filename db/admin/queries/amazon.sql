@@ -1,12 +1,34 @@
--- name: InsertAmazonProductDetail :execrows
-INSERT INTO amazon_cache (asin, title, image_medium_url, link) VALUES (?, ?, ?, ?);
+-- name: UpsertAmazonProductDetail :execrows
+INSERT INTO amazon_cache (asin, title, image_medium_url, link) VALUES (?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE
+    title = VALUES(title),
+    image_medium_url = VALUES(image_medium_url),
+    link = VALUES(link),
+    fetched_at = NOW(),
+    not_found = FALSE;
+
+-- name: MarkAmazonProductNotFound :exec
+-- A product cached before keeps its details; it is only asked for later.
+INSERT INTO amazon_cache (asin, link, not_found) VALUES (?, '', TRUE)
+ON DUPLICATE KEY UPDATE
+    fetched_at = NOW();
 
 -- name: CountAmazonCacheByAsin :one
 SELECT count(1)
 FROM amazon_cache
-WHERE asin = ?;
+WHERE asin = ? AND NOT not_found;
 
 -- name: GetAmazonImageUrlByAsin :one
 SELECT image_medium_url
 FROM amazon_cache
-WHERE asin = ?;
+WHERE asin = ? AND NOT not_found;
+
+-- name: GetAmazonCacheFetchedAt :many
+SELECT asin, fetched_at
+FROM amazon_cache
+WHERE asin IN (sqlc.slice(asins));
+
+-- name: ListEntryBodiesWithAsin :many
+SELECT path, body
+FROM entry
+WHERE body LIKE '%[asin:%';
@@ -0,0 +1,17 @@
+-- name: CreatePreviewToken :exec
+INSERT INTO preview_token (id, path, expires_at)
+VALUES (?, ?, ?);
+
+-- name: ListPreviewTokens :many
+SELECT id, path, expires_at, created_at
+FROM preview_token
+WHERE path = ? AND expires_at > NOW()
+ORDER BY created_at DESC, id;
+
+-- name: DeletePreviewToken :execrows
+DELETE FROM preview_token
+WHERE id = ? AND path = ?;
+
+-- name: DeleteExpiredPreviewTokens :exec
+DELETE FROM preview_token
+WHERE expires_at < NOW();
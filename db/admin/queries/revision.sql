@@ -0,0 +1,42 @@
+-- name: GetLatestEntryRevision :one
+SELECT *
+FROM entry_revision
+WHERE path = ?
+ORDER BY created_at DESC, id DESC
+LIMIT 1;
+
+-- name: InsertEntryRevision :execlastid
+INSERT INTO entry_revision (path, title, body, created_at, saved_at, edit_session)
+VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: UpdateEntryRevision :exec
+UPDATE entry_revision
+SET title = ?, body = ?, saved_at = ?
+WHERE id = ?;
+
+-- name: SealEntryRevisions :exec
+UPDATE entry_revision
+SET edit_session = NULL
+WHERE path = ? AND edit_session IS NOT NULL;
+
+-- name: ListEntryRevisions :many
+SELECT id, title, CAST(CHAR_LENGTH(body) AS SIGNED) AS body_length, created_at, saved_at
+FROM entry_revision
+WHERE path = ?
+ORDER BY created_at DESC, id DESC;
+
+-- name: GetEntryRevision :one
+SELECT *
+FROM entry_revision
+WHERE id = ? AND path = ?;
+
+-- name: InsertMissingEntryRevisions :execrows
+INSERT INTO entry_revision (path, title, body, created_at, saved_at)
+SELECT entry.path, entry.title, entry.body, COALESCE(entry.updated_at, NOW()), COALESCE(entry.updated_at, NOW())
+FROM entry
+WHERE NOT EXISTS (SELECT 1 FROM entry_revision WHERE entry_revision.path = entry.path);
+
+-- name: RestoreEntryRevision :execrows
+UPDATE entry
+SET title = ?, body = ?, last_edited_at = NOW()
+WHERE path = ? AND updated_at = ?;
@@ -1,13 +1,21 @@
 -- name: CreateSession :exec
-INSERT INTO admin_session (session_id, username, expires_at)
-VALUES (?, ?, ?);
+INSERT INTO admin_session (session_id, user_id, user_agent, ip_address, expires_at)
+VALUES (?, ?, ?, ?, ?);
 
 -- name: GetSession :one
-SELECT session_id, username, expires_at, created_at, last_accessed_at
+SELECT admin_session.id, admin_session.session_id, admin_session.user_id, admin_user.username,
+       admin_session.expires_at, admin_session.created_at, admin_session.last_accessed_at
 FROM admin_session
-WHERE session_id = ? AND expires_at > NOW()
+    INNER JOIN admin_user ON admin_user.id = admin_session.user_id
+WHERE admin_session.session_id = ? AND admin_session.expires_at > NOW()
 LIMIT 1;
 
+-- name: ListUserSessions :many
+SELECT id, user_agent, ip_address, expires_at, created_at, last_accessed_at
+FROM admin_session
+WHERE user_id = ? AND expires_at > NOW()
+ORDER BY last_accessed_at DESC, id DESC;
+
 -- name: UpdateSessionLastAccessed :exec
 UPDATE admin_session
 SET last_accessed_at = NOW()
@@ -17,6 +25,14 @@ WHERE session_id = ?;
 DELETE FROM admin_session
 WHERE session_id = ?;
 
--- name: DeleteExpiredSessions :exec
+-- name: DeleteUserSession :execrows
+DELETE FROM admin_session
+WHERE id = ? AND user_id = ?;
+
+-- name: DeleteExpiredSessions :execrows
+DELETE FROM admin_session
+WHERE expires_at < NOW();
+
+-- name: DeleteUserSessions :exec
 DELETE FROM admin_session
-WHERE expires_at < NOW();
\ No newline at end of file
+WHERE user_id = ?;
@@ -0,0 +1,74 @@
+-- name: SetAdminUserPendingTOTPSecret :exec
+UPDATE admin_user
+SET totp_pending_secret = ?
+WHERE id = ?;
+
+-- name: EnableAdminUserTOTP :execrows
+UPDATE admin_user
+SET totp_secret = totp_pending_secret, totp_pending_secret = NULL, totp_last_step = sqlc.arg(step)
+WHERE id = sqlc.arg(id) AND totp_pending_secret = sqlc.arg(pending_secret) AND totp_secret IS NULL;
+
+-- name: DisableAdminUserTOTP :exec
+UPDATE admin_user
+SET totp_secret = NULL, totp_pending_secret = NULL, totp_last_step = 0,
+    totp_failed_attempts = 0, totp_locked_until = NULL
+WHERE id = ?;
+
+-- name: UpdateAdminUserTOTPLastStep :execrows
+UPDATE admin_user
+SET totp_last_step = sqlc.arg(step)
+WHERE id = sqlc.arg(id) AND totp_last_step < sqlc.arg(step);
+
+-- name: AddAdminUserTOTPAttempt :execrows
+-- Counts a sign-in code attempt unless the user is locked out. The attempt
+-- that reaches max_attempts locks the user until locked_until and starts the
+-- count over.
+UPDATE admin_user
+SET totp_locked_until    = IF(totp_failed_attempts + 1 >= sqlc.arg(max_attempts), CAST(sqlc.arg(locked_until) AS DATETIME), NULL),
+    totp_failed_attempts = IF(totp_failed_attempts + 1 >= sqlc.arg(max_attempts), 0, totp_failed_attempts + 1)
+WHERE id = sqlc.arg(id) AND (totp_locked_until IS NULL OR totp_locked_until <= NOW());
+
+-- name: ResetAdminUserTOTPAttempts :exec
+UPDATE admin_user
+SET totp_failed_attempts = 0, totp_locked_until = NULL
+WHERE id = ?;
+
+-- name: CreateRecoveryCode :exec
+INSERT INTO admin_recovery_code (user_id, code_hash)
+VALUES (?, ?);
+
+-- name: DeleteRecoveryCodes :exec
+DELETE FROM admin_recovery_code
+WHERE user_id = ?;
+
+-- name: UseRecoveryCode :execrows
+UPDATE admin_recovery_code
+SET used_at = NOW()
+WHERE user_id = ? AND code_hash = ? AND used_at IS NULL;
+
+-- name: CountUnusedRecoveryCodes :one
+SELECT COUNT(*)
+FROM admin_recovery_code
+WHERE user_id = ? AND used_at IS NULL;
+
+-- name: CreatePendingLogin :exec
+INSERT INTO admin_pending_login (token, user_id, remember_me, expires_at)
+VALUES (?, ?, ?, ?);
+
+-- name: GetPendingLogin :one
+SELECT *
+FROM admin_pending_login
+WHERE token = ? AND expires_at > NOW();
+
+-- name: AddPendingLoginAttempt :execrows
+UPDATE admin_pending_login
+SET attempts = attempts + 1
+WHERE token = ? AND attempts < ? AND expires_at > NOW();
+
+-- name: DeletePendingLogin :execrows
+DELETE FROM admin_pending_login
+WHERE token = ?;
+
+-- name: DeleteExpiredPendingLogins :exec
+DELETE FROM admin_pending_login
+WHERE expires_at < NOW();
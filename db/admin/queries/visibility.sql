@@ -11,4 +11,20 @@ WHERE path = ?;
 -- name: UpdatePublishedAt :exec
 UPDATE entry
 SET published_at = NOW()
-WHERE path = ? AND published_at IS NULL;
+WHERE path = ? AND (published_at IS NULL OR published_at > NOW());
+
+-- name: ScheduleEntry :exec
+UPDATE entry
+SET visibility = 'scheduled', published_at = ?
+WHERE path = ?;
+
+-- name: ListDueScheduledEntries :many
+SELECT path
+FROM entry
+WHERE visibility = 'scheduled' AND published_at <= sqlc.arg(now)
+ORDER BY published_at, path;
+
+-- name: PublishScheduledEntry :execrows
+UPDATE entry
+SET visibility = 'public'
+WHERE path = ? AND visibility = 'scheduled';
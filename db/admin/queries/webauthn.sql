@@ -0,0 +1,40 @@
+-- name: CreateWebauthnCredential :exec
+INSERT INTO webauthn_credential (user_id, credential_id, name, credential)
+VALUES (?, ?, ?, ?);
+
+-- name: ListWebauthnCredentials :many
+SELECT *
+FROM webauthn_credential
+WHERE user_id = ?
+ORDER BY id;
+
+-- name: GetWebauthnCredentialByCredentialID :one
+SELECT *
+FROM webauthn_credential
+WHERE credential_id = ?;
+
+-- name: UpdateWebauthnCredentialUsage :exec
+UPDATE webauthn_credential
+SET credential = ?, last_used_at = NOW()
+WHERE id = ?;
+
+-- name: DeleteWebauthnCredential :execrows
+DELETE FROM webauthn_credential
+WHERE id = ? AND user_id = ?;
+
+-- name: CreateWebauthnChallenge :exec
+INSERT INTO webauthn_challenge (token, user_id, session_data, expires_at)
+VALUES (?, ?, ?, ?);
+
+-- name: GetWebauthnChallenge :one
+SELECT *
+FROM webauthn_challenge
+WHERE token = ? AND expires_at > NOW();
+
+-- name: DeleteWebauthnChallenge :execrows
+DELETE FROM webauthn_challenge
+WHERE token = ?;
+
+-- name: DeleteExpiredWebauthnChallenges :exec
+DELETE FROM webauthn_challenge
+WHERE expires_at < NOW();
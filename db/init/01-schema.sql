@@ -1,20 +1,59 @@
+-- Accounts that can sign in to the admin. Passwords are bcrypt hashes; create
+-- accounts and reset passwords with `blog4 user`.
+CREATE TABLE admin_user
+(
+    id                   BIGINT                                                        NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    username             VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL,
+    password_hash        VARCHAR(255) CHARACTER SET ascii COLLATE ascii_bin            NOT NULL,
+    -- base32 TOTP secret; NULL while two-factor authentication is off
+    totp_secret          VARCHAR(64) CHARACTER SET ascii COLLATE ascii_bin             DEFAULT NULL,
+    -- secret being enrolled, until a code generated from it is confirmed
+    totp_pending_secret  VARCHAR(64) CHARACTER SET ascii COLLATE ascii_bin             DEFAULT NULL,
+    -- time step of the last accepted code, so that a code cannot be used twice
+    totp_last_step       BIGINT                                                        NOT NULL DEFAULT 0,
+    -- codes tried at sign-in since the last success, across pending logins
+    totp_failed_attempts INT                                                           NOT NULL DEFAULT 0,
+    -- sign-in with a code is refused until then once the attempts run out
+    totp_locked_until    DATETIME                                                      DEFAULT NULL,
+    created_at           DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at           DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY username (username)
+) DEFAULT CHARSET = utf8mb4;
+
+-- One-time codes that stand in for a TOTP code when the authenticator is lost.
+-- Only SHA-256 hashes are stored; enabling TOTP again replaces the set.
+CREATE TABLE admin_recovery_code
+(
+    id         BIGINT                                         NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    user_id    BIGINT                                         NOT NULL,
+    code_hash  CHAR(64) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+    used_at    DATETIME DEFAULT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY user_code (user_id, code_hash),
+    FOREIGN KEY (user_id) REFERENCES admin_user (id) ON DELETE CASCADE
+) DEFAULT CHARSET = utf8mb4;
+
 CREATE TABLE entry
 (
     path         varchar(255) CHARACTER SET ascii COLLATE ascii_general_ci     NOT NULL,
     title        varchar(300) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL,
     body         text CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci         NOT NULL,
-    visibility   enum ('private','public')                                              DEFAULT 'private' NOT NULL,
+    -- scheduled: becomes public once published_at is reached
+    visibility   enum ('private','public','scheduled')                                  DEFAULT 'private' NOT NULL,
     format       enum ('html','mkdn')                                          NOT NULL DEFAULT 'mkdn',
     published_at datetime                                                               DEFAULT NULL,
     last_edited_at datetime                                                               DEFAULT CURRENT_TIMESTAMP comment 'last manualy edited at',
     created_at   datetime                                                               DEFAULT CURRENT_TIMESTAMP,
     updated_at   datetime                                                               DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    -- admin user who created the entry; NULL for entries older than admin_user
+    author_id    bigint                                                                 DEFAULT NULL,
     PRIMARY KEY (path),
     UNIQUE title (title),
     KEY created_at (created_at),
     KEY updated_at (updated_at),
     KEY published_at (published_at),
-    KEY last_edited_at (last_edited_at)
+    KEY last_edited_at (last_edited_at),
+    FOREIGN KEY (author_id) REFERENCES admin_user (id) ON DELETE SET NULL
 ) DEFAULT CHARSET = utf8mb4;
 
 create table entry_image
@@ -36,6 +75,47 @@ create table entry_link
     INDEX (dst_title)
 ) DEFAULT CHARSET = utf8mb4;
 
+create table entry_tag
+(
+    path   varchar(255) CHARACTER SET ascii COLLATE ascii_general_ci     NOT NULL,
+    tag    varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL,
+    -- manual: set in the tag editor, body: a #hashtag in the entry's body
+    source enum ('manual', 'body') NOT NULL DEFAULT 'manual',
+    PRIMARY KEY (path, tag),
+    FOREIGN KEY (path) REFERENCES entry (path) ON DELETE CASCADE,
+    INDEX (tag)
+) DEFAULT CHARSET = utf8mb4;
+
+-- n-gram index for /api/search. TiDB has no FULLTEXT, so unigrams and bigrams
+-- of every entry are stored here and matched with plain equality lookups.
+create table entry_search_token
+(
+    token varchar(2) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin          NOT NULL,
+    path  varchar(255) CHARACTER SET ascii COLLATE ascii_general_ci     NOT NULL,
+    PRIMARY KEY (token, path),
+    FOREIGN KEY (path) REFERENCES entry (path) ON DELETE CASCADE,
+    INDEX (path)
+) DEFAULT CHARSET = utf8mb4;
+
+-- Snapshots of an entry's title and body. Saves in quick succession from one
+-- editor page update the latest row instead of adding one, so autosave does
+-- not flood the table.
+create table entry_revision
+(
+    id           bigint                                                        NOT NULL AUTO_INCREMENT,
+    path         varchar(255) CHARACTER SET ascii COLLATE ascii_general_ci     NOT NULL,
+    title        varchar(300) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL,
+    body         text CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci         NOT NULL,
+    -- first and last save coalesced into this revision
+    created_at   datetime                                                      NOT NULL,
+    saved_at     datetime                                                      NOT NULL,
+    -- editor page whose saves are coalesced into this revision; NULL once sealed
+    edit_session varchar(32) CHARACTER SET ascii COLLATE ascii_bin                 NULL,
+    PRIMARY KEY (id),
+    FOREIGN KEY (path) REFERENCES entry (path) ON DELETE CASCADE,
+    INDEX (path, created_at)
+) DEFAULT CHARSET = utf8mb4;
+
 create table amazon_cache
 (
     asin             varchar(255) primary key,
@@ -43,15 +123,88 @@ create table amazon_cache
     image_medium_url text          default null,
     link             varchar(5000) not null,
     created_at       datetime      DEFAULT CURRENT_TIMESTAMP,
-    KEY created_at (created_at)
+    -- when the product was last fetched from PA-API; rows older than the
+    -- refresh interval are fetched again
+    fetched_at       datetime      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- PA-API has not returned the product (delisted or mistyped ASIN); the
+    -- row only keeps it from being asked for again until it is stale
+    not_found        boolean       NOT NULL DEFAULT FALSE,
+    KEY created_at (created_at),
+    KEY fetched_at (fetched_at)
 ) default charset = utf8mb4;
 
+-- Signed-in browsers. session_id is the secret in the admin_session cookie;
+-- id names the session on the account page, where it can be revoked.
 CREATE TABLE admin_session
 (
-    session_id VARCHAR(255) CHARACTER SET ascii COLLATE ascii_general_ci PRIMARY KEY,
-    username VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL,
+    id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    session_id VARCHAR(255) CHARACTER SET ascii COLLATE ascii_general_ci NOT NULL,
+    user_id BIGINT NOT NULL,
+    -- where the session signed in from
+    user_agent VARCHAR(512) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL DEFAULT '',
+    ip_address VARCHAR(45) CHARACTER SET ascii COLLATE ascii_general_ci NOT NULL DEFAULT '',
     expires_at DATETIME NOT NULL,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    -- updated at most every few minutes, not on every request
     last_accessed_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY session_id (session_id),
+    KEY idx_expires_at (expires_at),
+    FOREIGN KEY (user_id) REFERENCES admin_user (id) ON DELETE CASCADE
+) DEFAULT CHARSET=utf8mb4;
+
+-- Passkeys (WebAuthn credentials) that sign admin users in without a password
+CREATE TABLE webauthn_credential
+(
+    id            BIGINT                                                        NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    user_id       BIGINT                                                        NOT NULL,
+    credential_id VARBINARY(1023)                                               NOT NULL,
+    -- shown on the account page to tell passkeys apart
+    name          VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL,
+    -- go-webauthn's credential record: public key, sign count, flags
+    credential    JSON                                                          NOT NULL,
+    created_at    DATETIME DEFAULT CURRENT_TIMESTAMP,
+    last_used_at  DATETIME DEFAULT NULL,
+    UNIQUE KEY credential_id (credential_id),
+    FOREIGN KEY (user_id) REFERENCES admin_user (id) ON DELETE CASCADE
+) DEFAULT CHARSET = utf8mb4;
+
+-- Challenges of WebAuthn ceremonies, kept between the begin and the finish
+-- request. The token is handed to the browser in between.
+CREATE TABLE webauthn_challenge
+(
+    token VARCHAR(255) CHARACTER SET ascii COLLATE ascii_bin PRIMARY KEY,
+    -- user registering a passkey; NULL for a login
+    user_id BIGINT DEFAULT NULL,
+    session_data JSON NOT NULL,
+    expires_at DATETIME NOT NULL,
+    KEY idx_expires_at (expires_at),
+    FOREIGN KEY (user_id) REFERENCES admin_user (id) ON DELETE CASCADE
+) DEFAULT CHARSET=utf8mb4;
+
+-- Logins whose password was accepted and which wait for the TOTP code. The
+-- token is handed to the browser between the two steps.
+CREATE TABLE admin_pending_login
+(
+    token VARCHAR(255) CHARACTER SET ascii COLLATE ascii_bin PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    remember_me BOOLEAN NOT NULL DEFAULT FALSE,
+    -- codes tried so far; the login is dropped after too many
+    attempts INT NOT NULL DEFAULT 0,
+    expires_at DATETIME NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_expires_at (expires_at),
+    FOREIGN KEY (user_id) REFERENCES admin_user (id) ON DELETE CASCADE
+) DEFAULT CHARSET=utf8mb4;
+
+-- Preview links for entries that are not public yet. The link's token is
+-- signed with PREVIEW_SECRET; deleting the row revokes it.
+CREATE TABLE preview_token
+(
+    id         VARCHAR(32) CHARACTER SET ascii COLLATE ascii_bin NOT NULL PRIMARY KEY,
+    path       VARCHAR(255) CHARACTER SET ascii COLLATE ascii_general_ci NOT NULL,
+    expires_at DATETIME NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (path) REFERENCES entry (path) ON DELETE CASCADE,
+    INDEX (path),
     KEY idx_expires_at (expires_at)
 ) DEFAULT CHARSET=utf8mb4;
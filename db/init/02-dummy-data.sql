@@ -33,6 +33,17 @@ INSERT INTO entry_link (src_path, dst_title) VALUES
 ('japanese-content', 'API Documentation'),
 ('tech-stack', 'Docker Setup Guide');
 
+-- Insert sample entry tags
+INSERT INTO entry_tag (path, tag) VALUES
+('getting-started', 'blog4'),
+('docker-setup-guide', 'blog4'),
+('docker-setup-guide', 'Docker'),
+('api-documentation', 'blog4'),
+('tech-stack', 'blog4'),
+('tech-stack', 'Docker'),
+('japanese-content', '日本語'),
+('private-draft', 'draft');
+
 -- Insert sample entry images (these would normally be generated by the worker)
 INSERT INTO entry_image (path, url) VALUES
 ('getting-started', 'https://picsum.photos/1200/630?random=1'),
@@ -7,6 +7,7 @@ package publicdb
 import (
 	"database/sql"
 	"database/sql/driver"
+	"encoding/json"
 	"fmt"
 	"time"
 )
@@ -53,11 +54,54 @@ func (ns NullEntryFormat) Value() (driver.Value, error) {
 	return string(ns.EntryFormat), nil
 }
 
+type EntryTagSource string
+
+const (
+	EntryTagSourceManual EntryTagSource = "manual"
+	EntryTagSourceBody   EntryTagSource = "body"
+)
+
+func (e *EntryTagSource) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = EntryTagSource(s)
+	case string:
+		*e = EntryTagSource(s)
+	default:
+		return fmt.Errorf("unsupported scan type for EntryTagSource: %T", src)
+	}
+	return nil
+}
+
+type NullEntryTagSource struct {
+	EntryTagSource EntryTagSource
+	Valid          bool // Valid is true if EntryTagSource is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullEntryTagSource) Scan(value interface{}) error {
+	if value == nil {
+		ns.EntryTagSource, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.EntryTagSource.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullEntryTagSource) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.EntryTagSource), nil
+}
+
 type EntryVisibility string
 
 const (
-	EntryVisibilityPrivate EntryVisibility = "private"
-	EntryVisibilityPublic  EntryVisibility = "public"
+	EntryVisibilityPrivate   EntryVisibility = "private"
+	EntryVisibilityPublic    EntryVisibility = "public"
+	EntryVisibilityScheduled EntryVisibility = "scheduled"
 )
 
 func (e *EntryVisibility) Scan(src interface{}) error {
@@ -95,20 +139,55 @@ func (ns NullEntryVisibility) Value() (driver.Value, error) {
 	return string(ns.EntryVisibility), nil
 }
 
+type AdminPendingLogin struct {
+	Token      string
+	UserID     int64
+	RememberMe bool
+	Attempts   int32
+	ExpiresAt  time.Time
+	CreatedAt  sql.NullTime
+}
+
+type AdminRecoveryCode struct {
+	ID        int64
+	UserID    int64
+	CodeHash  string
+	UsedAt    sql.NullTime
+	CreatedAt sql.NullTime
+}
+
 type AdminSession struct {
+	ID             int64
 	SessionID      string
-	Username       string
+	UserID         int64
+	UserAgent      string
+	IpAddress      string
 	ExpiresAt      time.Time
 	CreatedAt      sql.NullTime
 	LastAccessedAt sql.NullTime
 }
 
+type AdminUser struct {
+	ID                 int64
+	Username           string
+	PasswordHash       string
+	TotpSecret         sql.NullString
+	TotpPendingSecret  sql.NullString
+	TotpLastStep       int64
+	TotpFailedAttempts int32
+	TotpLockedUntil    sql.NullTime
+	CreatedAt          sql.NullTime
+	UpdatedAt          sql.NullTime
+}
+
 type AmazonCache struct {
 	Asin           string
 	Title          sql.NullString
 	ImageMediumUrl sql.NullString
 	Link           string
 	CreatedAt      sql.NullTime
+	FetchedAt      time.Time
+	NotFound       bool
 }
 
 type Entry struct {
@@ -122,6 +201,7 @@ type Entry struct {
 	LastEditedAt sql.NullTime
 	CreatedAt    sql.NullTime
 	UpdatedAt    sql.NullTime
+	AuthorID     sql.NullInt64
 }
 
 type EntryImage struct {
@@ -134,3 +214,48 @@ type EntryLink struct {
 	SrcPath  string
 	DstTitle string
 }
+
+type EntryRevision struct {
+	ID          int64
+	Path        string
+	Title       string
+	Body        string
+	CreatedAt   time.Time
+	SavedAt     time.Time
+	EditSession sql.NullString
+}
+
+type EntrySearchToken struct {
+	Token string
+	Path  string
+}
+
+type EntryTag struct {
+	Path   string
+	Tag    string
+	Source EntryTagSource
+}
+
+type PreviewToken struct {
+	ID        string
+	Path      string
+	ExpiresAt time.Time
+	CreatedAt sql.NullTime
+}
+
+type WebauthnChallenge struct {
+	Token       string
+	UserID      sql.NullInt64
+	SessionData json.RawMessage
+	ExpiresAt   time.Time
+}
+
+type WebauthnCredential struct {
+	ID           int64
+	UserID       int64
+	CredentialID []byte
+	Name         string
+	Credential   json.RawMessage
+	CreatedAt    sql.NullTime
+	LastUsedAt   sql.NullTime
+}
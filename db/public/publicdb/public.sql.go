@@ -8,12 +8,52 @@ package publicdb
 import (
 	"context"
 	"database/sql"
+	"strings"
+	"time"
 )
 
+const countPublicEntriesByMonth = `-- name: CountPublicEntriesByMonth :many
+SELECT CAST(YEAR(published_at) AS SIGNED) AS year, CAST(MONTH(published_at) AS SIGNED) AS month, COUNT(*) AS entry_count
+FROM entry
+WHERE visibility = 'public' AND published_at IS NOT NULL
+GROUP BY year, month
+ORDER BY year DESC, month
+`
+
+type CountPublicEntriesByMonthRow struct {
+	Year       int64
+	Month      int64
+	EntryCount int64
+}
+
+// アーカイブの年別インデックス用
+func (q *Queries) CountPublicEntriesByMonth(ctx context.Context) ([]CountPublicEntriesByMonthRow, error) {
+	rows, err := q.db.QueryContext(ctx, countPublicEntriesByMonth)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountPublicEntriesByMonthRow
+	for rows.Next() {
+		var i CountPublicEntriesByMonthRow
+		if err := rows.Scan(&i.Year, &i.Month, &i.EntryCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getAsin = `-- name: GetAsin :one
-SELECT asin, title, image_medium_url, link, created_at
+SELECT asin, title, image_medium_url, link, created_at, fetched_at, not_found
 FROM amazon_cache
-WHERE asin = ?
+WHERE asin = ? AND NOT not_found
 `
 
 func (q *Queries) GetAsin(ctx context.Context, asin string) (AmazonCache, error) {
@@ -25,12 +65,14 @@ func (q *Queries) GetAsin(ctx context.Context, asin string) (AmazonCache, error)
 		&i.ImageMediumUrl,
 		&i.Link,
 		&i.CreatedAt,
+		&i.FetchedAt,
+		&i.NotFound,
 	)
 	return i, err
 }
 
 const getEntryByPath = `-- name: GetEntryByPath :one
-SELECT entry.path, entry.title, entry.body, entry.visibility, entry.format, entry.published_at, entry.last_edited_at, entry.created_at, entry.updated_at, entry_image.url image_url
+SELECT entry.path, entry.title, entry.body, entry.visibility, entry.format, entry.published_at, entry.last_edited_at, entry.created_at, entry.updated_at, entry.author_id, entry_image.url image_url
 FROM entry
     LEFT JOIN entry_image ON (entry.path = entry_image.path)
 WHERE entry.path = ? AND visibility = 'public'
@@ -46,6 +88,7 @@ type GetEntryByPathRow struct {
 	LastEditedAt sql.NullTime
 	CreatedAt    sql.NullTime
 	UpdatedAt    sql.NullTime
+	AuthorID     sql.NullInt64
 	ImageUrl     sql.NullString
 }
 
@@ -62,13 +105,14 @@ func (q *Queries) GetEntryByPath(ctx context.Context, path string) (GetEntryByPa
 		&i.LastEditedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AuthorID,
 		&i.ImageUrl,
 	)
 	return i, err
 }
 
 const getEntryByTitle = `-- name: GetEntryByTitle :one
-SELECT path, title, body, visibility, format, published_at, last_edited_at, created_at, updated_at
+SELECT path, title, body, visibility, format, published_at, last_edited_at, created_at, updated_at, author_id
 FROM entry
 WHERE title = ? AND visibility = 'public'
 `
@@ -86,12 +130,324 @@ func (q *Queries) GetEntryByTitle(ctx context.Context, title string) (Entry, err
 		&i.LastEditedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AuthorID,
+	)
+	return i, err
+}
+
+const getNextPublicEntry = `-- name: GetNextPublicEntry :one
+SELECT path, title
+FROM entry
+WHERE visibility = 'public'
+    AND (published_at > ?
+        OR (published_at = ? AND path > ?))
+ORDER BY published_at, path
+LIMIT 1
+`
+
+type GetNextPublicEntryParams struct {
+	PublishedAt sql.NullTime
+	Path        string
+}
+
+type GetNextPublicEntryRow struct {
+	Path  string
+	Title string
+}
+
+// 1 つ新しいエントリー
+func (q *Queries) GetNextPublicEntry(ctx context.Context, arg GetNextPublicEntryParams) (GetNextPublicEntryRow, error) {
+	row := q.db.QueryRowContext(ctx, getNextPublicEntry, arg.PublishedAt, arg.PublishedAt, arg.Path)
+	var i GetNextPublicEntryRow
+	err := row.Scan(&i.Path, &i.Title)
+	return i, err
+}
+
+const getPrevPublicEntry = `-- name: GetPrevPublicEntry :one
+SELECT path, title
+FROM entry
+WHERE visibility = 'public'
+    AND (published_at < ?
+        OR (published_at = ? AND path < ?))
+ORDER BY published_at DESC, path DESC
+LIMIT 1
+`
+
+type GetPrevPublicEntryParams struct {
+	PublishedAt sql.NullTime
+	Path        string
+}
+
+type GetPrevPublicEntryRow struct {
+	Path  string
+	Title string
+}
+
+// 1 つ古いエントリー。published_at が同じときは path で順序を決める
+func (q *Queries) GetPrevPublicEntry(ctx context.Context, arg GetPrevPublicEntryParams) (GetPrevPublicEntryRow, error) {
+	row := q.db.QueryRowContext(ctx, getPrevPublicEntry, arg.PublishedAt, arg.PublishedAt, arg.Path)
+	var i GetPrevPublicEntryRow
+	err := row.Scan(&i.Path, &i.Title)
+	return i, err
+}
+
+const getPreviewEntry = `-- name: GetPreviewEntry :one
+SELECT entry.path, entry.title, entry.body, entry.visibility, entry.format, entry.published_at, entry.last_edited_at, entry.created_at, entry.updated_at, entry.author_id, entry_image.url image_url
+FROM preview_token
+    JOIN entry ON (preview_token.path = entry.path)
+    LEFT JOIN entry_image ON (entry.path = entry_image.path)
+WHERE preview_token.id = ? AND entry.path = ? AND preview_token.expires_at > NOW()
+`
+
+type GetPreviewEntryParams struct {
+	ID   string
+	Path string
+}
+
+type GetPreviewEntryRow struct {
+	Path         string
+	Title        string
+	Body         string
+	Visibility   EntryVisibility
+	Format       EntryFormat
+	PublishedAt  sql.NullTime
+	LastEditedAt sql.NullTime
+	CreatedAt    sql.NullTime
+	UpdatedAt    sql.NullTime
+	AuthorID     sql.NullInt64
+	ImageUrl     sql.NullString
+}
+
+func (q *Queries) GetPreviewEntry(ctx context.Context, arg GetPreviewEntryParams) (GetPreviewEntryRow, error) {
+	row := q.db.QueryRowContext(ctx, getPreviewEntry, arg.ID, arg.Path)
+	var i GetPreviewEntryRow
+	err := row.Scan(
+		&i.Path,
+		&i.Title,
+		&i.Body,
+		&i.Visibility,
+		&i.Format,
+		&i.PublishedAt,
+		&i.LastEditedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.AuthorID,
+		&i.ImageUrl,
 	)
 	return i, err
 }
 
+const getPublicContentVersion = `-- name: GetPublicContentVersion :one
+SELECT
+    (SELECT COUNT(*) FROM entry WHERE entry.visibility = 'public') AS public_entries,
+    CAST(COALESCE((SELECT MAX(entry.updated_at) FROM entry WHERE entry.visibility = 'public'), '1970-01-01') AS DATETIME) AS entries_updated_at,
+    CAST(COALESCE((SELECT MAX(amazon_cache.fetched_at) FROM amazon_cache WHERE NOT amazon_cache.not_found), '1970-01-01') AS DATETIME) AS amazon_fetched_at
+`
+
+type GetPublicContentVersionRow struct {
+	PublicEntries    int64
+	EntriesUpdatedAt time.Time
+	AmazonFetchedAt  time.Time
+}
+
+// フィードの ETag 用。本文に描画される他のエントリ ([[...]] のリンク先、![[...]] の埋め込み) や ASIN の商品情報が変わったことも拾う
+func (q *Queries) GetPublicContentVersion(ctx context.Context) (GetPublicContentVersionRow, error) {
+	row := q.db.QueryRowContext(ctx, getPublicContentVersion)
+	var i GetPublicContentVersionRow
+	err := row.Scan(&i.PublicEntries, &i.EntriesUpdatedAt, &i.AmazonFetchedAt)
+	return i, err
+}
+
+const getPublicEntriesByPaths = `-- name: GetPublicEntriesByPaths :many
+SELECT entry.path, entry.title, entry.body, entry.visibility, entry.format, entry.published_at, entry.last_edited_at, entry.created_at, entry.updated_at, entry.author_id, entry_image.url image_url
+FROM entry
+    LEFT JOIN entry_image ON (entry.path = entry_image.path)
+WHERE entry.path IN (/*SLICE:paths*/?) AND visibility = 'public'
+ORDER BY published_at DESC
+`
+
+type GetPublicEntriesByPathsRow struct {
+	Path         string
+	Title        string
+	Body         string
+	Visibility   EntryVisibility
+	Format       EntryFormat
+	PublishedAt  sql.NullTime
+	LastEditedAt sql.NullTime
+	CreatedAt    sql.NullTime
+	UpdatedAt    sql.NullTime
+	AuthorID     sql.NullInt64
+	ImageUrl     sql.NullString
+}
+
+func (q *Queries) GetPublicEntriesByPaths(ctx context.Context, paths []string) ([]GetPublicEntriesByPathsRow, error) {
+	query := getPublicEntriesByPaths
+	var queryParams []interface{}
+	if len(paths) > 0 {
+		for _, v := range paths {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:paths*/?", strings.Repeat(",?", len(paths))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:paths*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPublicEntriesByPathsRow
+	for rows.Next() {
+		var i GetPublicEntriesByPathsRow
+		if err := rows.Scan(
+			&i.Path,
+			&i.Title,
+			&i.Body,
+			&i.Visibility,
+			&i.Format,
+			&i.PublishedAt,
+			&i.LastEditedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.AuthorID,
+			&i.ImageUrl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPublicEntriesByTitles = `-- name: GetPublicEntriesByTitles :many
+SELECT path, title, body
+FROM entry
+WHERE title IN (/*SLICE:titles*/?) AND visibility = 'public'
+`
+
+type GetPublicEntriesByTitlesRow struct {
+	Path  string
+	Title string
+	Body  string
+}
+
+// ![[...]] で埋め込む公開エントリをまとめて取得する
+func (q *Queries) GetPublicEntriesByTitles(ctx context.Context, titles []string) ([]GetPublicEntriesByTitlesRow, error) {
+	query := getPublicEntriesByTitles
+	var queryParams []interface{}
+	if len(titles) > 0 {
+		for _, v := range titles {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:titles*/?", strings.Repeat(",?", len(titles))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:titles*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPublicEntriesByTitlesRow
+	for rows.Next() {
+		var i GetPublicEntriesByTitlesRow
+		if err := rows.Scan(&i.Path, &i.Title, &i.Body); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPublicEntryPathsByTitles = `-- name: GetPublicEntryPathsByTitles :many
+SELECT path, title
+FROM entry
+WHERE title IN (/*SLICE:titles*/?) AND visibility = 'public'
+`
+
+type GetPublicEntryPathsByTitlesRow struct {
+	Path  string
+	Title string
+}
+
+// 1 ドキュメント内の [[...]] をまとめて解決する
+func (q *Queries) GetPublicEntryPathsByTitles(ctx context.Context, titles []string) ([]GetPublicEntryPathsByTitlesRow, error) {
+	query := getPublicEntryPathsByTitles
+	var queryParams []interface{}
+	if len(titles) > 0 {
+		for _, v := range titles {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:titles*/?", strings.Repeat(",?", len(titles))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:titles*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPublicEntryPathsByTitlesRow
+	for rows.Next() {
+		var i GetPublicEntryPathsByTitlesRow
+		if err := rows.Scan(&i.Path, &i.Title); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPublicEntryTags = `-- name: GetPublicEntryTags :many
+SELECT tag
+FROM entry_tag
+WHERE path = ?
+ORDER BY tag
+`
+
+func (q *Queries) GetPublicEntryTags(ctx context.Context, path string) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, getPublicEntryTags, path)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, err
+		}
+		items = append(items, tag)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getRelatedEntries1 = `-- name: GetRelatedEntries1 :many
-SELECT dst_entry.path, dst_entry.title, dst_entry.body, dst_entry.visibility, dst_entry.format, dst_entry.published_at, dst_entry.last_edited_at, dst_entry.created_at, dst_entry.updated_at
+SELECT dst_entry.path, dst_entry.title, dst_entry.body, dst_entry.visibility, dst_entry.format, dst_entry.published_at, dst_entry.last_edited_at, dst_entry.created_at, dst_entry.updated_at, dst_entry.author_id
 FROM entry dst_entry
          INNER JOIN entry_link ON (dst_entry.title = entry_link.dst_title)
 WHERE entry_link.src_path = ? AND dst_entry.visibility = 'public'
@@ -117,6 +473,7 @@ func (q *Queries) GetRelatedEntries1(ctx context.Context, srcPath string) ([]Ent
 			&i.LastEditedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.AuthorID,
 		); err != nil {
 			return nil, err
 		}
@@ -132,7 +489,7 @@ func (q *Queries) GetRelatedEntries1(ctx context.Context, srcPath string) ([]Ent
 }
 
 const getRelatedEntries2 = `-- name: GetRelatedEntries2 :many
-SELECT src_entry.path, src_entry.title, src_entry.body, src_entry.visibility, src_entry.format, src_entry.published_at, src_entry.last_edited_at, src_entry.created_at, src_entry.updated_at
+SELECT src_entry.path, src_entry.title, src_entry.body, src_entry.visibility, src_entry.format, src_entry.published_at, src_entry.last_edited_at, src_entry.created_at, src_entry.updated_at, src_entry.author_id
 FROM entry src_entry
          INNER JOIN entry_link ON (src_entry.path = entry_link.src_path)
 WHERE entry_link.dst_title = ? AND src_entry.visibility = 'public'
@@ -158,6 +515,7 @@ func (q *Queries) GetRelatedEntries2(ctx context.Context, dstTitle string) ([]En
 			&i.LastEditedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.AuthorID,
 		); err != nil {
 			return nil, err
 		}
@@ -173,7 +531,7 @@ func (q *Queries) GetRelatedEntries2(ctx context.Context, dstTitle string) ([]En
 }
 
 const getRelatedEntries3 = `-- name: GetRelatedEntries3 :many
-SELECT dst_entry.path, dst_entry.title, dst_entry.body, dst_entry.visibility, dst_entry.format, dst_entry.published_at, dst_entry.last_edited_at, dst_entry.created_at, dst_entry.updated_at
+SELECT dst_entry.path, dst_entry.title, dst_entry.body, dst_entry.visibility, dst_entry.format, dst_entry.published_at, dst_entry.last_edited_at, dst_entry.created_at, dst_entry.updated_at, dst_entry.author_id
 FROM entry dst_entry
          INNER JOIN entry_link ON (dst_entry.title = entry_link.dst_title)
 WHERE entry_link.src_path IN (SELECT src_entry.title
@@ -203,6 +561,7 @@ func (q *Queries) GetRelatedEntries3(ctx context.Context, dstTitle string) ([]En
 			&i.LastEditedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.AuthorID,
 		); err != nil {
 			return nil, err
 		}
@@ -218,14 +577,67 @@ func (q *Queries) GetRelatedEntries3(ctx context.Context, dstTitle string) ([]En
 }
 
 const listAllPublicEntries = `-- name: ListAllPublicEntries :many
-SELECT entry.path, entry.title, entry.body, entry.visibility, entry.format, entry.published_at, entry.last_edited_at, entry.created_at, entry.updated_at, entry_image.url image_url
+SELECT entry.path, entry.last_edited_at, entry.updated_at, entry_image.url image_url
 FROM entry
     LEFT JOIN entry_image ON (entry.path = entry_image.path)
 WHERE visibility = 'public'
-ORDER BY published_at DESC
+ORDER BY published_at, entry.path
 `
 
 type ListAllPublicEntriesRow struct {
+	Path         string
+	LastEditedAt sql.NullTime
+	UpdatedAt    sql.NullTime
+	ImageUrl     sql.NullString
+}
+
+// sitemap 用。古い順に並べて、分割したときに既存の sitemap がずれないようにする
+func (q *Queries) ListAllPublicEntries(ctx context.Context) ([]ListAllPublicEntriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listAllPublicEntries)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAllPublicEntriesRow
+	for rows.Next() {
+		var i ListAllPublicEntriesRow
+		if err := rows.Scan(
+			&i.Path,
+			&i.LastEditedAt,
+			&i.UpdatedAt,
+			&i.ImageUrl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPublicEntriesOnDay = `-- name: ListPublicEntriesOnDay :many
+SELECT entry.path, entry.title, entry.body, entry.visibility, entry.format, entry.published_at, entry.last_edited_at, entry.created_at, entry.updated_at, entry.author_id, entry_image.url image_url
+FROM entry
+    LEFT JOIN entry_image ON (entry.path = entry_image.path)
+WHERE visibility = 'public'
+    AND MONTH(published_at) = CAST(? AS SIGNED)
+    AND DAYOFMONTH(published_at) = CAST(? AS SIGNED)
+    AND published_at < ?
+ORDER BY published_at DESC
+`
+
+type ListPublicEntriesOnDayParams struct {
+	Month  int64
+	Day    int64
+	Before sql.NullTime
+}
+
+type ListPublicEntriesOnDayRow struct {
 	Path         string
 	Title        string
 	Body         string
@@ -235,18 +647,20 @@ type ListAllPublicEntriesRow struct {
 	LastEditedAt sql.NullTime
 	CreatedAt    sql.NullTime
 	UpdatedAt    sql.NullTime
+	AuthorID     sql.NullInt64
 	ImageUrl     sql.NullString
 }
 
-func (q *Queries) ListAllPublicEntries(ctx context.Context) ([]ListAllPublicEntriesRow, error) {
-	rows, err := q.db.QueryContext(ctx, listAllPublicEntries)
+// 「過去の今日」。月日が一致する、指定日より前の年のエントリー
+func (q *Queries) ListPublicEntriesOnDay(ctx context.Context, arg ListPublicEntriesOnDayParams) ([]ListPublicEntriesOnDayRow, error) {
+	rows, err := q.db.QueryContext(ctx, listPublicEntriesOnDay, arg.Month, arg.Day, arg.Before)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListAllPublicEntriesRow
+	var items []ListPublicEntriesOnDayRow
 	for rows.Next() {
-		var i ListAllPublicEntriesRow
+		var i ListPublicEntriesOnDayRow
 		if err := rows.Scan(
 			&i.Path,
 			&i.Title,
@@ -257,6 +671,7 @@ func (q *Queries) ListAllPublicEntries(ctx context.Context) ([]ListAllPublicEntr
 			&i.LastEditedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.AuthorID,
 			&i.ImageUrl,
 		); err != nil {
 			return nil, err
@@ -272,8 +687,45 @@ func (q *Queries) ListAllPublicEntries(ctx context.Context) ([]ListAllPublicEntr
 	return items, nil
 }
 
+const listPublicTags = `-- name: ListPublicTags :many
+SELECT entry_tag.tag, COUNT(*) AS entry_count
+FROM entry_tag
+    INNER JOIN entry ON (entry.path = entry_tag.path)
+WHERE entry.visibility = 'public'
+GROUP BY entry_tag.tag
+ORDER BY entry_count DESC, entry_tag.tag
+`
+
+type ListPublicTagsRow struct {
+	Tag        string
+	EntryCount int64
+}
+
+func (q *Queries) ListPublicTags(ctx context.Context) ([]ListPublicTagsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listPublicTags)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPublicTagsRow
+	for rows.Next() {
+		var i ListPublicTagsRow
+		if err := rows.Scan(&i.Tag, &i.EntryCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const searchEntries = `-- name: SearchEntries :many
-SELECT entry.path, entry.title, entry.body, entry.visibility, entry.format, entry.published_at, entry.last_edited_at, entry.created_at, entry.updated_at, entry_image.url image_url
+SELECT entry.path, entry.title, entry.body, entry.visibility, entry.format, entry.published_at, entry.last_edited_at, entry.created_at, entry.updated_at, entry.author_id, entry_image.url image_url
 FROM entry
     LEFT JOIN entry_image ON (entry.path = entry_image.path)
 WHERE visibility = 'public'
@@ -296,6 +748,7 @@ type SearchEntriesRow struct {
 	LastEditedAt sql.NullTime
 	CreatedAt    sql.NullTime
 	UpdatedAt    sql.NullTime
+	AuthorID     sql.NullInt64
 	ImageUrl     sql.NullString
 }
 
@@ -318,6 +771,7 @@ func (q *Queries) SearchEntries(ctx context.Context, arg SearchEntriesParams) ([
 			&i.LastEditedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.AuthorID,
 			&i.ImageUrl,
 		); err != nil {
 			return nil, err
@@ -332,3 +786,192 @@ func (q *Queries) SearchEntries(ctx context.Context, arg SearchEntriesParams) ([
 	}
 	return items, nil
 }
+
+const searchEntriesByPublishedRange = `-- name: SearchEntriesByPublishedRange :many
+SELECT entry.path, entry.title, entry.body, entry.visibility, entry.format, entry.published_at, entry.last_edited_at, entry.created_at, entry.updated_at, entry.author_id, entry_image.url image_url
+FROM entry
+    LEFT JOIN entry_image ON (entry.path = entry_image.path)
+WHERE visibility = 'public'
+    AND published_at >= ? AND published_at < ?
+ORDER BY published_at DESC
+LIMIT ? OFFSET ?
+`
+
+type SearchEntriesByPublishedRangeParams struct {
+	PublishedFrom sql.NullTime
+	PublishedTo   sql.NullTime
+	Limit         int32
+	Offset        int32
+}
+
+type SearchEntriesByPublishedRangeRow struct {
+	Path         string
+	Title        string
+	Body         string
+	Visibility   EntryVisibility
+	Format       EntryFormat
+	PublishedAt  sql.NullTime
+	LastEditedAt sql.NullTime
+	CreatedAt    sql.NullTime
+	UpdatedAt    sql.NullTime
+	AuthorID     sql.NullInt64
+	ImageUrl     sql.NullString
+}
+
+func (q *Queries) SearchEntriesByPublishedRange(ctx context.Context, arg SearchEntriesByPublishedRangeParams) ([]SearchEntriesByPublishedRangeRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchEntriesByPublishedRange,
+		arg.PublishedFrom,
+		arg.PublishedTo,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchEntriesByPublishedRangeRow
+	for rows.Next() {
+		var i SearchEntriesByPublishedRangeRow
+		if err := rows.Scan(
+			&i.Path,
+			&i.Title,
+			&i.Body,
+			&i.Visibility,
+			&i.Format,
+			&i.PublishedAt,
+			&i.LastEditedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.AuthorID,
+			&i.ImageUrl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchEntriesByTag = `-- name: SearchEntriesByTag :many
+SELECT entry.path, entry.title, entry.body, entry.visibility, entry.format, entry.published_at, entry.last_edited_at, entry.created_at, entry.updated_at, entry.author_id, entry_image.url image_url
+FROM entry
+    INNER JOIN entry_tag ON (entry.path = entry_tag.path)
+    LEFT JOIN entry_image ON (entry.path = entry_image.path)
+WHERE entry_tag.tag = ? AND visibility = 'public'
+ORDER BY published_at DESC
+LIMIT ? OFFSET ?
+`
+
+type SearchEntriesByTagParams struct {
+	Tag    string
+	Limit  int32
+	Offset int32
+}
+
+type SearchEntriesByTagRow struct {
+	Path         string
+	Title        string
+	Body         string
+	Visibility   EntryVisibility
+	Format       EntryFormat
+	PublishedAt  sql.NullTime
+	LastEditedAt sql.NullTime
+	CreatedAt    sql.NullTime
+	UpdatedAt    sql.NullTime
+	AuthorID     sql.NullInt64
+	ImageUrl     sql.NullString
+}
+
+func (q *Queries) SearchEntriesByTag(ctx context.Context, arg SearchEntriesByTagParams) ([]SearchEntriesByTagRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchEntriesByTag, arg.Tag, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchEntriesByTagRow
+	for rows.Next() {
+		var i SearchEntriesByTagRow
+		if err := rows.Scan(
+			&i.Path,
+			&i.Title,
+			&i.Body,
+			&i.Visibility,
+			&i.Format,
+			&i.PublishedAt,
+			&i.LastEditedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.AuthorID,
+			&i.ImageUrl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchEntryPathsByTokens = `-- name: SearchEntryPathsByTokens :many
+SELECT entry_search_token.path
+FROM entry_search_token
+    INNER JOIN entry ON (entry.path = entry_search_token.path)
+WHERE entry_search_token.token IN (/*SLICE:tokens*/?) AND entry.visibility = 'public'
+GROUP BY entry_search_token.path, entry.published_at
+HAVING COUNT(*) = CAST(? AS SIGNED)
+ORDER BY entry.published_at DESC
+LIMIT ?
+`
+
+type SearchEntryPathsByTokensParams struct {
+	Tokens     []string
+	TokenCount int64
+	Limit      int32
+}
+
+// 検索語の n-gram を全て含む公開エントリ。本文との照合は呼び出し側で行う
+func (q *Queries) SearchEntryPathsByTokens(ctx context.Context, arg SearchEntryPathsByTokensParams) ([]string, error) {
+	query := searchEntryPathsByTokens
+	var queryParams []interface{}
+	if len(arg.Tokens) > 0 {
+		for _, v := range arg.Tokens {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:tokens*/?", strings.Repeat(",?", len(arg.Tokens))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:tokens*/?", "NULL", 1)
+	}
+	queryParams = append(queryParams, arg.TokenCount)
+	queryParams = append(queryParams, arg.Limit)
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, err
+		}
+		items = append(items, path)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -20,7 +20,7 @@ WHERE title = ? AND visibility = 'public';
 -- name: GetAsin :one
 SELECT *
 FROM amazon_cache
-WHERE asin = ?;
+WHERE asin = ? AND NOT not_found;
 
 -- name: GetRelatedEntries1 :many
 /* 現在表示しているエントリがリンクしているページ */
@@ -48,8 +48,124 @@ WHERE entry_link.src_path IN (SELECT src_entry.title
     AND dst_entry.visibility = 'public';
 
 -- name: ListAllPublicEntries :many
+/* sitemap 用。古い順に並べて、分割したときに既存の sitemap がずれないようにする */
+SELECT entry.path, entry.last_edited_at, entry.updated_at, entry_image.url image_url
+FROM entry
+    LEFT JOIN entry_image ON (entry.path = entry_image.path)
+WHERE visibility = 'public'
+ORDER BY published_at, entry.path;
+
+-- name: GetPublicEntryPathsByTitles :many
+/* 1 ドキュメント内の [[...]] をまとめて解決する */
+SELECT path, title
+FROM entry
+WHERE title IN (sqlc.slice(titles)) AND visibility = 'public';
+
+-- name: GetPublicEntriesByTitles :many
+/* ![[...]] で埋め込む公開エントリをまとめて取得する */
+SELECT path, title, body
+FROM entry
+WHERE title IN (sqlc.slice(titles)) AND visibility = 'public';
+
+-- name: SearchEntryPathsByTokens :many
+/* 検索語の n-gram を全て含む公開エントリ。本文との照合は呼び出し側で行う */
+SELECT entry_search_token.path
+FROM entry_search_token
+    INNER JOIN entry ON (entry.path = entry_search_token.path)
+WHERE entry_search_token.token IN (sqlc.slice(tokens)) AND entry.visibility = 'public'
+GROUP BY entry_search_token.path, entry.published_at
+HAVING COUNT(*) = CAST(sqlc.arg(token_count) AS SIGNED)
+ORDER BY entry.published_at DESC
+LIMIT ?;
+
+-- name: GetPublicEntriesByPaths :many
+SELECT entry.*, entry_image.url image_url
+FROM entry
+    LEFT JOIN entry_image ON (entry.path = entry_image.path)
+WHERE entry.path IN (sqlc.slice(paths)) AND visibility = 'public'
+ORDER BY published_at DESC;
+
+-- name: ListPublicTags :many
+SELECT entry_tag.tag, COUNT(*) AS entry_count
+FROM entry_tag
+    INNER JOIN entry ON (entry.path = entry_tag.path)
+WHERE entry.visibility = 'public'
+GROUP BY entry_tag.tag
+ORDER BY entry_count DESC, entry_tag.tag;
+
+-- name: GetPublicEntryTags :many
+SELECT tag
+FROM entry_tag
+WHERE path = ?
+ORDER BY tag;
+
+-- name: SearchEntriesByTag :many
+SELECT entry.*, entry_image.url image_url
+FROM entry
+    INNER JOIN entry_tag ON (entry.path = entry_tag.path)
+    LEFT JOIN entry_image ON (entry.path = entry_image.path)
+WHERE entry_tag.tag = ? AND visibility = 'public'
+ORDER BY published_at DESC
+LIMIT ? OFFSET ?;
+
+-- name: SearchEntriesByPublishedRange :many
+SELECT entry.*, entry_image.url image_url
+FROM entry
+    LEFT JOIN entry_image ON (entry.path = entry_image.path)
+WHERE visibility = 'public'
+    AND published_at >= sqlc.arg(published_from) AND published_at < sqlc.arg(published_to)
+ORDER BY published_at DESC
+LIMIT ? OFFSET ?;
+
+-- name: CountPublicEntriesByMonth :many
+/* アーカイブの年別インデックス用 */
+SELECT CAST(YEAR(published_at) AS SIGNED) AS year, CAST(MONTH(published_at) AS SIGNED) AS month, COUNT(*) AS entry_count
+FROM entry
+WHERE visibility = 'public' AND published_at IS NOT NULL
+GROUP BY year, month
+ORDER BY year DESC, month;
+
+-- name: ListPublicEntriesOnDay :many
+/* 「過去の今日」。月日が一致する、指定日より前の年のエントリー */
 SELECT entry.*, entry_image.url image_url
 FROM entry
     LEFT JOIN entry_image ON (entry.path = entry_image.path)
 WHERE visibility = 'public'
+    AND MONTH(published_at) = CAST(sqlc.arg(month) AS SIGNED)
+    AND DAYOFMONTH(published_at) = CAST(sqlc.arg(day) AS SIGNED)
+    AND published_at < sqlc.arg(before)
 ORDER BY published_at DESC;
+
+-- name: GetPrevPublicEntry :one
+/* 1 つ古いエントリー。published_at が同じときは path で順序を決める */
+SELECT path, title
+FROM entry
+WHERE visibility = 'public'
+    AND (published_at < sqlc.arg(published_at)
+        OR (published_at = sqlc.arg(published_at) AND path < sqlc.arg(path)))
+ORDER BY published_at DESC, path DESC
+LIMIT 1;
+
+-- name: GetNextPublicEntry :one
+/* 1 つ新しいエントリー */
+SELECT path, title
+FROM entry
+WHERE visibility = 'public'
+    AND (published_at > sqlc.arg(published_at)
+        OR (published_at = sqlc.arg(published_at) AND path > sqlc.arg(path)))
+ORDER BY published_at, path
+LIMIT 1;
+
+-- name: GetPreviewEntry :one
+SELECT entry.*, entry_image.url image_url
+FROM preview_token
+    JOIN entry ON (preview_token.path = entry.path)
+    LEFT JOIN entry_image ON (entry.path = entry_image.path)
+WHERE preview_token.id = ? AND entry.path = ? AND preview_token.expires_at > NOW();
+
+-- name: GetPublicContentVersion :one
+/* フィードの ETag 用。本文に描画される他のエントリ ([[...]] のリンク先、![[...]] の埋め込み) や ASIN の商品情報が変わったことも拾う */
+SELECT
+    (SELECT COUNT(*) FROM entry WHERE entry.visibility = 'public') AS public_entries,
+    CAST(COALESCE((SELECT MAX(entry.updated_at) FROM entry WHERE entry.visibility = 'public'), '1970-01-01') AS DATETIME) AS entries_updated_at,
+    CAST(COALESCE((SELECT MAX(amazon_cache.fetched_at) FROM amazon_cache WHERE NOT amazon_cache.not_found), '1970-01-01') AS DATETIME) AS amazon_fetched_at;
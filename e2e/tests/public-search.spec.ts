@@ -1,7 +1,7 @@
 import { test, expect } from '@playwright/test';
 
-// クライアント検索 (search.js) が /search-index.json を読み、ブラウザ側で
-// 絞り込むことを検証する。MySQL FULLTEXT / MATCH AGAINST から移行した代替実装の確認。
+// search.js が /api/search (entry_search_token の n-gram 索引) を呼んで
+// 結果を描画することを検証する。
 
 test('public search: initial query from URL renders matching entry', async ({ page }) => {
     await page.goto('/search?q=Docker');
@@ -10,7 +10,7 @@ test('public search: initial query from URL renders matching entry', async ({ pa
 
 test('public search: incremental search works for Japanese keywords', async ({ page }) => {
     await page.goto('/search');
-    // fetch 完了 (= リスナー登録 + 初期表示) を待つ
+    // 初期表示 (= リスナー登録済み) を待つ
     await expect(page.locator('#search-results')).toContainText('Enter keywords');
     await page.locator('.search-input').fill('日本語');
     await expect(
@@ -34,6 +34,11 @@ test('public search: private entries are not searchable', async ({ page }) => {
     await expect(page.locator('#search-results')).toContainText('Enter keywords');
     await page.locator('.search-input').fill('Private Draft Example');
     await page.waitForTimeout(600);
-    // private エントリは /search-index.json に含まれないので結果に出ない
+    // private エントリは /api/search の対象外なので結果に出ない
     await expect(page.getByText('Private Draft Example')).toHaveCount(0);
 });
+
+test('public search: matches are highlighted in the snippet', async ({ page }) => {
+    await page.goto('/search?q=Docker');
+    await expect(page.locator('.entry-text-preview mark').first()).toHaveText(/docker/i);
+});
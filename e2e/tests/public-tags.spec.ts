@@ -0,0 +1,29 @@
+import { test, expect } from '@playwright/test';
+
+test('public tags: tag index lists tags of public entries only', async ({ page }) => {
+    await page.goto('/tags');
+    await expect(page.locator('.tag-cloud a', { hasText: '#Docker' })).toBeVisible();
+    await expect(page.locator('.tag-cloud a', { hasText: '#日本語' })).toBeVisible();
+    // private-draft にしか付いていないタグは出ない
+    await expect(page.locator('.tag-cloud a', { hasText: '#draft' })).toHaveCount(0);
+});
+
+test('public tags: tag page lists tagged entries', async ({ page }) => {
+    await page.goto('/tag/Docker');
+    await expect(page.locator('.tag-heading')).toHaveText('#Docker');
+    await expect(page.locator('.entry-title', { hasText: 'Docker Setup Guide' })).toBeVisible();
+    await expect(page.locator('.entry-title', { hasText: 'Technology Stack' })).toBeVisible();
+    await expect(page.locator('.entry-title', { hasText: 'Markdown Cheatsheet' })).toHaveCount(0);
+});
+
+test('public tags: entry page links to its tags', async ({ page }) => {
+    await page.goto('/entry/docker-setup-guide');
+    await page.locator('.entry-tags a', { hasText: '#Docker' }).click();
+    await expect(page).toHaveURL(/\/tag\/Docker$/);
+});
+
+test('public tags: tag feed is served', async ({ request }) => {
+    const res = await request.get('/tag/Docker/feed');
+    expect(res.ok()).toBeTruthy();
+    expect(await res.text()).toContain('Docker Setup Guide');
+});
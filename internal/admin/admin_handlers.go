@@ -1,8 +1,11 @@
 package admin
 
 import (
+	"context"
 	"crypto/rand"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
 	"log/slog"
@@ -13,40 +16,93 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/tokuhirom/blog4/internal/adminuser"
+	"github.com/tokuhirom/blog4/internal/entrylink"
+	"github.com/tokuhirom/blog4/internal/entrytag"
 	"github.com/tokuhirom/blog4/internal/ogimage"
+	"github.com/tokuhirom/blog4/internal/passkey"
+	"github.com/tokuhirom/blog4/internal/preview"
+	"github.com/tokuhirom/blog4/internal/revision"
+	"github.com/tokuhirom/blog4/internal/search"
 	"github.com/tokuhirom/blog4/internal/sobs"
+	"github.com/tokuhirom/blog4/internal/templates"
+	"github.com/tokuhirom/blog4/internal/websub"
 
 	"github.com/tokuhirom/blog4/db/admin/admindb"
 )
 
 // AdminHandler handles admin pages and JSON APIs
 type AdminHandler struct {
+	db                   *sql.DB
 	queries              *admindb.Queries
 	sobsClient           *sobs.SobsClient
-	adminUser            string
-	adminPassword        string
+	users                *adminuser.Service
+	passkeys             *passkey.Service
 	isSecure             bool
 	s3AttachmentsBaseUrl string
 	ogImageService       *ogimage.Service
+	linkService          *entrylink.Service
+	searchService        *search.Service
+	tagService           *entrytag.Service
+	websubPublisher      *websub.Publisher
+	siteBaseUrl          string
+	previewSigner        *preview.Signer
+	views                *templates.Registry
+
+	// Set while the rebuild of the same name runs in the background
+	linkRebuild   atomic.Bool
+	searchRebuild atomic.Bool
+	tagRebuild    atomic.Bool
 }
 
 // NewAdminHandler creates a new AdminHandler
-func NewAdminHandler(queries *admindb.Queries, sobsClient *sobs.SobsClient, adminUser, adminPassword string, isSecure bool, s3AttachmentsBaseUrl string, ogImageService *ogimage.Service) *AdminHandler {
+func NewAdminHandler(db *sql.DB, queries *admindb.Queries, sobsClient *sobs.SobsClient, passkeys *passkey.Service, isSecure bool, s3AttachmentsBaseUrl string, ogImageService *ogimage.Service, websubPublisher *websub.Publisher, siteBaseUrl string, previewSigner *preview.Signer, views *templates.Registry) *AdminHandler {
 	return &AdminHandler{
+		db:                   db,
 		queries:              queries,
 		sobsClient:           sobsClient,
-		adminUser:            adminUser,
-		adminPassword:        adminPassword,
+		users:                adminuser.NewService(queries),
+		passkeys:             passkeys,
 		isSecure:             isSecure,
 		s3AttachmentsBaseUrl: s3AttachmentsBaseUrl,
 		ogImageService:       ogImageService,
+		linkService:          entrylink.NewService(db, queries),
+		searchService:        search.NewService(db, queries),
+		tagService:           entrytag.NewService(db, queries),
+		websubPublisher:      websubPublisher,
+		siteBaseUrl:          siteBaseUrl,
+		previewSigner:        previewSigner,
+		views:                views,
 	}
 }
 
+// errUpdateConflict is returned from a withTx callback when the optimistic
+// updated_at check matched no rows.
+var errUpdateConflict = errors.New("entry was updated by another request")
+
+// withTx runs fn with queries bound to a new transaction. The transaction is
+// committed when fn returns nil and rolled back otherwise.
+func (h *AdminHandler) withTx(ctx context.Context, fn func(q *admindb.Queries) error) error {
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(h.queries.WithTx(tx)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
 // getEntryPath extracts the entry path from query parameter
 // For /entries/edit?path=getting-started -> returns "getting-started"
 // For /entries/edit?path=2024/01/01/120000 -> returns "2024/01/01/120000"
@@ -54,6 +110,19 @@ func getEntryPath(c *gin.Context) string {
 	return c.Query("path")
 }
 
+// currentUserID returns the id of the signed-in admin user, which
+// GinSessionMiddleware puts in the gin context
+func currentUserID(c *gin.Context) sql.NullInt64 {
+	id := c.GetInt64("user_id")
+	return sql.NullInt64{Int64: id, Valid: id != 0}
+}
+
+// currentSessionID returns the id of the admin_session row the request
+// signed in with
+func currentSessionID(c *gin.Context) int64 {
+	return c.GetInt64("admin_session_id")
+}
+
 func simplifyMarkdown(text string) string {
 	// Remove newlines
 	text = strings.ReplaceAll(text, "\n", " ")
@@ -62,9 +131,9 @@ func simplifyMarkdown(text string) string {
 	re := regexp.MustCompile(`\[(.*?)\]\(.*?\)`)
 	text = re.ReplaceAllString(text, "$1")
 
-	// Remove wiki links [[text]] -> text
-	text = strings.ReplaceAll(text, "[[", "")
-	text = strings.ReplaceAll(text, "]]", "")
+	// Remove wiki links [[text]] -> text, [[text|label]] -> label
+	re = regexp.MustCompile(`!?\[\[(?:[^\]|]*\|)?(.*?)\]\]`)
+	text = re.ReplaceAllString(text, "$1")
 
 	// Remove inline code
 	re = regexp.MustCompile("`.*?`")
@@ -94,22 +163,33 @@ func simplifyMarkdown(text string) string {
 // RenderEntriesPage はエントリ一覧ページのシェルを返す。
 // 一覧データの取得・検索は Preact 側が /admin/api/entries (全件) を使って行う。
 func (h *AdminHandler) RenderEntriesPage(c *gin.Context) {
-	tmpl, err := template.ParseFiles(
-		"admin/templates/layout.html",
-		"admin/templates/entries.html",
-	)
+	tmpl, err := h.views.Lookup("entries.html")
 	if err != nil {
-		slog.Error("failed to parse template", slog.Any("error", err))
+		slog.Error("failed to load template", slog.Any("error", err))
 		c.String(500, "Internal Server Error")
 		return
 	}
 
 	c.Header("Content-Type", "text/html; charset=utf-8")
-	_ = tmpl.ExecuteTemplate(c.Writer, "layout", nil)
+	_ = tmpl.ExecuteTemplate(c.Writer, "layout", layoutData(c))
+}
+
+// RenderAccountPage displays the signed-in user's account settings
+func (h *AdminHandler) RenderAccountPage(c *gin.Context) {
+	tmpl, err := h.views.Lookup("account.html")
+	if err != nil {
+		slog.Error("failed to load template", slog.Any("error", err))
+		c.String(500, "Internal Server Error")
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	_ = tmpl.ExecuteTemplate(c.Writer, "layout", layoutData(c))
 }
 
 // EntryEditData holds data for the entry edit page
 type EntryEditData struct {
+	LayoutData
 	Path       string
 	Title      string
 	Body       string
@@ -123,19 +203,57 @@ func (h *AdminHandler) RenderEntryEditPage(c *gin.Context) {
 	path := getEntryPath(c)
 
 	entry, err := h.queries.AdminGetEntryByPath(c.Request.Context(), path)
+	if errors.Is(err, sql.ErrNoRows) {
+		c.String(404, "Entry not found")
+		return
+	}
 	if err != nil {
 		slog.Error("failed to get entry", slog.String("path", path), slog.Any("error", err))
-		c.String(404, "Entry not found")
+		c.String(500, "Internal Server Error")
+		return
+	}
+
+	// Saves from this page are merged against the revision it was opened on
+	// when another tab saved in between. Viewing the page writes nothing, so
+	// there may be no such revision until the page saves for the first time.
+	baseRevision, baseErr := revision.MergeBase(c.Request.Context(), h.queries, entry, time.Now())
+
+	tags, err := h.queries.GetEntryTags(c.Request.Context(), path)
+	if err != nil {
+		slog.Error("failed to get entry tags", slog.String("path", path), slog.Any("error", err))
+		c.String(500, "Internal Server Error")
 		return
 	}
 
 	// Build JSON data for Preact app
-	initData := map[string]string{
+	initData := map[string]any{
 		"path":       entry.Path,
 		"title":      entry.Title,
 		"body":       entry.Body,
 		"visibility": string(entry.Visibility),
 		"updated_at": entry.UpdatedAt.Time.Format(time.RFC3339Nano),
+		"tags":       append([]string{}, tags...),
+	}
+	if entry.PublishedAt.Valid {
+		initData["published_at"] = entry.PublishedAt.Time.Format(time.RFC3339)
+	}
+	if entry.AuthorID.Valid {
+		author, err := h.queries.GetAdminUser(c.Request.Context(), entry.AuthorID.Int64)
+		if err != nil {
+			slog.Error("failed to get author", slog.Int64("author_id", entry.AuthorID.Int64), slog.Any("error", err))
+		} else {
+			initData["author"] = author.Username
+		}
+	}
+	if baseErr != nil {
+		slog.Error("failed to get base revision", slog.String("path", path), slog.Any("error", baseErr))
+	} else if baseRevision != 0 {
+		initData["revision_id"] = baseRevision
+	}
+	if editSession, err := revision.NewEditSession(); err != nil {
+		slog.Error("failed to create edit session", slog.Any("error", err))
+	} else {
+		initData["edit_session"] = editSession
 	}
 	jsonBytes, err := json.Marshal(initData)
 	if err != nil {
@@ -145,6 +263,7 @@ func (h *AdminHandler) RenderEntryEditPage(c *gin.Context) {
 	}
 
 	data := EntryEditData{
+		LayoutData: layoutData(c),
 		Path:       entry.Path,
 		Title:      entry.Title,
 		Body:       entry.Body,
@@ -153,12 +272,9 @@ func (h *AdminHandler) RenderEntryEditPage(c *gin.Context) {
 		InitJSON:   template.JS(jsonBytes),
 	}
 
-	tmpl, err := template.ParseFiles(
-		"admin/templates/layout.html",
-		"admin/templates/entry_edit.html",
-	)
+	tmpl, err := h.views.Lookup("entry_edit.html")
 	if err != nil {
-		slog.Error("failed to parse template", slog.Any("error", err))
+		slog.Error("failed to load template", slog.Any("error", err))
 		c.String(500, "Internal Server Error")
 		return
 	}
@@ -167,6 +283,93 @@ func (h *AdminHandler) RenderEntryEditPage(c *gin.Context) {
 	_ = tmpl.ExecuteTemplate(c.Writer, "layout", data)
 }
 
+// NewEntryData holds data for the page confirming a new entry
+type NewEntryData struct {
+	LayoutData
+	Title string
+}
+
+// HandleNewEntry opens the entry titled ?title=, or asks whether to create it
+// when it does not exist yet. Missing [[wiki links]] in the preview point
+// here; the entry is only created by the confirming POST, so that following
+// or prefetching a link never writes.
+func (h *AdminHandler) HandleNewEntry(c *gin.Context) {
+	ctx := c.Request.Context()
+	title := strings.TrimSpace(c.Query("title"))
+	if title == "" {
+		c.Redirect(http.StatusFound, "/admin/entries/search")
+		return
+	}
+
+	path, err := h.queries.GetEntryPathByTitle(ctx, title)
+	if err == nil {
+		c.Redirect(http.StatusFound, "/admin/entries/edit?path="+url.QueryEscape(path))
+		return
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		slog.Error("failed to get entry by title", slog.String("title", title), slog.Any("error", err))
+		c.String(http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	tmpl, err := h.views.Lookup("entry_new.html")
+	if err != nil {
+		slog.Error("failed to load template", slog.Any("error", err))
+		c.String(500, "Internal Server Error")
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	_ = tmpl.ExecuteTemplate(c.Writer, "layout", NewEntryData{LayoutData: layoutData(c), Title: title})
+}
+
+// HandleCreateEntry creates the entry confirmed on the new entry page and
+// opens it. An entry created with that title in the meantime is opened
+// instead.
+func (h *AdminHandler) HandleCreateEntry(c *gin.Context) {
+	ctx := c.Request.Context()
+	now := time.Now()
+	title := strings.TrimSpace(c.PostForm("title"))
+	if title == "" {
+		c.Redirect(http.StatusSeeOther, "/admin/entries/search")
+		return
+	}
+
+	path, err := h.queries.GetEntryPathByTitle(ctx, title)
+	if err == nil {
+		c.Redirect(http.StatusSeeOther, "/admin/entries/edit?path="+url.QueryEscape(path))
+		return
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		slog.Error("failed to get entry by title", slog.String("title", title), slog.Any("error", err))
+		c.String(http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	path = now.Format("2006/01/02/150405")
+	err = h.withTx(ctx, func(q *admindb.Queries) error {
+		if _, err := q.CreateEmptyEntry(ctx, admindb.CreateEmptyEntryParams{
+			Path:     path,
+			Title:    title,
+			AuthorID: currentUserID(c),
+		}); err != nil {
+			return err
+		}
+		if _, err := revision.Checkpoint(ctx, q, path, now); err != nil {
+			return err
+		}
+		return search.ReindexEntry(ctx, q, path)
+	})
+	if err != nil {
+		slog.Error("failed to create entry", slog.String("title", title), slog.Any("error", err))
+		c.String(http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	slog.Info("created entry from wiki link", slog.String("title", title), slog.String("path", path))
+	c.Redirect(http.StatusSeeOther, "/admin/entries/edit?path="+url.QueryEscape(path))
+}
+
 // HandleShareTarget handles Web Share Target API requests from Android
 func (h *AdminHandler) HandleShareTarget(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -177,6 +380,13 @@ func (h *AdminHandler) HandleShareTarget(c *gin.Context) {
 	sharedText := c.PostForm("text")
 	sharedURL := c.PostForm("url")
 
+	// The share sheet opens this page itself (Sec-Fetch-Site: none) and
+	// cannot send a CSRF token; any other post without one is confirmed first
+	if c.GetHeader("Sec-Fetch-Site") != "none" && !hasCSRFToken(c) {
+		h.renderShareConfirm(c, sharedTitle, sharedText, sharedURL)
+		return
+	}
+
 	// Generate unique title
 	var title string
 	if sharedTitle == "" {
@@ -200,11 +410,26 @@ func (h *AdminHandler) HandleShareTarget(c *gin.Context) {
 	// Generate path based on current time
 	path := now.Format("2006/01/02/150405")
 
-	// Create entry with body
-	_, err := h.queries.CreateEntryWithBody(ctx, admindb.CreateEntryWithBodyParams{
-		Path:  path,
-		Title: title,
-		Body:  body,
+	// Create entry with body and record its wiki links
+	err := h.withTx(ctx, func(q *admindb.Queries) error {
+		if _, err := q.CreateEntryWithBody(ctx, admindb.CreateEntryWithBodyParams{
+			Path:     path,
+			Title:    title,
+			Body:     body,
+			AuthorID: currentUserID(c),
+		}); err != nil {
+			return err
+		}
+		if err := entrylink.ReplaceLinks(ctx, q, path, body); err != nil {
+			return err
+		}
+		if err := entrytag.ReplaceBodyTags(ctx, q, path, body); err != nil {
+			return err
+		}
+		if _, err := revision.Checkpoint(ctx, q, path, now); err != nil {
+			return err
+		}
+		return search.ReindexEntry(ctx, q, path)
 	})
 	if err != nil {
 		slog.Error("failed to create shared entry",
@@ -213,9 +438,9 @@ func (h *AdminHandler) HandleShareTarget(c *gin.Context) {
 			slog.Any("error", err))
 
 		// Render error template
-		tmpl, tmplErr := template.ParseFiles("admin/templates/share_error.html")
+		tmpl, tmplErr := h.views.Lookup("share_error.html")
 		if tmplErr != nil {
-			slog.Error("failed to parse share error template", slog.Any("error", tmplErr))
+			slog.Error("failed to load share error template", slog.Any("error", tmplErr))
 			c.String(500, "Failed to save shared content")
 			return
 		}
@@ -241,11 +466,33 @@ func (h *AdminHandler) HandleShareTarget(c *gin.Context) {
 	c.Redirect(http.StatusSeeOther, "/admin/entries/edit?path="+url.QueryEscape(path))
 }
 
+// renderShareConfirm asks before saving shared content that may have been
+// posted by another site
+func (h *AdminHandler) renderShareConfirm(c *gin.Context, title, text, sharedURL string) {
+	tmpl, err := h.views.Lookup("share_confirm.html")
+	if err != nil {
+		slog.Error("failed to load share confirm template", slog.Any("error", err))
+		c.String(500, "Internal Server Error")
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	if err := tmpl.Execute(c.Writer, gin.H{
+		"title":      title,
+		"text":       text,
+		"url":        sharedURL,
+		"csrfToken":  c.GetString("csrf_token"),
+		"entriesUrl": "/admin/entries/search",
+	}); err != nil {
+		slog.Error("failed to execute share confirm template", slog.Any("error", err))
+	}
+}
+
 // RenderLoginPage displays the login page
 func (h *AdminHandler) RenderLoginPage(c *gin.Context) {
-	tmpl, err := template.ParseFiles("admin/templates/login.html")
+	tmpl, err := h.views.Lookup("login.html")
 	if err != nil {
-		slog.Error("failed to parse login template", slog.Any("error", err))
+		slog.Error("failed to load login template", slog.Any("error", err))
 		c.String(500, "Internal Server Error")
 		return
 	}
@@ -259,6 +506,21 @@ func (h *AdminHandler) RenderLoginPage(c *gin.Context) {
 	}
 }
 
+// HandleLogout ends the current session and returns to the login page
+func (h *AdminHandler) HandleLogout(c *gin.Context) {
+	if sessionID := getSessionID(c.Request); sessionID != "" {
+		if err := h.queries.DeleteSession(c.Request.Context(), sessionID); err != nil {
+			slog.Error("failed to delete session", slog.Any("error", err))
+			c.String(500, "Internal Server Error")
+			return
+		}
+	}
+	clearSessionCookie(c.Writer, h.isSecure)
+	slog.Info("user logged out", slog.String("username", c.GetString("username")))
+
+	c.Redirect(http.StatusSeeOther, "/admin/login")
+}
+
 // UploadEntryImage handles image uploads from paste/drag-drop
 func (h *AdminHandler) UploadEntryImage(c *gin.Context) {
 	// Get uploaded file
@@ -4,15 +4,24 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"io/fs"
 	"log/slog"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/tokuhirom/blog4/internal"
 	"github.com/tokuhirom/blog4/internal/ogimage"
+	"github.com/tokuhirom/blog4/internal/passkey"
+	"github.com/tokuhirom/blog4/internal/public"
+	"github.com/tokuhirom/blog4/internal/schedule"
 	"github.com/tokuhirom/blog4/internal/sobs"
+	"github.com/tokuhirom/blog4/internal/sweep"
+	"github.com/tokuhirom/blog4/internal/templates"
+	"github.com/tokuhirom/blog4/internal/websub"
 
 	"github.com/tokuhirom/blog4/db/admin/admindb"
 )
@@ -76,22 +85,62 @@ func GinSessionMiddleware(queries *admindb.Queries) gin.HandlerFunc {
 			return
 		}
 
-		// Update last accessed time in background
-		go func() {
-			ctx := context.Background()
-			if err := queries.UpdateSessionLastAccessed(ctx, sessionID); err != nil {
+		// Record activity, but only once per sessionTouchInterval
+		if needsTouch(session.LastAccessedAt, time.Now()) {
+			if err := queries.UpdateSessionLastAccessed(c.Request.Context(), sessionID); err != nil {
 				slog.Error("Failed to update session last accessed", slog.String("error", err.Error()))
 			}
-		}()
+		}
 
-		// Add username to gin context
+		// Add the signed-in user and their session to gin context
+		c.Set("user_id", session.UserID)
 		c.Set("username", session.Username)
+		c.Set("admin_session_id", session.ID)
+		c.Set("csrf_token", csrfToken(sessionID))
 		c.Next()
 	}
 }
 
-// SetupAdminRoutes configures admin routes on the given router group
-func SetupAdminRoutes(adminGroup *gin.RouterGroup, queries *admindb.Queries, sobsClient *sobs.SobsClient, cfg internal.Config) {
+// NewTemplates parses the admin pages' templates from assets, the tree
+// returned by admin.FS.
+func NewTemplates(assets fs.FS, reload bool) (*templates.Registry, error) {
+	return templates.New(assets, map[string][]string{
+		"entries.html":       {"templates/layout.html", "templates/entries.html"},
+		"entry_edit.html":    {"templates/layout.html", "templates/entry_edit.html"},
+		"account.html":       {"templates/layout.html", "templates/account.html"},
+		"share_error.html":   {"templates/share_error.html"},
+		"share_confirm.html": {"templates/share_confirm.html"},
+		"login.html":         {"templates/login.html"},
+	}, reload)
+}
+
+// staticFS serves the dir subtree of assets without directory listings, as
+// gin's Static does for a directory on disk.
+func staticFS(assets fs.FS, dir string) (http.FileSystem, error) {
+	sub, err := fs.Sub(assets, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", dir, err)
+	}
+	return &gin.OnlyFilesFS{FileSystem: http.FS(sub)}, nil
+}
+
+// SetupAdminRoutes configures admin routes on the given router group. assets
+// is the tree returned by admin.FS; its templates are reloaded on change in
+// local development.
+func SetupAdminRoutes(adminGroup *gin.RouterGroup, db *sql.DB, queries *admindb.Queries, sobsClient *sobs.SobsClient, cfg internal.Config, assets fs.FS) error {
+	views, err := NewTemplates(assets, cfg.LocalDev)
+	if err != nil {
+		return fmt.Errorf("failed to load admin templates: %w", err)
+	}
+	static, err := staticFS(assets, "static")
+	if err != nil {
+		return err
+	}
+	icons, err := staticFS(assets, "static/icons")
+	if err != nil {
+		return err
+	}
+
 	// Initialize OG image service
 	var ogImageService *ogimage.Service
 	if cfg.OGImageEnabled {
@@ -106,21 +155,45 @@ func SetupAdminRoutes(adminGroup *gin.RouterGroup, queries *admindb.Queries, sob
 		ogImageService = ogimage.NewService(ogGenerator, queries)
 	}
 
+	// Hubs cannot fetch a feed served from a developer's machine, so only ping them in production
+	var websubPublisher *websub.Publisher
+	if !cfg.LocalDev {
+		var topics []string
+		for _, feedPath := range public.FeedPaths() {
+			topics = append(topics, strings.TrimSuffix(cfg.SiteBaseUrl, "/")+feedPath)
+		}
+		websubPublisher = websub.NewPublisher(cfg.GetHubUrls(), topics...)
+	}
+
+	passkeys, err := passkey.NewService(queries, cfg.GetAdminOrigin(), cfg.SiteName)
+	if err != nil {
+		return err
+	}
+
 	// Create handler
-	handler := NewAdminHandler(queries, sobsClient, cfg.AdminUser, cfg.AdminPassword, !cfg.LocalDev, cfg.S3AttachmentsBaseUrl, ogImageService)
+	handler := NewAdminHandler(db, queries, sobsClient, passkeys, !cfg.LocalDev, cfg.S3AttachmentsBaseUrl, ogImageService, websubPublisher, cfg.SiteBaseUrl, cfg.PreviewSigner(), views)
+
+	// Publish scheduled entries with the same side effects as the visibility API
+	go schedule.NewScheduler(queries, handler.onPublished).Run(context.Background())
+	// Delete expired sessions and login state
+	go sweep.NewSweeper(queries).Run(context.Background())
 
 	// Login page (no session middleware needed)
 	adminGroup.GET("/login", handler.RenderLoginPage)
 	adminGroup.POST("/api/login", handler.APILogin)
+	adminGroup.POST("/api/login/totp", handler.APILoginTOTP)
+	adminGroup.POST("/api/webauthn/login/begin", handler.APIBeginPasskeyLogin)
+	adminGroup.POST("/api/webauthn/login/finish", handler.APIFinishPasskeyLogin)
 
 	// PWA files (served before session middleware for accessibility)
-	adminGroup.StaticFile("/manifest.webmanifest", "admin/manifest.webmanifest")
-	adminGroup.StaticFile("/sw.js", "admin/static/sw.js")
-	adminGroup.Static("/icons", "admin/static/icons/")
+	adminGroup.StaticFileFS("/manifest.webmanifest", "manifest.webmanifest", http.FS(assets))
+	adminGroup.StaticFileFS("/sw.js", "static/sw.js", http.FS(assets))
+	adminGroup.StaticFS("/icons", icons)
 
 	// Add middlewares for authenticated routes
 	adminGroup.Use(NoCacheMiddleware())
 	adminGroup.Use(GinSessionMiddleware(queries))
+	adminGroup.Use(CSRFMiddleware())
 
 	// Web Share Target endpoint (requires authentication)
 	adminGroup.POST("/share-target", handler.HandleShareTarget)
@@ -139,6 +212,12 @@ func SetupAdminRoutes(adminGroup *gin.RouterGroup, queries *admindb.Queries, sob
 	// Entry routes with query parameter (supports both slug and date-based paths)
 	// Examples: /entries/edit?path=getting-started, /entries/edit?path=2024/01/01/120000
 	adminGroup.GET("/entries/edit", handler.RenderEntryEditPage)
+	adminGroup.GET("/entries/new", handler.HandleNewEntry)
+	adminGroup.POST("/entries/new", handler.HandleCreateEntry)
+
+	// Account settings of the signed-in user
+	adminGroup.GET("/account", handler.RenderAccountPage)
+	adminGroup.POST("/logout", handler.HandleLogout)
 
 	// JSON API routes (used by Preact apps)
 	adminGroup.GET("/api/entries", handler.APIListEntries)
@@ -146,11 +225,35 @@ func SetupAdminRoutes(adminGroup *gin.RouterGroup, queries *admindb.Queries, sob
 	adminGroup.PUT("/api/entries/title", handler.APIUpdateTitle)
 	adminGroup.PUT("/api/entries/body", handler.APIUpdateBody)
 	adminGroup.PUT("/api/entries/visibility", handler.APIUpdateVisibility)
+	adminGroup.PUT("/api/entries/schedule", handler.APIScheduleEntry)
+	adminGroup.GET("/api/entries/previews", handler.APIListPreviewLinks)
+	adminGroup.POST("/api/entries/previews", handler.APICreatePreviewLink)
+	adminGroup.DELETE("/api/entries/previews", handler.APIRevokePreviewLink)
+	adminGroup.GET("/api/entries/revisions", handler.APIListRevisions)
+	adminGroup.GET("/api/entries/revisions/diff", handler.APIDiffRevisions)
+	adminGroup.POST("/api/entries/revisions/restore", handler.APIRestoreRevision)
+	adminGroup.PUT("/api/entries/tags", handler.APIUpdateTags)
 	adminGroup.DELETE("/api/entries/delete", handler.APIDeleteEntry)
 	adminGroup.POST("/api/entries/image/regenerate", handler.APIRegenerateEntryImage)
+	adminGroup.GET("/api/entries/links", handler.APIGetEntryLinks)
+	adminGroup.POST("/api/entries/links/rebuild", handler.APIRebuildEntryLinks)
+	adminGroup.POST("/api/search/rebuild", handler.APIRebuildSearchIndex)
+	adminGroup.GET("/api/tags", handler.APISearchTags)
+	adminGroup.POST("/api/tags/rebuild", handler.APIRebuildBodyTags)
 	adminGroup.POST("/api/entries/preview", handler.APIPreviewMarkdown)
 	adminGroup.POST("/api/entries/upload", handler.UploadEntryImage)
+	adminGroup.GET("/api/account/totp", handler.APIGetTOTPStatus)
+	adminGroup.POST("/api/account/totp/setup", handler.APISetupTOTP)
+	adminGroup.POST("/api/account/totp/enable", handler.APIEnableTOTP)
+	adminGroup.POST("/api/account/totp/disable", handler.APIDisableTOTP)
+	adminGroup.GET("/api/account/sessions", handler.APIListSessions)
+	adminGroup.DELETE("/api/account/sessions", handler.APIRevokeSession)
+	adminGroup.POST("/api/webauthn/register/begin", handler.APIBeginPasskeyRegistration)
+	adminGroup.POST("/api/webauthn/register/finish", handler.APIFinishPasskeyRegistration)
+	adminGroup.GET("/api/webauthn/credentials", handler.APIListPasskeys)
+	adminGroup.DELETE("/api/webauthn/credentials", handler.APIDeletePasskey)
 
 	// Static files
-	adminGroup.Static("/static", "admin/static/")
+	adminGroup.StaticFS("/static", static)
+	return nil
 }
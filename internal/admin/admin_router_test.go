@@ -0,0 +1,37 @@
+package admin
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+
+	adminassets "github.com/tokuhirom/blog4/admin"
+)
+
+// TestEmbeddedAssets checks that the embedded admin templates parse and that
+// the files served outside of templates are embedded too.
+func TestEmbeddedAssets(t *testing.T) {
+	assets := adminassets.FS(false)
+
+	views, err := NewTemplates(assets, false)
+	if err != nil {
+		t.Fatalf("NewTemplates() error = %v", err)
+	}
+	tmpl, err := views.Lookup("entries.html")
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	var out strings.Builder
+	if err := tmpl.ExecuteTemplate(&out, "layout", LayoutData{CSRFToken: "token"}); err != nil {
+		t.Fatalf("ExecuteTemplate() error = %v", err)
+	}
+	if !strings.Contains(out.String(), `<meta name="csrf-token" content="token">`) {
+		t.Error("layout should carry the CSRF token for the Preact apps")
+	}
+
+	for _, name := range []string{"manifest.webmanifest", "static/sw.js", "static/admin.css", "static/icons/icon-192.png"} {
+		if _, err := fs.Stat(assets, name); err != nil {
+			t.Errorf("%s is not embedded: %v", name, err)
+		}
+	}
+}
@@ -1,32 +1,61 @@
 package admin
 
 import (
+	"bytes"
 	"context"
-	"crypto/subtle"
 	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/png"
 	"log/slog"
 	"net/http"
 	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-sql-driver/mysql"
 
 	"github.com/tokuhirom/blog4/internal"
+	"github.com/tokuhirom/blog4/internal/adminuser"
+	"github.com/tokuhirom/blog4/internal/entrylink"
+	"github.com/tokuhirom/blog4/internal/entrytag"
 	"github.com/tokuhirom/blog4/internal/markdown"
+	"github.com/tokuhirom/blog4/internal/passkey"
+	"github.com/tokuhirom/blog4/internal/preview"
+	"github.com/tokuhirom/blog4/internal/revision"
+	"github.com/tokuhirom/blog4/internal/search"
 
 	"github.com/tokuhirom/blog4/db/admin/admindb"
 )
 
+// mysqlErrDupEntry is ER_DUP_ENTRY, a unique key violation
+const mysqlErrDupEntry = 1062
+
+// maxEditSessionLength is the width of entry_revision.edit_session
+const maxEditSessionLength = 32
+
 // APIUpdateTitleRequest is the JSON request body for updating entry title
 type APIUpdateTitleRequest struct {
-	Title     string `json:"title"`
-	UpdatedAt string `json:"updated_at"`
+	Title        string `json:"title"`
+	UpdatedAt    string `json:"updated_at"`
+	RewriteLinks bool   `json:"rewrite_links"`
+	EditSession  string `json:"edit_session"`
 }
 
-// APIUpdateBodyRequest is the JSON request body for updating entry body
+// APIUpdateBodyRequest is the JSON request body for updating entry body.
+// BaseRevision is the revision the editor's body was derived from; when
+// someone else saved in between, the edit is merged with theirs against it.
 type APIUpdateBodyRequest struct {
-	Body      string `json:"body"`
-	UpdatedAt string `json:"updated_at"`
+	Body         string `json:"body"`
+	UpdatedAt    string `json:"updated_at"`
+	BaseRevision int64  `json:"base_revision"`
+	EditSession  string `json:"edit_session"`
 }
 
 // APIUpdateVisibilityRequest is the JSON request body for updating entry visibility
@@ -43,7 +72,16 @@ type APIResponse struct {
 	Redirect  string `json:"redirect,omitempty"`
 }
 
-// APIUpdateTitle updates the entry title and returns JSON
+// APIUpdateTitleResponse is the JSON response for updating entry title.
+// RewrittenEntries lists the entries whose [[links]] were rewritten to the new title.
+type APIUpdateTitleResponse struct {
+	APIResponse
+	RevisionID       int64            `json:"revision_id,omitempty"`
+	RewrittenEntries []APILinkedEntry `json:"rewritten_entries,omitempty"`
+}
+
+// APIUpdateTitle updates the entry title and returns JSON. When rewrite_links
+// is set, [[Old Title]] links in other entries are rewritten in the same transaction.
 func (h *AdminHandler) APIUpdateTitle(c *gin.Context) {
 	path := getEntryPath(c)
 
@@ -57,6 +95,16 @@ func (h *AdminHandler) APIUpdateTitle(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, APIResponse{Error: "Title cannot be empty"})
 		return
 	}
+	// Rewritten links would otherwise be committed in a form that no longer
+	// points here
+	if req.RewriteLinks && !markdown.IsLinkableTitle(req.Title) {
+		c.JSON(http.StatusBadRequest, APIResponse{Error: "Links cannot be updated to a title containing [[, ]], |, # or line breaks, or starting or ending with spaces"})
+		return
+	}
+	if len(req.EditSession) > maxEditSessionLength {
+		c.JSON(http.StatusBadRequest, APIResponse{Error: "Invalid edit_session"})
+		return
+	}
 
 	updatedAt, err := time.Parse(time.RFC3339Nano, req.UpdatedAt)
 	if err != nil {
@@ -65,36 +113,141 @@ func (h *AdminHandler) APIUpdateTitle(c *gin.Context) {
 		return
 	}
 
-	rows, err := h.queries.UpdateEntryTitle(c.Request.Context(), admindb.UpdateEntryTitleParams{
-		Title:     req.Title,
-		Path:      path,
-		UpdatedAt: sql.NullTime{Time: updatedAt, Valid: true},
+	ctx := c.Request.Context()
+	var rewritten []entrylink.Node
+	var revisionID int64
+	err = h.withTx(ctx, func(q *admindb.Queries) error {
+		var oldTitle string
+		if req.RewriteLinks {
+			title, err := q.GetEntryTitleForUpdate(ctx, path)
+			if errors.Is(err, sql.ErrNoRows) {
+				return errUpdateConflict
+			}
+			if err != nil {
+				return err
+			}
+			oldTitle = title
+		}
+
+		rows, err := q.UpdateEntryTitle(ctx, admindb.UpdateEntryTitleParams{
+			Title:     req.Title,
+			Path:      path,
+			UpdatedAt: sql.NullTime{Time: updatedAt, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return errUpdateConflict
+		}
+
+		if req.RewriteLinks && oldTitle != req.Title {
+			rewritten, err = entrylink.RewriteInboundLinks(ctx, q, path, oldTitle, req.Title)
+			if err != nil {
+				return err
+			}
+			for _, node := range rewritten {
+				if _, err := revision.Checkpoint(ctx, q, node.Path, time.Now()); err != nil {
+					return err
+				}
+				if err := search.ReindexEntry(ctx, q, node.Path); err != nil {
+					return err
+				}
+			}
+		}
+		revisionID, err = revision.Record(ctx, q, path, req.EditSession, time.Now())
+		if err != nil {
+			return err
+		}
+		return search.ReindexEntry(ctx, q, path)
 	})
+	if errors.Is(err, errUpdateConflict) {
+		c.JSON(http.StatusConflict, APIResponse{Error: "他のタブで更新されています。ページをリロードしてください。"})
+		return
+	}
+	if errors.Is(err, entrylink.ErrEntryChanged) {
+		c.JSON(http.StatusConflict, APIResponse{Error: "リンク元のエントリが更新されています。もう一度お試しください。"})
+		return
+	}
 	if err != nil {
 		slog.Error("failed to update title", slog.String("path", path), slog.Any("error", err))
 		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to update title"})
 		return
 	}
-	if rows == 0 {
-		c.JSON(http.StatusConflict, APIResponse{Error: "他のタブで更新されています。ページをリロードしてください。"})
-		return
+
+	resp := APIUpdateTitleResponse{
+		APIResponse: APIResponse{
+			OK:      true,
+			Message: "Title updated!",
+		},
+		RevisionID:       revisionID,
+		RewrittenEntries: toAPILinkedEntries(rewritten),
+	}
+	if len(rewritten) > 0 {
+		resp.Message = fmt.Sprintf("Title updated! Rewrote links in %d entries.", len(rewritten))
 	}
 
 	entry, err := h.queries.AdminGetEntryByPath(c.Request.Context(), path)
 	if err != nil {
 		slog.Error("failed to get entry after update", slog.String("path", path), slog.Any("error", err))
-		c.JSON(http.StatusOK, APIResponse{OK: true, Message: "Title updated!"})
+		c.JSON(http.StatusOK, resp)
 		return
 	}
 
-	c.JSON(http.StatusOK, APIResponse{
-		OK:        true,
-		UpdatedAt: entry.UpdatedAt.Time.Format(time.RFC3339Nano),
-		Message:   "Title updated!",
-	})
+	resp.UpdatedAt = entry.UpdatedAt.Time.Format(time.RFC3339Nano)
+	c.JSON(http.StatusOK, resp)
+}
+
+// APIUpdateBodyResponse is the JSON response for updating entry body.
+// RevisionID is the revision the saved body is in, the base of the next save.
+// When the save was merged with someone else's, Body is the merged body.
+// Conflict is set, with status 409, when the merge needs a decision.
+type APIUpdateBodyResponse struct {
+	APIResponse
+	RevisionID int64            `json:"revision_id,omitempty"`
+	Merged     bool             `json:"merged,omitempty"`
+	Body       string           `json:"body,omitempty"`
+	Conflict   *APIBodyConflict `json:"conflict,omitempty"`
+}
+
+// APIBodyConflict describes a save that could not be merged. The editor
+// resolves the conflicting chunks and saves again on top of UpdatedAt and
+// RevisionID, which are those of the body currently stored.
+type APIBodyConflict struct {
+	UpdatedAt       string          `json:"updated_at"`
+	RevisionID      int64           `json:"revision_id"`
+	Chunks          []APIMergeChunk `json:"chunks"`
+	TrailingNewline bool            `json:"trailing_newline"`
+}
+
+// APIMergeChunk is a run of lines of a merged body. Conflicting chunks carry
+// the lines of the base, of the stored body (current) and of the save
+// (incoming) instead of Lines.
+type APIMergeChunk struct {
+	Lines    []string `json:"lines,omitempty"`
+	Conflict bool     `json:"conflict,omitempty"`
+	Base     []string `json:"base,omitempty"`
+	Current  []string `json:"current,omitempty"`
+	Incoming []string `json:"incoming,omitempty"`
+}
+
+func toAPIMergeChunks(chunks []revision.Chunk) []APIMergeChunk {
+	result := make([]APIMergeChunk, 0, len(chunks))
+	for _, c := range chunks {
+		result = append(result, APIMergeChunk{
+			Lines:    c.Lines,
+			Conflict: c.Conflict,
+			Base:     c.Base,
+			Current:  c.Current,
+			Incoming: c.Incoming,
+		})
+	}
+	return result
 }
 
-// APIUpdateBody updates the entry body and returns JSON
+// APIUpdateBody updates the entry body and returns JSON. When the entry was
+// saved elsewhere since updated_at, the body is merged three-way with the
+// stored one against base_revision, and saved if nothing conflicts.
 func (h *AdminHandler) APIUpdateBody(c *gin.Context) {
 	path := getEntryPath(c)
 
@@ -108,6 +261,10 @@ func (h *AdminHandler) APIUpdateBody(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, APIResponse{Error: "Body cannot be empty"})
 		return
 	}
+	if len(req.EditSession) > maxEditSessionLength {
+		c.JSON(http.StatusBadRequest, APIResponse{Error: "Invalid edit_session"})
+		return
+	}
 
 	updatedAt, err := time.Parse(time.RFC3339Nano, req.UpdatedAt)
 	if err != nil {
@@ -116,33 +273,144 @@ func (h *AdminHandler) APIUpdateBody(c *gin.Context) {
 		return
 	}
 
-	rows, err := h.queries.UpdateEntryBody(c.Request.Context(), admindb.UpdateEntryBodyParams{
-		Body:      req.Body,
-		Path:      path,
-		UpdatedAt: sql.NullTime{Time: updatedAt, Valid: true},
+	ctx := c.Request.Context()
+	resp := APIUpdateBodyResponse{APIResponse: APIResponse{OK: true, Message: "Body updated!"}}
+	err = h.withTx(ctx, func(q *admindb.Queries) error {
+		body := req.Body
+		rows, err := q.UpdateEntryBody(ctx, admindb.UpdateEntryBodyParams{
+			Body:      body,
+			Path:      path,
+			UpdatedAt: sql.NullTime{Time: updatedAt, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			if req.BaseRevision == 0 {
+				return errUpdateConflict
+			}
+			body, err = mergeBody(ctx, q, path, req.BaseRevision, req.Body, &resp)
+			if err != nil || resp.Conflict != nil {
+				return err
+			}
+		}
+		if err := entrylink.ReplaceLinks(ctx, q, path, body); err != nil {
+			return err
+		}
+		if err := entrytag.ReplaceBodyTags(ctx, q, path, body); err != nil {
+			return err
+		}
+		resp.RevisionID, err = revision.Record(ctx, q, path, req.EditSession, time.Now())
+		if err != nil {
+			return err
+		}
+		return search.ReindexEntry(ctx, q, path)
 	})
+	if errors.Is(err, errUpdateConflict) {
+		c.JSON(http.StatusConflict, APIResponse{Error: "他のタブで更新されています。ページをリロードしてください。"})
+		return
+	}
 	if err != nil {
 		slog.Error("failed to update body", slog.String("path", path), slog.Any("error", err))
 		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to update body"})
 		return
 	}
-	if rows == 0 {
-		c.JSON(http.StatusConflict, APIResponse{Error: "他のタブで更新されています。ページをリロードしてください。"})
+	if resp.Conflict != nil {
+		c.JSON(http.StatusConflict, APIUpdateBodyResponse{
+			APIResponse: APIResponse{Error: "他のタブでの変更と衝突しました。どちらを残すか選んでください。"},
+			Conflict:    resp.Conflict,
+		})
 		return
 	}
 
 	entry, err := h.queries.AdminGetEntryByPath(c.Request.Context(), path)
 	if err != nil {
 		slog.Error("failed to get entry after update", slog.String("path", path), slog.Any("error", err))
-		c.JSON(http.StatusOK, APIResponse{OK: true, Message: "Body updated!"})
+		c.JSON(http.StatusOK, resp)
 		return
 	}
 
-	c.JSON(http.StatusOK, APIResponse{
-		OK:        true,
-		UpdatedAt: entry.UpdatedAt.Time.Format(time.RFC3339Nano),
-		Message:   "Body updated!",
-	})
+	if h.websubPublisher != nil && entry.Visibility == admindb.EntryVisibilityPublic {
+		h.websubPublisher.Schedule()
+	}
+
+	resp.UpdatedAt = entry.UpdatedAt.Time.Format(time.RFC3339Nano)
+	c.JSON(http.StatusOK, resp)
+}
+
+// mergeBody merges incoming, an edit of the revision baseID, with the body
+// stored since, and saves the result. When the edits conflict nothing is
+// saved and resp.Conflict describes the conflict instead.
+func mergeBody(ctx context.Context, q *admindb.Queries, path string, baseID int64, incoming string, resp *APIUpdateBodyResponse) (string, error) {
+	base, err := q.GetEntryRevision(ctx, admindb.GetEntryRevisionParams{ID: baseID, Path: path})
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", errUpdateConflict
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get base revision %d: %w", baseID, err)
+	}
+	current, err := q.AdminGetEntryByPath(ctx, path)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", errUpdateConflict
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get entry: %w", err)
+	}
+
+	result := revision.Merge(base.Body, current.Body, incoming)
+	if result.Conflicts() > 0 {
+		// The editor resolves on top of the stored body, so give it a
+		// revision that stays as it is
+		currentRevision, err := revision.Checkpoint(ctx, q, path, time.Now())
+		if err != nil {
+			return "", err
+		}
+		resp.Conflict = &APIBodyConflict{
+			UpdatedAt:       current.UpdatedAt.Time.Format(time.RFC3339Nano),
+			RevisionID:      currentRevision,
+			Chunks:          toAPIMergeChunks(result.Chunks),
+			TrailingNewline: result.TrailingNewline,
+		}
+		return "", nil
+	}
+
+	merged := result.Text()
+	if merged != current.Body {
+		rows, err := q.UpdateEntryBody(ctx, admindb.UpdateEntryBodyParams{
+			Body:      merged,
+			Path:      path,
+			UpdatedAt: current.UpdatedAt,
+		})
+		if err != nil {
+			return "", err
+		}
+		if rows == 0 {
+			return "", errUpdateConflict
+		}
+	}
+	resp.Merged = true
+	resp.Body = merged
+	resp.Message = "Body merged with changes from another tab!"
+	return merged, nil
+}
+
+// onPublished fires the side effects of an entry becoming public: hubs are
+// told that the feeds changed and the OG image is generated in the background.
+// The scheduler calls it too, so scheduled entries get the same treatment.
+func (h *AdminHandler) onPublished(path string) {
+	if h.websubPublisher != nil {
+		h.websubPublisher.Schedule()
+	}
+
+	if h.ogImageService != nil {
+		go func() {
+			ctx := context.Background()
+			if err := h.ogImageService.EnsureOGImage(ctx, path); err != nil {
+				slog.Error("failed to ensure OG image",
+					slog.String("path", path), slog.Any("error", err))
+			}
+		}()
+	}
 }
 
 // APIUpdateVisibility updates the entry visibility and returns JSON
@@ -159,6 +427,11 @@ func (h *AdminHandler) APIUpdateVisibility(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, APIResponse{Error: "Visibility is required"})
 		return
 	}
+	if req.Visibility != string(admindb.EntryVisibilityPrivate) && req.Visibility != string(admindb.EntryVisibilityPublic) {
+		// Scheduling needs a publish time, so it has its own endpoint
+		c.JSON(http.StatusBadRequest, APIResponse{Error: "Visibility must be private or public"})
+		return
+	}
 
 	entry, err := h.queries.GetEntryVisibility(c.Request.Context(), path)
 	if err != nil {
@@ -181,25 +454,14 @@ func (h *AdminHandler) APIUpdateVisibility(c *gin.Context) {
 		return
 	}
 
-	if entry.Visibility == "private" && req.Visibility == "public" {
-		if !entry.PublishedAt.Valid {
-			if err := h.queries.UpdatePublishedAt(c.Request.Context(), path); err != nil {
-				slog.Error("failed to update published_at", slog.String("path", path), slog.Any("error", err))
-				c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to update published_at"})
-				return
-			}
-			slog.Info("updated published_at for newly public entry", slog.String("path", path))
-		}
-
-		if h.ogImageService != nil {
-			go func() {
-				ctx := context.Background()
-				if err := h.ogImageService.EnsureOGImage(ctx, path); err != nil {
-					slog.Error("failed to ensure OG image",
-						slog.String("path", path), slog.Any("error", err))
-				}
-			}()
+	if entry.Visibility != admindb.EntryVisibilityPublic && req.Visibility == "public" {
+		// Publishing a scheduled entry early moves its published_at up to now
+		if err := h.queries.UpdatePublishedAt(c.Request.Context(), path); err != nil {
+			slog.Error("failed to update published_at", slog.String("path", path), slog.Any("error", err))
+			c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to update published_at"})
+			return
 		}
+		h.onPublished(path)
 	}
 
 	c.JSON(http.StatusOK, APIResponse{
@@ -208,167 +470,814 @@ func (h *AdminHandler) APIUpdateVisibility(c *gin.Context) {
 	})
 }
 
-// APIDeleteEntry deletes an entry and returns JSON
-func (h *AdminHandler) APIDeleteEntry(c *gin.Context) {
+// APIScheduleEntryRequest is the JSON request body for scheduling an entry
+type APIScheduleEntryRequest struct {
+	PublishAt string `json:"publish_at"` // RFC 3339
+}
+
+// APIScheduleEntry hides the entry until publish_at, when the scheduler makes
+// it public. Rescheduling an entry that is already scheduled moves the time.
+func (h *AdminHandler) APIScheduleEntry(c *gin.Context) {
 	path := getEntryPath(c)
 
-	rows, err := h.queries.DeleteEntry(c.Request.Context(), path)
+	var req APIScheduleEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{Error: "Invalid request body"})
+		return
+	}
+
+	publishAt, err := time.Parse(time.RFC3339, req.PublishAt)
 	if err != nil {
-		slog.Error("failed to delete entry", slog.String("path", path), slog.Any("error", err))
-		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to delete entry"})
+		c.JSON(http.StatusBadRequest, APIResponse{Error: "publish_at must be an RFC 3339 timestamp"})
 		return
 	}
-	if rows == 0 {
-		c.JSON(http.StatusNotFound, APIResponse{Error: "Entry not found"})
+	if !publishAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, APIResponse{Error: "publish_at must be in the future"})
 		return
 	}
 
-	c.JSON(http.StatusOK, APIResponse{
-		OK:       true,
-		Redirect: "/admin/entries/search",
-	})
-}
-
-// APIRegenerateEntryImage triggers image regeneration and returns JSON
-func (h *AdminHandler) APIRegenerateEntryImage(c *gin.Context) {
-	path := getEntryPath(c)
-
-	_, err := h.queries.DeleteEntryImageByPath(c.Request.Context(), path)
+	entry, err := h.queries.GetEntryVisibility(c.Request.Context(), path)
 	if err != nil {
-		slog.Error("failed to delete entry image", slog.String("path", path), slog.Any("error", err))
-		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to delete entry image"})
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, APIResponse{Error: "Entry not found"})
+			return
+		}
+		slog.Error("failed to get entry visibility", slog.String("path", path), slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to get entry visibility"})
+		return
+	}
+	if entry.Visibility == admindb.EntryVisibilityPublic {
+		c.JSON(http.StatusConflict, APIResponse{Error: "Entry is already public; make it private before scheduling it"})
 		return
 	}
 
-	go func() {
-		ctx := context.Background()
-		service := internal.NewEntryImageService(h.queries)
+	err = h.queries.ScheduleEntry(c.Request.Context(), admindb.ScheduleEntryParams{
+		PublishedAt: sql.NullTime{Time: publishAt, Valid: true},
+		Path:        path,
+	})
+	if err != nil {
+		slog.Error("failed to schedule entry", slog.String("path", path), slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to schedule entry"})
+		return
+	}
+	slog.Info("scheduled entry", slog.String("path", path), slog.Time("publish_at", publishAt))
 
-		entryRow, err := h.queries.AdminGetEntryByPath(ctx, path)
-		if err != nil {
-			slog.Error("failed to get entry for image regeneration", slog.String("path", path), slog.Any("error", err))
-			return
-		}
+	c.JSON(http.StatusOK, APIResponse{
+		OK:      true,
+		Message: "Scheduled for " + publishAt.Format(time.RFC3339),
+	})
+}
 
-		entry := admindb.Entry{
-			Path:         entryRow.Path,
-			Title:        entryRow.Title,
-			Body:         entryRow.Body,
-			Visibility:   entryRow.Visibility,
-			Format:       entryRow.Format,
-			PublishedAt:  entryRow.PublishedAt,
-			LastEditedAt: entryRow.LastEditedAt,
-			CreatedAt:    entryRow.CreatedAt,
-			UpdatedAt:    entryRow.UpdatedAt,
-		}
+const (
+	defaultPreviewLinkTTL = 72 * time.Hour
+	maxPreviewLinkTTL     = 30 * 24 * time.Hour
+)
 
-		err = service.ProcessEntry(ctx, entry)
-		if err != nil {
-			slog.Error("failed to process entry image", slog.String("path", path), slog.Any("error", err))
-			return
-		}
+// APIPreviewLink is a preview link of an entry that is not public yet
+type APIPreviewLink struct {
+	ID        string `json:"id"`
+	URL       string `json:"url"`
+	ExpiresAt string `json:"expires_at"`
+	CreatedAt string `json:"created_at,omitempty"`
+}
 
-		slog.Info("successfully regenerated entry image", slog.String("path", path))
-	}()
+// APIPreviewLinksResponse is the JSON response for listing preview links
+type APIPreviewLinksResponse struct {
+	APIResponse
+	Links []APIPreviewLink `json:"links"`
+}
 
-	c.JSON(http.StatusOK, APIResponse{
-		OK:      true,
-		Message: "Image regeneration started!",
-	})
+// APICreatePreviewLinkRequest is the JSON request body for creating a preview
+// link. ExpiresInHours defaults to 72 and is at most 720.
+type APICreatePreviewLinkRequest struct {
+	ExpiresInHours int `json:"expires_in_hours"`
 }
 
-// APIPreviewMarkdownRequest is the JSON request body for markdown preview
-type APIPreviewMarkdownRequest struct {
-	Body string `json:"body"`
+// APICreatePreviewLinkResponse is the JSON response for creating a preview link
+type APICreatePreviewLinkResponse struct {
+	APIResponse
+	Link APIPreviewLink `json:"link"`
 }
 
-// APIPreviewMarkdownResponse is the JSON response for markdown preview
-type APIPreviewMarkdownResponse struct {
-	HTML string `json:"html"`
+func (h *AdminHandler) toAPIPreviewLink(token admindb.PreviewToken) APIPreviewLink {
+	link := APIPreviewLink{
+		ID:        token.ID,
+		URL:       preview.URL(h.siteBaseUrl, token.Path, h.previewSigner.Sign(token.ID, token.Path, token.ExpiresAt)),
+		ExpiresAt: token.ExpiresAt.Format(time.RFC3339),
+	}
+	if token.CreatedAt.Valid {
+		link.CreatedAt = token.CreatedAt.Time.Format(time.RFC3339)
+	}
+	return link
 }
 
-// APIPreviewMarkdown renders markdown and returns the HTML
-func (h *AdminHandler) APIPreviewMarkdown(c *gin.Context) {
-	var req APIPreviewMarkdownRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, APIResponse{Error: "Invalid request body"})
+// APIListPreviewLinks returns the preview links of an entry that have not
+// expired or been revoked
+func (h *AdminHandler) APIListPreviewLinks(c *gin.Context) {
+	path := getEntryPath(c)
+
+	if h.previewSigner == nil {
+		c.JSON(http.StatusOK, APIPreviewLinksResponse{APIResponse: APIResponse{OK: true}, Links: []APIPreviewLink{}})
 		return
 	}
 
-	md := markdown.NewPreviewMarkdown(c.Request.Context())
-	html, err := md.Render(req.Body)
+	tokens, err := h.queries.ListPreviewTokens(c.Request.Context(), path)
 	if err != nil {
-		slog.Error("failed to render markdown preview", slog.Any("error", err))
-		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to render markdown"})
+		slog.Error("failed to list preview tokens", slog.String("path", path), slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to list preview links"})
 		return
 	}
 
-	c.JSON(http.StatusOK, APIPreviewMarkdownResponse{HTML: string(html)})
+	links := make([]APIPreviewLink, 0, len(tokens))
+	for _, token := range tokens {
+		links = append(links, h.toAPIPreviewLink(token))
+	}
+	c.JSON(http.StatusOK, APIPreviewLinksResponse{APIResponse: APIResponse{OK: true}, Links: links})
 }
 
-// APIEntryCard represents a single entry in the list API response
-type APIEntryCard struct {
-	Path        string `json:"path"`
-	Title       string `json:"title"`
-	Body        string `json:"body"`
-	BodyPreview string `json:"body_preview"`
-	Visibility  string `json:"visibility"`
-	ImageURL    string `json:"image_url"`
-}
+// APICreatePreviewLink mints a preview link for an entry that is not public
+func (h *AdminHandler) APICreatePreviewLink(c *gin.Context) {
+	path := getEntryPath(c)
 
-// APIListEntries は全エントリ (private 含む、検索用に body 全文も) を JSON で返す。
-// 絞り込み・検索は Preact 側 (クライアント) が担う。
-func (h *AdminHandler) APIListEntries(c *gin.Context) {
-	entries, err := h.queries.AdminListAllEntries(c.Request.Context())
-	if err != nil {
-		slog.Error("failed to list entries", slog.Any("error", err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get entries"})
+	if h.previewSigner == nil {
+		c.JSON(http.StatusServiceUnavailable, APIResponse{Error: "Preview links are disabled; set PREVIEW_SECRET"})
 		return
 	}
 
-	cards := make([]APIEntryCard, 0, len(entries))
-	for _, e := range entries {
-		cards = append(cards, APIEntryCard{
-			Path:        e.Path,
-			Title:       e.Title,
-			Body:        e.Body,
-			BodyPreview: simplifyMarkdown(e.Body),
-			Visibility:  string(e.Visibility),
-			ImageURL:    e.ImageUrl.String,
-		})
-	}
-
-	c.JSON(http.StatusOK, cards)
-}
-
-// APICreateEntryRequest is the JSON request body for creating an entry
-type APICreateEntryRequest struct {
-	Title string `json:"title"`
-}
-
-// APICreateEntry creates a new entry and returns JSON with redirect URL
-func (h *AdminHandler) APICreateEntry(c *gin.Context) {
-	var req APICreateEntryRequest
+	var req APICreatePreviewLinkRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, APIResponse{Error: "Invalid request body"})
 		return
 	}
-
-	if req.Title == "" {
-		c.JSON(http.StatusBadRequest, APIResponse{Error: "Title is required"})
+	ttl := defaultPreviewLinkTTL
+	if req.ExpiresInHours != 0 {
+		ttl = time.Duration(req.ExpiresInHours) * time.Hour
+	}
+	if ttl <= 0 || ttl > maxPreviewLinkTTL {
+		c.JSON(http.StatusBadRequest, APIResponse{Error: fmt.Sprintf("expires_in_hours must be between 1 and %d", int(maxPreviewLinkTTL.Hours()))})
 		return
 	}
 
-	now := time.Now()
-	path := now.Format("2006/01/02/150405")
-
-	_, err := h.queries.CreateEmptyEntry(c.Request.Context(), admindb.CreateEmptyEntryParams{
-		Path:  path,
-		Title: req.Title,
-	})
+	entry, err := h.queries.GetEntryVisibility(c.Request.Context(), path)
 	if err != nil {
-		slog.Error("failed to create entry", slog.String("title", req.Title), slog.Any("error", err))
-		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to create entry"})
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, APIResponse{Error: "Entry not found"})
+			return
+		}
+		slog.Error("failed to get entry visibility", slog.String("path", path), slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to get entry visibility"})
+		return
+	}
+	if entry.Visibility == admindb.EntryVisibilityPublic {
+		c.JSON(http.StatusConflict, APIResponse{Error: "Entry is already public"})
+		return
+	}
+
+	if err := h.queries.DeleteExpiredPreviewTokens(c.Request.Context()); err != nil {
+		slog.Error("failed to delete expired preview tokens", slog.Any("error", err))
+	}
+
+	id, err := preview.NewTokenID()
+	if err != nil {
+		slog.Error("failed to generate preview token id", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to create preview link"})
+		return
+	}
+	token := admindb.PreviewToken{
+		ID:        id,
+		Path:      path,
+		ExpiresAt: time.Now().Add(ttl).Truncate(time.Second),
+	}
+	err = h.queries.CreatePreviewToken(c.Request.Context(), admindb.CreatePreviewTokenParams{
+		ID:        token.ID,
+		Path:      token.Path,
+		ExpiresAt: token.ExpiresAt,
+	})
+	if err != nil {
+		slog.Error("failed to create preview token", slog.String("path", path), slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to create preview link"})
+		return
+	}
+	slog.Info("created preview link", slog.String("path", path), slog.Time("expires_at", token.ExpiresAt))
+
+	c.JSON(http.StatusOK, APICreatePreviewLinkResponse{
+		APIResponse: APIResponse{OK: true, Message: "Preview link created"},
+		Link:        h.toAPIPreviewLink(token),
+	})
+}
+
+// APIRevokePreviewLink deletes a preview link so that it stops working
+func (h *AdminHandler) APIRevokePreviewLink(c *gin.Context) {
+	path := getEntryPath(c)
+	id := c.Query("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{Error: "id is required"})
+		return
+	}
+
+	n, err := h.queries.DeletePreviewToken(c.Request.Context(), admindb.DeletePreviewTokenParams{ID: id, Path: path})
+	if err != nil {
+		slog.Error("failed to delete preview token", slog.String("path", path), slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to revoke preview link"})
+		return
+	}
+	if n == 0 {
+		c.JSON(http.StatusNotFound, APIResponse{Error: "Preview link not found"})
+		return
+	}
+	slog.Info("revoked preview link", slog.String("path", path))
+
+	c.JSON(http.StatusOK, APIResponse{OK: true, Message: "Preview link revoked"})
+}
+
+// APIRevision is an entry revision in the revision list
+type APIRevision struct {
+	ID         int64  `json:"id"`
+	Title      string `json:"title"`
+	BodyLength int64  `json:"body_length"`
+	CreatedAt  string `json:"created_at"`
+	SavedAt    string `json:"saved_at"`
+}
+
+// APIRevisionsResponse is the JSON response for listing revisions, newest first
+type APIRevisionsResponse struct {
+	APIResponse
+	Revisions []APIRevision `json:"revisions"`
+}
+
+// APIRevisionDiffResponse is the JSON response for comparing two revisions.
+// Diff is a unified diff of the bodies.
+type APIRevisionDiffResponse struct {
+	APIResponse
+	FromTitle string `json:"from_title"`
+	ToTitle   string `json:"to_title"`
+	Diff      string `json:"diff"`
+}
+
+// APIRestoreRevisionRequest is the JSON request body for restoring a revision
+type APIRestoreRevisionRequest struct {
+	UpdatedAt string `json:"updated_at"`
+}
+
+// APIListRevisions returns the revisions of an entry, newest first
+func (h *AdminHandler) APIListRevisions(c *gin.Context) {
+	path := getEntryPath(c)
+
+	rows, err := h.queries.ListEntryRevisions(c.Request.Context(), path)
+	if err != nil {
+		slog.Error("failed to list revisions", slog.String("path", path), slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to list revisions"})
+		return
+	}
+
+	revisions := make([]APIRevision, 0, len(rows))
+	for _, row := range rows {
+		revisions = append(revisions, APIRevision{
+			ID:         row.ID,
+			Title:      row.Title,
+			BodyLength: row.BodyLength,
+			CreatedAt:  row.CreatedAt.Format(time.RFC3339),
+			SavedAt:    row.SavedAt.Format(time.RFC3339),
+		})
+	}
+	c.JSON(http.StatusOK, APIRevisionsResponse{APIResponse: APIResponse{OK: true}, Revisions: revisions})
+}
+
+// getRevisionParam loads the revision of path whose id is in the query
+// parameter name. It writes the error response and returns false on failure.
+func (h *AdminHandler) getRevisionParam(c *gin.Context, path string, name string) (admindb.EntryRevision, bool) {
+	id, err := strconv.ParseInt(c.Query(name), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{Error: "Invalid " + name})
+		return admindb.EntryRevision{}, false
+	}
+	rev, err := h.queries.GetEntryRevision(c.Request.Context(), admindb.GetEntryRevisionParams{ID: id, Path: path})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, APIResponse{Error: "Revision not found"})
+			return admindb.EntryRevision{}, false
+		}
+		slog.Error("failed to get revision", slog.String("path", path), slog.Int64("id", id), slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to get revision"})
+		return admindb.EntryRevision{}, false
+	}
+	return rev, true
+}
+
+// APIDiffRevisions returns the unified diff from revision ?from= to ?to=
+func (h *AdminHandler) APIDiffRevisions(c *gin.Context) {
+	path := getEntryPath(c)
+
+	from, ok := h.getRevisionParam(c, path, "from")
+	if !ok {
+		return
+	}
+	to, ok := h.getRevisionParam(c, path, "to")
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, APIRevisionDiffResponse{
+		APIResponse: APIResponse{OK: true},
+		FromTitle:   from.Title,
+		ToTitle:     to.Title,
+		Diff: revision.Unified(
+			fmt.Sprintf("revision %d (%s)", from.ID, from.SavedAt.Format(time.RFC3339)),
+			fmt.Sprintf("revision %d (%s)", to.ID, to.SavedAt.Format(time.RFC3339)),
+			from.Body, to.Body),
+	})
+}
+
+// APIRestoreRevision puts the title and body of revision ?id= back into the
+// entry. Like a save from the editor it fails with 409 when the entry has
+// changed since updated_at. The restored state gets a revision of its own, so
+// the restore can be undone by restoring the revision before it.
+func (h *AdminHandler) APIRestoreRevision(c *gin.Context) {
+	path := getEntryPath(c)
+
+	var req APIRestoreRevisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{Error: "Invalid request body"})
+		return
+	}
+	updatedAt, err := time.Parse(time.RFC3339Nano, req.UpdatedAt)
+	if err != nil {
+		slog.Error("failed to parse updated_at", slog.String("updated_at", req.UpdatedAt), slog.Any("error", err))
+		c.JSON(http.StatusBadRequest, APIResponse{Error: "Invalid updated_at"})
+		return
+	}
+
+	rev, ok := h.getRevisionParam(c, path, "id")
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	err = h.withTx(ctx, func(q *admindb.Queries) error {
+		rows, err := q.RestoreEntryRevision(ctx, admindb.RestoreEntryRevisionParams{
+			Title:     rev.Title,
+			Body:      rev.Body,
+			Path:      path,
+			UpdatedAt: sql.NullTime{Time: updatedAt, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return errUpdateConflict
+		}
+		if err := entrylink.ReplaceLinks(ctx, q, path, rev.Body); err != nil {
+			return err
+		}
+		if err := entrytag.ReplaceBodyTags(ctx, q, path, rev.Body); err != nil {
+			return err
+		}
+		if _, err := revision.Checkpoint(ctx, q, path, time.Now()); err != nil {
+			return err
+		}
+		return search.ReindexEntry(ctx, q, path)
+	})
+	if errors.Is(err, errUpdateConflict) {
+		c.JSON(http.StatusConflict, APIResponse{Error: "他のタブで更新されています。ページをリロードしてください。"})
+		return
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDupEntry {
+		c.JSON(http.StatusConflict, APIResponse{Error: "このリビジョンのタイトルは他のエントリで使われています。"})
+		return
+	}
+	if err != nil {
+		slog.Error("failed to restore revision", slog.String("path", path), slog.Int64("id", rev.ID), slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to restore revision"})
+		return
+	}
+	slog.Info("restored revision", slog.String("path", path), slog.Int64("id", rev.ID))
+
+	entry, err := h.queries.AdminGetEntryByPath(ctx, path)
+	if err != nil {
+		slog.Error("failed to get entry after restore", slog.String("path", path), slog.Any("error", err))
+		c.JSON(http.StatusOK, APIResponse{OK: true, Message: "Revision restored"})
+		return
+	}
+
+	if h.websubPublisher != nil && entry.Visibility == admindb.EntryVisibilityPublic {
+		h.websubPublisher.Schedule()
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		OK:        true,
+		UpdatedAt: entry.UpdatedAt.Time.Format(time.RFC3339Nano),
+		Message:   "Revision restored",
+	})
+}
+
+// APIDeleteEntry deletes an entry and returns JSON
+func (h *AdminHandler) APIDeleteEntry(c *gin.Context) {
+	path := getEntryPath(c)
+
+	rows, err := h.queries.DeleteEntry(c.Request.Context(), path)
+	if err != nil {
+		slog.Error("failed to delete entry", slog.String("path", path), slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to delete entry"})
+		return
+	}
+	if rows == 0 {
+		c.JSON(http.StatusNotFound, APIResponse{Error: "Entry not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		OK:       true,
+		Redirect: "/admin/entries/search",
+	})
+}
+
+// APIRegenerateEntryImage triggers image regeneration and returns JSON
+func (h *AdminHandler) APIRegenerateEntryImage(c *gin.Context) {
+	path := getEntryPath(c)
+
+	_, err := h.queries.DeleteEntryImageByPath(c.Request.Context(), path)
+	if err != nil {
+		slog.Error("failed to delete entry image", slog.String("path", path), slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to delete entry image"})
+		return
+	}
+
+	go func() {
+		ctx := context.Background()
+		service := internal.NewEntryImageService(h.queries)
+
+		entryRow, err := h.queries.AdminGetEntryByPath(ctx, path)
+		if err != nil {
+			slog.Error("failed to get entry for image regeneration", slog.String("path", path), slog.Any("error", err))
+			return
+		}
+
+		entry := admindb.Entry{
+			Path:         entryRow.Path,
+			Title:        entryRow.Title,
+			Body:         entryRow.Body,
+			Visibility:   entryRow.Visibility,
+			Format:       entryRow.Format,
+			PublishedAt:  entryRow.PublishedAt,
+			LastEditedAt: entryRow.LastEditedAt,
+			CreatedAt:    entryRow.CreatedAt,
+			UpdatedAt:    entryRow.UpdatedAt,
+		}
+
+		err = service.ProcessEntry(ctx, entry)
+		if err != nil {
+			slog.Error("failed to process entry image", slog.String("path", path), slog.Any("error", err))
+			return
+		}
+
+		slog.Info("successfully regenerated entry image", slog.String("path", path))
+	}()
+
+	c.JSON(http.StatusOK, APIResponse{
+		OK:      true,
+		Message: "Image regeneration started!",
+	})
+}
+
+// startRebuild runs rebuild in the background unless its previous run, marked
+// by running, is still going. It reports whether the rebuild was started.
+func startRebuild(running *atomic.Bool, name string, rebuild func(ctx context.Context) (int, error)) bool {
+	if !running.CompareAndSwap(false, true) {
+		return false
+	}
+	go func() {
+		defer running.Store(false)
+		processed, err := rebuild(context.Background())
+		if err != nil {
+			slog.Error("failed to rebuild "+name, slog.Any("error", err))
+			return
+		}
+		slog.Info("successfully rebuilt "+name, slog.Int("entries", processed))
+	}()
+	return true
+}
+
+// APIRebuildEntryLinks re-parses every entry and rebuilds the entry_link graph in the background
+func (h *AdminHandler) APIRebuildEntryLinks(c *gin.Context) {
+	if !startRebuild(&h.linkRebuild, "entry links", h.linkService.RebuildAll) {
+		c.JSON(http.StatusConflict, APIResponse{Error: "Link rebuild is already running"})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		OK:      true,
+		Message: "Link rebuild started!",
+	})
+}
+
+// APIRebuildSearchIndex re-indexes every entry for search in the background
+func (h *AdminHandler) APIRebuildSearchIndex(c *gin.Context) {
+	if !startRebuild(&h.searchRebuild, "search index", h.searchService.RebuildAll) {
+		c.JSON(http.StatusConflict, APIResponse{Error: "Search index rebuild is already running"})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		OK:      true,
+		Message: "Search index rebuild started!",
+	})
+}
+
+// APIRebuildBodyTags re-parses every entry body and rebuilds its #hashtag tags in the background
+func (h *AdminHandler) APIRebuildBodyTags(c *gin.Context) {
+	if !startRebuild(&h.tagRebuild, "body tags", h.tagService.RebuildAll) {
+		c.JSON(http.StatusConflict, APIResponse{Error: "Tag rebuild is already running"})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		OK:      true,
+		Message: "Tag rebuild started!",
+	})
+}
+
+// APIPreviewMarkdownRequest is the JSON request body for markdown preview
+type APIPreviewMarkdownRequest struct {
+	Body string `json:"body"`
+	// Title is the title of the entry being edited, which it cannot embed
+	Title string `json:"title"`
+}
+
+// APIPreviewMarkdownResponse is the JSON response for markdown preview
+type APIPreviewMarkdownResponse struct {
+	HTML string `json:"html"`
+}
+
+// APIPreviewMarkdown renders markdown and returns the HTML
+func (h *AdminHandler) APIPreviewMarkdown(c *gin.Context) {
+	var req APIPreviewMarkdownRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{Error: "Invalid request body"})
+		return
+	}
+
+	md := markdown.NewPreviewMarkdown(c.Request.Context(), &adminWikiLinkResolver{queries: h.queries})
+	html, err := md.RenderEntry(req.Title, req.Body)
+	if err != nil {
+		slog.Error("failed to render markdown preview", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to render markdown"})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIPreviewMarkdownResponse{HTML: string(html)})
+}
+
+// APIEntryCard represents a single entry in the list API response
+type APIEntryCard struct {
+	Path        string   `json:"path"`
+	Title       string   `json:"title"`
+	Body        string   `json:"body"`
+	BodyPreview string   `json:"body_preview"`
+	Visibility  string   `json:"visibility"`
+	ImageURL    string   `json:"image_url"`
+	Tags        []string `json:"tags"`
+}
+
+// APIListEntries は全エントリ (private 含む、検索用に body 全文も) を JSON で返す。
+// ?tag= を付けるとそのタグの付いたエントリだけに絞る。
+// キーワード検索は Preact 側 (クライアント) が担う。
+func (h *AdminHandler) APIListEntries(c *gin.Context) {
+	ctx := c.Request.Context()
+	entries, err := h.queries.AdminListAllEntries(ctx)
+	if err != nil {
+		slog.Error("failed to list entries", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get entries"})
+		return
+	}
+	entryTags, err := h.queries.ListAllEntryTags(ctx)
+	if err != nil {
+		slog.Error("failed to list entry tags", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get entries"})
+		return
+	}
+	tagsByPath := make(map[string][]string)
+	for _, t := range entryTags {
+		tagsByPath[t.Path] = append(tagsByPath[t.Path], t.Tag)
+	}
+
+	filter := strings.TrimPrefix(strings.TrimSpace(c.Query("tag")), "#")
+	cards := make([]APIEntryCard, 0, len(entries))
+	for _, e := range entries {
+		tags := tagsByPath[e.Path]
+		if filter != "" && !slices.ContainsFunc(tags, func(t string) bool { return strings.EqualFold(t, filter) }) {
+			continue
+		}
+		cards = append(cards, APIEntryCard{
+			Path:        e.Path,
+			Title:       e.Title,
+			Body:        e.Body,
+			BodyPreview: simplifyMarkdown(e.Body),
+			Visibility:  string(e.Visibility),
+			ImageURL:    e.ImageUrl.String,
+			Tags:        append([]string{}, tags...),
+		})
+	}
+
+	c.JSON(http.StatusOK, cards)
+}
+
+// APIUpdateTagsRequest is the JSON request body for replacing an entry's tags
+type APIUpdateTagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
+// APIUpdateTagsResponse returns the tags as stored, after normalization
+type APIUpdateTagsResponse struct {
+	APIResponse
+	Tags []string `json:"tags"`
+}
+
+// APIUpdateTags replaces every tag of the entry
+func (h *AdminHandler) APIUpdateTags(c *gin.Context) {
+	path := getEntryPath(c)
+
+	var req APIUpdateTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{Error: "Invalid request body"})
+		return
+	}
+
+	tags, err := entrytag.Normalize(req.Tags)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	err = h.withTx(ctx, func(q *admindb.Queries) error {
+		if err := entrytag.ReplaceTags(ctx, q, path, tags); err != nil {
+			return err
+		}
+		// Dropping a manual tag also drops a #hashtag of the same name, so
+		// restore the body's tags.
+		entry, err := q.AdminGetEntryByPath(ctx, path)
+		if err != nil {
+			return err
+		}
+		return entrytag.ReplaceBodyTags(ctx, q, path, entry.Body)
+	})
+	if err != nil {
+		slog.Error("failed to update tags", slog.String("path", path), slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to update tags"})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIUpdateTagsResponse{
+		APIResponse: APIResponse{OK: true, Message: "Tags updated!"},
+		Tags:        append([]string{}, tags...),
+	})
+}
+
+// APITagSuggestion is one autocomplete candidate for tag input
+type APITagSuggestion struct {
+	Tag   string `json:"tag"`
+	Count int64  `json:"count"`
+}
+
+// APISearchTags returns existing tags starting with ?q=, most used first
+func (h *AdminHandler) APISearchTags(c *gin.Context) {
+	prefix := strings.TrimPrefix(strings.TrimSpace(c.Query("q")), "#")
+	rows, err := h.queries.SearchTags(c.Request.Context(), admindb.SearchTagsParams{
+		Pattern: entrytag.LikePrefix(prefix),
+		Limit:   10,
+	})
+	if err != nil {
+		slog.Error("failed to search tags", slog.String("q", prefix), slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search tags"})
+		return
+	}
+
+	suggestions := make([]APITagSuggestion, 0, len(rows))
+	for _, row := range rows {
+		suggestions = append(suggestions, APITagSuggestion{Tag: row.Tag, Count: row.EntryCount})
+	}
+	c.JSON(http.StatusOK, suggestions)
+}
+
+// APILinkedEntry is one entry in the link graph of APIEntryLinksResponse.
+// Missing entries are link targets that have not been written yet.
+type APILinkedEntry struct {
+	Path        string `json:"path,omitempty"`
+	Title       string `json:"title"`
+	BodyPreview string `json:"body_preview"`
+	Visibility  string `json:"visibility,omitempty"`
+	ImageURL    string `json:"image_url"`
+	Missing     bool   `json:"missing"`
+}
+
+// APITwoHopLinks lists the entries that also link to Target
+type APITwoHopLinks struct {
+	Target  APILinkedEntry   `json:"target"`
+	Entries []APILinkedEntry `json:"entries"`
+}
+
+// APIEntryLinksResponse is the JSON response for an entry's link graph
+type APIEntryLinksResponse struct {
+	Links     []APILinkedEntry `json:"links"`
+	Backlinks []APILinkedEntry `json:"backlinks"`
+	TwoHops   []APITwoHopLinks `json:"two_hops"`
+}
+
+func toAPILinkedEntries(nodes []entrylink.Node) []APILinkedEntry {
+	entries := make([]APILinkedEntry, 0, len(nodes))
+	for _, n := range nodes {
+		entries = append(entries, toAPILinkedEntry(n))
+	}
+	return entries
+}
+
+func toAPILinkedEntry(n entrylink.Node) APILinkedEntry {
+	return APILinkedEntry{
+		Path:        n.Path,
+		Title:       n.Title,
+		BodyPreview: simplifyMarkdown(n.Body),
+		Visibility:  n.Visibility,
+		ImageURL:    n.ImageURL,
+		Missing:     n.Missing,
+	}
+}
+
+// APIGetEntryLinks returns the outgoing links, backlinks and two-hop links of an entry
+func (h *AdminHandler) APIGetEntryLinks(c *gin.Context) {
+	path := getEntryPath(c)
+
+	entry, err := h.queries.AdminGetEntryByPath(c.Request.Context(), path)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, APIResponse{Error: "Entry not found"})
+			return
+		}
+		slog.Error("failed to get entry", slog.String("path", path), slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to get entry"})
+		return
+	}
+
+	graph, err := entrylink.LoadGraph(c.Request.Context(), h.queries, entry.Path, entry.Title)
+	if err != nil {
+		slog.Error("failed to load entry links", slog.String("path", path), slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to get entry links"})
+		return
+	}
+
+	twoHops := make([]APITwoHopLinks, 0, len(graph.TwoHops))
+	for _, hop := range graph.TwoHops {
+		twoHops = append(twoHops, APITwoHopLinks{
+			Target:  toAPILinkedEntry(hop.Target),
+			Entries: toAPILinkedEntries(hop.Entries),
+		})
+	}
+
+	c.JSON(http.StatusOK, APIEntryLinksResponse{
+		Links:     toAPILinkedEntries(graph.Links),
+		Backlinks: toAPILinkedEntries(graph.Backlinks),
+		TwoHops:   twoHops,
+	})
+}
+
+// APICreateEntryRequest is the JSON request body for creating an entry
+type APICreateEntryRequest struct {
+	Title string `json:"title"`
+}
+
+// APICreateEntry creates a new entry and returns JSON with redirect URL
+func (h *AdminHandler) APICreateEntry(c *gin.Context) {
+	var req APICreateEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{Error: "Invalid request body"})
+		return
+	}
+
+	if req.Title == "" {
+		c.JSON(http.StatusBadRequest, APIResponse{Error: "Title is required"})
+		return
+	}
+
+	now := time.Now()
+	path := now.Format("2006/01/02/150405")
+
+	ctx := c.Request.Context()
+	err := h.withTx(ctx, func(q *admindb.Queries) error {
+		if _, err := q.CreateEmptyEntry(ctx, admindb.CreateEmptyEntryParams{
+			Path:     path,
+			Title:    req.Title,
+			AuthorID: currentUserID(c),
+		}); err != nil {
+			return err
+		}
+		if _, err := revision.Checkpoint(ctx, q, path, now); err != nil {
+			return err
+		}
+		return search.ReindexEntry(ctx, q, path)
+	})
+	if err != nil {
+		slog.Error("failed to create entry", slog.String("title", req.Title), slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to create entry"})
 		return
 	}
 
@@ -385,6 +1294,17 @@ type APILoginRequest struct {
 	RememberMe bool   `json:"remember_me"`
 }
 
+// APILoginResponse is the JSON response of both login steps. When the user
+// has TOTP enabled, the password step answers with TwoFactorRequired and the
+// token to send along with the code to /admin/api/login/totp.
+type APILoginResponse struct {
+	APIResponse
+	TwoFactorRequired bool   `json:"two_factor_required,omitempty"`
+	PendingToken      string `json:"pending_token,omitempty"`
+	// Restart tells the login page to ask for the password again
+	Restart bool `json:"restart,omitempty"`
+}
+
 // APILogin handles JSON-based login and returns JSON response
 func (h *AdminHandler) APILogin(c *gin.Context) {
 	var req APILoginRequest
@@ -393,36 +1313,129 @@ func (h *AdminHandler) APILogin(c *gin.Context) {
 		return
 	}
 
-	usernameMatch := subtle.ConstantTimeCompare([]byte(req.Username), []byte(h.adminUser)) == 1
-	passwordMatch := subtle.ConstantTimeCompare([]byte(req.Password), []byte(h.adminPassword)) == 1
-
-	if !usernameMatch || !passwordMatch {
+	user, err := h.users.Authenticate(c.Request.Context(), req.Username, req.Password)
+	if errors.Is(err, adminuser.ErrInvalidCredentials) {
+		slog.Info("login failed", slog.String("username", req.Username))
 		c.JSON(http.StatusUnauthorized, APIResponse{Error: "Invalid username or password"})
 		return
 	}
+	if err != nil {
+		slog.Error("failed to authenticate", slog.String("username", req.Username), slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to log in"})
+		return
+	}
 
-	sessionID, err := generateSessionID()
+	if user.TotpSecret.Valid {
+		token, err := h.users.StartLogin(c.Request.Context(), user.ID, req.RememberMe, time.Now())
+		if errors.Is(err, adminuser.ErrTOTPLocked) {
+			slog.Warn("login refused while locked out", slog.String("username", req.Username))
+			c.JSON(http.StatusTooManyRequests, APIResponse{Error: "Too many failed authentication codes. Please try again later."})
+			return
+		}
+		if err != nil {
+			slog.Error("failed to start login", slog.String("username", req.Username), slog.Any("error", err))
+			c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to log in"})
+			return
+		}
+		c.JSON(http.StatusOK, APILoginResponse{
+			APIResponse:       APIResponse{OK: true},
+			TwoFactorRequired: true,
+			PendingToken:      token,
+		})
+		return
+	}
+
+	if err := h.startSession(c, user.ID, req.RememberMe); err != nil {
+		slog.Error("failed to create session", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to create session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		OK:       true,
+		Redirect: "/admin/entries/search",
+	})
+}
+
+// APILoginTOTPRequest is the JSON request body for the second login step
+type APILoginTOTPRequest struct {
+	PendingToken string `json:"pending_token"`
+	// Code is a TOTP code or a recovery code
+	Code string `json:"code"`
+}
+
+// APILoginTOTP completes a login started by APILogin with a TOTP code or a
+// recovery code
+func (h *AdminHandler) APILoginTOTP(c *gin.Context) {
+	var req APILoginTOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{Error: "Invalid request body"})
+		return
+	}
+
+	pending, err := h.users.FinishLogin(c.Request.Context(), req.PendingToken, req.Code, time.Now())
+	if errors.Is(err, adminuser.ErrInvalidCode) {
+		c.JSON(http.StatusUnauthorized, APILoginResponse{
+			APIResponse:       APIResponse{Error: "Invalid authentication code"},
+			TwoFactorRequired: true,
+			PendingToken:      req.PendingToken,
+		})
+		return
+	}
+	if errors.Is(err, adminuser.ErrLoginExpired) {
+		c.JSON(http.StatusUnauthorized, APILoginResponse{
+			APIResponse: APIResponse{Error: "Login expired. Please sign in again."},
+			Restart:     true,
+		})
+		return
+	}
+	if errors.Is(err, adminuser.ErrTOTPLocked) {
+		c.JSON(http.StatusTooManyRequests, APILoginResponse{
+			APIResponse: APIResponse{Error: "Too many failed authentication codes. Please try again later."},
+			Restart:     true,
+		})
+		return
+	}
 	if err != nil {
-		slog.Error("failed to generate session ID", slog.Any("error", err))
+		slog.Error("failed to verify authentication code", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to log in"})
+		return
+	}
+
+	if err := h.startSession(c, pending.UserID, pending.RememberMe); err != nil {
+		slog.Error("failed to create session", slog.Any("error", err))
 		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to create session"})
 		return
 	}
 
+	c.JSON(http.StatusOK, APIResponse{
+		OK:       true,
+		Redirect: "/admin/entries/search",
+	})
+}
+
+// startSession signs userID in by creating a session and setting its cookie
+func (h *AdminHandler) startSession(c *gin.Context, userID int64, rememberMe bool) error {
+	sessionID, err := generateSessionID()
+	if err != nil {
+		return fmt.Errorf("failed to generate session ID: %w", err)
+	}
+
 	sessionTimeout := defaultSessionTimeout
-	if req.RememberMe {
+	if rememberMe {
 		sessionTimeout = extendedSessionTimeout
 	}
 	expires := time.Now().Add(sessionTimeout)
 
 	err = h.queries.CreateSession(c.Request.Context(), admindb.CreateSessionParams{
 		SessionID: sessionID,
-		Username:  req.Username,
+		UserID:    userID,
+		UserAgent: truncateUserAgent(c.Request.UserAgent()),
+		IpAddress: c.ClientIP(),
 		ExpiresAt: expires,
 	})
 	if err != nil {
-		slog.Error("failed to create session", slog.Any("error", err))
-		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to create session"})
-		return
+		return fmt.Errorf("failed to create session: %w", err)
 	}
 
 	http.SetCookie(c.Writer, &http.Cookie{
@@ -435,9 +1448,425 @@ func (h *AdminHandler) APILogin(c *gin.Context) {
 		Expires:  expires,
 		MaxAge:   int(time.Until(expires).Seconds()),
 	})
+	return nil
+}
+
+// APITOTPStatusResponse tells whether the signed-in user has TOTP enabled
+type APITOTPStatusResponse struct {
+	Enabled           bool  `json:"enabled"`
+	RecoveryCodesLeft int64 `json:"recovery_codes_left"`
+}
+
+// APIGetTOTPStatus returns the two-factor settings of the signed-in user
+func (h *AdminHandler) APIGetTOTPStatus(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := currentUserID(c).Int64
+
+	user, err := h.queries.GetAdminUser(ctx, userID)
+	if err != nil {
+		slog.Error("failed to get admin user", slog.Int64("userID", userID), slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to get two-factor settings"})
+		return
+	}
+	left, err := h.queries.CountUnusedRecoveryCodes(ctx, userID)
+	if err != nil {
+		slog.Error("failed to count recovery codes", slog.Int64("userID", userID), slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to get two-factor settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, APITOTPStatusResponse{
+		Enabled:           user.TotpSecret.Valid,
+		RecoveryCodesLeft: left,
+	})
+}
+
+// APISetupTOTPResponse carries a new TOTP secret for the authenticator app
+type APISetupTOTPResponse struct {
+	Secret string `json:"secret"`
+	// URI is the otpauth:// provisioning URI encoded in QRCode
+	URI string `json:"uri"`
+	// QRCode is a PNG data URI
+	QRCode string `json:"qr_code"`
+}
+
+// APISetupTOTP starts TOTP enrolment. The secret stays pending until
+// APIEnableTOTP confirms a code from it.
+func (h *AdminHandler) APISetupTOTP(c *gin.Context) {
+	userID := currentUserID(c).Int64
+
+	key, err := h.users.StartTOTPSetup(c.Request.Context(), userID, h.totpIssuer())
+	if errors.Is(err, adminuser.ErrTOTPEnabled) {
+		c.JSON(http.StatusConflict, APIResponse{Error: "Two-factor authentication is already enabled. Disable it first."})
+		return
+	}
+	if err != nil {
+		slog.Error("failed to start TOTP setup", slog.Int64("userID", userID), slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to start two-factor setup"})
+		return
+	}
+	img, err := key.Image(200, 200)
+	if err != nil {
+		slog.Error("failed to render TOTP QR code", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to start two-factor setup"})
+		return
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		slog.Error("failed to encode TOTP QR code", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to start two-factor setup"})
+		return
+	}
+
+	c.JSON(http.StatusOK, APISetupTOTPResponse{
+		Secret: key.Secret(),
+		URI:    key.URL(),
+		QRCode: "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()),
+	})
+}
+
+// totpIssuer names this blog in authenticator apps
+func (h *AdminHandler) totpIssuer() string {
+	if u, err := url.Parse(h.siteBaseUrl); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return "blog4"
+}
+
+// APITOTPCodeRequest is the JSON request body carrying an authentication code
+type APITOTPCodeRequest struct {
+	Code string `json:"code"`
+}
+
+// APIEnableTOTPResponse carries the recovery codes issued on enrolment. They
+// are shown once and only their hashes are kept.
+type APIEnableTOTPResponse struct {
+	APIResponse
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// APIEnableTOTP confirms TOTP enrolment with a code from the pending secret
+func (h *AdminHandler) APIEnableTOTP(c *gin.Context) {
+	var req APITOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{Error: "Invalid request body"})
+		return
+	}
+	ctx := c.Request.Context()
+	userID := currentUserID(c).Int64
+
+	var codes []string
+	err := h.withTx(ctx, func(q *admindb.Queries) error {
+		var err error
+		codes, err = adminuser.NewService(q).EnableTOTP(ctx, userID, req.Code, time.Now())
+		return err
+	})
+	if errors.Is(err, adminuser.ErrInvalidCode) {
+		c.JSON(http.StatusBadRequest, APIResponse{Error: "Invalid authentication code"})
+		return
+	}
+	if errors.Is(err, adminuser.ErrTOTPNotEnrolled) {
+		c.JSON(http.StatusConflict, APIResponse{Error: "Two-factor setup has expired. Please start again."})
+		return
+	}
+	if errors.Is(err, adminuser.ErrTOTPEnabled) {
+		c.JSON(http.StatusConflict, APIResponse{Error: "Two-factor authentication is already enabled. Disable it first."})
+		return
+	}
+	if err != nil {
+		slog.Error("failed to enable TOTP", slog.Int64("userID", userID), slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to enable two-factor authentication"})
+		return
+	}
+
+	slog.Info("TOTP enabled", slog.String("username", c.GetString("username")))
+	c.JSON(http.StatusOK, APIEnableTOTPResponse{
+		APIResponse:   APIResponse{OK: true, Message: "Two-factor authentication enabled!"},
+		RecoveryCodes: codes,
+	})
+}
+
+// APIDisableTOTP turns TOTP off after checking a current TOTP or recovery
+// code, so that a stolen session alone cannot remove the second factor.
+// Wrong codes count towards the same lockout as at sign-in.
+func (h *AdminHandler) APIDisableTOTP(c *gin.Context) {
+	var req APITOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{Error: "Invalid request body"})
+		return
+	}
+	ctx := c.Request.Context()
+	userID := currentUserID(c).Int64
+
+	// Checked outside the transaction, so that a wrong code stays counted
+	err := h.users.CheckCode(ctx, userID, req.Code, time.Now())
+	if err == nil {
+		err = h.withTx(ctx, func(q *admindb.Queries) error {
+			return adminuser.NewService(q).DisableTOTP(ctx, userID)
+		})
+	}
+	if errors.Is(err, adminuser.ErrInvalidCode) {
+		c.JSON(http.StatusBadRequest, APIResponse{Error: "Invalid authentication code"})
+		return
+	}
+	if errors.Is(err, adminuser.ErrTOTPLocked) {
+		c.JSON(http.StatusTooManyRequests, APIResponse{Error: "Too many failed authentication codes. Please try again later."})
+		return
+	}
+	if err != nil {
+		slog.Error("failed to disable TOTP", slog.Int64("userID", userID), slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to disable two-factor authentication"})
+		return
+	}
+
+	slog.Info("TOTP disabled", slog.String("username", c.GetString("username")))
+	c.JSON(http.StatusOK, APIResponse{OK: true, Message: "Two-factor authentication disabled"})
+}
+
+// APIPasskeyOptionsResponse carries the options for navigator.credentials
+// and the token to send back with the browser's response
+type APIPasskeyOptionsResponse struct {
+	Token   string `json:"token"`
+	Options any    `json:"options"`
+}
+
+// APIFinishPasskeyRequest is the JSON request body finishing a passkey
+// ceremony. Credential is the PublicKeyCredential's toJSON().
+type APIFinishPasskeyRequest struct {
+	Token      string          `json:"token"`
+	Name       string          `json:"name"`
+	Credential json.RawMessage `json:"credential"`
+}
+
+// APIBeginPasskeyLogin starts a login with a passkey
+func (h *AdminHandler) APIBeginPasskeyLogin(c *gin.Context) {
+	assertion, token, err := h.passkeys.BeginLogin(c.Request.Context(), time.Now())
+	if err != nil {
+		slog.Error("failed to begin passkey login", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to start passkey login"})
+		return
+	}
+	c.JSON(http.StatusOK, APIPasskeyOptionsResponse{Token: token, Options: assertion})
+}
+
+// APIFinishPasskeyLogin signs in the user whose passkey answered the
+// challenge. Passkeys verify the user on the device, so no TOTP code is
+// asked for.
+func (h *AdminHandler) APIFinishPasskeyLogin(c *gin.Context) {
+	var req APIFinishPasskeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{Error: "Invalid request body"})
+		return
+	}
+
+	userID, err := h.passkeys.FinishLogin(c.Request.Context(), req.Token, req.Credential)
+	if errors.Is(err, passkey.ErrChallengeExpired) || errors.Is(err, passkey.ErrVerificationFailed) {
+		slog.Info("passkey login failed", slog.Any("error", err))
+		c.JSON(http.StatusUnauthorized, APIResponse{Error: "Passkey login failed. Please try again."})
+		return
+	}
+	if err != nil {
+		slog.Error("failed to finish passkey login", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to log in"})
+		return
+	}
+
+	if err := h.startSession(c, userID, false); err != nil {
+		slog.Error("failed to create session", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to create session"})
+		return
+	}
 
 	c.JSON(http.StatusOK, APIResponse{
 		OK:       true,
 		Redirect: "/admin/entries/search",
 	})
 }
+
+// APIBeginPasskeyRegistration starts adding a passkey for the signed-in user
+func (h *AdminHandler) APIBeginPasskeyRegistration(c *gin.Context) {
+	userID := currentUserID(c).Int64
+
+	creation, token, err := h.passkeys.BeginRegistration(c.Request.Context(), userID, time.Now())
+	if err != nil {
+		slog.Error("failed to begin passkey registration", slog.Int64("userID", userID), slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to start passkey registration"})
+		return
+	}
+	c.JSON(http.StatusOK, APIPasskeyOptionsResponse{Token: token, Options: creation})
+}
+
+// APIFinishPasskeyRegistration stores the passkey created by the browser
+func (h *AdminHandler) APIFinishPasskeyRegistration(c *gin.Context) {
+	var req APIFinishPasskeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{Error: "Invalid request body"})
+		return
+	}
+	userID := currentUserID(c).Int64
+
+	err := h.passkeys.FinishRegistration(c.Request.Context(), userID, req.Token, req.Name, req.Credential)
+	if errors.Is(err, passkey.ErrInvalidName) {
+		c.JSON(http.StatusBadRequest, APIResponse{Error: "Name is required"})
+		return
+	}
+	if errors.Is(err, passkey.ErrChallengeExpired) || errors.Is(err, passkey.ErrVerificationFailed) {
+		slog.Info("passkey registration failed", slog.Int64("userID", userID), slog.Any("error", err))
+		c.JSON(http.StatusBadRequest, APIResponse{Error: "Passkey registration failed. Please try again."})
+		return
+	}
+	if err != nil {
+		slog.Error("failed to finish passkey registration", slog.Int64("userID", userID), slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to register passkey"})
+		return
+	}
+
+	slog.Info("passkey registered", slog.String("username", c.GetString("username")))
+	c.JSON(http.StatusOK, APIResponse{OK: true, Message: "Passkey added!"})
+}
+
+// APIPasskey is a passkey in the passkey list
+type APIPasskey struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	CreatedAt  string `json:"created_at"`
+	LastUsedAt string `json:"last_used_at,omitempty"`
+}
+
+// APIPasskeysResponse is the JSON response for listing passkeys
+type APIPasskeysResponse struct {
+	APIResponse
+	Passkeys []APIPasskey `json:"passkeys"`
+}
+
+// APIListPasskeys returns the passkeys of the signed-in user
+func (h *AdminHandler) APIListPasskeys(c *gin.Context) {
+	userID := currentUserID(c).Int64
+
+	rows, err := h.queries.ListWebauthnCredentials(c.Request.Context(), userID)
+	if err != nil {
+		slog.Error("failed to list passkeys", slog.Int64("userID", userID), slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to list passkeys"})
+		return
+	}
+
+	passkeys := make([]APIPasskey, 0, len(rows))
+	for _, row := range rows {
+		p := APIPasskey{ID: row.ID, Name: row.Name}
+		if row.CreatedAt.Valid {
+			p.CreatedAt = row.CreatedAt.Time.Format(time.RFC3339)
+		}
+		if row.LastUsedAt.Valid {
+			p.LastUsedAt = row.LastUsedAt.Time.Format(time.RFC3339)
+		}
+		passkeys = append(passkeys, p)
+	}
+	c.JSON(http.StatusOK, APIPasskeysResponse{APIResponse: APIResponse{OK: true}, Passkeys: passkeys})
+}
+
+// APIDeletePasskey removes a passkey of the signed-in user
+func (h *AdminHandler) APIDeletePasskey(c *gin.Context) {
+	userID := currentUserID(c).Int64
+	id, err := strconv.ParseInt(c.Query("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{Error: "id is required"})
+		return
+	}
+
+	n, err := h.queries.DeleteWebauthnCredential(c.Request.Context(), admindb.DeleteWebauthnCredentialParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		slog.Error("failed to delete passkey", slog.Int64("id", id), slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to delete passkey"})
+		return
+	}
+	if n == 0 {
+		c.JSON(http.StatusNotFound, APIResponse{Error: "Passkey not found"})
+		return
+	}
+	slog.Info("passkey deleted", slog.String("username", c.GetString("username")))
+
+	c.JSON(http.StatusOK, APIResponse{OK: true, Message: "Passkey deleted"})
+}
+
+// APISession is a signed-in browser in the session list
+type APISession struct {
+	ID             int64  `json:"id"`
+	UserAgent      string `json:"user_agent"`
+	IPAddress      string `json:"ip_address"`
+	CreatedAt      string `json:"created_at"`
+	LastAccessedAt string `json:"last_accessed_at"`
+	ExpiresAt      string `json:"expires_at"`
+	Current        bool   `json:"current"`
+}
+
+// APISessionsResponse is the JSON response for listing sessions
+type APISessionsResponse struct {
+	APIResponse
+	Sessions []APISession `json:"sessions"`
+}
+
+// APIListSessions returns the unexpired sessions of the signed-in user
+func (h *AdminHandler) APIListSessions(c *gin.Context) {
+	userID := currentUserID(c).Int64
+
+	rows, err := h.queries.ListUserSessions(c.Request.Context(), userID)
+	if err != nil {
+		slog.Error("failed to list sessions", slog.Int64("userID", userID), slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to list sessions"})
+		return
+	}
+
+	current := currentSessionID(c)
+	sessions := make([]APISession, 0, len(rows))
+	for _, row := range rows {
+		s := APISession{
+			ID:        row.ID,
+			UserAgent: row.UserAgent,
+			IPAddress: row.IpAddress,
+			ExpiresAt: row.ExpiresAt.Format(time.RFC3339),
+			Current:   row.ID == current,
+		}
+		if row.CreatedAt.Valid {
+			s.CreatedAt = row.CreatedAt.Time.Format(time.RFC3339)
+		}
+		if row.LastAccessedAt.Valid {
+			s.LastAccessedAt = row.LastAccessedAt.Time.Format(time.RFC3339)
+		}
+		sessions = append(sessions, s)
+	}
+	c.JSON(http.StatusOK, APISessionsResponse{APIResponse: APIResponse{OK: true}, Sessions: sessions})
+}
+
+// APIRevokeSession signs another browser of the signed-in user out
+func (h *AdminHandler) APIRevokeSession(c *gin.Context) {
+	userID := currentUserID(c).Int64
+	id, err := strconv.ParseInt(c.Query("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{Error: "id is required"})
+		return
+	}
+	if id == currentSessionID(c) {
+		c.JSON(http.StatusBadRequest, APIResponse{Error: "Use Log out to end the current session"})
+		return
+	}
+
+	n, err := h.queries.DeleteUserSession(c.Request.Context(), admindb.DeleteUserSessionParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		slog.Error("failed to revoke session", slog.Int64("id", id), slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, APIResponse{Error: "Failed to revoke session"})
+		return
+	}
+	if n == 0 {
+		c.JSON(http.StatusNotFound, APIResponse{Error: "Session not found"})
+		return
+	}
+	slog.Info("session revoked", slog.String("username", c.GetString("username")), slog.Int64("id", id))
+
+	c.JSON(http.StatusOK, APIResponse{OK: true, Message: "Session revoked"})
+}
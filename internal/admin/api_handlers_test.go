@@ -0,0 +1,32 @@
+package admin
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStartRebuild(t *testing.T) {
+	var running atomic.Bool
+	release := make(chan struct{})
+	done := make(chan struct{})
+	rebuild := func(ctx context.Context) (int, error) {
+		<-release
+		return 0, nil
+	}
+
+	assert.True(t, startRebuild(&running, "test", func(ctx context.Context) (int, error) {
+		defer close(done)
+		return rebuild(ctx)
+	}))
+	// A second request while the first runs is refused
+	assert.False(t, startRebuild(&running, "test", rebuild))
+
+	close(release)
+	<-done
+	assert.Eventually(t, func() bool { return !running.Load() }, time.Second, time.Millisecond)
+	assert.True(t, startRebuild(&running, "test", rebuild))
+}
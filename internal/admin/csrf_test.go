@@ -0,0 +1,78 @@
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newCSRFTestRouter(sessionID string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	admin := r.Group("/admin")
+	admin.Use(func(c *gin.Context) {
+		// Stands in for GinSessionMiddleware
+		c.Set("csrf_token", csrfToken(sessionID))
+	})
+	admin.Use(CSRFMiddleware())
+	ok := func(c *gin.Context) { c.String(http.StatusOK, "ok") }
+	admin.GET("/api/entries", ok)
+	admin.PUT("/api/entries/title", ok)
+	admin.POST("/logout", ok)
+	admin.POST("/share-target", ok)
+	return r
+}
+
+func TestCSRFToken(t *testing.T) {
+	if csrfToken("a") != csrfToken("a") {
+		t.Error("CSRF token should be stable for a session")
+	}
+	if csrfToken("a") == csrfToken("b") {
+		t.Error("CSRF tokens of different sessions should differ")
+	}
+	if strings.Contains(csrfToken("session-secret"), "session-secret") {
+		t.Error("CSRF token should not contain the session ID")
+	}
+}
+
+func TestCSRFMiddleware(t *testing.T) {
+	token := csrfToken("session")
+	r := newCSRFTestRouter("session")
+
+	form := func(values url.Values) *http.Request {
+		req := httptest.NewRequest("POST", "/admin/logout", strings.NewReader(values.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req
+	}
+	withHeader := func(req *http.Request, value string) *http.Request {
+		req.Header.Set(csrfHeaderName, value)
+		return req
+	}
+
+	tests := []struct {
+		name string
+		req  *http.Request
+		want int
+	}{
+		{"GET needs no token", httptest.NewRequest("GET", "/admin/api/entries", nil), http.StatusOK},
+		{"API with header", withHeader(httptest.NewRequest("PUT", "/admin/api/entries/title", nil), token), http.StatusOK},
+		{"API without token", httptest.NewRequest("PUT", "/admin/api/entries/title", nil), http.StatusForbidden},
+		{"API with another session's token", withHeader(httptest.NewRequest("PUT", "/admin/api/entries/title", nil), csrfToken("other")), http.StatusForbidden},
+		{"form with field", form(url.Values{csrfFormField: {token}}), http.StatusOK},
+		{"form without field", form(url.Values{}), http.StatusForbidden},
+		{"share target is checked by its handler", httptest.NewRequest("POST", "/admin/share-target", nil), http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, tt.req)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package admin
 
 import (
 	"crypto/rand"
+	"database/sql"
 	"encoding/base64"
 	"net/http"
 	"time"
@@ -12,6 +13,10 @@ const (
 	sessionIDLength        = 32
 	defaultSessionTimeout  = 24 * time.Hour
 	extendedSessionTimeout = 30 * 24 * time.Hour // 30 days for "remember me"
+	// sessionTouchInterval is how stale last_accessed_at may get before a
+	// request updates it, so that browsing does not write on every request
+	sessionTouchInterval = 5 * time.Minute
+	maxUserAgentLength   = 512 // admin_session.user_agent
 )
 
 func generateSessionID() (string, error) {
@@ -30,3 +35,31 @@ func getSessionID(r *http.Request) string {
 	}
 	return cookie.Value
 }
+
+// needsTouch reports whether a request at now should update a session last
+// accessed at lastAccessed
+func needsTouch(lastAccessed sql.NullTime, now time.Time) bool {
+	return !lastAccessed.Valid || now.Sub(lastAccessed.Time) >= sessionTouchInterval
+}
+
+// truncateUserAgent cuts ua to the characters admin_session.user_agent holds
+func truncateUserAgent(ua string) string {
+	runes := []rune(ua)
+	if len(runes) <= maxUserAgentLength {
+		return ua
+	}
+	return string(runes[:maxUserAgentLength])
+}
+
+// clearSessionCookie makes the browser forget its session
+func clearSessionCookie(w http.ResponseWriter, secure bool) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    "",
+		Path:     "/admin",
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: http.SameSiteStrictMode,
+		MaxAge:   -1,
+	})
+}
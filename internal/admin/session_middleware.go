@@ -7,13 +7,17 @@ import (
 	"log/slog"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/tokuhirom/blog4/db/admin/admindb"
 )
 
 type contextKey string
 
-const usernameKey contextKey = "username"
+const (
+	userIDKey   contextKey = "user_id"
+	usernameKey contextKey = "username"
+)
 
 // SessionMiddleware creates a middleware that checks for valid sessions
 func SessionMiddleware(queries *admindb.Queries) func(http.Handler) http.Handler {
@@ -46,16 +50,16 @@ func SessionMiddleware(queries *admindb.Queries) func(http.Handler) http.Handler
 				return
 			}
 
-			// Update last accessed time in background
-			go func() {
-				ctx := context.Background()
-				if err := queries.UpdateSessionLastAccessed(ctx, sessionID); err != nil {
+			// Record activity, but only once per sessionTouchInterval
+			if needsTouch(session.LastAccessedAt, time.Now()) {
+				if err := queries.UpdateSessionLastAccessed(r.Context(), sessionID); err != nil {
 					slog.Error("Failed to update session last accessed", slog.String("error", err.Error()))
 				}
-			}()
+			}
 
-			// Add username to context
-			ctx := context.WithValue(r.Context(), usernameKey, session.Username)
+			// Add the signed-in user to context
+			ctx := context.WithValue(r.Context(), userIDKey, session.UserID)
+			ctx = context.WithValue(ctx, usernameKey, session.Username)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
@@ -1,7 +1,10 @@
 package admin
 
 import (
+	"database/sql"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestGenerateSessionID(t *testing.T) {
@@ -32,3 +35,32 @@ func TestGenerateSessionID(t *testing.T) {
 		t.Errorf("Session ID length should be %d, got %d", expectedLen, len(id1))
 	}
 }
+
+func TestNeedsTouch(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) sql.NullTime {
+		return sql.NullTime{Time: now.Add(-d), Valid: true}
+	}
+
+	if needsTouch(at(time.Minute), now) {
+		t.Error("A session accessed a minute ago should not be updated")
+	}
+	if !needsTouch(at(sessionTouchInterval), now) {
+		t.Error("A session accessed sessionTouchInterval ago should be updated")
+	}
+	if !needsTouch(sql.NullTime{}, now) {
+		t.Error("A session never accessed should be updated")
+	}
+}
+
+func TestTruncateUserAgent(t *testing.T) {
+	if got := truncateUserAgent("Mozilla/5.0"); got != "Mozilla/5.0" {
+		t.Errorf("Short user agent should be kept, got %q", got)
+	}
+
+	// Multi-byte characters are counted as characters, as the column does
+	long := strings.Repeat("あ", maxUserAgentLength+10)
+	if got := []rune(truncateUserAgent(long)); len(got) != maxUserAgentLength {
+		t.Errorf("User agent should be cut to %d characters, got %d", maxUserAgentLength, len(got))
+	}
+}
@@ -0,0 +1,51 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/tokuhirom/blog4/internal/markdown"
+
+	"github.com/tokuhirom/blog4/db/admin/admindb"
+)
+
+// adminWikiLinkResolver resolves [[Title]] in the admin preview. Every entry,
+// private or public, links to its edit page, and unknown titles link to a
+// page that creates them. Only public entries are transcluded by ![[Title]],
+// so the preview matches what readers will see.
+type adminWikiLinkResolver struct {
+	queries *admindb.Queries
+}
+
+func (r *adminWikiLinkResolver) ResolveWikiLinks(ctx context.Context, titles []string) (map[string]string, error) {
+	rows, err := r.queries.GetEntryPathsByTitles(ctx, titles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entries by titles: %w", err)
+	}
+	hrefs := make(map[string]string, len(rows))
+	for _, row := range rows {
+		hrefs[row.Title] = "/admin/entries/edit?path=" + url.QueryEscape(row.Path)
+	}
+	return hrefs, nil
+}
+
+func (r *adminWikiLinkResolver) MissingWikiLinkHref(title string) string {
+	return "/admin/entries/new?title=" + url.QueryEscape(title)
+}
+
+func (r *adminWikiLinkResolver) LoadWikiEmbeds(ctx context.Context, titles []string) (map[string]markdown.WikiEmbed, error) {
+	rows, err := r.queries.GetPublicEntriesByTitles(ctx, titles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get public entries by titles: %w", err)
+	}
+	embeds := make(map[string]markdown.WikiEmbed, len(rows))
+	for _, row := range rows {
+		embeds[row.Title] = markdown.WikiEmbed{
+			Title: row.Title,
+			Href:  "/admin/entries/edit?path=" + url.QueryEscape(row.Path),
+			Body:  row.Body,
+		}
+	}
+	return embeds, nil
+}
@@ -0,0 +1,166 @@
+// Package adminuser manages the accounts that can sign in to the admin.
+// Passwords are stored as bcrypt hashes in admin_user; accounts may add TOTP
+// as a second factor (see totp.go).
+package adminuser
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/tokuhirom/blog4/db/admin/admindb"
+)
+
+//go:generate go run go.uber.org/mock/mockgen -source=adminuser.go -destination=mocks/mock_adminuser.go -package=mocks
+
+// MinPasswordLength is the shortest password accepted for an account
+const MinPasswordLength = 8
+
+var (
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	ErrUserNotFound       = errors.New("admin user not found")
+	ErrInvalidUsername    = errors.New("username must not be empty")
+	ErrPasswordTooShort   = fmt.Errorf("password must be at least %d characters", MinPasswordLength)
+	ErrNoAdminUser        = errors.New("admin_user is empty and ADMIN_PW is not set; create a user with `blog4 user add NAME`")
+)
+
+// dummyHash is compared against when the username is unknown, so that a
+// failed login takes as long whether or not the user exists.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("blog4-dummy-password"), bcrypt.DefaultCost)
+
+// Store defines the database operations needed to manage admin users
+type Store interface {
+	CreateAdminUser(ctx context.Context, arg admindb.CreateAdminUserParams) (int64, error)
+	GetAdminUser(ctx context.Context, id int64) (admindb.AdminUser, error)
+	GetAdminUserByUsername(ctx context.Context, username string) (admindb.AdminUser, error)
+	UpdateAdminUserPassword(ctx context.Context, arg admindb.UpdateAdminUserPasswordParams) (int64, error)
+	DeleteUserSessions(ctx context.Context, userID int64) error
+	CountAdminUsers(ctx context.Context) (int64, error)
+
+	SetAdminUserPendingTOTPSecret(ctx context.Context, arg admindb.SetAdminUserPendingTOTPSecretParams) error
+	EnableAdminUserTOTP(ctx context.Context, arg admindb.EnableAdminUserTOTPParams) (int64, error)
+	DisableAdminUserTOTP(ctx context.Context, id int64) error
+	UpdateAdminUserTOTPLastStep(ctx context.Context, arg admindb.UpdateAdminUserTOTPLastStepParams) (int64, error)
+	AddAdminUserTOTPAttempt(ctx context.Context, arg admindb.AddAdminUserTOTPAttemptParams) (int64, error)
+	ResetAdminUserTOTPAttempts(ctx context.Context, id int64) error
+	CreateRecoveryCode(ctx context.Context, arg admindb.CreateRecoveryCodeParams) error
+	DeleteRecoveryCodes(ctx context.Context, userID int64) error
+	UseRecoveryCode(ctx context.Context, arg admindb.UseRecoveryCodeParams) (int64, error)
+	CreatePendingLogin(ctx context.Context, arg admindb.CreatePendingLoginParams) error
+	GetPendingLogin(ctx context.Context, token string) (admindb.AdminPendingLogin, error)
+	AddPendingLoginAttempt(ctx context.Context, arg admindb.AddPendingLoginAttemptParams) (int64, error)
+	DeletePendingLogin(ctx context.Context, token string) (int64, error)
+	DeleteExpiredPendingLogins(ctx context.Context) error
+}
+
+// Service creates, authenticates and updates admin users
+type Service struct {
+	store Store
+}
+
+// NewService creates a new Service
+func NewService(store Store) *Service {
+	return &Service{store: store}
+}
+
+// HashPassword returns the bcrypt hash of password, after checking that it is
+// long enough. bcrypt rejects passwords longer than 72 bytes.
+func HashPassword(password string) (string, error) {
+	if len(password) < MinPasswordLength {
+		return "", ErrPasswordTooShort
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// Create adds a user and returns its id
+func (s *Service) Create(ctx context.Context, username, password string) (int64, error) {
+	username = strings.TrimSpace(username)
+	if username == "" {
+		return 0, ErrInvalidUsername
+	}
+	hash, err := HashPassword(password)
+	if err != nil {
+		return 0, err
+	}
+	id, err := s.store.CreateAdminUser(ctx, admindb.CreateAdminUserParams{
+		Username:     username,
+		PasswordHash: hash,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create admin user %s: %w", username, err)
+	}
+	return id, nil
+}
+
+// ResetPassword sets the password of username and signs the user out
+// everywhere.
+func (s *Service) ResetPassword(ctx context.Context, username, password string) error {
+	user, err := s.store.GetAdminUserByUsername(ctx, username)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get admin user %s: %w", username, err)
+	}
+	hash, err := HashPassword(password)
+	if err != nil {
+		return err
+	}
+	if _, err := s.store.UpdateAdminUserPassword(ctx, admindb.UpdateAdminUserPasswordParams{
+		PasswordHash: hash,
+		ID:           user.ID,
+	}); err != nil {
+		return fmt.Errorf("failed to update password of %s: %w", username, err)
+	}
+	if err := s.store.DeleteUserSessions(ctx, user.ID); err != nil {
+		return fmt.Errorf("failed to delete sessions of %s: %w", username, err)
+	}
+	return nil
+}
+
+// Authenticate returns the user whose username and password match, or
+// ErrInvalidCredentials.
+func (s *Service) Authenticate(ctx context.Context, username, password string) (admindb.AdminUser, error) {
+	user, err := s.store.GetAdminUserByUsername(ctx, username)
+	if errors.Is(err, sql.ErrNoRows) {
+		_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return admindb.AdminUser{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return admindb.AdminUser{}, fmt.Errorf("failed to get admin user %s: %w", username, err)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return admindb.AdminUser{}, ErrInvalidCredentials
+	}
+	return user, nil
+}
+
+// EnsureInitialUser creates the first user from the ADMIN_USER/ADMIN_PW pair
+// when there is no user yet, so that a deployment set up before admin_user
+// keeps its login. It reports whether the user was created, and returns
+// ErrNoAdminUser when there is no user and no password to create one with,
+// since nobody could sign in.
+func (s *Service) EnsureInitialUser(ctx context.Context, username, password string) (bool, error) {
+	n, err := s.store.CountAdminUsers(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to count admin users: %w", err)
+	}
+	if n > 0 {
+		return false, nil
+	}
+	if password == "" {
+		return false, ErrNoAdminUser
+	}
+	if _, err := s.Create(ctx, username, password); err != nil {
+		return false, err
+	}
+	return true, nil
+}
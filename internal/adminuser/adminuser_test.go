@@ -0,0 +1,114 @@
+package adminuser
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/tokuhirom/blog4/db/admin/admindb"
+	"github.com/tokuhirom/blog4/internal/adminuser/mocks"
+)
+
+func testUser(t *testing.T, password string) admindb.AdminUser {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	require.NoError(t, err)
+	return admindb.AdminUser{ID: 3, Username: "alice", PasswordHash: string(hash)}
+}
+
+func TestAuthenticate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mocks.NewMockStore(ctrl)
+	store.EXPECT().GetAdminUserByUsername(gomock.Any(), "alice").Return(testUser(t, "correct horse"), nil).Times(2)
+	store.EXPECT().GetAdminUserByUsername(gomock.Any(), "bob").Return(admindb.AdminUser{}, sql.ErrNoRows)
+	s := NewService(store)
+
+	user, err := s.Authenticate(context.Background(), "alice", "correct horse")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), user.ID)
+
+	_, err = s.Authenticate(context.Background(), "alice", "wrong")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	_, err = s.Authenticate(context.Background(), "bob", "correct horse")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func TestCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mocks.NewMockStore(ctrl)
+	store.EXPECT().CreateAdminUser(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg admindb.CreateAdminUserParams) (int64, error) {
+			assert.Equal(t, "alice", arg.Username)
+			assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(arg.PasswordHash), []byte("correct horse")))
+			return 3, nil
+		})
+	s := NewService(store)
+
+	id, err := s.Create(context.Background(), " alice ", "correct horse")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), id)
+
+	_, err = s.Create(context.Background(), "alice", "short")
+	assert.ErrorIs(t, err, ErrPasswordTooShort)
+
+	_, err = s.Create(context.Background(), " ", "correct horse")
+	assert.ErrorIs(t, err, ErrInvalidUsername)
+}
+
+func TestResetPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mocks.NewMockStore(ctrl)
+	store.EXPECT().GetAdminUserByUsername(gomock.Any(), "alice").Return(testUser(t, "old password"), nil)
+	store.EXPECT().UpdateAdminUserPassword(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg admindb.UpdateAdminUserPasswordParams) (int64, error) {
+			assert.Equal(t, int64(3), arg.ID)
+			assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(arg.PasswordHash), []byte("new password")))
+			return 1, nil
+		})
+	store.EXPECT().DeleteUserSessions(gomock.Any(), int64(3)).Return(nil)
+	store.EXPECT().GetAdminUserByUsername(gomock.Any(), "bob").Return(admindb.AdminUser{}, sql.ErrNoRows)
+	s := NewService(store)
+
+	require.NoError(t, s.ResetPassword(context.Background(), "alice", "new password"))
+	assert.ErrorIs(t, s.ResetPassword(context.Background(), "bob", "new password"), ErrUserNotFound)
+}
+
+func TestEnsureInitialUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mocks.NewMockStore(ctrl)
+	s := NewService(store)
+
+	// Existing users are kept whether or not ADMIN_PW is set
+	store.EXPECT().CountAdminUsers(gomock.Any()).Return(int64(1), nil).Times(2)
+	created, err := s.EnsureInitialUser(context.Background(), "admin", "")
+	require.NoError(t, err)
+	assert.False(t, created)
+	created, err = s.EnsureInitialUser(context.Background(), "admin", "password")
+	require.NoError(t, err)
+	assert.False(t, created)
+
+	// Nobody could sign in without a user or ADMIN_PW
+	store.EXPECT().CountAdminUsers(gomock.Any()).Return(int64(0), nil)
+	created, err = s.EnsureInitialUser(context.Background(), "admin", "")
+	assert.ErrorIs(t, err, ErrNoAdminUser)
+	assert.False(t, created)
+
+	store.EXPECT().CountAdminUsers(gomock.Any()).Return(int64(0), nil)
+	store.EXPECT().CreateAdminUser(gomock.Any(), gomock.Any()).Return(int64(1), nil)
+	created, err = s.EnsureInitialUser(context.Background(), "admin", "password")
+	require.NoError(t, err)
+	assert.True(t, created)
+}
@@ -0,0 +1,334 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: adminuser.go
+//
+// Generated by this command:
+//
+//	mockgen -source=adminuser.go -destination=mocks/mock_adminuser.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	admindb "github.com/tokuhirom/blog4/db/admin/admindb"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockStore is a mock of Store interface.
+type MockStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockStoreMockRecorder is the mock recorder for MockStore.
+type MockStoreMockRecorder struct {
+	mock *MockStore
+}
+
+// NewMockStore creates a new mock instance.
+func NewMockStore(ctrl *gomock.Controller) *MockStore {
+	mock := &MockStore{ctrl: ctrl}
+	mock.recorder = &MockStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStore) EXPECT() *MockStoreMockRecorder {
+	return m.recorder
+}
+
+// AddAdminUserTOTPAttempt mocks base method.
+func (m *MockStore) AddAdminUserTOTPAttempt(ctx context.Context, arg admindb.AddAdminUserTOTPAttemptParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAdminUserTOTPAttempt", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddAdminUserTOTPAttempt indicates an expected call of AddAdminUserTOTPAttempt.
+func (mr *MockStoreMockRecorder) AddAdminUserTOTPAttempt(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAdminUserTOTPAttempt", reflect.TypeOf((*MockStore)(nil).AddAdminUserTOTPAttempt), ctx, arg)
+}
+
+// AddPendingLoginAttempt mocks base method.
+func (m *MockStore) AddPendingLoginAttempt(ctx context.Context, arg admindb.AddPendingLoginAttemptParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddPendingLoginAttempt", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddPendingLoginAttempt indicates an expected call of AddPendingLoginAttempt.
+func (mr *MockStoreMockRecorder) AddPendingLoginAttempt(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPendingLoginAttempt", reflect.TypeOf((*MockStore)(nil).AddPendingLoginAttempt), ctx, arg)
+}
+
+// CountAdminUsers mocks base method.
+func (m *MockStore) CountAdminUsers(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAdminUsers", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAdminUsers indicates an expected call of CountAdminUsers.
+func (mr *MockStoreMockRecorder) CountAdminUsers(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAdminUsers", reflect.TypeOf((*MockStore)(nil).CountAdminUsers), ctx)
+}
+
+// CreateAdminUser mocks base method.
+func (m *MockStore) CreateAdminUser(ctx context.Context, arg admindb.CreateAdminUserParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAdminUser", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAdminUser indicates an expected call of CreateAdminUser.
+func (mr *MockStoreMockRecorder) CreateAdminUser(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAdminUser", reflect.TypeOf((*MockStore)(nil).CreateAdminUser), ctx, arg)
+}
+
+// CreatePendingLogin mocks base method.
+func (m *MockStore) CreatePendingLogin(ctx context.Context, arg admindb.CreatePendingLoginParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePendingLogin", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePendingLogin indicates an expected call of CreatePendingLogin.
+func (mr *MockStoreMockRecorder) CreatePendingLogin(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePendingLogin", reflect.TypeOf((*MockStore)(nil).CreatePendingLogin), ctx, arg)
+}
+
+// CreateRecoveryCode mocks base method.
+func (m *MockStore) CreateRecoveryCode(ctx context.Context, arg admindb.CreateRecoveryCodeParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRecoveryCode", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRecoveryCode indicates an expected call of CreateRecoveryCode.
+func (mr *MockStoreMockRecorder) CreateRecoveryCode(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRecoveryCode", reflect.TypeOf((*MockStore)(nil).CreateRecoveryCode), ctx, arg)
+}
+
+// DeleteExpiredPendingLogins mocks base method.
+func (m *MockStore) DeleteExpiredPendingLogins(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredPendingLogins", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredPendingLogins indicates an expected call of DeleteExpiredPendingLogins.
+func (mr *MockStoreMockRecorder) DeleteExpiredPendingLogins(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredPendingLogins", reflect.TypeOf((*MockStore)(nil).DeleteExpiredPendingLogins), ctx)
+}
+
+// DeletePendingLogin mocks base method.
+func (m *MockStore) DeletePendingLogin(ctx context.Context, token string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePendingLogin", ctx, token)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeletePendingLogin indicates an expected call of DeletePendingLogin.
+func (mr *MockStoreMockRecorder) DeletePendingLogin(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePendingLogin", reflect.TypeOf((*MockStore)(nil).DeletePendingLogin), ctx, token)
+}
+
+// DeleteRecoveryCodes mocks base method.
+func (m *MockStore) DeleteRecoveryCodes(ctx context.Context, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRecoveryCodes", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRecoveryCodes indicates an expected call of DeleteRecoveryCodes.
+func (mr *MockStoreMockRecorder) DeleteRecoveryCodes(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRecoveryCodes", reflect.TypeOf((*MockStore)(nil).DeleteRecoveryCodes), ctx, userID)
+}
+
+// DeleteUserSessions mocks base method.
+func (m *MockStore) DeleteUserSessions(ctx context.Context, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserSessions", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserSessions indicates an expected call of DeleteUserSessions.
+func (mr *MockStoreMockRecorder) DeleteUserSessions(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserSessions", reflect.TypeOf((*MockStore)(nil).DeleteUserSessions), ctx, userID)
+}
+
+// DisableAdminUserTOTP mocks base method.
+func (m *MockStore) DisableAdminUserTOTP(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableAdminUserTOTP", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableAdminUserTOTP indicates an expected call of DisableAdminUserTOTP.
+func (mr *MockStoreMockRecorder) DisableAdminUserTOTP(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableAdminUserTOTP", reflect.TypeOf((*MockStore)(nil).DisableAdminUserTOTP), ctx, id)
+}
+
+// EnableAdminUserTOTP mocks base method.
+func (m *MockStore) EnableAdminUserTOTP(ctx context.Context, arg admindb.EnableAdminUserTOTPParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableAdminUserTOTP", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableAdminUserTOTP indicates an expected call of EnableAdminUserTOTP.
+func (mr *MockStoreMockRecorder) EnableAdminUserTOTP(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableAdminUserTOTP", reflect.TypeOf((*MockStore)(nil).EnableAdminUserTOTP), ctx, arg)
+}
+
+// GetAdminUser mocks base method.
+func (m *MockStore) GetAdminUser(ctx context.Context, id int64) (admindb.AdminUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAdminUser", ctx, id)
+	ret0, _ := ret[0].(admindb.AdminUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAdminUser indicates an expected call of GetAdminUser.
+func (mr *MockStoreMockRecorder) GetAdminUser(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdminUser", reflect.TypeOf((*MockStore)(nil).GetAdminUser), ctx, id)
+}
+
+// GetAdminUserByUsername mocks base method.
+func (m *MockStore) GetAdminUserByUsername(ctx context.Context, username string) (admindb.AdminUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAdminUserByUsername", ctx, username)
+	ret0, _ := ret[0].(admindb.AdminUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAdminUserByUsername indicates an expected call of GetAdminUserByUsername.
+func (mr *MockStoreMockRecorder) GetAdminUserByUsername(ctx, username any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdminUserByUsername", reflect.TypeOf((*MockStore)(nil).GetAdminUserByUsername), ctx, username)
+}
+
+// GetPendingLogin mocks base method.
+func (m *MockStore) GetPendingLogin(ctx context.Context, token string) (admindb.AdminPendingLogin, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingLogin", ctx, token)
+	ret0, _ := ret[0].(admindb.AdminPendingLogin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingLogin indicates an expected call of GetPendingLogin.
+func (mr *MockStoreMockRecorder) GetPendingLogin(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingLogin", reflect.TypeOf((*MockStore)(nil).GetPendingLogin), ctx, token)
+}
+
+// ResetAdminUserTOTPAttempts mocks base method.
+func (m *MockStore) ResetAdminUserTOTPAttempts(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetAdminUserTOTPAttempts", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetAdminUserTOTPAttempts indicates an expected call of ResetAdminUserTOTPAttempts.
+func (mr *MockStoreMockRecorder) ResetAdminUserTOTPAttempts(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetAdminUserTOTPAttempts", reflect.TypeOf((*MockStore)(nil).ResetAdminUserTOTPAttempts), ctx, id)
+}
+
+// SetAdminUserPendingTOTPSecret mocks base method.
+func (m *MockStore) SetAdminUserPendingTOTPSecret(ctx context.Context, arg admindb.SetAdminUserPendingTOTPSecretParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAdminUserPendingTOTPSecret", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAdminUserPendingTOTPSecret indicates an expected call of SetAdminUserPendingTOTPSecret.
+func (mr *MockStoreMockRecorder) SetAdminUserPendingTOTPSecret(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAdminUserPendingTOTPSecret", reflect.TypeOf((*MockStore)(nil).SetAdminUserPendingTOTPSecret), ctx, arg)
+}
+
+// UpdateAdminUserPassword mocks base method.
+func (m *MockStore) UpdateAdminUserPassword(ctx context.Context, arg admindb.UpdateAdminUserPasswordParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAdminUserPassword", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAdminUserPassword indicates an expected call of UpdateAdminUserPassword.
+func (mr *MockStoreMockRecorder) UpdateAdminUserPassword(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAdminUserPassword", reflect.TypeOf((*MockStore)(nil).UpdateAdminUserPassword), ctx, arg)
+}
+
+// UpdateAdminUserTOTPLastStep mocks base method.
+func (m *MockStore) UpdateAdminUserTOTPLastStep(ctx context.Context, arg admindb.UpdateAdminUserTOTPLastStepParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAdminUserTOTPLastStep", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAdminUserTOTPLastStep indicates an expected call of UpdateAdminUserTOTPLastStep.
+func (mr *MockStoreMockRecorder) UpdateAdminUserTOTPLastStep(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAdminUserTOTPLastStep", reflect.TypeOf((*MockStore)(nil).UpdateAdminUserTOTPLastStep), ctx, arg)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockStore) UseRecoveryCode(ctx context.Context, arg admindb.UseRecoveryCodeParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockStoreMockRecorder) UseRecoveryCode(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockStore)(nil).UseRecoveryCode), ctx, arg)
+}
@@ -0,0 +1,388 @@
+package adminuser
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+
+	"github.com/tokuhirom/blog4/db/admin/admindb"
+)
+
+const (
+	// PendingLoginTimeout is how long the TOTP step of a login may take
+	PendingLoginTimeout = 5 * time.Minute
+	// MaxLoginAttempts is how many codes a pending login may try
+	MaxLoginAttempts = 5
+	// MaxUserLoginAttempts is how many codes may be tried for a user, across
+	// pending logins, before the user is locked out for UserLockout
+	MaxUserLoginAttempts = 10
+	// UserLockout is how long sign-in with a code is refused after
+	// MaxUserLoginAttempts failures
+	UserLockout = 15 * time.Minute
+	// RecoveryCodeCount is the number of recovery codes issued on enrolment
+	RecoveryCodeCount = 10
+
+	totpPeriod = 30
+	// totpSkew is how many steps a code may be off, for clock drift
+	totpSkew = 1
+)
+
+var (
+	ErrInvalidCode     = errors.New("invalid authentication code")
+	ErrLoginExpired    = errors.New("login expired")
+	ErrTOTPNotEnrolled = errors.New("two-factor enrolment has not been started")
+	ErrTOTPLocked      = errors.New("too many failed authentication codes")
+	ErrTOTPEnabled     = errors.New("two-factor authentication is already enabled")
+)
+
+var totpOpts = totp.ValidateOpts{
+	Period:    totpPeriod,
+	Digits:    otp.DigitsSix,
+	Algorithm: otp.AlgorithmSHA1,
+}
+
+// StartTOTPSetup generates a new secret for the user and keeps it pending
+// until EnableTOTP confirms a code from it. The returned key carries the
+// otpauth:// provisioning URI for authenticator apps. It returns
+// ErrTOTPEnabled while TOTP is on, since replacing the secret would bypass
+// the code that DisableTOTP asks for.
+func (s *Service) StartTOTPSetup(ctx context.Context, userID int64, issuer string) (*otp.Key, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TotpSecret.Valid {
+		return nil, ErrTOTPEnabled
+	}
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      issuer,
+		AccountName: user.Username,
+		Period:      totpPeriod,
+		Digits:      totpOpts.Digits,
+		Algorithm:   totpOpts.Algorithm,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate TOTP secret: %w", err)
+	}
+	if err := s.store.SetAdminUserPendingTOTPSecret(ctx, admindb.SetAdminUserPendingTOTPSecretParams{
+		TotpPendingSecret: sql.NullString{String: key.Secret(), Valid: true},
+		ID:                userID,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to store pending TOTP secret of %s: %w", user.Username, err)
+	}
+	return key, nil
+}
+
+// EnableTOTP turns on two-factor authentication once code matches the
+// pending secret, and returns a fresh set of recovery codes. Run it in a
+// transaction so that the secret and the codes change together.
+func (s *Service) EnableTOTP(ctx context.Context, userID int64, code string, now time.Time) ([]string, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TotpSecret.Valid {
+		return nil, ErrTOTPEnabled
+	}
+	if !user.TotpPendingSecret.Valid {
+		return nil, ErrTOTPNotEnrolled
+	}
+	step, ok := matchTOTP(user.TotpPendingSecret.String, code, now)
+	if !ok {
+		return nil, ErrInvalidCode
+	}
+	n, err := s.store.EnableAdminUserTOTP(ctx, admindb.EnableAdminUserTOTPParams{
+		Step:          step,
+		ID:            userID,
+		PendingSecret: user.TotpPendingSecret,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to enable TOTP of %s: %w", user.Username, err)
+	}
+	if n == 0 {
+		// Another setup replaced the pending secret, or enabled TOTP, meanwhile
+		return nil, ErrTOTPNotEnrolled
+	}
+	return s.replaceRecoveryCodes(ctx, userID)
+}
+
+// DisableTOTP turns off two-factor authentication and drops the recovery
+// codes.
+func (s *Service) DisableTOTP(ctx context.Context, userID int64) error {
+	if err := s.store.DisableAdminUserTOTP(ctx, userID); err != nil {
+		return fmt.Errorf("failed to disable TOTP of user %d: %w", userID, err)
+	}
+	if err := s.store.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes of user %d: %w", userID, err)
+	}
+	return nil
+}
+
+// ResetTOTP disables two-factor authentication of username, for a user who
+// has lost both the authenticator and the recovery codes.
+func (s *Service) ResetTOTP(ctx context.Context, username string) error {
+	user, err := s.store.GetAdminUserByUsername(ctx, username)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get admin user %s: %w", username, err)
+	}
+	return s.DisableTOTP(ctx, user.ID)
+}
+
+// VerifyCode checks a TOTP code or an unused recovery code of user, and
+// consumes it so that it cannot be used again.
+func (s *Service) VerifyCode(ctx context.Context, user admindb.AdminUser, code string, now time.Time) error {
+	if !user.TotpSecret.Valid {
+		return ErrInvalidCode
+	}
+	code = normalizeCode(code)
+	if isTOTPCode(code) {
+		step, ok := matchTOTP(user.TotpSecret.String, code, now)
+		if !ok {
+			return ErrInvalidCode
+		}
+		n, err := s.store.UpdateAdminUserTOTPLastStep(ctx, admindb.UpdateAdminUserTOTPLastStepParams{
+			Step: step,
+			ID:   user.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update TOTP step of %s: %w", user.Username, err)
+		}
+		if n == 0 {
+			// The code, or a later one, has been used already
+			return ErrInvalidCode
+		}
+		return nil
+	}
+
+	n, err := s.store.UseRecoveryCode(ctx, admindb.UseRecoveryCodeParams{
+		UserID:   user.ID,
+		CodeHash: hashRecoveryCode(code),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to use recovery code of %s: %w", user.Username, err)
+	}
+	if n == 0 {
+		return ErrInvalidCode
+	}
+	slog.Info("recovery code used", slog.String("username", user.Username))
+	return nil
+}
+
+// StartLogin records a login whose password was accepted and which waits
+// for the TOTP code. It returns the token that FinishLogin takes, or
+// ErrTOTPLocked while the user is locked out after too many wrong codes.
+func (s *Service) StartLogin(ctx context.Context, userID int64, rememberMe bool, now time.Time) (string, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if user.TotpLockedUntil.Valid && user.TotpLockedUntil.Time.After(now) {
+		return "", ErrTOTPLocked
+	}
+	if err := s.store.DeleteExpiredPendingLogins(ctx); err != nil {
+		return "", fmt.Errorf("failed to delete expired pending logins: %w", err)
+	}
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate pending login token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	if err := s.store.CreatePendingLogin(ctx, admindb.CreatePendingLoginParams{
+		Token:      token,
+		UserID:     userID,
+		RememberMe: rememberMe,
+		ExpiresAt:  now.Add(PendingLoginTimeout),
+	}); err != nil {
+		return "", fmt.Errorf("failed to create pending login: %w", err)
+	}
+	return token, nil
+}
+
+// FinishLogin checks code for the pending login token and, when it matches,
+// consumes the pending login and returns it. A wrong code returns
+// ErrInvalidCode; an unknown, expired or exhausted token returns
+// ErrLoginExpired, after which the login has to start over. Attempts are
+// also counted per user, since every password login gets a new token, and
+// ErrTOTPLocked is returned while the user is locked out.
+func (s *Service) FinishLogin(ctx context.Context, token, code string, now time.Time) (admindb.AdminPendingLogin, error) {
+	// Count the attempt before checking the code, so that concurrent guesses
+	// cannot exceed the limit
+	n, err := s.store.AddPendingLoginAttempt(ctx, admindb.AddPendingLoginAttemptParams{
+		Token:    token,
+		Attempts: MaxLoginAttempts,
+	})
+	if err != nil {
+		return admindb.AdminPendingLogin{}, fmt.Errorf("failed to count login attempt: %w", err)
+	}
+	if n == 0 {
+		if _, err := s.store.DeletePendingLogin(ctx, token); err != nil {
+			return admindb.AdminPendingLogin{}, fmt.Errorf("failed to delete pending login: %w", err)
+		}
+		return admindb.AdminPendingLogin{}, ErrLoginExpired
+	}
+
+	pending, err := s.store.GetPendingLogin(ctx, token)
+	if errors.Is(err, sql.ErrNoRows) {
+		return admindb.AdminPendingLogin{}, ErrLoginExpired
+	}
+	if err != nil {
+		return admindb.AdminPendingLogin{}, fmt.Errorf("failed to get pending login: %w", err)
+	}
+	if err := s.CheckCode(ctx, pending.UserID, code, now); err != nil {
+		if errors.Is(err, ErrTOTPLocked) {
+			if _, err := s.store.DeletePendingLogin(ctx, token); err != nil {
+				return admindb.AdminPendingLogin{}, fmt.Errorf("failed to delete pending login: %w", err)
+			}
+		}
+		return admindb.AdminPendingLogin{}, err
+	}
+
+	// Only one request may turn the token into a session
+	n, err = s.store.DeletePendingLogin(ctx, token)
+	if err != nil {
+		return admindb.AdminPendingLogin{}, fmt.Errorf("failed to delete pending login: %w", err)
+	}
+	if n == 0 {
+		return admindb.AdminPendingLogin{}, ErrLoginExpired
+	}
+	return pending, nil
+}
+
+// CheckCode verifies code like VerifyCode, counting it against the attempts
+// of the user so that codes cannot be guessed without limit. It returns
+// ErrTOTPLocked while the user is locked out. Call it outside of a
+// transaction that a wrong code rolls back, or the attempt is not counted.
+func (s *Service) CheckCode(ctx context.Context, userID int64, code string, now time.Time) error {
+	n, err := s.store.AddAdminUserTOTPAttempt(ctx, admindb.AddAdminUserTOTPAttemptParams{
+		MaxAttempts: MaxUserLoginAttempts,
+		LockedUntil: now.Add(UserLockout),
+		ID:          userID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to count code attempt of user %d: %w", userID, err)
+	}
+	if n == 0 {
+		slog.Warn("code refused while locked out", slog.Int64("user_id", userID))
+		return ErrTOTPLocked
+	}
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.VerifyCode(ctx, user, code, now); err != nil {
+		return err
+	}
+	if err := s.store.ResetAdminUserTOTPAttempts(ctx, user.ID); err != nil {
+		return fmt.Errorf("failed to reset code attempts of %s: %w", user.Username, err)
+	}
+	return nil
+}
+
+func (s *Service) getUser(ctx context.Context, id int64) (admindb.AdminUser, error) {
+	user, err := s.store.GetAdminUser(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return admindb.AdminUser{}, ErrUserNotFound
+	}
+	if err != nil {
+		return admindb.AdminUser{}, fmt.Errorf("failed to get admin user %d: %w", id, err)
+	}
+	return user, nil
+}
+
+// replaceRecoveryCodes issues a new set of recovery codes for the user and
+// invalidates the previous ones.
+func (s *Service) replaceRecoveryCodes(ctx context.Context, userID int64) ([]string, error) {
+	if err := s.store.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to delete recovery codes of user %d: %w", userID, err)
+	}
+	codes := make([]string, RecoveryCodeCount)
+	for i := range codes {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		if err := s.store.CreateRecoveryCode(ctx, admindb.CreateRecoveryCodeParams{
+			UserID:   userID,
+			CodeHash: hashRecoveryCode(normalizeCode(code)),
+		}); err != nil {
+			return nil, fmt.Errorf("failed to store recovery code of user %d: %w", userID, err)
+		}
+		codes[i] = code
+	}
+	return codes, nil
+}
+
+// matchTOTP reports whether code is valid for secret at now, allowing for
+// totpSkew steps of clock drift, and returns the time step it belongs to.
+func matchTOTP(secret, code string, now time.Time) (int64, bool) {
+	code = normalizeCode(code)
+	if !isTOTPCode(code) {
+		return 0, false
+	}
+	for i := -totpSkew; i <= totpSkew; i++ {
+		t := now.Add(time.Duration(i*totpPeriod) * time.Second)
+		expected, err := totp.GenerateCodeCustom(secret, t, totpOpts)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return t.Unix() / totpPeriod, true
+		}
+	}
+	return 0, false
+}
+
+// newRecoveryCode returns 50 random bits as ten base32 characters, grouped
+// for reading as XXXXX-XXXXX.
+func newRecoveryCode() (string, error) {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate recovery code: %w", err)
+	}
+	code := base32.StdEncoding.EncodeToString(b)[:10]
+	return code[:5] + "-" + code[5:], nil
+}
+
+// normalizeCode drops the separators people type or paste along with a code
+func normalizeCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '\t':
+			return -1
+		}
+		return r
+	}, strings.ToUpper(code))
+}
+
+func isTOTPCode(code string) bool {
+	if len(code) != int(totpOpts.Digits) {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,278 @@
+package adminuser
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/tokuhirom/blog4/db/admin/admindb"
+	"github.com/tokuhirom/blog4/internal/adminuser/mocks"
+)
+
+const testSecret = "JBSWY3DPEHPK3PXP"
+
+var testNow = time.Date(2026, 10, 18, 12, 0, 15, 0, time.UTC)
+
+func testCode(t *testing.T, at time.Time) string {
+	code, err := totp.GenerateCodeCustom(testSecret, at, totpOpts)
+	require.NoError(t, err)
+	return code
+}
+
+func totpUser() admindb.AdminUser {
+	return admindb.AdminUser{
+		ID:         3,
+		Username:   "alice",
+		TotpSecret: sql.NullString{String: testSecret, Valid: true},
+	}
+}
+
+func TestMatchTOTP(t *testing.T) {
+	step := testNow.Unix() / totpPeriod
+
+	got, ok := matchTOTP(testSecret, testCode(t, testNow), testNow)
+	assert.True(t, ok)
+	assert.Equal(t, step, got)
+
+	// A code from the previous step is accepted for clock drift
+	got, ok = matchTOTP(testSecret, testCode(t, testNow.Add(-30*time.Second)), testNow)
+	assert.True(t, ok)
+	assert.Equal(t, step-1, got)
+
+	_, ok = matchTOTP(testSecret, testCode(t, testNow.Add(-2*time.Minute)), testNow)
+	assert.False(t, ok)
+
+	_, ok = matchTOTP(testSecret, "abcdef", testNow)
+	assert.False(t, ok)
+}
+
+func TestNormalizeCode(t *testing.T) {
+	assert.Equal(t, "123456", normalizeCode(" 123 456 "))
+	assert.Equal(t, "ABCDEFGHIJ", normalizeCode("abcde-fghij"))
+}
+
+func TestEnableTOTP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mocks.NewMockStore(ctrl)
+	pending := admindb.AdminUser{
+		ID:                3,
+		Username:          "alice",
+		TotpPendingSecret: sql.NullString{String: testSecret, Valid: true},
+	}
+	store.EXPECT().GetAdminUser(gomock.Any(), int64(3)).Return(pending, nil).Times(2)
+	store.EXPECT().EnableAdminUserTOTP(gomock.Any(), admindb.EnableAdminUserTOTPParams{
+		Step:          testNow.Unix() / totpPeriod,
+		ID:            3,
+		PendingSecret: pending.TotpPendingSecret,
+	}).Return(int64(1), nil)
+	store.EXPECT().DeleteRecoveryCodes(gomock.Any(), int64(3)).Return(nil)
+	var hashes []string
+	store.EXPECT().CreateRecoveryCode(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg admindb.CreateRecoveryCodeParams) error {
+			hashes = append(hashes, arg.CodeHash)
+			return nil
+		}).Times(RecoveryCodeCount)
+	s := NewService(store)
+
+	_, err := s.EnableTOTP(context.Background(), 3, "000000", testNow)
+	assert.ErrorIs(t, err, ErrInvalidCode)
+
+	codes, err := s.EnableTOTP(context.Background(), 3, testCode(t, testNow), testNow)
+	require.NoError(t, err)
+	require.Len(t, codes, RecoveryCodeCount)
+	assert.Regexp(t, `^[A-Z2-7]{5}-[A-Z2-7]{5}$`, codes[0])
+	assert.Equal(t, hashRecoveryCode(normalizeCode(codes[0])), hashes[0])
+}
+
+func TestEnableTOTP_NotEnrolled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mocks.NewMockStore(ctrl)
+	store.EXPECT().GetAdminUser(gomock.Any(), int64(3)).Return(admindb.AdminUser{ID: 3, Username: "alice"}, nil)
+	s := NewService(store)
+
+	_, err := s.EnableTOTP(context.Background(), 3, testCode(t, testNow), testNow)
+	assert.ErrorIs(t, err, ErrTOTPNotEnrolled)
+}
+
+func TestTOTPSetup_AlreadyEnabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// A session alone must not swap the authenticator of an enabled user
+	store := mocks.NewMockStore(ctrl)
+	enabled := totpUser()
+	enabled.TotpPendingSecret = sql.NullString{String: "KRSXG5CTMVRXEZLU", Valid: true}
+	store.EXPECT().GetAdminUser(gomock.Any(), int64(3)).Return(enabled, nil).Times(2)
+	s := NewService(store)
+
+	_, err := s.StartTOTPSetup(context.Background(), 3, "example.com")
+	assert.ErrorIs(t, err, ErrTOTPEnabled)
+
+	_, err = s.EnableTOTP(context.Background(), 3, testCode(t, testNow), testNow)
+	assert.ErrorIs(t, err, ErrTOTPEnabled)
+}
+
+func TestVerifyCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mocks.NewMockStore(ctrl)
+	step := testNow.Unix() / totpPeriod
+	store.EXPECT().UpdateAdminUserTOTPLastStep(gomock.Any(), admindb.UpdateAdminUserTOTPLastStepParams{Step: step, ID: 3}).
+		Return(int64(1), nil)
+	// The same code again: totp_last_step is no longer below its step
+	store.EXPECT().UpdateAdminUserTOTPLastStep(gomock.Any(), admindb.UpdateAdminUserTOTPLastStepParams{Step: step, ID: 3}).
+		Return(int64(0), nil)
+	store.EXPECT().UseRecoveryCode(gomock.Any(), admindb.UseRecoveryCodeParams{
+		UserID:   3,
+		CodeHash: hashRecoveryCode("ABCDEFGHIJ"),
+	}).Return(int64(1), nil)
+	store.EXPECT().UseRecoveryCode(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+	s := NewService(store)
+	ctx := context.Background()
+
+	code := testCode(t, testNow)
+	require.NoError(t, s.VerifyCode(ctx, totpUser(), code, testNow))
+	assert.ErrorIs(t, s.VerifyCode(ctx, totpUser(), code, testNow), ErrInvalidCode)
+	assert.ErrorIs(t, s.VerifyCode(ctx, totpUser(), "000000", testNow.Add(-time.Hour)), ErrInvalidCode)
+
+	require.NoError(t, s.VerifyCode(ctx, totpUser(), "abcde-fghij", testNow))
+	assert.ErrorIs(t, s.VerifyCode(ctx, totpUser(), "KLMNO-PQRST", testNow), ErrInvalidCode)
+
+	// Without TOTP there is nothing to verify against
+	assert.ErrorIs(t, s.VerifyCode(ctx, admindb.AdminUser{ID: 4}, code, testNow), ErrInvalidCode)
+}
+
+func TestFinishLogin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mocks.NewMockStore(ctrl)
+	pending := admindb.AdminPendingLogin{Token: "token", UserID: 3, RememberMe: true}
+	attempt := admindb.AddPendingLoginAttemptParams{Token: "token", Attempts: MaxLoginAttempts}
+	userAttempt := admindb.AddAdminUserTOTPAttemptParams{MaxAttempts: MaxUserLoginAttempts, LockedUntil: testNow.Add(UserLockout), ID: 3}
+	s := NewService(store)
+	ctx := context.Background()
+
+	// A wrong code keeps the pending login for another try
+	wrongAt := testNow.Add(-time.Hour)
+	store.EXPECT().AddPendingLoginAttempt(gomock.Any(), attempt).Return(int64(1), nil)
+	store.EXPECT().GetPendingLogin(gomock.Any(), "token").Return(pending, nil)
+	store.EXPECT().AddAdminUserTOTPAttempt(gomock.Any(), admindb.AddAdminUserTOTPAttemptParams{
+		MaxAttempts: MaxUserLoginAttempts,
+		LockedUntil: wrongAt.Add(UserLockout),
+		ID:          3,
+	}).Return(int64(1), nil)
+	store.EXPECT().GetAdminUser(gomock.Any(), int64(3)).Return(totpUser(), nil)
+	_, err := s.FinishLogin(ctx, "token", "000000", wrongAt)
+	assert.ErrorIs(t, err, ErrInvalidCode)
+
+	// A matching code resets the attempts of the user
+	store.EXPECT().AddPendingLoginAttempt(gomock.Any(), attempt).Return(int64(1), nil)
+	store.EXPECT().GetPendingLogin(gomock.Any(), "token").Return(pending, nil)
+	store.EXPECT().AddAdminUserTOTPAttempt(gomock.Any(), userAttempt).Return(int64(1), nil)
+	store.EXPECT().GetAdminUser(gomock.Any(), int64(3)).Return(totpUser(), nil)
+	store.EXPECT().UpdateAdminUserTOTPLastStep(gomock.Any(), gomock.Any()).Return(int64(1), nil)
+	store.EXPECT().ResetAdminUserTOTPAttempts(gomock.Any(), int64(3)).Return(nil)
+	store.EXPECT().DeletePendingLogin(gomock.Any(), "token").Return(int64(1), nil)
+	got, err := s.FinishLogin(ctx, "token", testCode(t, testNow), testNow)
+	require.NoError(t, err)
+	assert.Equal(t, pending, got)
+
+	// Unknown, expired and exhausted tokens all start the login over
+	store.EXPECT().AddPendingLoginAttempt(gomock.Any(), attempt).Return(int64(0), nil)
+	store.EXPECT().DeletePendingLogin(gomock.Any(), "token").Return(int64(0), nil)
+	_, err = s.FinishLogin(ctx, "token", testCode(t, testNow), testNow)
+	assert.ErrorIs(t, err, ErrLoginExpired)
+}
+
+func TestFinishLogin_Locked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mocks.NewMockStore(ctrl)
+	pending := admindb.AdminPendingLogin{Token: "token", UserID: 3}
+	s := NewService(store)
+
+	// Even the right code is refused, and the pending login is dropped, while
+	// the user is locked out
+	store.EXPECT().AddPendingLoginAttempt(gomock.Any(), gomock.Any()).Return(int64(1), nil)
+	store.EXPECT().GetPendingLogin(gomock.Any(), "token").Return(pending, nil)
+	store.EXPECT().AddAdminUserTOTPAttempt(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+	store.EXPECT().DeletePendingLogin(gomock.Any(), "token").Return(int64(1), nil)
+	_, err := s.FinishLogin(context.Background(), "token", testCode(t, testNow), testNow)
+	assert.ErrorIs(t, err, ErrTOTPLocked)
+}
+
+func TestStartLogin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mocks.NewMockStore(ctrl)
+	s := NewService(store)
+	ctx := context.Background()
+
+	locked := totpUser()
+	locked.TotpLockedUntil = sql.NullTime{Time: testNow.Add(time.Minute), Valid: true}
+	store.EXPECT().GetAdminUser(gomock.Any(), int64(3)).Return(locked, nil)
+	_, err := s.StartLogin(ctx, 3, false, testNow)
+	assert.ErrorIs(t, err, ErrTOTPLocked)
+
+	// The lockout is over once locked_until has passed
+	store.EXPECT().GetAdminUser(gomock.Any(), int64(3)).Return(locked, nil)
+	store.EXPECT().DeleteExpiredPendingLogins(gomock.Any()).Return(nil)
+	store.EXPECT().CreatePendingLogin(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg admindb.CreatePendingLoginParams) error {
+			assert.Equal(t, int64(3), arg.UserID)
+			assert.True(t, arg.RememberMe)
+			assert.Equal(t, testNow.Add(time.Hour+PendingLoginTimeout), arg.ExpiresAt)
+			return nil
+		})
+	token, err := s.StartLogin(ctx, 3, true, testNow.Add(time.Hour))
+	require.NoError(t, err)
+	assert.NotEmpty(t, token)
+}
+
+func TestCheckCode_LocksAfterTooManyFailures(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Mirror AddAdminUserTOTPAttempt: the attempt that reaches the limit
+	// locks the user, and a locked user's attempts are not counted
+	store := mocks.NewMockStore(ctrl)
+	failures := 0
+	var lockedUntil time.Time
+	store.EXPECT().AddAdminUserTOTPAttempt(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg admindb.AddAdminUserTOTPAttemptParams) (int64, error) {
+			if testNow.Before(lockedUntil) {
+				return 0, nil
+			}
+			failures++
+			if failures >= int(arg.MaxAttempts) {
+				failures = 0
+				lockedUntil = arg.LockedUntil
+			}
+			return 1, nil
+		}).AnyTimes()
+	store.EXPECT().GetAdminUser(gomock.Any(), int64(3)).Return(totpUser(), nil).AnyTimes()
+	store.EXPECT().UseRecoveryCode(gomock.Any(), gomock.Any()).Return(int64(0), nil).AnyTimes()
+	s := NewService(store)
+	ctx := context.Background()
+
+	for i := 0; i < MaxUserLoginAttempts; i++ {
+		assert.ErrorIs(t, s.CheckCode(ctx, 3, "KLMNO-PQRST", testNow), ErrInvalidCode)
+	}
+	// Once locked out even the right code is refused
+	assert.ErrorIs(t, s.CheckCode(ctx, 3, testCode(t, testNow), testNow), ErrTOTPLocked)
+	assert.Equal(t, testNow.Add(UserLockout), lockedUntil)
+}
@@ -0,0 +1,168 @@
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"time"
+
+	"github.com/tokuhirom/blog4/db/admin/admindb"
+	"github.com/tokuhirom/blog4/internal/paapi"
+)
+
+// asinTagRe matches [asin:XXX:detail] tags, the same syntax AsinParser renders.
+var asinTagRe = regexp.MustCompile(`\[asin:([A-Z0-9]+):detail]`)
+
+// amazonCacheLookupChunk bounds the IN list when checking which ASINs are cached.
+const amazonCacheLookupChunk = 500
+
+// AmazonCacheService keeps amazon_cache filled for every ASIN referenced from
+// an entry body, fetching missing and stale products from PA-API.
+type AmazonCacheService struct {
+	store      AmazonCacheStore
+	fetcher    AmazonProductFetcher
+	staleAfter time.Duration
+	now        func() time.Time
+}
+
+// NewAmazonCacheService creates a service that refetches cached products
+// older than staleAfter.
+func NewAmazonCacheService(store AmazonCacheStore, fetcher AmazonProductFetcher, staleAfter time.Duration) *AmazonCacheService {
+	return &AmazonCacheService{
+		store:      store,
+		fetcher:    fetcher,
+		staleAfter: staleAfter,
+		now:        time.Now,
+	}
+}
+
+// FindTargets returns the ASINs referenced from entry bodies that are not
+// cached yet or were fetched more than staleAfter ago.
+func (s *AmazonCacheService) FindTargets(ctx context.Context) ([]string, error) {
+	entries, err := s.store.ListEntryBodiesWithAsin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list entries with ASIN: %w", err)
+	}
+
+	var asins []string
+	seen := make(map[string]struct{})
+	for _, entry := range entries {
+		for _, m := range asinTagRe.FindAllStringSubmatch(entry.Body, -1) {
+			if _, dup := seen[m[1]]; !dup {
+				seen[m[1]] = struct{}{}
+				asins = append(asins, m[1])
+			}
+		}
+	}
+
+	staleBefore := s.now().Add(-s.staleAfter)
+	fresh := make(map[string]struct{})
+	for start := 0; start < len(asins); start += amazonCacheLookupChunk {
+		end := min(start+amazonCacheLookupChunk, len(asins))
+		rows, err := s.store.GetAmazonCacheFetchedAt(ctx, asins[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("failed to get amazon cache state: %w", err)
+		}
+		for _, row := range rows {
+			if row.FetchedAt.After(staleBefore) {
+				fresh[row.Asin] = struct{}{}
+			}
+		}
+	}
+
+	targets := make([]string, 0, len(asins))
+	for _, asin := range asins {
+		if _, ok := fresh[asin]; !ok {
+			targets = append(targets, asin)
+		}
+	}
+	return targets, nil
+}
+
+// Refresh fetches every target ASIN from PA-API and upserts the results. A
+// failed batch is logged and skipped so that one bad request does not stop the
+// rest. ASINs that PA-API does not return are marked not found, so that they
+// wait out staleAfter like the others. It returns the number of products
+// stored.
+func (s *AmazonCacheService) Refresh(ctx context.Context) (int, error) {
+	targets, err := s.FindTargets(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if len(targets) == 0 {
+		return 0, nil
+	}
+	slog.Info("refreshing amazon cache", slog.Int("asins", len(targets)))
+
+	stored := 0
+	for start := 0; start < len(targets); start += paapi.MaxItemIDs {
+		batch := targets[start:min(start+paapi.MaxItemIDs, len(targets))]
+
+		items, err := s.fetcher.GetItems(ctx, batch)
+		if err != nil {
+			if ctx.Err() != nil {
+				return stored, ctx.Err()
+			}
+			slog.Error("failed to get items from PA-API", slog.Any("asins", batch), slog.Any("error", err))
+			continue
+		}
+
+		returned := make(map[string]struct{}, len(items))
+		for _, item := range items {
+			returned[item.ASIN] = struct{}{}
+			_, err := s.store.UpsertAmazonProductDetail(ctx, admindb.UpsertAmazonProductDetailParams{
+				Asin:           item.ASIN,
+				Title:          sql.NullString{String: item.Title, Valid: item.Title != ""},
+				ImageMediumUrl: sql.NullString{String: item.ImageURL, Valid: item.ImageURL != ""},
+				Link:           item.DetailPageURL,
+			})
+			if err != nil {
+				return stored, fmt.Errorf("failed to upsert amazon product %s: %w", item.ASIN, err)
+			}
+			stored++
+		}
+		for _, asin := range batch {
+			if _, ok := returned[asin]; ok {
+				continue
+			}
+			slog.Warn("PA-API did not return the item", slog.String("asin", asin))
+			if err := s.store.MarkAmazonProductNotFound(ctx, asin); err != nil {
+				return stored, fmt.Errorf("failed to mark amazon product %s not found: %w", asin, err)
+			}
+		}
+	}
+	return stored, nil
+}
+
+// StartAmazonCacheWorker refreshes amazon_cache once at startup and then every
+// hour. It does nothing when PA-API credentials are not configured.
+func StartAmazonCacheWorker(config *Config, queries *admindb.Queries) {
+	if config.AmazonPaapi5AccessKey == "" || config.AmazonPaapi5SecretKey == "" || config.AmazonPaapi5PartnerTag == "" {
+		slog.Info("PA-API credentials are not configured, amazon cache worker is disabled")
+		return
+	}
+
+	client := paapi.NewClient(config.AmazonPaapi5AccessKey, config.AmazonPaapi5SecretKey, config.AmazonPaapi5PartnerTag)
+	client.Endpoint = config.AmazonPaapi5Endpoint
+	service := NewAmazonCacheService(queries, client, 24*time.Hour)
+
+	refresh := func() {
+		stored, err := service.Refresh(context.Background())
+		if err != nil {
+			slog.Error("failed to refresh amazon cache", slog.Any("error", err))
+			return
+		}
+		if stored > 0 {
+			slog.Info("refreshed amazon cache", slog.Int("stored", stored))
+		}
+	}
+
+	refresh()
+	ticker := time.NewTicker(1 * time.Hour)
+	defer ticker.Stop()
+	for range ticker.C {
+		refresh()
+	}
+}
@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/tokuhirom/blog4/db/admin/admindb"
+	"github.com/tokuhirom/blog4/internal/mocks"
+	"github.com/tokuhirom/blog4/internal/paapi"
+)
+
+func newTestAmazonCacheService(store AmazonCacheStore, fetcher AmazonProductFetcher) *AmazonCacheService {
+	service := NewAmazonCacheService(store, fetcher, 24*time.Hour)
+	service.now = func() time.Time { return time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC) }
+	return service
+}
+
+func TestAmazonCacheService_FindTargets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mocks.NewMockAmazonCacheStore(ctrl)
+	mockStore.EXPECT().
+		ListEntryBodiesWithAsin(gomock.Any()).
+		Return([]admindb.ListEntryBodiesWithAsinRow{
+			{Path: "a", Body: "[asin:FRESH00001:detail] [asin:STALE00001:detail]"},
+			{Path: "b", Body: "[asin:MISSING001:detail] [asin:FRESH00001:detail]"},
+		}, nil)
+	mockStore.EXPECT().
+		GetAmazonCacheFetchedAt(gomock.Any(), []string{"FRESH00001", "STALE00001", "MISSING001"}).
+		Return([]admindb.GetAmazonCacheFetchedAtRow{
+			{Asin: "FRESH00001", FetchedAt: time.Date(2026, 1, 9, 12, 0, 0, 0, time.UTC)},
+			{Asin: "STALE00001", FetchedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		}, nil)
+
+	service := newTestAmazonCacheService(mockStore, mocks.NewMockAmazonProductFetcher(ctrl))
+	targets, err := service.FindTargets(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"STALE00001", "MISSING001"}, targets)
+}
+
+func TestAmazonCacheService_Refresh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	body := ""
+	var asins []string
+	for i := range paapi.MaxItemIDs + 2 {
+		asin := string(rune('A'+i)) + "000000000"
+		asins = append(asins, asin)
+		body += "[asin:" + asin + ":detail]\n"
+	}
+
+	mockStore := mocks.NewMockAmazonCacheStore(ctrl)
+	mockFetcher := mocks.NewMockAmazonProductFetcher(ctrl)
+	mockStore.EXPECT().
+		ListEntryBodiesWithAsin(gomock.Any()).
+		Return([]admindb.ListEntryBodiesWithAsinRow{{Path: "a", Body: body}}, nil)
+	mockStore.EXPECT().
+		GetAmazonCacheFetchedAt(gomock.Any(), asins).
+		Return(nil, nil)
+
+	gomock.InOrder(
+		mockFetcher.EXPECT().
+			GetItems(gomock.Any(), asins[:paapi.MaxItemIDs]).
+			Return(nil, errors.New("throttled")),
+		mockFetcher.EXPECT().
+			GetItems(gomock.Any(), asins[paapi.MaxItemIDs:]).
+			Return([]paapi.Item{{
+				ASIN:          asins[paapi.MaxItemIDs],
+				Title:         "Book",
+				ImageURL:      "https://example.com/book.jpg",
+				DetailPageURL: "https://www.amazon.co.jp/dp/book",
+			}}, nil),
+	)
+	mockStore.EXPECT().
+		UpsertAmazonProductDetail(gomock.Any(), admindb.UpsertAmazonProductDetailParams{
+			Asin:           asins[paapi.MaxItemIDs],
+			Title:          sql.NullString{String: "Book", Valid: true},
+			ImageMediumUrl: sql.NullString{String: "https://example.com/book.jpg", Valid: true},
+			Link:           "https://www.amazon.co.jp/dp/book",
+		}).
+		Return(int64(1), nil)
+	// Not returned: recorded so that it is not asked for again every hour.
+	// The failed batch is left to retry.
+	mockStore.EXPECT().
+		MarkAmazonProductNotFound(gomock.Any(), asins[paapi.MaxItemIDs+1]).
+		Return(nil)
+
+	service := newTestAmazonCacheService(mockStore, mockFetcher)
+	stored, err := service.Refresh(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored)
+}
+
+func TestAmazonCacheService_RefreshNothingToDo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mocks.NewMockAmazonCacheStore(ctrl)
+	mockStore.EXPECT().
+		ListEntryBodiesWithAsin(gomock.Any()).
+		Return([]admindb.ListEntryBodiesWithAsinRow{{Path: "a", Body: "no products"}}, nil)
+
+	service := newTestAmazonCacheService(mockStore, mocks.NewMockAmazonProductFetcher(ctrl))
+	stored, err := service.Refresh(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, stored)
+}
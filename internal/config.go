@@ -2,6 +2,9 @@ package internal
 
 import (
 	"strings"
+	"time"
+
+	"github.com/tokuhirom/blog4/internal/preview"
 )
 
 type Config struct {
@@ -15,15 +18,23 @@ type Config struct {
 	DBPort     int    `env:"DATABASE_PORT" envDefault:"3306"`
 	DBName     string `env:"DATABASE_NAME" envDefault:"blog3"`
 
+	// The first admin user, created at startup while admin_user is empty.
+	// Further users are managed with `blog4 user`.
 	AdminUser     string `env:"ADMIN_USER"   envDefault:"admin"`
 	AdminPassword string `env:"ADMIN_PW"`
 
 	AllowedOrigins []string `env:"ALLOWED_ORIGINS" envSeparator:","`
 
+	// Origin the admin is served from, such as http://localhost:8181.
+	// Passkeys are bound to its host. Defaults to SITE_BASE_URL.
+	AdminOrigin string `env:"ADMIN_ORIGIN"`
+
 	HubUrls string `env:"HUB_URLS"`
 
-	AmazonPaapi5AccessKey string `env:"AMAZON_PAAPI5_ACCESS_KEY"`
-	AmazonPaapi5SecretKey string `env:"AMAZON_PAAPI5_SECRET_KEY"`
+	AmazonPaapi5AccessKey  string `env:"AMAZON_PAAPI5_ACCESS_KEY"`
+	AmazonPaapi5SecretKey  string `env:"AMAZON_PAAPI5_SECRET_KEY"`
+	AmazonPaapi5PartnerTag string `env:"AMAZON_PAAPI5_PARTNER_TAG"`
+	AmazonPaapi5Endpoint   string `env:"AMAZON_PAAPI5_ENDPOINT" envDefault:"https://webservices.amazon.co.jp"`
 
 	S3AccessKeyId           string `env:"S3_ACCESS_KEY_ID"`
 	S3SecretAccessKey       string `env:"S3_SECRET_ACCESS_KEY"`
@@ -36,8 +47,19 @@ type Config struct {
 	SiteBaseUrl string `env:"SITE_BASE_URL" envDefault:"https://blog.64p.org"`
 	SiteName    string `env:"SITE_NAME" envDefault:"tokuhirom's blog"`
 
+	SiteDescription string `env:"SITE_DESCRIPTION" envDefault:"tokuhirom's thoughts"`
+	SiteAuthorName  string `env:"SITE_AUTHOR_NAME" envDefault:"Tokuhiro Matsuno"`
+	SiteAuthorEmail string `env:"SITE_AUTHOR_EMAIL" envDefault:"tokuhirom+blog-gmail.com"`
+
+	// Number of entries in /feed, /feed.atom and /feed.json
+	FeedSize int `env:"FEED_SIZE" envDefault:"10"`
+
 	BackupEncryptionKey string `env:"BACKUP_ENCRYPTION_KEY"`
 
+	// Key for signing preview links of entries that are not public yet.
+	// Preview links are disabled when it is empty.
+	PreviewSecret string `env:"PREVIEW_SECRET"`
+
 	WebAccelGuard string `env:"WEBACCEL_GUARD"`
 
 	// 9*60*60=32400 is JST
@@ -57,3 +79,26 @@ func (c *Config) GetHubUrls() []string {
 		"https://pubsubhubbub.superfeedr.com/",
 	}
 }
+
+// GetAdminOrigin returns the origin the admin is served from
+func (c *Config) GetAdminOrigin() string {
+	if c.AdminOrigin != "" {
+		return c.AdminOrigin
+	}
+	return c.SiteBaseUrl
+}
+
+// PreviewSigner returns the signer of preview links, or nil when
+// PREVIEW_SECRET is not set.
+func (c *Config) PreviewSigner() *preview.Signer {
+	if c.PreviewSecret == "" {
+		return nil
+	}
+	return preview.NewSigner(c.PreviewSecret)
+}
+
+// Location is the time zone the blog's dates are written in. The database
+// connection uses it too, so datetime columns and query arguments agree.
+func (c *Config) Location() *time.Location {
+	return time.FixedZone("Asia/Tokyo", c.TimeZoneOffset)
+}
@@ -46,3 +46,15 @@ func TestConfig_GetHubUrls(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_GetAdminOrigin(t *testing.T) {
+	c := &Config{SiteBaseUrl: "https://blog.example.com"}
+	if got := c.GetAdminOrigin(); got != "https://blog.example.com" {
+		t.Errorf("Config.GetAdminOrigin() = %v, want SITE_BASE_URL", got)
+	}
+
+	c.AdminOrigin = "http://localhost:8181"
+	if got := c.GetAdminOrigin(); got != "http://localhost:8181" {
+		t.Errorf("Config.GetAdminOrigin() = %v, want ADMIN_ORIGIN", got)
+	}
+}
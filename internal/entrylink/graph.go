@@ -0,0 +1,139 @@
+package entrylink
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tokuhirom/blog4/db/admin/admindb"
+)
+
+//go:generate go run go.uber.org/mock/mockgen -source=graph.go -destination=mocks/mock_graph.go -package=mocks
+
+// GraphStore defines the database operations needed to read the link graph around an entry
+type GraphStore interface {
+	GetLinkedEntries(ctx context.Context, srcPath string) ([]admindb.GetLinkedEntriesRow, error)
+	GetEntriesByLinkedTitle(ctx context.Context, dstTitle string) ([]admindb.GetEntriesByLinkedTitleRow, error)
+	GetTwoHopEntries(ctx context.Context, arg admindb.GetTwoHopEntriesParams) ([]admindb.GetTwoHopEntriesRow, error)
+}
+
+// Node is one entry in the link graph. A Missing node is a link target that
+// has no entry yet, so only Title is set.
+type Node struct {
+	Path       string
+	Title      string
+	Body       string
+	Visibility string
+	ImageURL   string
+	Missing    bool
+}
+
+// TwoHop groups the entries that link to Target, which the current entry
+// also links to.
+type TwoHop struct {
+	Target  Node
+	Entries []Node
+}
+
+// Graph is the neighbourhood of an entry: what it links to, what links to it,
+// and the entries reachable through a shared link target.
+type Graph struct {
+	Links     []Node
+	Backlinks []Node
+	TwoHops   []TwoHop
+}
+
+// LoadGraph reads the link graph around the entry at path, whose title is title.
+// The entry itself is never listed as its own neighbour.
+func LoadGraph(ctx context.Context, store GraphStore, path string, title string) (*Graph, error) {
+	linked, err := store.GetLinkedEntries(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get linked entries for %s: %w", path, err)
+	}
+
+	graph := &Graph{
+		Links:     make([]Node, 0, len(linked)),
+		Backlinks: []Node{},
+		TwoHops:   []TwoHop{},
+	}
+
+	targets := make([]string, 0, len(linked))
+	targetIndex := make(map[string]int, len(linked))
+	for _, row := range linked {
+		if row.Path.String == path {
+			continue
+		}
+		node := Node{
+			Path:       row.Path.String,
+			Title:      row.Title.String,
+			Body:       row.Body.String,
+			Visibility: string(row.Visibility.EntryVisibility),
+			ImageURL:   row.ImageUrl.String,
+			Missing:    !row.Path.Valid,
+		}
+		if node.Missing {
+			node.Title = row.DstTitle
+		}
+		graph.Links = append(graph.Links, node)
+
+		key := strings.ToLower(row.DstTitle)
+		if _, ok := targetIndex[key]; !ok {
+			targetIndex[key] = len(targets)
+			targets = append(targets, row.DstTitle)
+			graph.TwoHops = append(graph.TwoHops, TwoHop{Target: node})
+		}
+	}
+
+	backlinks, err := store.GetEntriesByLinkedTitle(ctx, title)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get backlinks for %s: %w", path, err)
+	}
+	for _, row := range backlinks {
+		if row.Path == path {
+			continue
+		}
+		graph.Backlinks = append(graph.Backlinks, Node{
+			Path:       row.Path,
+			Title:      row.Title,
+			Body:       row.Body,
+			Visibility: string(row.Visibility),
+			ImageURL:   row.ImageUrl.String,
+		})
+	}
+
+	if len(targets) == 0 {
+		return graph, nil
+	}
+
+	hops, err := store.GetTwoHopEntries(ctx, admindb.GetTwoHopEntriesParams{
+		DstTitles: targets,
+		SrcPath:   path,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get two-hop entries for %s: %w", path, err)
+	}
+	for _, row := range hops {
+		i, ok := targetIndex[strings.ToLower(row.DstTitle)]
+		if !ok || row.Path == graph.TwoHops[i].Target.Path {
+			continue
+		}
+		graph.TwoHops[i].Entries = append(graph.TwoHops[i].Entries, Node{
+			Path:       row.Path,
+			Title:      row.Title,
+			Body:       row.Body,
+			Visibility: string(row.Visibility),
+			ImageURL:   row.ImageUrl.String,
+		})
+	}
+
+	// A target nobody else links to adds nothing beyond the outgoing link itself.
+	twoHops := graph.TwoHops[:0]
+	for _, hop := range graph.TwoHops {
+		if len(hop.Entries) > 0 {
+			twoHops = append(twoHops, hop)
+		}
+	}
+	graph.TwoHops = twoHops
+
+	return graph, nil
+}
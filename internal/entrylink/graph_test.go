@@ -0,0 +1,104 @@
+package entrylink
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/tokuhirom/blog4/db/admin/admindb"
+	"github.com/tokuhirom/blog4/internal/entrylink/mocks"
+)
+
+func linkedRow(dstTitle string, path string) admindb.GetLinkedEntriesRow {
+	row := admindb.GetLinkedEntriesRow{DstTitle: dstTitle}
+	if path != "" {
+		row.Path = sql.NullString{String: path, Valid: true}
+		row.Title = sql.NullString{String: dstTitle, Valid: true}
+		row.Visibility = admindb.NullEntryVisibility{EntryVisibility: admindb.EntryVisibilityPublic, Valid: true}
+	}
+	return row
+}
+
+func TestLoadGraph(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mocks.NewMockGraphStore(ctrl)
+	mockStore.EXPECT().
+		GetLinkedEntries(gomock.Any(), "self").
+		Return([]admindb.GetLinkedEntriesRow{
+			linkedRow("Go", "go"),
+			linkedRow("Someday", ""),
+			linkedRow("Self", "self"),
+		}, nil)
+	mockStore.EXPECT().
+		GetEntriesByLinkedTitle(gomock.Any(), "Self").
+		Return([]admindb.GetEntriesByLinkedTitleRow{
+			{Path: "fan", Title: "Fan", Visibility: admindb.EntryVisibilityPrivate},
+			{Path: "self", Title: "Self"},
+		}, nil)
+	mockStore.EXPECT().
+		GetTwoHopEntries(gomock.Any(), admindb.GetTwoHopEntriesParams{
+			DstTitles: []string{"Go", "Someday"},
+			SrcPath:   "self",
+		}).
+		Return([]admindb.GetTwoHopEntriesRow{
+			{DstTitle: "go", Path: "gopher", Title: "Gopher"},
+			{DstTitle: "Go", Path: "go", Title: "Go"},
+		}, nil)
+
+	graph, err := LoadGraph(context.Background(), mockStore, "self", "Self")
+	require.NoError(t, err)
+
+	require.Len(t, graph.Links, 2)
+	assert.Equal(t, Node{Path: "go", Title: "Go", Visibility: "public"}, graph.Links[0])
+	assert.Equal(t, Node{Title: "Someday", Missing: true}, graph.Links[1])
+
+	require.Len(t, graph.Backlinks, 1)
+	assert.Equal(t, "fan", graph.Backlinks[0].Path)
+	assert.Equal(t, "private", graph.Backlinks[0].Visibility)
+
+	// "Someday" has no other referrers and the target's own self-link is dropped.
+	require.Len(t, graph.TwoHops, 1)
+	assert.Equal(t, "Go", graph.TwoHops[0].Target.Title)
+	require.Len(t, graph.TwoHops[0].Entries, 1)
+	assert.Equal(t, "gopher", graph.TwoHops[0].Entries[0].Path)
+}
+
+func TestLoadGraph_NoOutgoingLinksSkipsTwoHop(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mocks.NewMockGraphStore(ctrl)
+	mockStore.EXPECT().
+		GetLinkedEntries(gomock.Any(), "lonely").
+		Return(nil, nil)
+	mockStore.EXPECT().
+		GetEntriesByLinkedTitle(gomock.Any(), "Lonely").
+		Return(nil, nil)
+
+	graph, err := LoadGraph(context.Background(), mockStore, "lonely", "Lonely")
+	require.NoError(t, err)
+	assert.Empty(t, graph.Links)
+	assert.Empty(t, graph.Backlinks)
+	assert.Empty(t, graph.TwoHops)
+}
+
+func TestLoadGraph_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mocks.NewMockGraphStore(ctrl)
+	mockStore.EXPECT().
+		GetLinkedEntries(gomock.Any(), "broken").
+		Return(nil, errors.New("db down"))
+
+	_, err := LoadGraph(context.Background(), mockStore, "broken", "Broken")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get linked entries")
+}
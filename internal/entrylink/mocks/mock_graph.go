@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: graph.go
+//
+// Generated by this command:
+//
+//	mockgen -source=graph.go -destination=mocks/mock_graph.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	admindb "github.com/tokuhirom/blog4/db/admin/admindb"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockGraphStore is a mock of GraphStore interface.
+type MockGraphStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockGraphStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockGraphStoreMockRecorder is the mock recorder for MockGraphStore.
+type MockGraphStoreMockRecorder struct {
+	mock *MockGraphStore
+}
+
+// NewMockGraphStore creates a new mock instance.
+func NewMockGraphStore(ctrl *gomock.Controller) *MockGraphStore {
+	mock := &MockGraphStore{ctrl: ctrl}
+	mock.recorder = &MockGraphStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGraphStore) EXPECT() *MockGraphStoreMockRecorder {
+	return m.recorder
+}
+
+// GetEntriesByLinkedTitle mocks base method.
+func (m *MockGraphStore) GetEntriesByLinkedTitle(ctx context.Context, dstTitle string) ([]admindb.GetEntriesByLinkedTitleRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntriesByLinkedTitle", ctx, dstTitle)
+	ret0, _ := ret[0].([]admindb.GetEntriesByLinkedTitleRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntriesByLinkedTitle indicates an expected call of GetEntriesByLinkedTitle.
+func (mr *MockGraphStoreMockRecorder) GetEntriesByLinkedTitle(ctx, dstTitle any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntriesByLinkedTitle", reflect.TypeOf((*MockGraphStore)(nil).GetEntriesByLinkedTitle), ctx, dstTitle)
+}
+
+// GetLinkedEntries mocks base method.
+func (m *MockGraphStore) GetLinkedEntries(ctx context.Context, srcPath string) ([]admindb.GetLinkedEntriesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLinkedEntries", ctx, srcPath)
+	ret0, _ := ret[0].([]admindb.GetLinkedEntriesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLinkedEntries indicates an expected call of GetLinkedEntries.
+func (mr *MockGraphStoreMockRecorder) GetLinkedEntries(ctx, srcPath any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLinkedEntries", reflect.TypeOf((*MockGraphStore)(nil).GetLinkedEntries), ctx, srcPath)
+}
+
+// GetTwoHopEntries mocks base method.
+func (m *MockGraphStore) GetTwoHopEntries(ctx context.Context, arg admindb.GetTwoHopEntriesParams) ([]admindb.GetTwoHopEntriesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTwoHopEntries", ctx, arg)
+	ret0, _ := ret[0].([]admindb.GetTwoHopEntriesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTwoHopEntries indicates an expected call of GetTwoHopEntries.
+func (mr *MockGraphStoreMockRecorder) GetTwoHopEntries(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTwoHopEntries", reflect.TypeOf((*MockGraphStore)(nil).GetTwoHopEntries), ctx, arg)
+}
@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: rename.go
+//
+// Generated by this command:
+//
+//	mockgen -source=rename.go -destination=mocks/mock_rename.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	admindb "github.com/tokuhirom/blog4/db/admin/admindb"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRenameStore is a mock of RenameStore interface.
+type MockRenameStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockRenameStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockRenameStoreMockRecorder is the mock recorder for MockRenameStore.
+type MockRenameStoreMockRecorder struct {
+	mock *MockRenameStore
+}
+
+// NewMockRenameStore creates a new mock instance.
+func NewMockRenameStore(ctrl *gomock.Controller) *MockRenameStore {
+	mock := &MockRenameStore{ctrl: ctrl}
+	mock.recorder = &MockRenameStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRenameStore) EXPECT() *MockRenameStoreMockRecorder {
+	return m.recorder
+}
+
+// DeleteEntryLinkByPath mocks base method.
+func (m *MockRenameStore) DeleteEntryLinkByPath(ctx context.Context, srcPath string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEntryLinkByPath", ctx, srcPath)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteEntryLinkByPath indicates an expected call of DeleteEntryLinkByPath.
+func (mr *MockRenameStoreMockRecorder) DeleteEntryLinkByPath(ctx, srcPath any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEntryLinkByPath", reflect.TypeOf((*MockRenameStore)(nil).DeleteEntryLinkByPath), ctx, srcPath)
+}
+
+// GetEntriesByLinkedTitle mocks base method.
+func (m *MockRenameStore) GetEntriesByLinkedTitle(ctx context.Context, dstTitle string) ([]admindb.GetEntriesByLinkedTitleRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntriesByLinkedTitle", ctx, dstTitle)
+	ret0, _ := ret[0].([]admindb.GetEntriesByLinkedTitleRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntriesByLinkedTitle indicates an expected call of GetEntriesByLinkedTitle.
+func (mr *MockRenameStoreMockRecorder) GetEntriesByLinkedTitle(ctx, dstTitle any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntriesByLinkedTitle", reflect.TypeOf((*MockRenameStore)(nil).GetEntriesByLinkedTitle), ctx, dstTitle)
+}
+
+// InsertEntryLinks mocks base method.
+func (m *MockRenameStore) InsertEntryLinks(ctx context.Context, srcPath string, dstTitles []string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertEntryLinks", ctx, srcPath, dstTitles)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertEntryLinks indicates an expected call of InsertEntryLinks.
+func (mr *MockRenameStoreMockRecorder) InsertEntryLinks(ctx, srcPath, dstTitles any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertEntryLinks", reflect.TypeOf((*MockRenameStore)(nil).InsertEntryLinks), ctx, srcPath, dstTitles)
+}
+
+// RewriteEntryBody mocks base method.
+func (m *MockRenameStore) RewriteEntryBody(ctx context.Context, arg admindb.RewriteEntryBodyParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RewriteEntryBody", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RewriteEntryBody indicates an expected call of RewriteEntryBody.
+func (mr *MockRenameStoreMockRecorder) RewriteEntryBody(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RewriteEntryBody", reflect.TypeOf((*MockRenameStore)(nil).RewriteEntryBody), ctx, arg)
+}
@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=mocks/mock_service.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLinkStore is a mock of LinkStore interface.
+type MockLinkStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockLinkStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockLinkStoreMockRecorder is the mock recorder for MockLinkStore.
+type MockLinkStoreMockRecorder struct {
+	mock *MockLinkStore
+}
+
+// NewMockLinkStore creates a new mock instance.
+func NewMockLinkStore(ctrl *gomock.Controller) *MockLinkStore {
+	mock := &MockLinkStore{ctrl: ctrl}
+	mock.recorder = &MockLinkStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLinkStore) EXPECT() *MockLinkStoreMockRecorder {
+	return m.recorder
+}
+
+// DeleteEntryLinkByPath mocks base method.
+func (m *MockLinkStore) DeleteEntryLinkByPath(ctx context.Context, srcPath string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEntryLinkByPath", ctx, srcPath)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteEntryLinkByPath indicates an expected call of DeleteEntryLinkByPath.
+func (mr *MockLinkStoreMockRecorder) DeleteEntryLinkByPath(ctx, srcPath any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEntryLinkByPath", reflect.TypeOf((*MockLinkStore)(nil).DeleteEntryLinkByPath), ctx, srcPath)
+}
+
+// InsertEntryLinks mocks base method.
+func (m *MockLinkStore) InsertEntryLinks(ctx context.Context, srcPath string, dstTitles []string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertEntryLinks", ctx, srcPath, dstTitles)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertEntryLinks indicates an expected call of InsertEntryLinks.
+func (mr *MockLinkStoreMockRecorder) InsertEntryLinks(ctx, srcPath, dstTitles any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertEntryLinks", reflect.TypeOf((*MockLinkStore)(nil).InsertEntryLinks), ctx, srcPath, dstTitles)
+}
@@ -0,0 +1,71 @@
+package entrylink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/tokuhirom/blog4/db/admin/admindb"
+	"github.com/tokuhirom/blog4/internal/markdown"
+)
+
+//go:generate go run go.uber.org/mock/mockgen -source=rename.go -destination=mocks/mock_rename.go -package=mocks
+
+// ErrEntryChanged is returned when a linking entry was modified after it was
+// read, so its body could not be rewritten safely.
+var ErrEntryChanged = errors.New("linking entry was updated by another request")
+
+// RenameStore defines the database operations needed to rewrite inbound links
+type RenameStore interface {
+	LinkStore
+	GetEntriesByLinkedTitle(ctx context.Context, dstTitle string) ([]admindb.GetEntriesByLinkedTitleRow, error)
+	RewriteEntryBody(ctx context.Context, arg admindb.RewriteEntryBodyParams) (int64, error)
+}
+
+// RewriteInboundLinks rewrites [[oldTitle]] to [[newTitle]] in every entry
+// that links to oldTitle, except the renamed entry at path itself, and
+// replaces their entry_link rows. It returns the entries whose bodies were
+// changed. The store should be bound to the transaction that renames the entry.
+func RewriteInboundLinks(ctx context.Context, store RenameStore, path string, oldTitle string, newTitle string) ([]Node, error) {
+	linking, err := store.GetEntriesByLinkedTitle(ctx, oldTitle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entries linking to %s: %w", oldTitle, err)
+	}
+
+	rewritten := []Node{}
+	for _, entry := range linking {
+		if entry.Path == path {
+			continue
+		}
+
+		body, changed := markdown.RewriteWikiLinks(entry.Body, oldTitle, newTitle)
+		if !changed {
+			continue
+		}
+
+		rows, err := store.RewriteEntryBody(ctx, admindb.RewriteEntryBodyParams{
+			Body:      body,
+			Path:      entry.Path,
+			UpdatedAt: entry.UpdatedAt,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to rewrite body of %s: %w", entry.Path, err)
+		}
+		if rows == 0 {
+			return nil, fmt.Errorf("failed to rewrite body of %s: %w", entry.Path, ErrEntryChanged)
+		}
+
+		if err := ReplaceLinks(ctx, store, entry.Path, body); err != nil {
+			return nil, err
+		}
+
+		rewritten = append(rewritten, Node{
+			Path:       entry.Path,
+			Title:      entry.Title,
+			Body:       body,
+			Visibility: string(entry.Visibility),
+			ImageURL:   entry.ImageUrl.String,
+		})
+	}
+	return rewritten, nil
+}
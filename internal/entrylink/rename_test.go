@@ -0,0 +1,85 @@
+package entrylink
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/tokuhirom/blog4/db/admin/admindb"
+	"github.com/tokuhirom/blog4/internal/entrylink/mocks"
+)
+
+func TestRewriteInboundLinks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	updatedAt := sql.NullTime{Time: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC), Valid: true}
+
+	mockStore := mocks.NewMockRenameStore(ctrl)
+	mockStore.EXPECT().
+		GetEntriesByLinkedTitle(gomock.Any(), "Old").
+		Return([]admindb.GetEntriesByLinkedTitleRow{
+			{Path: "renamed", Title: "New", Body: "self [[Old]]"},
+			{Path: "fan", Title: "Fan", Body: "see [[Old|it]] and [[Other]]", UpdatedAt: updatedAt},
+			{Path: "code-only", Title: "Code", Body: "`[[Old]]`"},
+		}, nil)
+	gomock.InOrder(
+		mockStore.EXPECT().
+			RewriteEntryBody(gomock.Any(), admindb.RewriteEntryBodyParams{
+				Body:      "see [[New|it]] and [[Other]]",
+				Path:      "fan",
+				UpdatedAt: updatedAt,
+			}).
+			Return(int64(1), nil),
+		mockStore.EXPECT().
+			DeleteEntryLinkByPath(gomock.Any(), "fan").
+			Return(int64(2), nil),
+		mockStore.EXPECT().
+			InsertEntryLinks(gomock.Any(), "fan", []string{"New", "Other"}).
+			Return(int64(2), nil),
+	)
+
+	rewritten, err := RewriteInboundLinks(context.Background(), mockStore, "renamed", "Old", "New")
+	require.NoError(t, err)
+	require.Len(t, rewritten, 1)
+	assert.Equal(t, "fan", rewritten[0].Path)
+	assert.Equal(t, "Fan", rewritten[0].Title)
+}
+
+func TestRewriteInboundLinks_Conflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mocks.NewMockRenameStore(ctrl)
+	mockStore.EXPECT().
+		GetEntriesByLinkedTitle(gomock.Any(), "Old").
+		Return([]admindb.GetEntriesByLinkedTitleRow{
+			{Path: "fan", Title: "Fan", Body: "[[Old]]"},
+		}, nil)
+	mockStore.EXPECT().
+		RewriteEntryBody(gomock.Any(), gomock.Any()).
+		Return(int64(0), nil)
+
+	_, err := RewriteInboundLinks(context.Background(), mockStore, "renamed", "Old", "New")
+	assert.ErrorIs(t, err, ErrEntryChanged)
+}
+
+func TestRewriteInboundLinks_QueryError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mocks.NewMockRenameStore(ctrl)
+	mockStore.EXPECT().
+		GetEntriesByLinkedTitle(gomock.Any(), "Old").
+		Return(nil, errors.New("db down"))
+
+	_, err := RewriteInboundLinks(context.Background(), mockStore, "renamed", "Old", "New")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get entries linking to Old")
+}
@@ -0,0 +1,87 @@
+package entrylink
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"github.com/tokuhirom/blog4/db/admin/admindb"
+	"github.com/tokuhirom/blog4/internal/markdown"
+)
+
+//go:generate go run go.uber.org/mock/mockgen -source=service.go -destination=mocks/mock_service.go -package=mocks
+
+// LinkStore defines the database operations needed to maintain entry_link
+type LinkStore interface {
+	DeleteEntryLinkByPath(ctx context.Context, srcPath string) (int64, error)
+	InsertEntryLinks(ctx context.Context, srcPath string, dstTitles []string) (int64, error)
+}
+
+// ReplaceLinks parses body for [[...]] links and replaces every outgoing link
+// of path with them. The store should be bound to a transaction so that
+// readers never observe a half-updated link set.
+func ReplaceLinks(ctx context.Context, store LinkStore, path string, body string) error {
+	if _, err := store.DeleteEntryLinkByPath(ctx, path); err != nil {
+		return fmt.Errorf("failed to delete entry links for %s: %w", path, err)
+	}
+
+	titles := markdown.ExtractWikiLinks(body)
+	if len(titles) == 0 {
+		return nil
+	}
+	if _, err := store.InsertEntryLinks(ctx, path, titles); err != nil {
+		return fmt.Errorf("failed to insert entry links for %s: %w", path, err)
+	}
+	return nil
+}
+
+// Service rebuilds the link graph for entries that already exist
+type Service struct {
+	db      *sql.DB
+	queries *admindb.Queries
+}
+
+// NewService creates a new Service
+func NewService(db *sql.DB, queries *admindb.Queries) *Service {
+	return &Service{
+		db:      db,
+		queries: queries,
+	}
+}
+
+// RebuildAll re-parses every entry body and replaces its outgoing links, one
+// transaction per entry. It returns the number of entries processed. A failure
+// on one entry is logged and does not stop the rest.
+func (s *Service) RebuildAll(ctx context.Context) (int, error) {
+	entries, err := s.queries.AdminListAllEntries(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list entries: %w", err)
+	}
+
+	processed := 0
+	for _, entry := range entries {
+		if err := s.rebuildEntry(ctx, entry.Path, entry.Body); err != nil {
+			slog.Error("failed to rebuild entry links", slog.String("path", entry.Path), slog.Any("error", err))
+			continue
+		}
+		processed++
+	}
+	return processed, nil
+}
+
+func (s *Service) rebuildEntry(ctx context.Context, path string, body string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := ReplaceLinks(ctx, s.queries.WithTx(tx), path, body); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
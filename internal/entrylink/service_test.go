@@ -0,0 +1,75 @@
+package entrylink
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/tokuhirom/blog4/internal/entrylink/mocks"
+)
+
+func TestReplaceLinks_InsertsParsedLinks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mocks.NewMockLinkStore(ctrl)
+	gomock.InOrder(
+		mockStore.EXPECT().
+			DeleteEntryLinkByPath(gomock.Any(), "2026/01/01/120000").
+			Return(int64(3), nil),
+		mockStore.EXPECT().
+			InsertEntryLinks(gomock.Any(), "2026/01/01/120000", []string{"Foo", "Bar"}).
+			Return(int64(2), nil),
+	)
+
+	err := ReplaceLinks(context.Background(), mockStore, "2026/01/01/120000", "[[Foo]] and [[Bar]] and [[Foo]]")
+	require.NoError(t, err)
+}
+
+func TestReplaceLinks_NoLinksOnlyDeletes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mocks.NewMockLinkStore(ctrl)
+	mockStore.EXPECT().
+		DeleteEntryLinkByPath(gomock.Any(), "getting-started").
+		Return(int64(1), nil)
+
+	err := ReplaceLinks(context.Background(), mockStore, "getting-started", "no links here")
+	require.NoError(t, err)
+}
+
+func TestReplaceLinks_DeleteError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mocks.NewMockLinkStore(ctrl)
+	mockStore.EXPECT().
+		DeleteEntryLinkByPath(gomock.Any(), "getting-started").
+		Return(int64(0), errors.New("db down"))
+
+	err := ReplaceLinks(context.Background(), mockStore, "getting-started", "[[Foo]]")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to delete entry links")
+}
+
+func TestReplaceLinks_InsertError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mocks.NewMockLinkStore(ctrl)
+	mockStore.EXPECT().
+		DeleteEntryLinkByPath(gomock.Any(), "getting-started").
+		Return(int64(0), nil)
+	mockStore.EXPECT().
+		InsertEntryLinks(gomock.Any(), "getting-started", []string{"Foo"}).
+		Return(int64(0), errors.New("db down"))
+
+	err := ReplaceLinks(context.Background(), mockStore, "getting-started", "[[Foo]]")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to insert entry links")
+}
@@ -0,0 +1,91 @@
+package entrytag
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"github.com/tokuhirom/blog4/db/admin/admindb"
+	"github.com/tokuhirom/blog4/internal/markdown"
+)
+
+//go:generate go run go.uber.org/mock/mockgen -source=body.go -destination=mocks/mock_body.go -package=mocks
+
+// BodyTagStore defines the database operations needed to keep the #hashtags
+// of an entry's body in entry_tag
+type BodyTagStore interface {
+	DeleteBodyEntryTags(ctx context.Context, path string) (int64, error)
+	InsertBodyEntryTag(ctx context.Context, arg admindb.InsertBodyEntryTagParams) (int64, error)
+}
+
+// ReplaceBodyTags parses body for #hashtags and replaces the body tags of path
+// with them. Tags set in the tag editor are left alone; a hashtag that is also
+// one of those stays a manual tag. Only the first MaxTagsPerEntry hashtags are
+// kept. The store should be bound to the transaction that saved body.
+func ReplaceBodyTags(ctx context.Context, store BodyTagStore, path string, body string) error {
+	if _, err := store.DeleteBodyEntryTags(ctx, path); err != nil {
+		return fmt.Errorf("failed to delete body tags for %s: %w", path, err)
+	}
+
+	tags := markdown.ExtractHashtags(body)
+	if len(tags) > MaxTagsPerEntry {
+		tags = tags[:MaxTagsPerEntry]
+	}
+	for _, tag := range tags {
+		if _, err := store.InsertBodyEntryTag(ctx, admindb.InsertBodyEntryTagParams{Path: path, Tag: tag}); err != nil {
+			return fmt.Errorf("failed to insert body tag %q for %s: %w", tag, path, err)
+		}
+	}
+	return nil
+}
+
+// Service rebuilds the body tags of entries that already exist
+type Service struct {
+	db      *sql.DB
+	queries *admindb.Queries
+}
+
+// NewService creates a new Service
+func NewService(db *sql.DB, queries *admindb.Queries) *Service {
+	return &Service{
+		db:      db,
+		queries: queries,
+	}
+}
+
+// RebuildAll re-parses every entry body and replaces its body tags, one
+// transaction per entry. It returns the number of entries processed. A failure
+// on one entry is logged and does not stop the rest.
+func (s *Service) RebuildAll(ctx context.Context) (int, error) {
+	entries, err := s.queries.AdminListAllEntries(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list entries: %w", err)
+	}
+
+	processed := 0
+	for _, entry := range entries {
+		if err := s.rebuildEntry(ctx, entry.Path, entry.Body); err != nil {
+			slog.Error("failed to rebuild body tags", slog.String("path", entry.Path), slog.Any("error", err))
+			continue
+		}
+		processed++
+	}
+	return processed, nil
+}
+
+func (s *Service) rebuildEntry(ctx context.Context, path string, body string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := ReplaceBodyTags(ctx, s.queries.WithTx(tx), path, body); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package entrytag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/tokuhirom/blog4/db/admin/admindb"
+	"github.com/tokuhirom/blog4/internal/entrytag/mocks"
+)
+
+func TestReplaceBodyTags_InsertsHashtags(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mocks.NewMockBodyTagStore(ctrl)
+	gomock.InOrder(
+		mockStore.EXPECT().
+			DeleteBodyEntryTags(gomock.Any(), "2026/01/01/120000").
+			Return(int64(1), nil),
+		mockStore.EXPECT().
+			InsertBodyEntryTag(gomock.Any(), admindb.InsertBodyEntryTagParams{Path: "2026/01/01/120000", Tag: "Go"}).
+			Return(int64(1), nil),
+		mockStore.EXPECT().
+			InsertBodyEntryTag(gomock.Any(), admindb.InsertBodyEntryTagParams{Path: "2026/01/01/120000", Tag: "日本語"}).
+			Return(int64(1), nil),
+	)
+
+	err := ReplaceBodyTags(context.Background(), mockStore, "2026/01/01/120000", "#Go と #日本語 と #go と `#code`")
+	require.NoError(t, err)
+}
+
+func TestReplaceBodyTags_NoHashtagsOnlyDeletes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mocks.NewMockBodyTagStore(ctrl)
+	mockStore.EXPECT().
+		DeleteBodyEntryTags(gomock.Any(), "getting-started").
+		Return(int64(2), nil)
+
+	err := ReplaceBodyTags(context.Background(), mockStore, "getting-started", "# Heading\n\nissue #123")
+	require.NoError(t, err)
+}
+
+func TestReplaceBodyTags_CapsTagCount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var body strings.Builder
+	for i := range MaxTagsPerEntry + 5 {
+		_, _ = fmt.Fprintf(&body, "#tag%d ", i)
+	}
+
+	mockStore := mocks.NewMockBodyTagStore(ctrl)
+	mockStore.EXPECT().
+		DeleteBodyEntryTags(gomock.Any(), "getting-started").
+		Return(int64(0), nil)
+	mockStore.EXPECT().
+		InsertBodyEntryTag(gomock.Any(), gomock.Any()).
+		Return(int64(1), nil).
+		Times(MaxTagsPerEntry)
+
+	err := ReplaceBodyTags(context.Background(), mockStore, "getting-started", body.String())
+	require.NoError(t, err)
+}
+
+func TestReplaceBodyTags_InsertError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mocks.NewMockBodyTagStore(ctrl)
+	mockStore.EXPECT().
+		DeleteBodyEntryTags(gomock.Any(), "getting-started").
+		Return(int64(0), nil)
+	mockStore.EXPECT().
+		InsertBodyEntryTag(gomock.Any(), gomock.Any()).
+		Return(int64(0), errors.New("db down"))
+
+	err := ReplaceBodyTags(context.Background(), mockStore, "getting-started", "#blog4")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to insert body tag")
+}
@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: body.go
+//
+// Generated by this command:
+//
+//	mockgen -source=body.go -destination=mocks/mock_body.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	admindb "github.com/tokuhirom/blog4/db/admin/admindb"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBodyTagStore is a mock of BodyTagStore interface.
+type MockBodyTagStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockBodyTagStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockBodyTagStoreMockRecorder is the mock recorder for MockBodyTagStore.
+type MockBodyTagStoreMockRecorder struct {
+	mock *MockBodyTagStore
+}
+
+// NewMockBodyTagStore creates a new mock instance.
+func NewMockBodyTagStore(ctrl *gomock.Controller) *MockBodyTagStore {
+	mock := &MockBodyTagStore{ctrl: ctrl}
+	mock.recorder = &MockBodyTagStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBodyTagStore) EXPECT() *MockBodyTagStoreMockRecorder {
+	return m.recorder
+}
+
+// DeleteBodyEntryTags mocks base method.
+func (m *MockBodyTagStore) DeleteBodyEntryTags(ctx context.Context, path string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBodyEntryTags", ctx, path)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteBodyEntryTags indicates an expected call of DeleteBodyEntryTags.
+func (mr *MockBodyTagStoreMockRecorder) DeleteBodyEntryTags(ctx, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBodyEntryTags", reflect.TypeOf((*MockBodyTagStore)(nil).DeleteBodyEntryTags), ctx, path)
+}
+
+// InsertBodyEntryTag mocks base method.
+func (m *MockBodyTagStore) InsertBodyEntryTag(ctx context.Context, arg admindb.InsertBodyEntryTagParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertBodyEntryTag", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertBodyEntryTag indicates an expected call of InsertBodyEntryTag.
+func (mr *MockBodyTagStoreMockRecorder) InsertBodyEntryTag(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertBodyEntryTag", reflect.TypeOf((*MockBodyTagStore)(nil).InsertBodyEntryTag), ctx, arg)
+}
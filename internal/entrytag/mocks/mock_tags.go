@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: tags.go
+//
+// Generated by this command:
+//
+//	mockgen -source=tags.go -destination=mocks/mock_tags.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	admindb "github.com/tokuhirom/blog4/db/admin/admindb"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTagStore is a mock of TagStore interface.
+type MockTagStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockTagStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockTagStoreMockRecorder is the mock recorder for MockTagStore.
+type MockTagStoreMockRecorder struct {
+	mock *MockTagStore
+}
+
+// NewMockTagStore creates a new mock instance.
+func NewMockTagStore(ctrl *gomock.Controller) *MockTagStore {
+	mock := &MockTagStore{ctrl: ctrl}
+	mock.recorder = &MockTagStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTagStore) EXPECT() *MockTagStoreMockRecorder {
+	return m.recorder
+}
+
+// DeleteEntryTagsByPath mocks base method.
+func (m *MockTagStore) DeleteEntryTagsByPath(ctx context.Context, path string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEntryTagsByPath", ctx, path)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteEntryTagsByPath indicates an expected call of DeleteEntryTagsByPath.
+func (mr *MockTagStoreMockRecorder) DeleteEntryTagsByPath(ctx, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEntryTagsByPath", reflect.TypeOf((*MockTagStore)(nil).DeleteEntryTagsByPath), ctx, path)
+}
+
+// InsertEntryTag mocks base method.
+func (m *MockTagStore) InsertEntryTag(ctx context.Context, arg admindb.InsertEntryTagParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertEntryTag", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertEntryTag indicates an expected call of InsertEntryTag.
+func (mr *MockTagStoreMockRecorder) InsertEntryTag(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertEntryTag", reflect.TypeOf((*MockTagStore)(nil).InsertEntryTag), ctx, arg)
+}
@@ -0,0 +1,84 @@
+// Package entrytag maintains the tags attached to entries.
+package entrytag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/tokuhirom/blog4/db/admin/admindb"
+)
+
+//go:generate go run go.uber.org/mock/mockgen -source=tags.go -destination=mocks/mock_tags.go -package=mocks
+
+const (
+	// MaxTagLength matches the width of entry_tag.tag.
+	MaxTagLength = 100
+	// MaxTagsPerEntry keeps a single entry from flooding the tag index.
+	MaxTagsPerEntry = 20
+)
+
+// ErrInvalidTag is returned by Normalize for a tag that cannot be stored or
+// used as a /tag/{name} path segment.
+var ErrInvalidTag = errors.New("invalid tag")
+
+// Normalize trims tags and drops a leading '#', blanks and duplicates.
+// Duplicates are compared case-insensitively, as the column's collation does,
+// and the first spelling wins.
+func Normalize(tags []string) ([]string, error) {
+	seen := map[string]struct{}{}
+	var result []string
+	for _, tag := range tags {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+		if tag == "" {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > MaxTagLength {
+			return nil, fmt.Errorf("%w: %q is longer than %d characters", ErrInvalidTag, tag, MaxTagLength)
+		}
+		if strings.ContainsFunc(tag, func(r rune) bool { return r == '/' || unicode.IsSpace(r) || unicode.IsControl(r) }) {
+			return nil, fmt.Errorf("%w: %q contains a slash or whitespace", ErrInvalidTag, tag)
+		}
+
+		key := strings.ToLower(tag)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		result = append(result, tag)
+	}
+	if len(result) > MaxTagsPerEntry {
+		return nil, fmt.Errorf("%w: an entry can have at most %d tags", ErrInvalidTag, MaxTagsPerEntry)
+	}
+	return result, nil
+}
+
+// TagStore defines the database operations needed to maintain entry_tag
+type TagStore interface {
+	DeleteEntryTagsByPath(ctx context.Context, path string) (int64, error)
+	InsertEntryTag(ctx context.Context, arg admindb.InsertEntryTagParams) (int64, error)
+}
+
+// ReplaceTags replaces every tag of path with tags, which must already be
+// normalized. The store should be bound to a transaction.
+func ReplaceTags(ctx context.Context, store TagStore, path string, tags []string) error {
+	if _, err := store.DeleteEntryTagsByPath(ctx, path); err != nil {
+		return fmt.Errorf("failed to delete entry tags for %s: %w", path, err)
+	}
+	for _, tag := range tags {
+		if _, err := store.InsertEntryTag(ctx, admindb.InsertEntryTagParams{Path: path, Tag: tag}); err != nil {
+			return fmt.Errorf("failed to insert entry tag %q for %s: %w", tag, path, err)
+		}
+	}
+	return nil
+}
+
+// LikePrefix turns user input into a LIKE pattern matching tags that start
+// with it.
+func LikePrefix(prefix string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return r.Replace(prefix) + "%"
+}
@@ -0,0 +1,82 @@
+package entrytag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/tokuhirom/blog4/db/admin/admindb"
+	"github.com/tokuhirom/blog4/internal/entrytag/mocks"
+)
+
+func TestNormalize(t *testing.T) {
+	tags, err := Normalize([]string{" Go ", "#日本語", "", "go", "#", "Rust"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Go", "日本語", "Rust"}, tags)
+}
+
+func TestNormalize_Invalid(t *testing.T) {
+	tooMany := make([]string, MaxTagsPerEntry+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("tag%d", i)
+	}
+
+	for _, tags := range [][]string{
+		{"a/b"},
+		{"two words"},
+		{strings.Repeat("あ", MaxTagLength+1)},
+		tooMany,
+	} {
+		_, err := Normalize(tags)
+		assert.ErrorIs(t, err, ErrInvalidTag, "%v", tags)
+	}
+}
+
+func TestReplaceTags(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mocks.NewMockTagStore(ctrl)
+	gomock.InOrder(
+		mockStore.EXPECT().
+			DeleteEntryTagsByPath(gomock.Any(), "2026/01/01/120000").
+			Return(int64(1), nil),
+		mockStore.EXPECT().
+			InsertEntryTag(gomock.Any(), admindb.InsertEntryTagParams{Path: "2026/01/01/120000", Tag: "Go"}).
+			Return(int64(1), nil),
+		mockStore.EXPECT().
+			InsertEntryTag(gomock.Any(), admindb.InsertEntryTagParams{Path: "2026/01/01/120000", Tag: "日本語"}).
+			Return(int64(1), nil),
+	)
+
+	err := ReplaceTags(context.Background(), mockStore, "2026/01/01/120000", []string{"Go", "日本語"})
+	require.NoError(t, err)
+}
+
+func TestReplaceTags_InsertError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mocks.NewMockTagStore(ctrl)
+	mockStore.EXPECT().
+		DeleteEntryTagsByPath(gomock.Any(), "a").
+		Return(int64(0), nil)
+	mockStore.EXPECT().
+		InsertEntryTag(gomock.Any(), gomock.Any()).
+		Return(int64(0), errors.New("db down"))
+
+	err := ReplaceTags(context.Background(), mockStore, "a", []string{"Go"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to insert entry tag")
+}
+
+func TestLikePrefix(t *testing.T) {
+	assert.Equal(t, `go%`, LikePrefix("go"))
+	assert.Equal(t, `100\%\_\\%`, LikePrefix(`100%_\`))
+}
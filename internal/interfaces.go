@@ -5,6 +5,7 @@ import (
 	"database/sql"
 
 	"github.com/tokuhirom/blog4/db/admin/admindb"
+	"github.com/tokuhirom/blog4/internal/paapi"
 )
 
 // EntryImageStore defines the database operations needed by EntryImageService
@@ -15,3 +16,16 @@ type EntryImageStore interface {
 	GetAmazonImageUrlByAsin(ctx context.Context, asin string) (sql.NullString, error)
 	InsertEntryImage(ctx context.Context, params admindb.InsertEntryImageParams) (int64, error)
 }
+
+// AmazonCacheStore defines the database operations needed by AmazonCacheService
+type AmazonCacheStore interface {
+	ListEntryBodiesWithAsin(ctx context.Context) ([]admindb.ListEntryBodiesWithAsinRow, error)
+	GetAmazonCacheFetchedAt(ctx context.Context, asins []string) ([]admindb.GetAmazonCacheFetchedAtRow, error)
+	UpsertAmazonProductDetail(ctx context.Context, arg admindb.UpsertAmazonProductDetailParams) (int64, error)
+	MarkAmazonProductNotFound(ctx context.Context, asin string) error
+}
+
+// AmazonProductFetcher looks up product details by ASIN, e.g. *paapi.Client
+type AmazonProductFetcher interface {
+	GetItems(ctx context.Context, asins []string) ([]paapi.Item, error)
+}
@@ -0,0 +1,200 @@
+package markdown
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// maxHashtagLength matches the width of entry_tag.tag.
+const maxHashtagLength = 100
+
+// Hashtag turns #tag in text into a link to the tag's page.
+type Hashtag struct {
+	// Href returns the link for a tag; nil links to the public tag page.
+	Href func(tag string) string
+}
+
+// PublicTagHref returns the path of a tag's page on the public site.
+func PublicTagHref(tag string) string {
+	return "/tag/" + url.PathEscape(tag)
+}
+
+// AdminTagHref returns the admin entry list narrowed to a tag, which also
+// lists private entries.
+func AdminTagHref(tag string) string {
+	return "/admin/entries/search?tag=" + url.QueryEscape(tag)
+}
+
+func (h Hashtag) Extend(markdown goldmark.Markdown) {
+	href := h.Href
+	if href == nil {
+		href = PublicTagHref
+	}
+	markdown.Parser().AddOptions(
+		parser.WithInlineParsers(
+			util.Prioritized(&HashtagParser{}, 100),
+		),
+	)
+	markdown.Renderer().AddOptions(
+		renderer.WithNodeRenderers(
+			util.Prioritized(&HashtagRenderer{Href: href}, 199),
+		),
+	)
+}
+
+type HashtagParser struct{}
+
+func (p *HashtagParser) Trigger() []byte {
+	return []byte{'#'}
+}
+
+type HashtagNode struct {
+	ast.BaseInline
+
+	Tag []byte
+}
+
+var HashtagKind = ast.NewNodeKind("Hashtag")
+
+func (n *HashtagNode) Kind() ast.NodeKind {
+	return HashtagKind
+}
+
+func (n *HashtagNode) Dump(src []byte, level int) {
+	ast.DumpHelper(n, src, level, map[string]string{
+		"Tag": string(n.Tag),
+	}, nil)
+}
+
+// #tag, #日本語, #go_lang, #blog-4
+//
+// The '#' must start a word: "C#", "/#top" and "&#123;" are not tags. A tag
+// runs over letters, digits, '_' and '-', and must contain a letter, so that
+// issue numbers like #123 stay plain text. Code spans never reach inline
+// parsers, and autolinked URLs are consumed before their '#' is seen.
+func (p *HashtagParser) Parse(_ ast.Node, block text.Reader, _ parser.Context) ast.Node {
+	if !startsHashtag(block.PrecendingCharacter()) {
+		return nil
+	}
+
+	line, _ := block.PeekLine()
+	tag := scanHashtag(line[1:])
+	if tag == nil {
+		return nil
+	}
+
+	block.Advance(1 + len(tag))
+	return &HashtagNode{Tag: tag}
+}
+
+// startsHashtag reports whether '#' after prev begins a tag. prev is '\n' at
+// the start of a line.
+func startsHashtag(prev rune) bool {
+	if unicode.IsLetter(prev) || unicode.IsDigit(prev) || unicode.IsMark(prev) {
+		return false
+	}
+	return !strings.ContainsRune("/&#:=?%@_-", prev)
+}
+
+func isHashtagRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) || r == '_' || r == '-'
+}
+
+// scanHashtag returns the tag at the start of s, or nil if there is none.
+func scanHashtag(s []byte) []byte {
+	end, runes := 0, 0
+	hasLetter := false
+	for end < len(s) {
+		r, size := utf8.DecodeRune(s[end:])
+		if !isHashtagRune(r) {
+			break
+		}
+		hasLetter = hasLetter || unicode.IsLetter(r)
+		end += size
+		runes++
+	}
+	// A trailing '-' is punctuation ("#go-"), not part of the tag.
+	for end > 0 && s[end-1] == '-' {
+		end--
+		runes--
+	}
+	if end == 0 || !hasLetter || runes > maxHashtagLength {
+		return nil
+	}
+	return s[:end]
+}
+
+type HashtagRenderer struct {
+	Href func(tag string) string
+}
+
+func (r *HashtagRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(HashtagKind, r.Render)
+}
+
+func (r *HashtagRenderer) Render(writer util.BufWriter, _ []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n, ok := node.(*HashtagNode)
+	if !ok {
+		return ast.WalkStop, fmt.Errorf("unexpected node %T, expected *HashtagNode", node)
+	}
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`<a href="`)
+	buf.Write(util.EscapeHTML([]byte(r.Href(string(n.Tag)))))
+	buf.WriteString(`" class="hashtag">#`)
+	buf.Write(util.EscapeHTML(n.Tag))
+	buf.WriteString("</a>")
+	if _, err := writer.Write(buf.Bytes()); err != nil {
+		return ast.WalkStop, fmt.Errorf("failed to write hashtag: %w", err)
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+// ExtractHashtags returns the distinct #tags of body in order of appearance,
+// parsed the same way as for rendering. Duplicates are compared
+// case-insensitively, like entry_tag.tag.
+func ExtractHashtags(body string) []string {
+	md := goldmark.New(
+		goldmark.WithExtensions(
+			extension.GFM,
+			extension.Linkify,
+			&WikiLink{Context: context.Background()},
+			&Hashtag{},
+		),
+	)
+	doc := md.Parser().Parse(text.NewReader([]byte(body)))
+
+	var tags []string
+	seen := make(map[string]struct{})
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		if hn, ok := n.(*HashtagNode); ok {
+			tag := string(hn.Tag)
+			key := strings.ToLower(tag)
+			if _, dup := seen[key]; !dup {
+				seen[key] = struct{}{}
+				tags = append(tags, tag)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	return tags
+}
@@ -0,0 +1,134 @@
+package markdown
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/yuin/goldmark/text"
+)
+
+func TestHashtagParser_Parse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		prev    string
+		wantTag string
+	}{
+		{name: "ascii tag", input: "#golang", wantTag: "golang"},
+		{name: "japanese tag", input: "#日本語 です", wantTag: "日本語"},
+		{name: "underscore and hyphen", input: "#go_lang-1.26", wantTag: "go_lang-1"},
+		{name: "trailing hyphen is dropped", input: "#go- and", wantTag: "go"},
+		{name: "punctuation ends the tag", input: "#blog4、です", wantTag: "blog4"},
+		{name: "after space", input: "#tag", prev: "see ", wantTag: "tag"},
+		{name: "after paren", input: "#tag)", prev: "(", wantTag: "tag"},
+		{name: "digits only", input: "#123"},
+		{name: "heading-like", input: "# title"},
+		{name: "after a letter", input: "#sharp", prev: "C"},
+		{name: "after a slash", input: "#top", prev: "/"},
+		{name: "html entity", input: "#x27;", prev: "&"},
+		{name: "too long", input: "#" + strings.Repeat("a", maxHashtagLength+1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := []byte(tt.prev + tt.input)
+			reader := text.NewReader(src)
+			reader.Advance(len(tt.prev))
+
+			node := (&HashtagParser{}).Parse(nil, reader, nil)
+			if tt.wantTag == "" {
+				if node != nil {
+					t.Errorf("Parse() = %v, want nil", node)
+				}
+				return
+			}
+			hashtag, ok := node.(*HashtagNode)
+			if !ok {
+				t.Fatalf("Parse() returned %T, want *HashtagNode", node)
+			}
+			if string(hashtag.Tag) != tt.wantTag {
+				t.Errorf("Parse() Tag = %s, want %s", hashtag.Tag, tt.wantTag)
+			}
+		})
+	}
+}
+
+func TestHashtagNode_Kind(t *testing.T) {
+	node := &HashtagNode{}
+	if node.Kind() != HashtagKind {
+		t.Errorf("Kind() = %v, want %v", node.Kind(), HashtagKind)
+	}
+}
+
+func TestExtractHashtags(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "no tags",
+			input: "# Heading\n\nissue #42",
+			want:  nil,
+		},
+		{
+			name:  "tags in order",
+			input: "#Go and #日本語\n\n- #blog4",
+			want:  []string{"Go", "日本語", "blog4"},
+		},
+		{
+			name:  "duplicates are removed case-insensitively",
+			input: "#Go #go #GO",
+			want:  []string{"Go"},
+		},
+		{
+			name:  "tags in code are ignored",
+			input: "`#inline`\n\n```\n#block\n```\n\n#real",
+			want:  []string{"real"},
+		},
+		{
+			name:  "urls and wiki fragments are not tags",
+			input: "https://example.com/page#section https://example.com/#top [[Page#Heading]] [[#Local]] [x](#anchor)",
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExtractHashtags(tt.input)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ExtractHashtags() = %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("ExtractHashtags()[%d] = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestHashtagRenderer(t *testing.T) {
+	html := renderWithResolver(t, "about #日本語 and `#code`", nil)
+
+	want := `<a href="/admin/entries/search?tag=%E6%97%A5%E6%9C%AC%E8%AA%9E" class="hashtag">#日本語</a>`
+	if !strings.Contains(html, want) {
+		t.Errorf("Render() = %q, want it to contain %q", html, want)
+	}
+	if !strings.Contains(html, "<code>#code</code>") {
+		t.Errorf("Render() = %q, want the code span untouched", html)
+	}
+}
+
+func TestHashtagRenderer_PublicHref(t *testing.T) {
+	md := NewMarkdown(context.Background(), nil)
+	html, err := md.Render("#blog4 tips")
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	want := `<a href="/tag/blog4" class="hashtag">#blog4</a>`
+	if !strings.Contains(string(html), want) {
+		t.Errorf("Render() = %q, want it to contain %q", html, want)
+	}
+}
@@ -0,0 +1,59 @@
+package markdown
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+// HeadingID returns the anchor ID generated for the first heading with the
+// given text. [[Title#heading]] links use it to build their fragment.
+//
+// Unlike goldmark's default generator, letters and digits outside ASCII are
+// kept so that Japanese headings get readable, distinct IDs.
+func HeadingID(text string) string {
+	var sb strings.Builder
+	for _, r := range strings.TrimSpace(text) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			sb.WriteRune(unicode.ToLower(r))
+		case unicode.IsSpace(r) || r == '-' || r == '_':
+			sb.WriteRune('-')
+		}
+	}
+	if sb.Len() == 0 {
+		return "heading"
+	}
+	return sb.String()
+}
+
+// headingIDs implements parser.IDs with HeadingID, suffixing repeated IDs
+// with -1, -2, ... the same way goldmark does.
+type headingIDs struct {
+	values map[string]bool
+}
+
+func newHeadingIDs() *headingIDs {
+	return &headingIDs{values: map[string]bool{}}
+}
+
+func (s *headingIDs) Generate(value []byte, _ ast.NodeKind) []byte {
+	id := HeadingID(string(value))
+	if !s.values[id] {
+		s.values[id] = true
+		return []byte(id)
+	}
+	for i := 1; ; i++ {
+		candidate := fmt.Sprintf("%s-%d", id, i)
+		if !s.values[candidate] {
+			s.values[candidate] = true
+			return []byte(candidate)
+		}
+	}
+}
+
+func (s *headingIDs) Put(value []byte) {
+	s.values[string(value)] = true
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/yuin/goldmark"
 	highlighting "github.com/yuin/goldmark-highlighting/v2"
 	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/renderer/html"
 
 	"github.com/tokuhirom/blog4/db/public/publicdb"
@@ -18,37 +19,39 @@ type Markdown struct {
 	md goldmark.Markdown
 }
 
-type WikiLinkResolver struct {
+// markdownOptions holds what is needed to build a Markdown renderer, so that
+// ![[Title]] transclusion can render embedded bodies with the same settings.
+type markdownOptions struct {
+	ctx      context.Context
+	queries  *publicdb.Queries // nil renders ASIN links as fallback text
+	resolver WikiLinkResolver  // nil renders wiki links as plain text
+	tagHref  func(tag string) string
 }
 
 // NewPreviewMarkdown creates a Markdown renderer for preview purposes.
 // It does not require database queries - ASIN links render as fallback text.
-func NewPreviewMarkdown(ctx context.Context) *Markdown {
-	md := goldmark.New(
-		goldmark.WithExtensions(
-			extension.GFM,
-			extension.Linkify,
-			highlighting.NewHighlighting(
-				highlighting.WithStyle("monokai"),
-			),
-			&AsinLink{
-				Context: ctx,
-				Queries: nil,
-			},
-			&WikiLink{
-				Context: ctx,
-			},
-		),
-		goldmark.WithRendererOptions(
-			html.WithXHTML(),
-			html.WithUnsafe(),
-		),
-	)
-
-	return &Markdown{md}
+// Wiki links are resolved through resolver, which may be nil to render them as plain text.
+// #hashtags link to the admin entry list rather than the public tag page.
+func NewPreviewMarkdown(ctx context.Context, resolver WikiLinkResolver) *Markdown {
+	return newMarkdown(markdownOptions{
+		ctx:      ctx,
+		resolver: resolver,
+		tagHref:  AdminTagHref,
+	})
 }
 
 func NewMarkdown(ctx context.Context, queries *publicdb.Queries) *Markdown {
+	return newMarkdown(markdownOptions{
+		ctx:      ctx,
+		queries:  queries,
+		resolver: &PublicWikiLinkResolver{Queries: queries},
+		tagHref:  PublicTagHref,
+	})
+}
+
+// newMarkdown builds a renderer with opts. Embedded entries are rendered by
+// the same renderer, so they get the same settings.
+func newMarkdown(opts markdownOptions) *Markdown {
 	md := goldmark.New(
 		goldmark.WithExtensions(
 			extension.GFM,     // Enable GitHub Flavored Markdown
@@ -57,13 +60,20 @@ func NewMarkdown(ctx context.Context, queries *publicdb.Queries) *Markdown {
 				highlighting.WithStyle("monokai"),
 			),
 			&AsinLink{
-				Context: ctx,
-				Queries: queries,
+				Context: opts.ctx,
+				Queries: opts.queries,
 			},
 			&WikiLink{
-				Context: ctx,
+				Context:  opts.ctx,
+				Resolver: opts.resolver,
+			},
+			&Hashtag{
+				Href: opts.tagHref,
 			},
 		),
+		goldmark.WithParserOptions(
+			parser.WithAutoHeadingID(), // Give headings IDs for [[Title#heading]] links
+		),
 		goldmark.WithRendererOptions(
 			html.WithXHTML(),  // Render as XHTML
 			html.WithUnsafe(), // Allow unsafe HTML (e.g., raw HTML tags)
@@ -75,9 +85,26 @@ func NewMarkdown(ctx context.Context, queries *publicdb.Queries) *Markdown {
 	}
 }
 
+// Render renders a document that is not an entry, or whose title is not
+// known.
 func (m *Markdown) Render(input string) (template.HTML, error) {
+	return m.RenderEntry("", input)
+}
+
+// RenderEntry renders the body of the entry titled title. The title starts
+// the ![[Title]] chain, so that an entry never embeds itself.
+func (m *Markdown) RenderEntry(title, body string) (template.HTML, error) {
+	return m.render(newEmbedScope(title, m.render), body)
+}
+
+// render converts body as a document of scope. Headings of embedded entries
+// take their IDs from the same set as the page they are embedded in, so that
+// no ID appears twice.
+func (m *Markdown) render(scope *embedScope, body string) (template.HTML, error) {
 	var buf bytes.Buffer
-	if err := m.md.Convert([]byte(input), &buf); err != nil {
+	pc := parser.NewContext(parser.WithIDs(scope.ids))
+	pc.Set(embedScopeKey, scope)
+	if err := m.md.Convert([]byte(body), &buf, parser.WithContext(pc)); err != nil {
 		return "", fmt.Errorf("failed to convert markdown: %w", err)
 	}
 	return template.HTML(buf.String()), nil
@@ -0,0 +1,80 @@
+package markdown
+
+import (
+	"bytes"
+	"html/template"
+	"slices"
+	"strings"
+
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/util"
+)
+
+// maxEmbedDepth is how many ![[Title]] levels are expanded. Deeper embeds,
+// and embeds that would include an entry already being expanded, render as
+// ordinary wiki links.
+const maxEmbedDepth = 3
+
+// WikiEmbed is an entry that can be transcluded with ![[Title]].
+type WikiEmbed struct {
+	Title string
+	Href  string
+	Body  string
+}
+
+// embedScopeKey holds the *embedScope of the document being parsed.
+var embedScopeKey = parser.NewContextKey()
+
+// embedScope tracks the chain of entries being transcluded into a document.
+type embedScope struct {
+	// chain holds the lowercased titles being expanded, outermost first,
+	// starting with the rendered entry itself when its title is known.
+	chain []string
+	// depth is how many ![[Title]] levels deep the document is.
+	depth int
+	// ids is shared by the whole embed tree.
+	ids    *headingIDs
+	render func(child *embedScope, body string) (template.HTML, error)
+}
+
+func newEmbedScope(title string, render func(child *embedScope, body string) (template.HTML, error)) *embedScope {
+	s := &embedScope{ids: newHeadingIDs(), render: render}
+	if title != "" {
+		s.chain = []string{strings.ToLower(title)}
+	}
+	return s
+}
+
+// allows reports whether title can be expanded at this depth without
+// creating a cycle.
+func (s *embedScope) allows(title string) bool {
+	if s.depth >= maxEmbedDepth {
+		return false
+	}
+	return !slices.Contains(s.chain, strings.ToLower(title))
+}
+
+// renderChild renders the body of title as a document nested in s.
+func (s *embedScope) renderChild(title string, body string) (template.HTML, error) {
+	child := &embedScope{
+		chain:  append(slices.Clone(s.chain), strings.ToLower(title)),
+		depth:  s.depth + 1,
+		ids:    s.ids,
+		render: s.render,
+	}
+	return s.render(child, body)
+}
+
+func renderWikiEmbed(n *WikiNode) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(`<div class="wiki-embed">`)
+	buf.WriteString(`<div class="wiki-embed-source"><a href="`)
+	buf.Write(util.EscapeHTML([]byte(n.Href)))
+	buf.WriteString(`" class="wiki-link">`)
+	buf.Write(util.EscapeHTML(n.DisplayText()))
+	buf.WriteString(`</a></div>`)
+	buf.WriteString(`<div class="wiki-embed-body">`)
+	buf.Write(n.EmbedHTML)
+	buf.WriteString(`</div></div>`)
+	return buf.Bytes()
+}
@@ -0,0 +1,129 @@
+package markdown
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestWikiEmbed_RendersBodyWithSourceLink(t *testing.T) {
+	resolver := &fakeWikiLinkResolver{
+		embeds: map[string]WikiEmbed{
+			"Books": {Title: "Books", Href: "/entry/books", Body: "- **Book A**\n- Book B"},
+		},
+	}
+
+	html := renderWithResolver(t, "before\n\n![[Books]]\n\nafter", resolver)
+
+	if !strings.Contains(html, `<div class="wiki-embed"><div class="wiki-embed-source"><a href="/entry/books" class="wiki-link">Books</a></div>`) {
+		t.Errorf("embed header not rendered: %s", html)
+	}
+	if !strings.Contains(html, "<strong>Book A</strong>") {
+		t.Errorf("embedded body not rendered: %s", html)
+	}
+	if resolver.embedCalls != 1 {
+		t.Errorf("LoadWikiEmbeds called %d times, want 1", resolver.embedCalls)
+	}
+}
+
+func TestWikiEmbed_CycleRendersAsLink(t *testing.T) {
+	resolver := &fakeWikiLinkResolver{
+		hrefs: map[string]string{"A": "/entry/a", "B": "/entry/b"},
+		embeds: map[string]WikiEmbed{
+			"A": {Title: "A", Href: "/entry/a", Body: "in A ![[B]]"},
+			"B": {Title: "B", Href: "/entry/b", Body: "in B ![[A]]"},
+		},
+	}
+
+	html := renderWithResolver(t, "![[A]]", resolver)
+
+	if strings.Count(html, "in A") != 1 || strings.Count(html, "in B") != 1 {
+		t.Errorf("cycle was expanded more than once: %s", html)
+	}
+	if !strings.Contains(html, `in B <a href="/entry/a" class="wiki-link">A</a>`) {
+		t.Errorf("cyclic embed should fall back to a link: %s", html)
+	}
+}
+
+func TestWikiEmbed_DepthLimit(t *testing.T) {
+	resolver := &fakeWikiLinkResolver{
+		hrefs: map[string]string{"L4": "/entry/l4"},
+		embeds: map[string]WikiEmbed{
+			"L1": {Title: "L1", Href: "/entry/l1", Body: "one ![[L2]]"},
+			"L2": {Title: "L2", Href: "/entry/l2", Body: "two ![[L3]]"},
+			"L3": {Title: "L3", Href: "/entry/l3", Body: "three ![[L4]]"},
+			"L4": {Title: "L4", Href: "/entry/l4", Body: "four"},
+		},
+	}
+
+	html := renderWithResolver(t, "![[L1]]", resolver)
+
+	if !strings.Contains(html, "three") {
+		t.Errorf("embeds within the depth limit should expand: %s", html)
+	}
+	if strings.Contains(html, "four") {
+		t.Errorf("embed beyond the depth limit was expanded: %s", html)
+	}
+	if !strings.Contains(html, `<a href="/entry/l4" class="wiki-link">L4</a>`) {
+		t.Errorf("embed beyond the depth limit should render as a link: %s", html)
+	}
+}
+
+func TestWikiEmbed_NotEmbeddableFallsBackToLink(t *testing.T) {
+	// "Private" exists but LoadWikiEmbeds leaves it out.
+	resolver := &fakeWikiLinkResolver{
+		hrefs: map[string]string{"Private": "/admin/entries/edit?path=private"},
+	}
+
+	html := renderWithResolver(t, "![[Private]] ![[Nowhere]]", resolver)
+
+	if strings.Contains(html, "wiki-embed") {
+		t.Errorf("non-embeddable entry was embedded: %s", html)
+	}
+	if !strings.Contains(html, `<a href="/admin/entries/edit?path=private" class="wiki-link">Private</a>`) {
+		t.Errorf("existing entry should render as a link: %s", html)
+	}
+	if !strings.Contains(html, `<span class="wiki-link missing">Nowhere</span>`) {
+		t.Errorf("unknown entry should render as missing: %s", html)
+	}
+	if resolver.calls != 1 {
+		t.Errorf("ResolveWikiLinks called %d times, want 1", resolver.calls)
+	}
+}
+
+func TestWikiEmbed_EntryDoesNotEmbedItself(t *testing.T) {
+	resolver := &fakeWikiLinkResolver{
+		hrefs: map[string]string{"Self": "/entry/self"},
+		embeds: map[string]WikiEmbed{
+			"Self": {Title: "Self", Href: "/entry/self", Body: "me ![[Self]]"},
+		},
+	}
+
+	html, err := NewPreviewMarkdown(context.Background(), resolver).RenderEntry("Self", "me ![[self]]")
+	if err != nil {
+		t.Fatalf("RenderEntry() error = %v", err)
+	}
+
+	if strings.Contains(string(html), "wiki-embed") {
+		t.Errorf("entry was embedded into itself: %s", html)
+	}
+	if !strings.Contains(string(html), `<a href="/entry/self" class="wiki-link">self</a>`) {
+		t.Errorf("self embed should render as a link: %s", html)
+	}
+}
+
+func TestWikiEmbed_HeadingIDsAreUniqueAcrossEmbeds(t *testing.T) {
+	resolver := &fakeWikiLinkResolver{
+		embeds: map[string]WikiEmbed{
+			"Notes": {Title: "Notes", Href: "/entry/notes", Body: "## Summary\n\nembedded"},
+		},
+	}
+
+	html := renderWithResolver(t, "## Summary\n\n![[Notes]]\n\n![[Notes]]", resolver)
+
+	for _, id := range []string{`id="summary"`, `id="summary-1"`, `id="summary-2"`} {
+		if strings.Count(html, id) != 1 {
+			t.Errorf("%s should appear once: %s", id, html)
+		}
+	}
+}
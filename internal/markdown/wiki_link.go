@@ -4,9 +4,12 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"log/slog"
+	"strings"
 
 	"github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
 	"github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/renderer"
 	"github.com/yuin/goldmark/text"
@@ -15,8 +18,24 @@ import (
 	"github.com/tokuhirom/blog4/db/public/publicdb"
 )
 
+// WikiLinkResolver turns [[Title]] targets into hrefs. It is called once per
+// document with every target in it, so implementations can batch lookups.
+type WikiLinkResolver interface {
+	// ResolveWikiLinks returns the href for each title that points at an
+	// existing entry, keyed by the entry's stored title.
+	ResolveWikiLinks(ctx context.Context, titles []string) (map[string]string, error)
+	// MissingWikiLinkHref returns the href for a title that did not resolve,
+	// or "" to render it without a link.
+	MissingWikiLinkHref(title string) string
+	// LoadWikiEmbeds returns the entries that ![[Title]] may transclude,
+	// keyed by the entry's stored title. Entries that must not be embedded,
+	// such as private ones, are left out and render as ordinary links.
+	LoadWikiEmbeds(ctx context.Context, titles []string) (map[string]WikiEmbed, error)
+}
+
 type WikiLink struct {
-	Context context.Context
+	Context  context.Context
+	Resolver WikiLinkResolver
 }
 
 func (a WikiLink) Extend(markdown goldmark.Markdown) {
@@ -27,6 +46,16 @@ func (a WikiLink) Extend(markdown goldmark.Markdown) {
 			}, 100),
 		),
 	)
+	if a.Resolver != nil {
+		markdown.Parser().AddOptions(
+			parser.WithASTTransformers(
+				util.Prioritized(&WikiResolveTransformer{
+					Context:  a.Context,
+					Resolver: a.Resolver,
+				}, 100),
+			),
+		)
+	}
 	markdown.Renderer().AddOptions(
 		renderer.WithNodeRenderers(
 			util.Prioritized(&WikiRenderer{
@@ -41,19 +70,34 @@ type WikiParser struct {
 }
 
 func (p *WikiParser) Trigger() []byte {
-	return []byte{'['}
+	return []byte{'[', '!'}
 }
 
 var (
-	wikiOpen  = []byte("[[")
-	wikiClose = []byte("]]")
+	wikiOpen      = []byte("[[")
+	wikiEmbedOpen = []byte("![[")
+	wikiClose     = []byte("]]")
 )
 
 type WikiNode struct {
 	ast.BaseInline
 
+	// Target is the canonical entry title, without fragment or label.
 	Target []byte
-	Embed  bool
+	// Fragment is the heading text after '#', if any.
+	Fragment []byte
+	// Label is the text after '|', if any.
+	Label []byte
+	Embed bool
+	// Segment is the position of the text between the brackets in the source.
+	Segment text.Segment
+
+	// Href and Missing are filled in by WikiResolveTransformer.
+	Href    string
+	Missing bool
+	// EmbedHTML is the rendered body of a transcluded entry. It stays nil
+	// when the embed could not be expanded, and the node renders as a link.
+	EmbedHTML []byte
 }
 
 var WikiKind = ast.NewNodeKind("WikiLink")
@@ -64,11 +108,29 @@ func (a *WikiNode) Kind() ast.NodeKind {
 
 func (a *WikiNode) Dump(src []byte, level int) {
 	ast.DumpHelper(a, src, level, map[string]string{
-		"Target": string(a.Target),
+		"Target":   string(a.Target),
+		"Fragment": string(a.Fragment),
+		"Label":    string(a.Label),
 	}, nil)
 }
 
-// [[Link]]
+// DisplayText returns the label, falling back to the link as written.
+func (a *WikiNode) DisplayText() []byte {
+	if len(a.Label) > 0 {
+		return a.Label
+	}
+	if len(a.Fragment) > 0 {
+		return []byte(string(a.Target) + "#" + string(a.Fragment))
+	}
+	return a.Target
+}
+
+// [[Link]], [[Link|label]], [[Link#heading]], [[Link#heading|label]], [[#heading]]
+// ![[Link]] embeds the body of Link.
+//
+// The first '|' separates the label and the first '#' before it separates the
+// heading, so a title that itself contains '#' can only be linked with an
+// empty heading part, e.g. [[C#]].
 func (p *WikiParser) Parse(_ ast.Node, block text.Reader, _ parser.Context) ast.Node {
 	line, seg := block.PeekLine()
 	stop := bytes.Index(line, wikiClose)
@@ -81,27 +143,181 @@ func (p *WikiParser) Parse(_ ast.Node, block text.Reader, _ parser.Context) ast.
 	switch {
 	case bytes.HasPrefix(line, wikiOpen):
 		seg = text.NewSegment(seg.Start+len(wikiOpen), seg.Start+stop)
+	case bytes.HasPrefix(line, wikiEmbedOpen):
+		embed = true
+		seg = text.NewSegment(seg.Start+len(wikiEmbedOpen), seg.Start+stop)
 	default:
-		println(string(line))
 		return nil
 	}
 
-	n := &WikiNode{
-		Target: block.Value(seg),
-		Embed:  embed,
+	if seg.Len() == 0 {
+		return nil // target must not be empty
 	}
-	if len(n.Target) == 0 || seg.Len() == 0 {
-		return nil // target and label must not be empty
+	n := parseWikiLinkBody(block.Value(seg))
+	if n == nil {
+		return nil
 	}
+	n.Embed = embed
+	n.Segment = seg
 
 	block.Advance(stop + 2) // "]]".length == 2
 	return n
 }
 
-type WikiRenderer struct {
-	Resolver any
-	Queries  *publicdb.Queries
+func parseWikiLinkBody(body []byte) *WikiNode {
+	target := body
+	var label, fragment []byte
+	if i := bytes.IndexByte(target, '|'); i >= 0 {
+		label = bytes.TrimSpace(target[i+1:])
+		target = target[:i]
+	}
+	if i := bytes.IndexByte(target, '#'); i >= 0 {
+		if f := bytes.TrimSpace(target[i+1:]); len(f) > 0 {
+			fragment = f
+			target = target[:i]
+		}
+	}
+	target = bytes.TrimSpace(target)
+
+	if len(target) == 0 && len(fragment) == 0 {
+		return nil
+	}
+
+	n := &WikiNode{
+		Target:   target,
+		Fragment: fragment,
+		Label:    label,
+	}
+	if len(target) == 0 {
+		// [[#heading]] points into the current document; nothing to resolve.
+		n.Href = "#" + HeadingID(string(fragment))
+	}
+	return n
+}
+
+// WikiResolveTransformer resolves every WikiNode in a document with one
+// ResolveWikiLinks call and one LoadWikiEmbeds call. ![[Title]] is expanded
+// only in documents rendered by Markdown, whose parser context carries the
+// transclusion chain.
+type WikiResolveTransformer struct {
 	Context  context.Context
+	Resolver WikiLinkResolver
+}
+
+func (t *WikiResolveTransformer) Transform(doc *ast.Document, _ text.Reader, pc parser.Context) {
+	scope, _ := pc.Get(embedScopeKey).(*embedScope)
+	var links, embeds []*WikiNode
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		if wn, ok := n.(*WikiNode); ok {
+			if len(wn.Target) == 0 {
+				return ast.WalkSkipChildren, nil
+			}
+			if wn.Embed && scope != nil && scope.allows(string(wn.Target)) {
+				embeds = append(embeds, wn)
+			} else {
+				links = append(links, wn)
+			}
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+
+	links = append(links, t.resolveEmbeds(scope, embeds)...)
+	t.resolveLinks(links)
+}
+
+func (t *WikiResolveTransformer) resolveLinks(nodes []*WikiNode) {
+	if len(nodes) == 0 {
+		return
+	}
+
+	titles := uniqueWikiTargets(nodes)
+	hrefs, err := t.Resolver.ResolveWikiLinks(t.Context, titles)
+	if err != nil {
+		// Leave the nodes unresolved; they render as plain text.
+		slog.Error("failed to resolve wiki links", slog.Any("titles", titles), slog.Any("error", err))
+		return
+	}
+	// Titles are compared case-insensitively by the database collation.
+	byTitle := make(map[string]string, len(hrefs))
+	for title, href := range hrefs {
+		byTitle[strings.ToLower(title)] = href
+	}
+
+	for _, wn := range nodes {
+		title := string(wn.Target)
+		if href, ok := byTitle[strings.ToLower(title)]; ok {
+			wn.Href = withFragment(href, wn.Fragment)
+		} else {
+			wn.Missing = true
+			wn.Href = t.Resolver.MissingWikiLinkHref(title)
+		}
+	}
+}
+
+// resolveEmbeds expands the embeds it can and returns the nodes that should
+// be resolved as ordinary links instead.
+func (t *WikiResolveTransformer) resolveEmbeds(scope *embedScope, nodes []*WikiNode) []*WikiNode {
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	titles := uniqueWikiTargets(nodes)
+	embeds, err := t.Resolver.LoadWikiEmbeds(t.Context, titles)
+	if err != nil {
+		slog.Error("failed to load wiki embeds", slog.Any("titles", titles), slog.Any("error", err))
+		return nodes
+	}
+	byTitle := make(map[string]WikiEmbed, len(embeds))
+	for title, embed := range embeds {
+		byTitle[strings.ToLower(title)] = embed
+	}
+
+	var fallback []*WikiNode
+	for _, wn := range nodes {
+		embed, ok := byTitle[strings.ToLower(string(wn.Target))]
+		if !ok {
+			fallback = append(fallback, wn)
+			continue
+		}
+
+		html, err := scope.renderChild(embed.Title, embed.Body)
+		if err != nil {
+			slog.Error("failed to render wiki embed", slog.String("title", embed.Title), slog.Any("error", err))
+			fallback = append(fallback, wn)
+			continue
+		}
+		wn.Href = withFragment(embed.Href, wn.Fragment)
+		wn.EmbedHTML = []byte(html)
+	}
+	return fallback
+}
+
+func uniqueWikiTargets(nodes []*WikiNode) []string {
+	var titles []string
+	seen := make(map[string]struct{})
+	for _, wn := range nodes {
+		title := string(wn.Target)
+		if _, dup := seen[title]; !dup {
+			seen[title] = struct{}{}
+			titles = append(titles, title)
+		}
+	}
+	return titles
+}
+
+func withFragment(href string, fragment []byte) string {
+	if len(fragment) == 0 {
+		return href
+	}
+	return href + "#" + HeadingID(string(fragment))
+}
+
+type WikiRenderer struct {
+	Context context.Context
 }
 
 func (r *WikiRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
@@ -115,12 +331,184 @@ func (r *WikiRenderer) Render(writer util.BufWriter, source []byte, node ast.Nod
 	}
 
 	if entering {
-		_, err := writer.WriteString(string(n.Target))
-		if err != nil {
-			return ast.WalkStop, fmt.Errorf("failed to write target: %w", err)
+		if n.Embed && n.EmbedHTML != nil {
+			if _, err := writer.Write(renderWikiEmbed(n)); err != nil {
+				return ast.WalkStop, fmt.Errorf("failed to write wiki embed: %w", err)
+			}
+			return ast.WalkSkipChildren, nil
+		}
+		if _, err := writer.Write(renderWikiLink(n)); err != nil {
+			return ast.WalkStop, fmt.Errorf("failed to write wiki link: %w", err)
 		}
 		return ast.WalkSkipChildren, nil
 	}
 
 	return ast.WalkContinue, nil
 }
+
+func renderWikiLink(n *WikiNode) []byte {
+	class := "wiki-link"
+	if n.Missing {
+		class = "wiki-link missing"
+	}
+
+	var buf bytes.Buffer
+	switch {
+	case n.Href != "":
+		buf.WriteString(`<a href="`)
+		buf.Write(util.EscapeHTML([]byte(n.Href)))
+		buf.WriteString(`" class="`)
+		buf.WriteString(class)
+		buf.WriteString(`">`)
+		buf.Write(util.EscapeHTML(n.DisplayText()))
+		buf.WriteString("</a>")
+	case n.Missing:
+		buf.WriteString(`<span class="`)
+		buf.WriteString(class)
+		buf.WriteString(`">`)
+		buf.Write(util.EscapeHTML(n.DisplayText()))
+		buf.WriteString("</span>")
+	default:
+		buf.Write(util.EscapeHTML(n.DisplayText()))
+	}
+	return buf.Bytes()
+}
+
+// PublicWikiLinkResolver links [[Title]] to public entries only. Private or
+// nonexistent titles render as missing pages without a link.
+type PublicWikiLinkResolver struct {
+	Queries *publicdb.Queries
+}
+
+func (r *PublicWikiLinkResolver) ResolveWikiLinks(ctx context.Context, titles []string) (map[string]string, error) {
+	rows, err := r.Queries.GetPublicEntryPathsByTitles(ctx, titles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get public entries by titles: %w", err)
+	}
+	hrefs := make(map[string]string, len(rows))
+	for _, row := range rows {
+		hrefs[row.Title] = "/entry/" + row.Path
+	}
+	return hrefs, nil
+}
+
+func (r *PublicWikiLinkResolver) MissingWikiLinkHref(string) string {
+	return ""
+}
+
+func (r *PublicWikiLinkResolver) LoadWikiEmbeds(ctx context.Context, titles []string) (map[string]WikiEmbed, error) {
+	rows, err := r.Queries.GetPublicEntriesByTitles(ctx, titles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get public entries by titles: %w", err)
+	}
+	embeds := make(map[string]WikiEmbed, len(rows))
+	for _, row := range rows {
+		embeds[row.Title] = WikiEmbed{
+			Title: row.Title,
+			Href:  "/entry/" + row.Path,
+			Body:  row.Body,
+		}
+	}
+	return embeds, nil
+}
+
+// parseWikiNodes parses body and returns its wiki links in order of appearance.
+func parseWikiNodes(source []byte) []*WikiNode {
+	md := goldmark.New(
+		goldmark.WithExtensions(
+			extension.GFM,
+			&WikiLink{Context: context.Background()},
+		),
+	)
+	doc := md.Parser().Parse(text.NewReader(source))
+
+	var nodes []*WikiNode
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		if wn, ok := n.(*WikiNode); ok {
+			nodes = append(nodes, wn)
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return nodes
+}
+
+// ExtractWikiLinks parses body with WikiParser and returns the distinct [[...]]
+// target titles in order of appearance. Labels and heading fragments are
+// dropped so that entry_link always stores the canonical title. Links inside code spans and code blocks are
+// ignored, since goldmark never hands those to inline parsers.
+func ExtractWikiLinks(body string) []string {
+	var titles []string
+	// entry_link.dst_title uses a case-insensitive collation, so dedupe the same way.
+	seen := make(map[string]struct{})
+	for _, wn := range parseWikiNodes([]byte(body)) {
+		title := string(wn.Target)
+		if title == "" {
+			continue
+		}
+		key := strings.ToLower(title)
+		if _, dup := seen[key]; !dup {
+			seen[key] = struct{}{}
+			titles = append(titles, title)
+		}
+	}
+	return titles
+}
+
+// IsLinkableTitle reports whether [[title]] links back to title. Brackets,
+// "|", "#", line breaks and surrounding spaces would be read as link syntax,
+// so links rewritten to such a title would break.
+func IsLinkableTitle(title string) bool {
+	if strings.TrimSpace(title) != title {
+		return false
+	}
+	return !strings.Contains(title, "[[") && !strings.Contains(title, "]]") &&
+		!strings.ContainsAny(title, "|#\r\n")
+}
+
+// RewriteWikiLinks replaces the target of every [[oldTitle]] link in body with
+// newTitle, keeping any heading fragment, label and embed marker as written.
+// Titles match case-insensitively, like entry_link.dst_title. It reports
+// whether anything was replaced.
+func RewriteWikiLinks(body string, oldTitle string, newTitle string) (string, bool) {
+	source := []byte(body)
+
+	var segments []text.Segment
+	for _, wn := range parseWikiNodes(source) {
+		if strings.EqualFold(string(wn.Target), oldTitle) {
+			segments = append(segments, wn.Segment)
+		}
+	}
+	if len(segments) == 0 {
+		return body, false
+	}
+
+	var buf bytes.Buffer
+	last := 0
+	for _, seg := range segments {
+		start, stop := wikiTargetBounds(source[seg.Start:seg.Stop])
+		buf.Write(source[last : seg.Start+start])
+		buf.WriteString(newTitle)
+		last = seg.Start + stop
+	}
+	buf.Write(source[last:])
+	return buf.String(), true
+}
+
+// wikiTargetBounds returns the offsets of the target title within the text
+// between the brackets, mirroring parseWikiLinkBody.
+func wikiTargetBounds(body []byte) (int, int) {
+	stop := len(body)
+	if i := bytes.IndexByte(body, '|'); i >= 0 {
+		stop = i
+	}
+	if i := bytes.IndexByte(body[:stop], '#'); i >= 0 && len(bytes.TrimSpace(body[i+1:stop])) > 0 {
+		stop = i
+	}
+	start := len(body[:stop]) - len(bytes.TrimLeft(body[:stop], " \t"))
+	stop = len(bytes.TrimRight(body[:stop], " \t"))
+	return start, stop
+}
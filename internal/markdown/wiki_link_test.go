@@ -3,6 +3,7 @@ package markdown
 import (
 	"bytes"
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/yuin/goldmark/text"
@@ -10,10 +11,13 @@ import (
 
 func TestWikiParser_Parse(t *testing.T) {
 	tests := []struct {
-		name       string
-		input      string
-		wantTarget string
-		wantNil    bool
+		name         string
+		input        string
+		wantTarget   string
+		wantFragment string
+		wantLabel    string
+		wantEmbed    bool
+		wantNil      bool
 	}{
 		{
 			name:       "valid wiki link",
@@ -33,6 +37,53 @@ func TestWikiParser_Parse(t *testing.T) {
 			wantTarget: "PageName",
 			wantNil:    false,
 		},
+		{
+			name:       "alias",
+			input:      "[[Page Name|the label]]",
+			wantTarget: "Page Name",
+			wantLabel:  "the label",
+		},
+		{
+			name:         "heading fragment",
+			input:        "[[Page Name#Install Steps]]",
+			wantTarget:   "Page Name",
+			wantFragment: "Install Steps",
+		},
+		{
+			name:         "heading fragment with alias",
+			input:        "[[Page#見出し|see here]]",
+			wantTarget:   "Page",
+			wantFragment: "見出し",
+			wantLabel:    "see here",
+		},
+		{
+			name:         "same-page heading",
+			input:        "[[#Summary]]",
+			wantTarget:   "",
+			wantFragment: "Summary",
+		},
+		{
+			name:       "trailing hash is part of the title",
+			input:      "[[C#]]",
+			wantTarget: "C#",
+		},
+		{
+			name:       "embed",
+			input:      "![[Books]]",
+			wantTarget: "Books",
+			wantEmbed:  true,
+		},
+		{
+			name:    "invalid - bang without brackets",
+			input:   "!Books",
+			wantNil: true,
+		},
+		{
+			name:       "invalid - label only",
+			input:      "[[|label]]",
+			wantTarget: "",
+			wantNil:    true,
+		},
 		{
 			name:       "invalid - not closed on same line",
 			input:      "[[PageName\n]]",
@@ -95,6 +146,15 @@ func TestWikiParser_Parse(t *testing.T) {
 			if string(wikiNode.Target) != tt.wantTarget {
 				t.Errorf("Parse() Target = %s, want %s", string(wikiNode.Target), tt.wantTarget)
 			}
+			if string(wikiNode.Fragment) != tt.wantFragment {
+				t.Errorf("Parse() Fragment = %s, want %s", string(wikiNode.Fragment), tt.wantFragment)
+			}
+			if string(wikiNode.Label) != tt.wantLabel {
+				t.Errorf("Parse() Label = %s, want %s", string(wikiNode.Label), tt.wantLabel)
+			}
+			if wikiNode.Embed != tt.wantEmbed {
+				t.Errorf("Parse() Embed = %v, want %v", wikiNode.Embed, tt.wantEmbed)
+			}
 		})
 	}
 }
@@ -102,8 +162,8 @@ func TestWikiParser_Parse(t *testing.T) {
 func TestWikiParser_Trigger(t *testing.T) {
 	parser := &WikiParser{}
 	trigger := parser.Trigger()
-	if !bytes.Equal(trigger, []byte{'['}) {
-		t.Errorf("Trigger() = %v, want %v", trigger, []byte{'['})
+	if !bytes.Equal(trigger, []byte{'[', '!'}) {
+		t.Errorf("Trigger() = %v, want %v", trigger, []byte{'[', '!'})
 	}
 }
 
@@ -114,7 +174,240 @@ func TestWikiNode_Kind(t *testing.T) {
 	}
 }
 
-// Note: WikiRenderer.Render is difficult to test in isolation because it requires
-// a proper util.BufWriter interface implementation from goldmark.
-// This is documented in ISSUES.md for future refactoring.
-// Integration tests would be more appropriate for testing the rendering functionality.
+func TestExtractWikiLinks(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "no links",
+			input: "plain text",
+			want:  nil,
+		},
+		{
+			name:  "multiple links in order",
+			input: "see [[Foo]] and [[Bar Baz]]\n\n- [[Qux]]",
+			want:  []string{"Foo", "Bar Baz", "Qux"},
+		},
+		{
+			name:  "duplicates are removed case-insensitively",
+			input: "[[Foo]] [[foo]] [[Foo]]",
+			want:  []string{"Foo"},
+		},
+		{
+			name:  "aliases and fragments store the canonical title",
+			input: "[[Foo|label]] [[Foo#Heading]] [[Bar#Heading|label]] [[#Local]]",
+			want:  []string{"Foo", "Bar"},
+		},
+		{
+			name:  "links in code are ignored",
+			input: "`[[InCode]]`\n\n```\n[[InBlock]]\n```\n\n[[Real]]",
+			want:  []string{"Real"},
+		},
+		{
+			name:  "asin and markdown links are not wiki links",
+			input: "[asin:B0BC73K2BW:detail] [text](https://example.com) [[日本語]]",
+			want:  []string{"日本語"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExtractWikiLinks(tt.input)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ExtractWikiLinks() = %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("ExtractWikiLinks()[%d] = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRewriteWikiLinks(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		want        string
+		wantChanged bool
+	}{
+		{
+			name:        "plain link",
+			input:       "see [[Old Title]].",
+			want:        "see [[New Title]].",
+			wantChanged: true,
+		},
+		{
+			name:        "case-insensitive match",
+			input:       "[[old title]] and [[OLD TITLE]]",
+			want:        "[[New Title]] and [[New Title]]",
+			wantChanged: true,
+		},
+		{
+			name:        "label, fragment and embed are kept",
+			input:       "[[Old Title|label]] [[Old Title#Intro]] [[ Old Title # Intro | x ]] ![[Old Title]]",
+			want:        "[[New Title|label]] [[New Title#Intro]] [[ New Title # Intro | x ]] ![[New Title]]",
+			wantChanged: true,
+		},
+		{
+			name:        "other links and code are untouched",
+			input:       "[[Old Title 2]] [[#Old Title]] `[[Old Title]]`\n\n```\n[[Old Title]]\n```",
+			want:        "[[Old Title 2]] [[#Old Title]] `[[Old Title]]`\n\n```\n[[Old Title]]\n```",
+			wantChanged: false,
+		},
+		{
+			name:        "multibyte text around links",
+			input:       "日本語[[Old Title]]日本語\n\n- [[Old Title]]",
+			want:        "日本語[[New Title]]日本語\n\n- [[New Title]]",
+			wantChanged: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := RewriteWikiLinks(tt.input, "Old Title", "New Title")
+			if got != tt.want {
+				t.Errorf("RewriteWikiLinks() = %q, want %q", got, tt.want)
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("RewriteWikiLinks() changed = %v, want %v", changed, tt.wantChanged)
+			}
+		})
+	}
+}
+
+func TestIsLinkableTitle(t *testing.T) {
+	for _, title := range []string{"New Title", "C++ の話", "a]b[c", "50% off!"} {
+		if !IsLinkableTitle(title) {
+			t.Errorf("IsLinkableTitle(%q) = false, want true", title)
+		}
+	}
+	for _, title := range []string{"a]]b", "a[[b", "a|b", "C#", "a\nb", "a\rb", " padded", "padded "} {
+		if IsLinkableTitle(title) {
+			t.Errorf("IsLinkableTitle(%q) = true, want false", title)
+		}
+	}
+}
+
+type fakeWikiLinkResolver struct {
+	hrefs   map[string]string
+	missing string
+	calls   int
+	titles  []string
+
+	embeds     map[string]WikiEmbed
+	embedCalls int
+}
+
+func (r *fakeWikiLinkResolver) ResolveWikiLinks(_ context.Context, titles []string) (map[string]string, error) {
+	r.calls++
+	r.titles = titles
+	return r.hrefs, nil
+}
+
+func (r *fakeWikiLinkResolver) MissingWikiLinkHref(title string) string {
+	if r.missing == "" {
+		return ""
+	}
+	return r.missing + title
+}
+
+func (r *fakeWikiLinkResolver) LoadWikiEmbeds(_ context.Context, titles []string) (map[string]WikiEmbed, error) {
+	r.embedCalls++
+	embeds := make(map[string]WikiEmbed)
+	for _, title := range titles {
+		if e, ok := r.embeds[title]; ok {
+			embeds[title] = e
+		}
+	}
+	return embeds, nil
+}
+
+func renderWithResolver(t *testing.T, input string, resolver WikiLinkResolver) string {
+	t.Helper()
+	md := NewPreviewMarkdown(context.Background(), resolver)
+	html, err := md.Render(input)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	return string(html)
+}
+
+func TestWikiRenderer_ResolvedAndMissing(t *testing.T) {
+	resolver := &fakeWikiLinkResolver{
+		hrefs: map[string]string{"Foo": "/entry/2024/01/01/120000"},
+	}
+
+	html := renderWithResolver(t, "[[foo]] [[Bar]] [[Foo]]", resolver)
+
+	if resolver.calls != 1 {
+		t.Errorf("ResolveWikiLinks called %d times, want 1", resolver.calls)
+	}
+	if len(resolver.titles) != 3 {
+		t.Errorf("ResolveWikiLinks titles = %q, want 3 distinct titles", resolver.titles)
+	}
+	if !strings.Contains(html, `<a href="/entry/2024/01/01/120000" class="wiki-link">foo</a>`) {
+		t.Errorf("resolved link (case-insensitive) not rendered: %s", html)
+	}
+	if !strings.Contains(html, `<span class="wiki-link missing">Bar</span>`) {
+		t.Errorf("missing link not rendered: %s", html)
+	}
+}
+
+func TestWikiRenderer_MissingHref(t *testing.T) {
+	resolver := &fakeWikiLinkResolver{missing: "/admin/entries/new?title="}
+
+	html := renderWithResolver(t, "[[New]]", resolver)
+
+	if !strings.Contains(html, `<a href="/admin/entries/new?title=New" class="wiki-link missing">New</a>`) {
+		t.Errorf("create link not rendered: %s", html)
+	}
+}
+
+func TestWikiRenderer_NoResolverEscapesText(t *testing.T) {
+	html := renderWithResolver(t, "[[<b>]]", nil)
+
+	if !strings.Contains(html, "&lt;b&gt;") {
+		t.Errorf("target not escaped: %s", html)
+	}
+	if strings.Contains(html, "wiki-link") {
+		t.Errorf("unexpected link markup without resolver: %s", html)
+	}
+}
+
+func TestWikiRenderer_LabelAndFragment(t *testing.T) {
+	resolver := &fakeWikiLinkResolver{
+		hrefs: map[string]string{"Foo": "/entry/foo"},
+	}
+
+	html := renderWithResolver(t, "[[Foo#Install Steps|how to install]] [[Foo#見出し]] [[#Local Part]]", resolver)
+
+	if !strings.Contains(html, `<a href="/entry/foo#install-steps" class="wiki-link">how to install</a>`) {
+		t.Errorf("aliased fragment link not rendered: %s", html)
+	}
+	if !strings.Contains(html, `<a href="/entry/foo#見出し" class="wiki-link">Foo#見出し</a>`) {
+		t.Errorf("fragment link not rendered: %s", html)
+	}
+	if !strings.Contains(html, `<a href="#local-part" class="wiki-link">#Local Part</a>`) {
+		t.Errorf("same-page link not rendered: %s", html)
+	}
+	if len(resolver.titles) != 1 || resolver.titles[0] != "Foo" {
+		t.Errorf("ResolveWikiLinks titles = %q, want [Foo]", resolver.titles)
+	}
+}
+
+func TestWikiRenderer_FragmentMatchesHeadingID(t *testing.T) {
+	html := renderWithResolver(t, "## Install Steps\n\n## 日本語 の見出し\n\n## Install Steps\n", nil)
+
+	for _, id := range []string{`id="install-steps"`, `id="日本語-の見出し"`, `id="install-steps-1"`} {
+		if !strings.Contains(html, id) {
+			t.Errorf("heading %s not found: %s", id, html)
+		}
+	}
+	if HeadingID("日本語 の見出し") != "日本語-の見出し" {
+		t.Errorf("HeadingID() = %s", HeadingID("日本語 の見出し"))
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"reflect"
 
 	"github.com/tokuhirom/blog4/db/admin/admindb"
+	"github.com/tokuhirom/blog4/internal/paapi"
 	"go.uber.org/mock/gomock"
 )
 
@@ -86,3 +87,125 @@ func (mr *MockEntryImageStoreMockRecorder) InsertEntryImage(ctx, params any) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertEntryImage", reflect.TypeOf((*MockEntryImageStore)(nil).InsertEntryImage), ctx, params)
 }
+
+// MockAmazonCacheStore is a mock of AmazonCacheStore interface.
+type MockAmazonCacheStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockAmazonCacheStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockAmazonCacheStoreMockRecorder is the mock recorder for MockAmazonCacheStore.
+type MockAmazonCacheStoreMockRecorder struct {
+	mock *MockAmazonCacheStore
+}
+
+// NewMockAmazonCacheStore creates a new mock instance.
+func NewMockAmazonCacheStore(ctrl *gomock.Controller) *MockAmazonCacheStore {
+	mock := &MockAmazonCacheStore{ctrl: ctrl}
+	mock.recorder = &MockAmazonCacheStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAmazonCacheStore) EXPECT() *MockAmazonCacheStoreMockRecorder {
+	return m.recorder
+}
+
+// GetAmazonCacheFetchedAt mocks base method.
+func (m *MockAmazonCacheStore) GetAmazonCacheFetchedAt(ctx context.Context, asins []string) ([]admindb.GetAmazonCacheFetchedAtRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAmazonCacheFetchedAt", ctx, asins)
+	ret0, _ := ret[0].([]admindb.GetAmazonCacheFetchedAtRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAmazonCacheFetchedAt indicates an expected call of GetAmazonCacheFetchedAt.
+func (mr *MockAmazonCacheStoreMockRecorder) GetAmazonCacheFetchedAt(ctx, asins any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAmazonCacheFetchedAt", reflect.TypeOf((*MockAmazonCacheStore)(nil).GetAmazonCacheFetchedAt), ctx, asins)
+}
+
+// ListEntryBodiesWithAsin mocks base method.
+func (m *MockAmazonCacheStore) ListEntryBodiesWithAsin(ctx context.Context) ([]admindb.ListEntryBodiesWithAsinRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntryBodiesWithAsin", ctx)
+	ret0, _ := ret[0].([]admindb.ListEntryBodiesWithAsinRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntryBodiesWithAsin indicates an expected call of ListEntryBodiesWithAsin.
+func (mr *MockAmazonCacheStoreMockRecorder) ListEntryBodiesWithAsin(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntryBodiesWithAsin", reflect.TypeOf((*MockAmazonCacheStore)(nil).ListEntryBodiesWithAsin), ctx)
+}
+
+// MarkAmazonProductNotFound mocks base method.
+func (m *MockAmazonCacheStore) MarkAmazonProductNotFound(ctx context.Context, asin string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAmazonProductNotFound", ctx, asin)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkAmazonProductNotFound indicates an expected call of MarkAmazonProductNotFound.
+func (mr *MockAmazonCacheStoreMockRecorder) MarkAmazonProductNotFound(ctx, asin any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAmazonProductNotFound", reflect.TypeOf((*MockAmazonCacheStore)(nil).MarkAmazonProductNotFound), ctx, asin)
+}
+
+// UpsertAmazonProductDetail mocks base method.
+func (m *MockAmazonCacheStore) UpsertAmazonProductDetail(ctx context.Context, arg admindb.UpsertAmazonProductDetailParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertAmazonProductDetail", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertAmazonProductDetail indicates an expected call of UpsertAmazonProductDetail.
+func (mr *MockAmazonCacheStoreMockRecorder) UpsertAmazonProductDetail(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertAmazonProductDetail", reflect.TypeOf((*MockAmazonCacheStore)(nil).UpsertAmazonProductDetail), ctx, arg)
+}
+
+// MockAmazonProductFetcher is a mock of AmazonProductFetcher interface.
+type MockAmazonProductFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockAmazonProductFetcherMockRecorder
+	isgomock struct{}
+}
+
+// MockAmazonProductFetcherMockRecorder is the mock recorder for MockAmazonProductFetcher.
+type MockAmazonProductFetcherMockRecorder struct {
+	mock *MockAmazonProductFetcher
+}
+
+// NewMockAmazonProductFetcher creates a new mock instance.
+func NewMockAmazonProductFetcher(ctrl *gomock.Controller) *MockAmazonProductFetcher {
+	mock := &MockAmazonProductFetcher{ctrl: ctrl}
+	mock.recorder = &MockAmazonProductFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAmazonProductFetcher) EXPECT() *MockAmazonProductFetcherMockRecorder {
+	return m.recorder
+}
+
+// GetItems mocks base method.
+func (m *MockAmazonProductFetcher) GetItems(ctx context.Context, asins []string) ([]paapi.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItems", ctx, asins)
+	ret0, _ := ret[0].([]paapi.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItems indicates an expected call of GetItems.
+func (mr *MockAmazonProductFetcherMockRecorder) GetItems(ctx, asins any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItems", reflect.TypeOf((*MockAmazonProductFetcher)(nil).GetItems), ctx, asins)
+}
@@ -0,0 +1,225 @@
+// Package paapi is a minimal client for the Amazon Product Advertising API 5.0.
+// Only GetItems is implemented, which is all the blog needs to render
+// [asin:XXX:detail] tags.
+package paapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+const (
+	// DefaultEndpoint is the PA-API host for the Japanese marketplace.
+	DefaultEndpoint = "https://webservices.amazon.co.jp"
+	// DefaultRegion is the SigV4 region that serves amazon.co.jp.
+	DefaultRegion = "us-west-2"
+	// DefaultMarketplace is the marketplace items are looked up in.
+	DefaultMarketplace = "www.amazon.co.jp"
+	// DefaultInterval keeps requests within the initial quota of one request per second.
+	DefaultInterval = 1 * time.Second
+
+	// MaxItemIDs is the most ASINs GetItems accepts in one request.
+	MaxItemIDs = 10
+
+	getItemsPath   = "/paapi5/getitems"
+	getItemsTarget = "com.amazon.paapi5.v1.ProductAdvertisingAPIv1.GetItems"
+	signingService = "ProductAdvertisingAPI"
+)
+
+// Item is the subset of a GetItems result stored in amazon_cache.
+type Item struct {
+	ASIN          string
+	Title         string
+	ImageURL      string
+	DetailPageURL string
+}
+
+// Client calls PA-API 5.0. Requests are spaced by Interval so that a burst
+// of lookups stays within the account's rate limit.
+type Client struct {
+	AccessKey   string
+	SecretKey   string
+	PartnerTag  string
+	Endpoint    string
+	Region      string
+	Marketplace string
+	Interval    time.Duration
+
+	HTTPClient *http.Client
+
+	signer *v4.Signer
+	now    func() time.Time
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// NewClient creates a Client for the Japanese marketplace. Endpoint and the
+// other exported fields can be overridden afterwards, e.g. to point at a stub.
+func NewClient(accessKey, secretKey, partnerTag string) *Client {
+	return &Client{
+		AccessKey:   accessKey,
+		SecretKey:   secretKey,
+		PartnerTag:  partnerTag,
+		Endpoint:    DefaultEndpoint,
+		Region:      DefaultRegion,
+		Marketplace: DefaultMarketplace,
+		Interval:    DefaultInterval,
+		HTTPClient:  &http.Client{Timeout: 30 * time.Second},
+		signer:      v4.NewSigner(),
+		now:         time.Now,
+	}
+}
+
+type getItemsRequest struct {
+	ItemIds     []string `json:"ItemIds"`
+	ItemIdType  string   `json:"ItemIdType"`
+	Resources   []string `json:"Resources"`
+	PartnerTag  string   `json:"PartnerTag"`
+	PartnerType string   `json:"PartnerType"`
+	Marketplace string   `json:"Marketplace"`
+}
+
+type getItemsResponse struct {
+	ItemsResult struct {
+		Items []struct {
+			ASIN          string `json:"ASIN"`
+			DetailPageURL string `json:"DetailPageURL"`
+			Images        struct {
+				Primary struct {
+					Medium struct {
+						URL string `json:"URL"`
+					} `json:"Medium"`
+				} `json:"Primary"`
+			} `json:"Images"`
+			ItemInfo struct {
+				Title struct {
+					DisplayValue string `json:"DisplayValue"`
+				} `json:"Title"`
+			} `json:"ItemInfo"`
+		} `json:"Items"`
+	} `json:"ItemsResult"`
+	Errors []APIError `json:"Errors"`
+}
+
+// APIError is an error entry returned by PA-API.
+type APIError struct {
+	Code    string `json:"Code"`
+	Message string `json:"Message"`
+}
+
+func (e APIError) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// GetItems looks up at most MaxItemIDs ASINs. ASINs that PA-API cannot
+// return (e.g. discontinued items) are simply absent from the result.
+func (c *Client) GetItems(ctx context.Context, asins []string) ([]Item, error) {
+	if len(asins) == 0 {
+		return nil, nil
+	}
+	if len(asins) > MaxItemIDs {
+		return nil, fmt.Errorf("too many ASINs: %d > %d", len(asins), MaxItemIDs)
+	}
+
+	payload, err := json.Marshal(getItemsRequest{
+		ItemIds:     asins,
+		ItemIdType:  "ASIN",
+		Resources:   []string{"Images.Primary.Medium", "ItemInfo.Title"},
+		PartnerTag:  c.PartnerTag,
+		PartnerType: "Associates",
+		Marketplace: c.Marketplace,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal GetItems request: %w", err)
+	}
+
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(c.Endpoint, "/")+getItemsPath, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GetItems request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Content-Encoding", "amz-1.0")
+	req.Header.Set("X-Amz-Target", getItemsTarget)
+
+	hash := sha256.Sum256(payload)
+	creds := aws.Credentials{AccessKeyID: c.AccessKey, SecretAccessKey: c.SecretKey}
+	if err := c.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(hash[:]), signingService, c.Region, c.now()); err != nil {
+		return nil, fmt.Errorf("failed to sign GetItems request: %w", err)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call GetItems: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GetItems response: %w", err)
+	}
+
+	var result getItemsResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode GetItems response (status %d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if len(result.Errors) > 0 {
+			return nil, fmt.Errorf("GetItems returned status %d: %w", resp.StatusCode, result.Errors[0])
+		}
+		return nil, fmt.Errorf("GetItems returned status %d", resp.StatusCode)
+	}
+
+	items := make([]Item, 0, len(result.ItemsResult.Items))
+	for _, it := range result.ItemsResult.Items {
+		items = append(items, Item{
+			ASIN:          it.ASIN,
+			Title:         it.ItemInfo.Title.DisplayValue,
+			ImageURL:      it.Images.Primary.Medium.URL,
+			DetailPageURL: it.DetailPageURL,
+		})
+	}
+	return items, nil
+}
+
+// wait blocks until the next request slot, so that requests are at least
+// Interval apart.
+func (c *Client) wait(ctx context.Context) error {
+	c.mu.Lock()
+	now := c.now()
+	at := c.next
+	if at.Before(now) {
+		at = now
+	}
+	c.next = at.Add(c.Interval)
+	c.mu.Unlock()
+
+	delay := at.Sub(now)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,105 @@
+package paapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStubClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := NewClient("AKIDEXAMPLE", "secret", "example-22")
+	client.Endpoint = server.URL
+	client.Interval = 0
+	return client
+}
+
+func TestGetItems(t *testing.T) {
+	var got getItemsRequest
+	client := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/paapi5/getitems", r.URL.Path)
+		assert.Equal(t, getItemsTarget, r.Header.Get("X-Amz-Target"))
+		assert.Equal(t, "amz-1.0", r.Header.Get("Content-Encoding"))
+
+		auth := r.Header.Get("Authorization")
+		assert.True(t, strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/"), auth)
+		assert.Contains(t, auth, "/us-west-2/ProductAdvertisingAPI/aws4_request")
+		assert.Contains(t, auth, "x-amz-target")
+		assert.NotEmpty(t, r.Header.Get("X-Amz-Date"))
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(body, &got))
+
+		_, _ = w.Write([]byte(`{
+			"ItemsResult": {
+				"Items": [{
+					"ASIN": "4022520221",
+					"DetailPageURL": "https://www.amazon.co.jp/dp/4022520221?tag=example-22",
+					"Images": {"Primary": {"Medium": {"URL": "https://m.media-amazon.com/images/I/example.jpg"}}},
+					"ItemInfo": {"Title": {"DisplayValue": "Example Book"}}
+				}]
+			},
+			"Errors": [{"Code": "ItemNotAccessible", "Message": "B000000000 is not accessible"}]
+		}`))
+	})
+
+	items, err := client.GetItems(context.Background(), []string{"4022520221", "B000000000"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"4022520221", "B000000000"}, got.ItemIds)
+	assert.Equal(t, "example-22", got.PartnerTag)
+	assert.Equal(t, "www.amazon.co.jp", got.Marketplace)
+
+	require.Len(t, items, 1)
+	assert.Equal(t, Item{
+		ASIN:          "4022520221",
+		Title:         "Example Book",
+		ImageURL:      "https://m.media-amazon.com/images/I/example.jpg",
+		DetailPageURL: "https://www.amazon.co.jp/dp/4022520221?tag=example-22",
+	}, items[0])
+}
+
+func TestGetItems_ErrorStatus(t *testing.T) {
+	client := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"Errors": [{"Code": "TooManyRequests", "Message": "slow down"}]}`))
+	})
+
+	_, err := client.GetItems(context.Background(), []string{"4022520221"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 429")
+	assert.Contains(t, err.Error(), "TooManyRequests")
+}
+
+func TestGetItems_TooManyASINs(t *testing.T) {
+	client := NewClient("a", "b", "c")
+	_, err := client.GetItems(context.Background(), make([]string, MaxItemIDs+1))
+	assert.Error(t, err)
+}
+
+func TestWait_SpacesRequests(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	client := NewClient("a", "b", "c")
+	client.Interval = time.Hour
+	client.now = func() time.Time { return now }
+
+	require.NoError(t, client.wait(context.Background()))
+
+	// The second slot is an hour away, so a cancelled context returns at once.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, client.wait(ctx), context.Canceled)
+}
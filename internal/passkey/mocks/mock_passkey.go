@@ -0,0 +1,173 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: passkey.go
+//
+// Generated by this command:
+//
+//	mockgen -source=passkey.go -destination=mocks/mock_passkey.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	admindb "github.com/tokuhirom/blog4/db/admin/admindb"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockStore is a mock of Store interface.
+type MockStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockStoreMockRecorder is the mock recorder for MockStore.
+type MockStoreMockRecorder struct {
+	mock *MockStore
+}
+
+// NewMockStore creates a new mock instance.
+func NewMockStore(ctrl *gomock.Controller) *MockStore {
+	mock := &MockStore{ctrl: ctrl}
+	mock.recorder = &MockStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStore) EXPECT() *MockStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateWebauthnChallenge mocks base method.
+func (m *MockStore) CreateWebauthnChallenge(ctx context.Context, arg admindb.CreateWebauthnChallengeParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebauthnChallenge", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWebauthnChallenge indicates an expected call of CreateWebauthnChallenge.
+func (mr *MockStoreMockRecorder) CreateWebauthnChallenge(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebauthnChallenge", reflect.TypeOf((*MockStore)(nil).CreateWebauthnChallenge), ctx, arg)
+}
+
+// CreateWebauthnCredential mocks base method.
+func (m *MockStore) CreateWebauthnCredential(ctx context.Context, arg admindb.CreateWebauthnCredentialParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebauthnCredential", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWebauthnCredential indicates an expected call of CreateWebauthnCredential.
+func (mr *MockStoreMockRecorder) CreateWebauthnCredential(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebauthnCredential", reflect.TypeOf((*MockStore)(nil).CreateWebauthnCredential), ctx, arg)
+}
+
+// DeleteExpiredWebauthnChallenges mocks base method.
+func (m *MockStore) DeleteExpiredWebauthnChallenges(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredWebauthnChallenges", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredWebauthnChallenges indicates an expected call of DeleteExpiredWebauthnChallenges.
+func (mr *MockStoreMockRecorder) DeleteExpiredWebauthnChallenges(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredWebauthnChallenges", reflect.TypeOf((*MockStore)(nil).DeleteExpiredWebauthnChallenges), ctx)
+}
+
+// DeleteWebauthnChallenge mocks base method.
+func (m *MockStore) DeleteWebauthnChallenge(ctx context.Context, token string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebauthnChallenge", ctx, token)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteWebauthnChallenge indicates an expected call of DeleteWebauthnChallenge.
+func (mr *MockStoreMockRecorder) DeleteWebauthnChallenge(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebauthnChallenge", reflect.TypeOf((*MockStore)(nil).DeleteWebauthnChallenge), ctx, token)
+}
+
+// GetAdminUser mocks base method.
+func (m *MockStore) GetAdminUser(ctx context.Context, id int64) (admindb.AdminUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAdminUser", ctx, id)
+	ret0, _ := ret[0].(admindb.AdminUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAdminUser indicates an expected call of GetAdminUser.
+func (mr *MockStoreMockRecorder) GetAdminUser(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdminUser", reflect.TypeOf((*MockStore)(nil).GetAdminUser), ctx, id)
+}
+
+// GetWebauthnChallenge mocks base method.
+func (m *MockStore) GetWebauthnChallenge(ctx context.Context, token string) (admindb.WebauthnChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebauthnChallenge", ctx, token)
+	ret0, _ := ret[0].(admindb.WebauthnChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebauthnChallenge indicates an expected call of GetWebauthnChallenge.
+func (mr *MockStoreMockRecorder) GetWebauthnChallenge(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebauthnChallenge", reflect.TypeOf((*MockStore)(nil).GetWebauthnChallenge), ctx, token)
+}
+
+// GetWebauthnCredentialByCredentialID mocks base method.
+func (m *MockStore) GetWebauthnCredentialByCredentialID(ctx context.Context, credentialID []byte) (admindb.WebauthnCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebauthnCredentialByCredentialID", ctx, credentialID)
+	ret0, _ := ret[0].(admindb.WebauthnCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebauthnCredentialByCredentialID indicates an expected call of GetWebauthnCredentialByCredentialID.
+func (mr *MockStoreMockRecorder) GetWebauthnCredentialByCredentialID(ctx, credentialID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebauthnCredentialByCredentialID", reflect.TypeOf((*MockStore)(nil).GetWebauthnCredentialByCredentialID), ctx, credentialID)
+}
+
+// ListWebauthnCredentials mocks base method.
+func (m *MockStore) ListWebauthnCredentials(ctx context.Context, userID int64) ([]admindb.WebauthnCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebauthnCredentials", ctx, userID)
+	ret0, _ := ret[0].([]admindb.WebauthnCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebauthnCredentials indicates an expected call of ListWebauthnCredentials.
+func (mr *MockStoreMockRecorder) ListWebauthnCredentials(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebauthnCredentials", reflect.TypeOf((*MockStore)(nil).ListWebauthnCredentials), ctx, userID)
+}
+
+// UpdateWebauthnCredentialUsage mocks base method.
+func (m *MockStore) UpdateWebauthnCredentialUsage(ctx context.Context, arg admindb.UpdateWebauthnCredentialUsageParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebauthnCredentialUsage", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWebauthnCredentialUsage indicates an expected call of UpdateWebauthnCredentialUsage.
+func (mr *MockStoreMockRecorder) UpdateWebauthnCredentialUsage(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebauthnCredentialUsage", reflect.TypeOf((*MockStore)(nil).UpdateWebauthnCredentialUsage), ctx, arg)
+}
@@ -0,0 +1,294 @@
+// Package passkey signs admin users in with WebAuthn credentials (passkeys).
+// A ceremony spans two requests: Begin* returns the options for
+// navigator.credentials and a token naming the stored challenge, and Finish*
+// checks the browser's response against it.
+package passkey
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+
+	"github.com/tokuhirom/blog4/db/admin/admindb"
+)
+
+//go:generate go run go.uber.org/mock/mockgen -source=passkey.go -destination=mocks/mock_passkey.go -package=mocks
+
+// ChallengeTimeout is how long a ceremony may take between begin and finish
+const ChallengeTimeout = 5 * time.Minute
+
+var (
+	// ErrChallengeExpired is returned when the token of a ceremony is unknown,
+	// expired or already used
+	ErrChallengeExpired = errors.New("passkey challenge expired")
+	// ErrVerificationFailed is returned when the browser's response does not
+	// verify against the challenge or a stored passkey
+	ErrVerificationFailed = errors.New("passkey verification failed")
+	ErrInvalidName        = errors.New("passkey name must not be empty")
+)
+
+// Store defines the database operations needed for passkeys
+type Store interface {
+	GetAdminUser(ctx context.Context, id int64) (admindb.AdminUser, error)
+	CreateWebauthnCredential(ctx context.Context, arg admindb.CreateWebauthnCredentialParams) error
+	ListWebauthnCredentials(ctx context.Context, userID int64) ([]admindb.WebauthnCredential, error)
+	GetWebauthnCredentialByCredentialID(ctx context.Context, credentialID []byte) (admindb.WebauthnCredential, error)
+	UpdateWebauthnCredentialUsage(ctx context.Context, arg admindb.UpdateWebauthnCredentialUsageParams) error
+	CreateWebauthnChallenge(ctx context.Context, arg admindb.CreateWebauthnChallengeParams) error
+	GetWebauthnChallenge(ctx context.Context, token string) (admindb.WebauthnChallenge, error)
+	DeleteWebauthnChallenge(ctx context.Context, token string) (int64, error)
+	DeleteExpiredWebauthnChallenges(ctx context.Context) error
+}
+
+// Service registers passkeys and verifies passkey logins
+type Service struct {
+	store    Store
+	webAuthn *webauthn.WebAuthn
+}
+
+// NewService creates a new Service for the admin served from origin, such
+// as https://blog.example.com. Passkeys are bound to the origin's host.
+func NewService(store Store, origin, displayName string) (*Service, error) {
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid passkey origin %q", origin)
+	}
+	w, err := webauthn.New(&webauthn.Config{
+		RPID:          u.Hostname(),
+		RPDisplayName: displayName,
+		RPOrigins:     []string{u.Scheme + "://" + u.Host},
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			ResidentKey:      protocol.ResidentKeyRequirementRequired,
+			UserVerification: protocol.VerificationRequired,
+		},
+		Timeouts: webauthn.TimeoutsConfig{
+			Login:        webauthn.TimeoutConfig{Enforce: true, Timeout: ChallengeTimeout},
+			Registration: webauthn.TimeoutConfig{Enforce: true, Timeout: ChallengeTimeout},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure WebAuthn: %w", err)
+	}
+	return &Service{store: store, webAuthn: w}, nil
+}
+
+// user adapts an admin user and its passkeys to webauthn.User
+type user struct {
+	admindb.AdminUser
+	credentials []webauthn.Credential
+}
+
+func (u *user) WebAuthnID() []byte                         { return userHandle(u.ID) }
+func (u *user) WebAuthnName() string                       { return u.Username }
+func (u *user) WebAuthnDisplayName() string                { return u.Username }
+func (u *user) WebAuthnCredentials() []webauthn.Credential { return u.credentials }
+
+// userHandle is the opaque id passkeys store for their user: the admin
+// user's id as 8 big-endian bytes
+func userHandle(id int64) []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(id))
+}
+
+func (s *Service) loadUser(ctx context.Context, id int64) (*user, error) {
+	u, err := s.store.GetAdminUser(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get admin user %d: %w", id, err)
+	}
+	rows, err := s.store.ListWebauthnCredentials(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list passkeys of %s: %w", u.Username, err)
+	}
+	credentials := make([]webauthn.Credential, 0, len(rows))
+	for _, row := range rows {
+		var credential webauthn.Credential
+		if err := json.Unmarshal(row.Credential, &credential); err != nil {
+			return nil, fmt.Errorf("failed to decode passkey %d: %w", row.ID, err)
+		}
+		credentials = append(credentials, credential)
+	}
+	return &user{AdminUser: u, credentials: credentials}, nil
+}
+
+// BeginRegistration starts adding a passkey for the signed-in user
+func (s *Service) BeginRegistration(ctx context.Context, userID int64, now time.Time) (*protocol.CredentialCreation, string, error) {
+	u, err := s.loadUser(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	creation, session, err := s.webAuthn.BeginRegistration(u,
+		webauthn.WithExclusions(webauthn.Credentials(u.credentials).CredentialDescriptors()))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to begin passkey registration: %w", err)
+	}
+	token, err := s.saveChallenge(ctx, sql.NullInt64{Int64: userID, Valid: true}, session, now)
+	if err != nil {
+		return nil, "", err
+	}
+	return creation, token, nil
+}
+
+// FinishRegistration verifies the browser's response to BeginRegistration
+// and stores the new passkey under name
+func (s *Service) FinishRegistration(ctx context.Context, userID int64, token, name string, response []byte) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ErrInvalidName
+	}
+	challenge, session, err := s.takeChallenge(ctx, token)
+	if err != nil {
+		return err
+	}
+	if challenge.UserID.Int64 != userID {
+		return ErrChallengeExpired
+	}
+	u, err := s.loadUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(response)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrVerificationFailed, err)
+	}
+	credential, err := s.webAuthn.CreateCredential(u, *session, parsed)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrVerificationFailed, err)
+	}
+	encoded, err := json.Marshal(credential)
+	if err != nil {
+		return fmt.Errorf("failed to encode passkey: %w", err)
+	}
+	if err := s.store.CreateWebauthnCredential(ctx, admindb.CreateWebauthnCredentialParams{
+		UserID:       userID,
+		CredentialID: credential.ID,
+		Name:         name,
+		Credential:   encoded,
+	}); err != nil {
+		return fmt.Errorf("failed to store passkey of %s: %w", u.Username, err)
+	}
+	return nil
+}
+
+// BeginLogin starts a login with any passkey of this site, chosen by the
+// browser
+func (s *Service) BeginLogin(ctx context.Context, now time.Time) (*protocol.CredentialAssertion, string, error) {
+	assertion, session, err := s.webAuthn.BeginDiscoverableLogin(
+		webauthn.WithUserVerification(protocol.VerificationRequired))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to begin passkey login: %w", err)
+	}
+	token, err := s.saveChallenge(ctx, sql.NullInt64{}, session, now)
+	if err != nil {
+		return nil, "", err
+	}
+	return assertion, token, nil
+}
+
+// FinishLogin verifies the browser's response to BeginLogin and returns the
+// id of the user whose passkey signed it
+func (s *Service) FinishLogin(ctx context.Context, token string, response []byte) (int64, error) {
+	challenge, session, err := s.takeChallenge(ctx, token)
+	if err != nil {
+		return 0, err
+	}
+	if challenge.UserID.Valid {
+		return 0, ErrChallengeExpired
+	}
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(response)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrVerificationFailed, err)
+	}
+
+	var row admindb.WebauthnCredential
+	found, credential, err := s.webAuthn.ValidatePasskeyLogin(func(rawID, handle []byte) (webauthn.User, error) {
+		found, err := s.store.GetWebauthnCredentialByCredentialID(ctx, rawID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get passkey: %w", err)
+		}
+		row = found
+		if string(userHandle(row.UserID)) != string(handle) {
+			return nil, errors.New("passkey belongs to another user")
+		}
+		return s.loadUser(ctx, row.UserID)
+	}, *session, parsed)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrVerificationFailed, err)
+	}
+	u := found.(*user)
+	if credential.Authenticator.CloneWarning {
+		return 0, fmt.Errorf("%w: sign count of a passkey of %s went backwards", ErrVerificationFailed, u.Username)
+	}
+
+	// Keep the sign count for detecting cloned authenticators
+	encoded, err := json.Marshal(credential)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode passkey: %w", err)
+	}
+	if err := s.store.UpdateWebauthnCredentialUsage(ctx, admindb.UpdateWebauthnCredentialUsageParams{
+		Credential: encoded,
+		ID:         row.ID,
+	}); err != nil {
+		return 0, fmt.Errorf("failed to update passkey %d: %w", row.ID, err)
+	}
+	return u.ID, nil
+}
+
+// saveChallenge stores session until the ceremony finishes and returns the
+// token naming it
+func (s *Service) saveChallenge(ctx context.Context, userID sql.NullInt64, session *webauthn.SessionData, now time.Time) (string, error) {
+	if err := s.store.DeleteExpiredWebauthnChallenges(ctx); err != nil {
+		return "", fmt.Errorf("failed to delete expired passkey challenges: %w", err)
+	}
+	data, err := json.Marshal(session)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode passkey challenge: %w", err)
+	}
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate passkey challenge token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	if err := s.store.CreateWebauthnChallenge(ctx, admindb.CreateWebauthnChallengeParams{
+		Token:       token,
+		UserID:      userID,
+		SessionData: data,
+		ExpiresAt:   now.Add(ChallengeTimeout),
+	}); err != nil {
+		return "", fmt.Errorf("failed to store passkey challenge: %w", err)
+	}
+	return token, nil
+}
+
+// takeChallenge loads the challenge named by token and deletes it, so that
+// each challenge is answered at most once
+func (s *Service) takeChallenge(ctx context.Context, token string) (admindb.WebauthnChallenge, *webauthn.SessionData, error) {
+	challenge, err := s.store.GetWebauthnChallenge(ctx, token)
+	if errors.Is(err, sql.ErrNoRows) {
+		return admindb.WebauthnChallenge{}, nil, ErrChallengeExpired
+	}
+	if err != nil {
+		return admindb.WebauthnChallenge{}, nil, fmt.Errorf("failed to get passkey challenge: %w", err)
+	}
+	n, err := s.store.DeleteWebauthnChallenge(ctx, token)
+	if err != nil {
+		return admindb.WebauthnChallenge{}, nil, fmt.Errorf("failed to delete passkey challenge: %w", err)
+	}
+	if n == 0 {
+		return admindb.WebauthnChallenge{}, nil, ErrChallengeExpired
+	}
+	var session webauthn.SessionData
+	if err := json.Unmarshal(challenge.SessionData, &session); err != nil {
+		return admindb.WebauthnChallenge{}, nil, fmt.Errorf("failed to decode passkey challenge: %w", err)
+	}
+	return challenge, &session, nil
+}
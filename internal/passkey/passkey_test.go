@@ -0,0 +1,125 @@
+package passkey
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/tokuhirom/blog4/db/admin/admindb"
+	"github.com/tokuhirom/blog4/internal/passkey/mocks"
+)
+
+var testNow = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+func newTestService(t *testing.T, store Store) *Service {
+	s, err := NewService(store, "https://blog.example.com/", "Example")
+	require.NoError(t, err)
+	return s
+}
+
+func TestNewService(t *testing.T) {
+	s := newTestService(t, nil)
+	assert.Equal(t, "blog.example.com", s.webAuthn.Config.RPID)
+	assert.Equal(t, []string{"https://blog.example.com"}, s.webAuthn.Config.RPOrigins)
+
+	_, err := NewService(nil, "blog.example.com", "Example")
+	assert.Error(t, err)
+}
+
+func TestBeginRegistration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	existing, err := json.Marshal(webauthn.Credential{ID: []byte("existing")})
+	require.NoError(t, err)
+
+	store := mocks.NewMockStore(ctrl)
+	store.EXPECT().GetAdminUser(gomock.Any(), int64(3)).Return(admindb.AdminUser{ID: 3, Username: "alice"}, nil)
+	store.EXPECT().ListWebauthnCredentials(gomock.Any(), int64(3)).Return([]admindb.WebauthnCredential{
+		{ID: 1, UserID: 3, CredentialID: []byte("existing"), Credential: existing},
+	}, nil)
+	store.EXPECT().DeleteExpiredWebauthnChallenges(gomock.Any()).Return(nil)
+	var saved admindb.CreateWebauthnChallengeParams
+	store.EXPECT().CreateWebauthnChallenge(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg admindb.CreateWebauthnChallengeParams) error {
+			saved = arg
+			return nil
+		})
+	s := newTestService(t, store)
+
+	creation, token, err := s.BeginRegistration(context.Background(), 3, testNow)
+	require.NoError(t, err)
+
+	assert.Equal(t, token, saved.Token)
+	assert.Equal(t, sql.NullInt64{Int64: 3, Valid: true}, saved.UserID)
+	assert.Equal(t, testNow.Add(ChallengeTimeout), saved.ExpiresAt)
+	assert.Equal(t, "alice", creation.Response.User.Name)
+	assert.EqualValues(t, userHandle(3), creation.Response.User.ID)
+	// A passkey already on the authenticator is not registered twice
+	require.Len(t, creation.Response.CredentialExcludeList, 1)
+	assert.Equal(t, []byte("existing"), []byte(creation.Response.CredentialExcludeList[0].CredentialID))
+
+	var session webauthn.SessionData
+	require.NoError(t, json.Unmarshal(saved.SessionData, &session))
+	assert.Equal(t, creation.Response.Challenge.String(), session.Challenge)
+}
+
+func TestFinishRegistration_Rejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mocks.NewMockStore(ctrl)
+	s := newTestService(t, store)
+	ctx := context.Background()
+
+	assert.ErrorIs(t, s.FinishRegistration(ctx, 3, "token", " ", nil), ErrInvalidName)
+
+	// Unknown or expired token
+	store.EXPECT().GetWebauthnChallenge(gomock.Any(), "token").Return(admindb.WebauthnChallenge{}, sql.ErrNoRows)
+	assert.ErrorIs(t, s.FinishRegistration(ctx, 3, "token", "phone", nil), ErrChallengeExpired)
+
+	// Answered concurrently by another request
+	store.EXPECT().GetWebauthnChallenge(gomock.Any(), "token").Return(admindb.WebauthnChallenge{
+		Token: "token", UserID: sql.NullInt64{Int64: 3, Valid: true}, SessionData: json.RawMessage(`{}`),
+	}, nil)
+	store.EXPECT().DeleteWebauthnChallenge(gomock.Any(), "token").Return(int64(0), nil)
+	assert.ErrorIs(t, s.FinishRegistration(ctx, 3, "token", "phone", nil), ErrChallengeExpired)
+
+	// Started by another user
+	store.EXPECT().GetWebauthnChallenge(gomock.Any(), "token").Return(admindb.WebauthnChallenge{
+		Token: "token", UserID: sql.NullInt64{Int64: 4, Valid: true}, SessionData: json.RawMessage(`{}`),
+	}, nil)
+	store.EXPECT().DeleteWebauthnChallenge(gomock.Any(), "token").Return(int64(1), nil)
+	assert.ErrorIs(t, s.FinishRegistration(ctx, 3, "token", "phone", nil), ErrChallengeExpired)
+}
+
+func TestFinishLogin_Rejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mocks.NewMockStore(ctrl)
+	s := newTestService(t, store)
+	ctx := context.Background()
+
+	// A registration challenge cannot be used to log in
+	store.EXPECT().GetWebauthnChallenge(gomock.Any(), "token").Return(admindb.WebauthnChallenge{
+		Token: "token", UserID: sql.NullInt64{Int64: 3, Valid: true}, SessionData: json.RawMessage(`{}`),
+	}, nil)
+	store.EXPECT().DeleteWebauthnChallenge(gomock.Any(), "token").Return(int64(1), nil)
+	_, err := s.FinishLogin(ctx, "token", nil)
+	assert.ErrorIs(t, err, ErrChallengeExpired)
+
+	store.EXPECT().GetWebauthnChallenge(gomock.Any(), "token").Return(admindb.WebauthnChallenge{
+		Token: "token", SessionData: json.RawMessage(`{}`),
+	}, nil)
+	store.EXPECT().DeleteWebauthnChallenge(gomock.Any(), "token").Return(int64(1), nil)
+	_, err = s.FinishLogin(ctx, "token", []byte(`{"id": "broken"}`))
+	assert.ErrorIs(t, err, ErrVerificationFailed)
+}
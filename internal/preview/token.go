@@ -0,0 +1,94 @@
+// Package preview signs and verifies the tokens of preview links, which let
+// someone without an admin session read an entry that is not public yet.
+package preview
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// QueryParam is the query parameter of /entry/ that carries the token.
+const QueryParam = "preview"
+
+var (
+	ErrInvalidToken = errors.New("invalid preview token")
+	ErrExpiredToken = errors.New("preview token has expired")
+)
+
+// Signer mints tokens of the form <id>.<expires>.<signature>. The signature
+// covers the id, the entry path and the expiry, so a token only opens the
+// entry it was minted for. The id is the key of the preview_token row, which
+// must still exist for the token to be accepted; deleting it revokes the link.
+type Signer struct {
+	key []byte
+	now func() time.Time
+}
+
+// NewSigner creates a Signer keyed with secret.
+func NewSigner(secret string) *Signer {
+	return &Signer{key: []byte(secret), now: time.Now}
+}
+
+// NewTokenID returns a random id for a new preview_token row.
+func NewTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate preview token id: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Sign returns the token for the preview_token row id of path. expiresAt is
+// truncated to seconds, the precision of the expires_at column, so that a
+// token minted again from the stored row is the same.
+func (s *Signer) Sign(id, path string, expiresAt time.Time) string {
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	return id + "." + expires + "." + s.signature(id, path, expires)
+}
+
+// Verify checks that token was minted for path and has not expired, and
+// returns its id. The caller still has to check that the id was not revoked.
+func (s *Signer) Verify(token, path string) (string, error) {
+	id, expires, sig, ok := splitToken(token)
+	if !ok {
+		return "", ErrInvalidToken
+	}
+	if !hmac.Equal([]byte(sig), []byte(s.signature(id, path, expires))) {
+		return "", ErrInvalidToken
+	}
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+	if !s.now().Before(time.Unix(unix, 0)) {
+		return "", ErrExpiredToken
+	}
+	return id, nil
+}
+
+func (s *Signer) signature(id, path, expires string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(id + "\x00" + path + "\x00" + expires))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func splitToken(token string) (id, expires, sig string, ok bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", false
+	}
+	return parts[0], parts[1], parts[2], true
+}
+
+// URL returns the preview link of path on the site at baseURL.
+func URL(baseURL, path, token string) string {
+	return strings.TrimSuffix(baseURL, "/") + "/entry/" + path + "?" + url.Values{QueryParam: {token}}.Encode()
+}
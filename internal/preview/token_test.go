@@ -0,0 +1,65 @@
+package preview
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+
+func newTestSigner(secret string) *Signer {
+	s := NewSigner(secret)
+	s.now = func() time.Time { return testNow }
+	return s
+}
+
+func TestSignAndVerify(t *testing.T) {
+	s := newTestSigner("secret")
+	token := s.Sign("abc", "2026/01/01/120000", testNow.Add(time.Hour))
+
+	id, err := s.Verify(token, "2026/01/01/120000")
+	require.NoError(t, err)
+	assert.Equal(t, "abc", id)
+}
+
+func TestSign_IgnoresSubSecond(t *testing.T) {
+	s := newTestSigner("secret")
+	expires := testNow.Add(time.Hour)
+	assert.Equal(t, s.Sign("abc", "draft", expires), s.Sign("abc", "draft", expires.Add(500*time.Millisecond)))
+}
+
+func TestVerify_Rejects(t *testing.T) {
+	s := newTestSigner("secret")
+	token := s.Sign("abc", "draft", testNow.Add(time.Hour))
+	sig := token[strings.LastIndex(token, ".")+1:]
+
+	tests := []struct {
+		name  string
+		token string
+		path  string
+		err   error
+	}{
+		{name: "other entry", token: token, path: "other", err: ErrInvalidToken},
+		{name: "other key", token: newTestSigner("other").Sign("abc", "draft", testNow.Add(time.Hour)), path: "draft", err: ErrInvalidToken},
+		{name: "extended expiry", token: "abc.9999999999." + sig, path: "draft", err: ErrInvalidToken},
+		{name: "malformed", token: "abc", path: "draft", err: ErrInvalidToken},
+		{name: "empty part", token: "abc..sig", path: "draft", err: ErrInvalidToken},
+		{name: "expired", token: s.Sign("abc", "draft", testNow), path: "draft", err: ErrExpiredToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.Verify(tt.token, tt.path)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestURL(t *testing.T) {
+	assert.Equal(t,
+		"https://blog.example.com/entry/2026/01/01/120000?preview=abc.1.sig",
+		URL("https://blog.example.com/", "2026/01/01/120000", "abc.1.sig"))
+}
@@ -0,0 +1,192 @@
+package public
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/tokuhirom/blog4/db/public/publicdb"
+	"github.com/tokuhirom/blog4/internal"
+	"github.com/tokuhirom/blog4/internal/templates"
+)
+
+var (
+	archiveYearPattern  = regexp.MustCompile(`^[0-9]{4}$`)
+	archiveMonthPattern = regexp.MustCompile(`^(0[1-9]|1[0-2])$`)
+)
+
+// ArchiveIndexData is the data for the /archive page.
+type ArchiveIndexData struct {
+	Years []ArchiveYearData
+	// Today is the month and day OnThisDay was collected for, e.g. "01-02".
+	Today     string
+	OnThisDay []EntryViewData
+}
+
+// ArchiveYearData is one year on the archive pages.
+type ArchiveYearData struct {
+	Year       int
+	Path       string
+	EntryCount int64
+	Months     []ArchiveMonthData
+}
+
+// ArchiveMonthData is one month that has entries.
+type ArchiveMonthData struct {
+	Label      string
+	Path       string
+	EntryCount int64
+}
+
+func archiveYearPath(year int) string {
+	return fmt.Sprintf("/archive/%04d", year)
+}
+
+func archiveMonthPath(year int, month time.Month) string {
+	return fmt.Sprintf("/archive/%04d/%02d", year, int(month))
+}
+
+// groupArchiveMonths turns per-month counts, newest year first, into years.
+func groupArchiveMonths(rows []publicdb.CountPublicEntriesByMonthRow) []ArchiveYearData {
+	var years []ArchiveYearData
+	for _, row := range rows {
+		year := int(row.Year)
+		if len(years) == 0 || years[len(years)-1].Year != year {
+			years = append(years, ArchiveYearData{Year: year, Path: archiveYearPath(year)})
+		}
+		y := &years[len(years)-1]
+		y.EntryCount += row.EntryCount
+		y.Months = append(y.Months, ArchiveMonthData{
+			Label:      fmt.Sprintf("%02d", row.Month),
+			Path:       archiveMonthPath(year, time.Month(row.Month)),
+			EntryCount: row.EntryCount,
+		})
+	}
+	return years
+}
+
+// RenderArchiveIndexPage lists every year and month that has entries, and the
+// entries published on today's date in past years.
+func RenderArchiveIndexPage(c *gin.Context, queries *publicdb.Queries, cfg *internal.Config, views *templates.Registry) {
+	tmpl, err := views.Lookup("archive.html")
+	if err != nil {
+		slog.Error("failed to load template", slog.String("template", "archive.html"), slog.Any("error", err))
+		c.String(http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	ctx := c.Request.Context()
+	counts, err := queries.CountPublicEntriesByMonth(ctx)
+	if err != nil {
+		slog.Error("failed to count entries by month", slog.Any("error", err))
+		c.String(http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	now := time.Now().In(cfg.Location())
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	rows, err := queries.ListPublicEntriesOnDay(ctx, publicdb.ListPublicEntriesOnDayParams{
+		Month:  int64(today.Month()),
+		Day:    int64(today.Day()),
+		Before: sql.NullTime{Time: today, Valid: true},
+	})
+	if err != nil {
+		slog.Error("failed to list entries on this day", slog.Any("error", err))
+		c.String(http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+	entries := make([]publicdb.SearchEntriesRow, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, publicdb.SearchEntriesRow(row))
+	}
+	onThisDay, err := toEntryViewData(entries)
+	if err != nil {
+		slog.Error("failed to prepare entries", slog.Any("error", err))
+		c.String(http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	c.Status(http.StatusOK)
+	err = tmpl.Execute(c.Writer, ArchiveIndexData{
+		Years:     groupArchiveMonths(counts),
+		Today:     today.Format("01-02"),
+		OnThisDay: onThisDay,
+	})
+	if err != nil {
+		slog.Error("failed to execute template", slog.String("template", "archive.html"), slog.Any("error", err))
+		c.String(http.StatusInternalServerError, "Internal Server Error")
+	}
+}
+
+// RenderYearArchivePage lists the entries published in :year, with links to
+// its months.
+func RenderYearArchivePage(c *gin.Context, queries *publicdb.Queries, cfg *internal.Config, views *templates.Registry) {
+	if !archiveYearPattern.MatchString(c.Param("year")) {
+		c.String(http.StatusNotFound, "Not Found")
+		return
+	}
+	year, _ := strconv.Atoi(c.Param("year"))
+
+	counts, err := queries.CountPublicEntriesByMonth(c.Request.Context())
+	if err != nil {
+		slog.Error("failed to count entries by month", slog.Any("error", err))
+		c.String(http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+	var months []ArchiveMonthData
+	for _, y := range groupArchiveMonths(counts) {
+		if y.Year == year {
+			months = y.Months
+		}
+	}
+
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, cfg.Location())
+	renderEntryList(c, views, TopPageData{
+		Archive:  fmt.Sprintf("%04d", year),
+		Months:   months,
+		PagePath: archiveYearPath(year),
+	}, publishedRangeLoader(queries, from, from.AddDate(1, 0, 0)))
+}
+
+// RenderMonthArchivePage lists the entries published in :year/:month.
+func RenderMonthArchivePage(c *gin.Context, queries *publicdb.Queries, cfg *internal.Config, views *templates.Registry) {
+	if !archiveYearPattern.MatchString(c.Param("year")) || !archiveMonthPattern.MatchString(c.Param("month")) {
+		c.String(http.StatusNotFound, "Not Found")
+		return
+	}
+	year, _ := strconv.Atoi(c.Param("year"))
+	month, _ := strconv.Atoi(c.Param("month"))
+
+	from := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, cfg.Location())
+	renderEntryList(c, views, TopPageData{
+		Archive:  fmt.Sprintf("%04d-%02d", year, month),
+		PagePath: archiveMonthPath(year, time.Month(month)),
+	}, publishedRangeLoader(queries, from, from.AddDate(0, 1, 0)))
+}
+
+// publishedRangeLoader loads the public entries published in [from, to).
+func publishedRangeLoader(queries *publicdb.Queries, from, to time.Time) func(ctx context.Context, limit, offset int32) ([]publicdb.SearchEntriesRow, error) {
+	return func(ctx context.Context, limit, offset int32) ([]publicdb.SearchEntriesRow, error) {
+		rows, err := queries.SearchEntriesByPublishedRange(ctx, publicdb.SearchEntriesByPublishedRangeParams{
+			PublishedFrom: sql.NullTime{Time: from, Valid: true},
+			PublishedTo:   sql.NullTime{Time: to, Valid: true},
+			Limit:         limit,
+			Offset:        offset,
+		})
+		if err != nil {
+			return nil, err
+		}
+		entries := make([]publicdb.SearchEntriesRow, 0, len(rows))
+		for _, row := range rows {
+			entries = append(entries, publicdb.SearchEntriesRow(row))
+		}
+		return entries, nil
+	}
+}
@@ -0,0 +1,81 @@
+package public
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tokuhirom/blog4/db/public/publicdb"
+	"github.com/tokuhirom/blog4/internal/templates"
+	publicassets "github.com/tokuhirom/blog4/public"
+)
+
+func newTestTemplates(t *testing.T) *templates.Registry {
+	t.Helper()
+	views, err := NewTemplates(publicassets.FS(false), false)
+	require.NoError(t, err)
+	return views
+}
+
+func TestGroupArchiveMonths(t *testing.T) {
+	years := groupArchiveMonths([]publicdb.CountPublicEntriesByMonthRow{
+		{Year: 2026, Month: 1, EntryCount: 3},
+		{Year: 2026, Month: 10, EntryCount: 2},
+		{Year: 2024, Month: 12, EntryCount: 1},
+	})
+
+	assert.Equal(t, []ArchiveYearData{
+		{
+			Year:       2026,
+			Path:       "/archive/2026",
+			EntryCount: 5,
+			Months: []ArchiveMonthData{
+				{Label: "01", Path: "/archive/2026/01", EntryCount: 3},
+				{Label: "10", Path: "/archive/2026/10", EntryCount: 2},
+			},
+		},
+		{
+			Year:       2024,
+			Path:       "/archive/2024",
+			EntryCount: 1,
+			Months: []ArchiveMonthData{
+				{Label: "12", Path: "/archive/2024/12", EntryCount: 1},
+			},
+		},
+	}, years)
+	assert.Empty(t, groupArchiveMonths(nil))
+}
+
+func TestArchiveTemplates(t *testing.T) {
+	views := newTestTemplates(t)
+	tmpl, err := views.Lookup("archive.html")
+	require.NoError(t, err)
+	var out strings.Builder
+	require.NoError(t, tmpl.Execute(&out, ArchiveIndexData{
+		Years: groupArchiveMonths([]publicdb.CountPublicEntriesByMonthRow{{Year: 2026, Month: 1, EntryCount: 3}}),
+		Today: "01-02",
+		OnThisDay: []EntryViewData{
+			{Path: "2024/01/02/120000", Title: "Two years ago", PublishedAt: "2024-01-02(Tue)"},
+		},
+	}))
+	assert.Contains(t, out.String(), `<a href="/archive/2026">2026</a>`)
+	assert.Contains(t, out.String(), `<a href="/archive/2026/01">01`)
+	assert.Contains(t, out.String(), `<a href="/entry/2024/01/02/120000">Two years ago</a>`)
+
+	tmpl, err = views.Lookup("index.html")
+	require.NoError(t, err)
+	out.Reset()
+	require.NoError(t, tmpl.Execute(&out, TopPageData{
+		Page:     1,
+		Next:     2,
+		HasNext:  true,
+		Archive:  "2026",
+		Months:   []ArchiveMonthData{{Label: "01", Path: "/archive/2026/01", EntryCount: 3}},
+		PagePath: "/archive/2026",
+	}))
+	assert.Contains(t, out.String(), `<title>2026 - tokuhirom's blog</title>`)
+	assert.Contains(t, out.String(), `<a href="/archive/2026/01">01`)
+	assert.Contains(t, out.String(), `<a href="/archive/2026?page=2">Next</a>`)
+}
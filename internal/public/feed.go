@@ -0,0 +1,334 @@
+package public
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/feeds"
+
+	"github.com/tokuhirom/blog4/db/public/publicdb"
+	"github.com/tokuhirom/blog4/internal"
+	"github.com/tokuhirom/blog4/internal/markdown"
+)
+
+// feedFormat is one of the serializations the feed is offered in.
+type feedFormat struct {
+	path        string
+	contentType string
+	render      func(feed *feeds.Feed, selfURL string, hubs []string) (string, error)
+}
+
+var (
+	rssFeedFormat = feedFormat{
+		path:        "/feed",
+		contentType: "application/rss+xml; charset=utf-8",
+		render:      toRssWithHubs,
+	}
+	atomFeedFormat = feedFormat{
+		path:        "/feed.atom",
+		contentType: "application/atom+xml; charset=utf-8",
+		render:      toAtomWithHubs,
+	}
+	jsonFeedFormat = feedFormat{
+		path:        "/feed.json",
+		contentType: "application/feed+json; charset=utf-8",
+		render:      toJSONFeedWithHubs,
+	}
+)
+
+// FeedPaths returns the path of every feed format, e.g. for WebSub topics.
+func FeedPaths() []string {
+	return []string{rssFeedFormat.path, atomFeedFormat.path, jsonFeedFormat.path}
+}
+
+// feedSource selects the entries a feed lists.
+type feedSource struct {
+	// prefix is prepended to the format's path: "" for the whole blog,
+	// "/tag/{name}" for a tag.
+	prefix string
+	title  string
+	// hubs are advertised in the feed. Only the site-wide feeds are announced
+	// to WebSub hubs, so tag feeds advertise none.
+	hubs []string
+	load func(ctx context.Context, limit int32) ([]publicdb.SearchEntriesRow, error)
+}
+
+func siteFeedSource(queries *publicdb.Queries, cfg *internal.Config) feedSource {
+	return feedSource{
+		title: cfg.SiteName,
+		hubs:  cfg.GetHubUrls(),
+		load: func(ctx context.Context, limit int32) ([]publicdb.SearchEntriesRow, error) {
+			return queries.SearchEntries(ctx, publicdb.SearchEntriesParams{Limit: limit, Offset: 0})
+		},
+	}
+}
+
+func tagFeedSource(queries *publicdb.Queries, cfg *internal.Config, tag string) feedSource {
+	return feedSource{
+		prefix: tagPath(tag),
+		title:  cfg.SiteName + " - #" + tag,
+		load: func(ctx context.Context, limit int32) ([]publicdb.SearchEntriesRow, error) {
+			return searchEntriesByTag(ctx, queries, tag, limit, 0)
+		},
+	}
+}
+
+// RenderFeed serves the latest public entries as RSS 2.0.
+func RenderFeed(c *gin.Context, queries *publicdb.Queries, cfg *internal.Config) {
+	renderFeed(c, queries, cfg, rssFeedFormat, siteFeedSource(queries, cfg))
+}
+
+// RenderAtomFeed serves the latest public entries as Atom 1.0.
+func RenderAtomFeed(c *gin.Context, queries *publicdb.Queries, cfg *internal.Config) {
+	renderFeed(c, queries, cfg, atomFeedFormat, siteFeedSource(queries, cfg))
+}
+
+// RenderJSONFeed serves the latest public entries as JSON Feed 1.1.
+func RenderJSONFeed(c *gin.Context, queries *publicdb.Queries, cfg *internal.Config) {
+	renderFeed(c, queries, cfg, jsonFeedFormat, siteFeedSource(queries, cfg))
+}
+
+// RenderTagFeed serves the latest public entries tagged :name as RSS 2.0.
+func RenderTagFeed(c *gin.Context, queries *publicdb.Queries, cfg *internal.Config) {
+	renderFeed(c, queries, cfg, rssFeedFormat, tagFeedSource(queries, cfg, c.Param("name")))
+}
+
+// RenderTagAtomFeed serves the latest public entries tagged :name as Atom 1.0.
+func RenderTagAtomFeed(c *gin.Context, queries *publicdb.Queries, cfg *internal.Config) {
+	renderFeed(c, queries, cfg, atomFeedFormat, tagFeedSource(queries, cfg, c.Param("name")))
+}
+
+// RenderTagJSONFeed serves the latest public entries tagged :name as JSON Feed 1.1.
+func RenderTagJSONFeed(c *gin.Context, queries *publicdb.Queries, cfg *internal.Config) {
+	renderFeed(c, queries, cfg, jsonFeedFormat, tagFeedSource(queries, cfg, c.Param("name")))
+}
+
+func renderFeed(c *gin.Context, queries *publicdb.Queries, cfg *internal.Config, format feedFormat, source feedSource) {
+	entries, err := source.load(c.Request.Context(), int32(cfg.FeedSize))
+	if err != nil {
+		slog.Error("failed to search entries for feed", slog.String("prefix", source.prefix), slog.Any("error", err))
+		c.String(http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	site, err := queries.GetPublicContentVersion(c.Request.Context())
+	if err != nil {
+		slog.Error("failed to get public content version", slog.Any("error", err))
+		c.String(http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	// Check validators before rendering markdown, which is the expensive part.
+	feedPath := source.prefix + format.path
+	etag, lastModified := feedValidators(feedPath, site, entries)
+	if writeNotModified(c, etag, lastModified) {
+		return
+	}
+
+	baseURL := strings.TrimSuffix(cfg.SiteBaseUrl, "/")
+	link := baseURL + "/"
+	if source.prefix != "" {
+		link = baseURL + source.prefix
+	}
+	feed := &feeds.Feed{
+		Title:       source.title,
+		Link:        &feeds.Link{Href: link},
+		Description: cfg.SiteDescription,
+		Author:      &feeds.Author{Name: cfg.SiteAuthorName, Email: cfg.SiteAuthorEmail},
+		Created:     time.Now(),
+		Updated:     lastModified,
+	}
+	md := markdown.NewMarkdown(c.Request.Context(), queries)
+	for _, entry := range entries {
+		render, err := md.RenderEntry(entry.Title, entry.Body)
+		if err != nil {
+			slog.Error("failed to render markdown for feed", slog.String("path", entry.Path), slog.Any("error", err))
+			// skip this entry
+			continue
+		}
+
+		entryURL := baseURL + "/entry/" + entry.Path
+		feed.Items = append(feed.Items, &feeds.Item{
+			Id:          entryURL,
+			IsPermaLink: "true",
+			Title:       entry.Title,
+			Link:        &feeds.Link{Href: entryURL},
+			Description: summarizeEntry(entry.Body, 200),
+			Content:     string(render),
+			Created:     entry.PublishedAt.Time,
+			Updated:     entry.LastEditedAt.Time,
+		})
+	}
+
+	body, err := format.render(feed, baseURL+feedPath, source.hubs)
+	if err != nil {
+		slog.Error("failed to generate feed", slog.String("path", feedPath), slog.Any("error", err))
+		c.String(http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	c.Header("Content-Type", format.contentType)
+	c.String(http.StatusOK, body)
+}
+
+// feedValidators derives the ETag and Last-Modified of a feed from the
+// entries it lists and from site, the version of what else their bodies
+// render: entries linked with [[...]] or embedded with ![[...]], and Amazon
+// products. updated_at moves on every change to an entry, and site moves when
+// any public entry is edited, published, unpublished or deleted, so all of
+// these yield a new ETag.
+func feedValidators(feedPath string, site publicdb.GetPublicContentVersionRow, entries []publicdb.SearchEntriesRow) (string, time.Time) {
+	key := fmt.Sprintf("%s\n%d\t%d\t%d", feedPath,
+		site.PublicEntries, site.EntriesUpdatedAt.UnixNano(), site.AmazonFetchedAt.UnixNano())
+	etag, lastModified := listValidators(key, entries, func(entry publicdb.SearchEntriesRow) (string, time.Time) {
+		return entry.Path, entry.UpdatedAt.Time
+	})
+	for _, t := range []time.Time{site.EntriesUpdatedAt, site.AmazonFetchedAt} {
+		if t.After(lastModified) {
+			lastModified = t
+		}
+	}
+	return etag, lastModified
+}
+
+// listValidators hashes key and the version of every item into an ETag, and
+// returns the newest version time as Last-Modified.
+func listValidators[T any](key string, items []T, version func(T) (string, time.Time)) (string, time.Time) {
+	h := sha256.New()
+	h.Write([]byte(key))
+	var lastModified time.Time
+	for _, item := range items {
+		id, updatedAt := version(item)
+		_, _ = fmt.Fprintf(h, "\n%s\t%d", id, updatedAt.UnixNano())
+		if updatedAt.After(lastModified) {
+			lastModified = updatedAt
+		}
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil))[:32] + `"`, lastModified
+}
+
+// writeNotModified sets ETag and Last-Modified, and answers 304 when the
+// request's conditional headers show the client already has this version.
+func writeNotModified(c *gin.Context, etag string, lastModified time.Time) bool {
+	c.Header("ETag", etag)
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	// If-None-Match takes precedence over If-Modified-Since (RFC 9110 13.2.2).
+	if inm := c.GetHeader("If-None-Match"); inm != "" {
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == etag || candidate == "*" {
+				c.Status(http.StatusNotModified)
+				return true
+			}
+		}
+		return false
+	}
+
+	if ims := c.GetHeader("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		t, err := http.ParseTime(ims)
+		if err == nil && !lastModified.Truncate(time.Second).After(t) {
+			c.Status(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// atomLink is an <atom:link> element, which RSS 2.0 uses to advertise the
+// feed's own URL and its WebSub hubs.
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type rssChannelWithLinks struct {
+	*feeds.RssFeed
+	AtomLinks []atomLink `xml:"atom:link"`
+}
+
+type rssWithLinks struct {
+	XMLName          xml.Name `xml:"rss"`
+	Version          string   `xml:"version,attr"`
+	ContentNamespace string   `xml:"xmlns:content,attr"`
+	AtomNamespace    string   `xml:"xmlns:atom,attr"`
+	Channel          *rssChannelWithLinks
+}
+
+// toRssWithHubs renders feed as RSS 2.0 with rel="self" and rel="hub" links,
+// which gorilla/feeds cannot emit on its own.
+func toRssWithHubs(feed *feeds.Feed, selfURL string, hubs []string) (string, error) {
+	links := []atomLink{{Href: selfURL, Rel: "self", Type: "application/rss+xml"}}
+	for _, hub := range hubs {
+		links = append(links, atomLink{Href: hub, Rel: "hub"})
+	}
+
+	doc := rssWithLinks{
+		Version:          "2.0",
+		ContentNamespace: "http://purl.org/rss/1.0/modules/content/",
+		AtomNamespace:    "http://www.w3.org/2005/Atom",
+		Channel: &rssChannelWithLinks{
+			RssFeed:   (&feeds.Rss{Feed: feed}).RssFeed(),
+			AtomLinks: links,
+		},
+	}
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal RSS: %w", err)
+	}
+	return xml.Header + string(data), nil
+}
+
+type atomFeedWithLinks struct {
+	*feeds.AtomFeed
+	// Links replaces AtomFeed.Link, which can only hold a single link.
+	Links []feeds.AtomLink `xml:"link"`
+}
+
+// toAtomWithHubs renders feed as Atom 1.0 with alternate, self and hub links.
+func toAtomWithHubs(feed *feeds.Feed, selfURL string, hubs []string) (string, error) {
+	atom := (&feeds.Atom{Feed: feed}).AtomFeed()
+	links := []feeds.AtomLink{
+		{Href: feed.Link.Href, Rel: "alternate", Type: "text/html"},
+		{Href: selfURL, Rel: "self", Type: "application/atom+xml"},
+	}
+	for _, hub := range hubs {
+		links = append(links, feeds.AtomLink{Href: hub, Rel: "hub"})
+	}
+	atom.Link = nil
+
+	data, err := xml.MarshalIndent(atomFeedWithLinks{AtomFeed: atom, Links: links}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal Atom: %w", err)
+	}
+	return xml.Header + string(data), nil
+}
+
+// toJSONFeedWithHubs renders feed as JSON Feed 1.1 with feed_url and hubs.
+func toJSONFeedWithHubs(feed *feeds.Feed, selfURL string, hubs []string) (string, error) {
+	jsonFeed := (&feeds.JSON{Feed: feed}).JSONFeed()
+	jsonFeed.FeedUrl = selfURL
+	// author is deprecated in JSON Feed 1.1 in favour of authors.
+	jsonFeed.Author = nil
+	for _, hub := range hubs {
+		jsonFeed.Hubs = append(jsonFeed.Hubs, &feeds.JSONHub{Type: "WebSub", Url: hub})
+	}
+
+	data, err := json.MarshalIndent(jsonFeed, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JSON Feed: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,191 @@
+package public
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/feeds"
+
+	"github.com/tokuhirom/blog4/db/public/publicdb"
+)
+
+func testFeed() *feeds.Feed {
+	return &feeds.Feed{
+		Title:   "blog",
+		Link:    &feeds.Link{Href: "https://blog.example.com/"},
+		Author:  &feeds.Author{Name: "author"},
+		Created: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Updated: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+		Items: []*feeds.Item{{
+			Id:      "https://blog.example.com/entry/a",
+			Title:   "entry",
+			Link:    &feeds.Link{Href: "https://blog.example.com/entry/a"},
+			Content: "<p>hi</p>",
+			Created: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			Updated: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		}},
+	}
+}
+
+func assertContainsAll(t *testing.T, got string, wants ...string) {
+	t.Helper()
+	for _, want := range wants {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestToRssWithHubs(t *testing.T) {
+	rss, err := toRssWithHubs(testFeed(), "https://blog.example.com/feed", []string{"https://hub.example.com/"})
+	if err != nil {
+		t.Fatalf("toRssWithHubs() error = %v", err)
+	}
+
+	assertContainsAll(t, rss,
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`xmlns:atom="http://www.w3.org/2005/Atom"`,
+		`<atom:link href="https://blog.example.com/feed" rel="self" type="application/rss+xml"></atom:link>`,
+		`<atom:link href="https://hub.example.com/" rel="hub"></atom:link>`,
+		`<title>entry</title>`,
+		`<content:encoded><![CDATA[<p>hi</p>]]></content:encoded>`,
+	)
+}
+
+func TestToAtomWithHubs(t *testing.T) {
+	atom, err := toAtomWithHubs(testFeed(), "https://blog.example.com/feed.atom", []string{"https://hub.example.com/"})
+	if err != nil {
+		t.Fatalf("toAtomWithHubs() error = %v", err)
+	}
+
+	assertContainsAll(t, atom,
+		`<feed xmlns="http://www.w3.org/2005/Atom">`,
+		`<link href="https://blog.example.com/" rel="alternate" type="text/html"></link>`,
+		`<link href="https://blog.example.com/feed.atom" rel="self" type="application/atom+xml"></link>`,
+		`<link href="https://hub.example.com/" rel="hub"></link>`,
+		`<id>https://blog.example.com/entry/a</id>`,
+		`<updated>2026-01-02T03:04:05Z</updated>`,
+	)
+	if n := strings.Count(atom, `rel="alternate"`); n != 2 {
+		t.Errorf("expected one alternate link for the feed and one for the entry, got %d:\n%s", n, atom)
+	}
+}
+
+func TestToJSONFeedWithHubs(t *testing.T) {
+	out, err := toJSONFeedWithHubs(testFeed(), "https://blog.example.com/feed.json", []string{"https://hub.example.com/"})
+	if err != nil {
+		t.Fatalf("toJSONFeedWithHubs() error = %v", err)
+	}
+
+	var got feeds.JSONFeed
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if got.Version != "https://jsonfeed.org/version/1.1" {
+		t.Errorf("version = %q", got.Version)
+	}
+	if got.FeedUrl != "https://blog.example.com/feed.json" {
+		t.Errorf("feed_url = %q", got.FeedUrl)
+	}
+	if got.Author != nil || len(got.Authors) != 1 {
+		t.Errorf("expected only authors to be set, got author=%v authors=%v", got.Author, got.Authors)
+	}
+	if len(got.Hubs) != 1 || got.Hubs[0].Type != "WebSub" || got.Hubs[0].Url != "https://hub.example.com/" {
+		t.Errorf("hubs = %v", got.Hubs)
+	}
+	if len(got.Items) != 1 || got.Items[0].ModifiedDate == nil || !got.Items[0].ModifiedDate.Equal(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("items = %v", got.Items)
+	}
+}
+
+func TestFeedValidators(t *testing.T) {
+	entries := []publicdb.SearchEntriesRow{
+		{Path: "a", UpdatedAt: sql.NullTime{Time: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true}},
+		{Path: "b", UpdatedAt: sql.NullTime{Time: time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC), Valid: true}},
+	}
+	site := publicdb.GetPublicContentVersionRow{
+		PublicEntries:    10,
+		EntriesUpdatedAt: time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC),
+		AmazonFetchedAt:  time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+
+	etag, lastModified := feedValidators(rssFeedFormat.path, site, entries)
+	if !lastModified.Equal(time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("lastModified = %v", lastModified)
+	}
+
+	if atomETag, _ := feedValidators(atomFeedFormat.path, site, entries); atomETag == etag {
+		t.Error("formats must not share an ETag")
+	}
+	if tagETag, _ := feedValidators(tagPath("go")+rssFeedFormat.path, site, entries); tagETag == etag {
+		t.Error("tag feeds must not share an ETag with the site feed")
+	}
+
+	entries[0].UpdatedAt.Time = entries[0].UpdatedAt.Time.Add(time.Second)
+	edited, _ := feedValidators(rssFeedFormat.path, site, entries)
+	if edited == etag {
+		t.Error("ETag did not change after an entry was updated")
+	}
+
+	// An entry that is embedded in a listed one, but not listed itself, was
+	// renamed: only the site version moves
+	renamed := site
+	renamed.EntriesUpdatedAt = renamed.EntriesUpdatedAt.Add(time.Hour)
+	renamedETag, renamedLastModified := feedValidators(rssFeedFormat.path, renamed, entries)
+	if renamedETag == edited {
+		t.Error("ETag did not change after a linked entry was updated")
+	}
+	if !renamedLastModified.Equal(renamed.EntriesUpdatedAt) {
+		t.Errorf("lastModified = %v, want %v", renamedLastModified, renamed.EntriesUpdatedAt)
+	}
+
+	deleted := site
+	deleted.PublicEntries--
+	if deletedETag, _ := feedValidators(rssFeedFormat.path, deleted, entries); deletedETag == edited {
+		t.Error("ETag did not change after a linked entry was deleted")
+	}
+}
+
+func TestWriteNotModified(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	lastModified := time.Date(2026, 1, 3, 12, 0, 0, 500, time.UTC)
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    bool
+	}{
+		{name: "unconditional", want: false},
+		{name: "matching etag", headers: map[string]string{"If-None-Match": `"other", W/"abc"`}, want: true},
+		{name: "stale etag wins over date", headers: map[string]string{"If-None-Match": `"other"`, "If-Modified-Since": "Sat, 03 Jan 2026 12:00:00 GMT"}, want: false},
+		{name: "not modified since", headers: map[string]string{"If-Modified-Since": "Sat, 03 Jan 2026 12:00:00 GMT"}, want: true},
+		{name: "modified since", headers: map[string]string{"If-Modified-Since": "Sat, 03 Jan 2026 11:59:59 GMT"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/feed", nil)
+			for k, v := range tt.headers {
+				c.Request.Header.Set(k, v)
+			}
+
+			got := writeNotModified(c, `"abc"`, lastModified)
+			if got != tt.want {
+				t.Errorf("writeNotModified() = %v, want %v", got, tt.want)
+			}
+			if w.Header().Get("ETag") != `"abc"` {
+				t.Errorf("ETag header = %q", w.Header().Get("ETag"))
+			}
+			if w.Header().Get("Last-Modified") != "Sat, 03 Jan 2026 12:00:00 GMT" {
+				t.Errorf("Last-Modified header = %q", w.Header().Get("Last-Modified"))
+			}
+		})
+	}
+}
@@ -2,10 +2,13 @@ package public
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"html/template"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
@@ -13,13 +16,15 @@ import (
 	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
-	"github.com/gorilla/feeds"
 
 	"github.com/tokuhirom/blog4/internal"
 	"github.com/tokuhirom/blog4/internal/utils"
 
 	"github.com/tokuhirom/blog4/db/public/publicdb"
 	"github.com/tokuhirom/blog4/internal/markdown"
+	"github.com/tokuhirom/blog4/internal/preview"
+	"github.com/tokuhirom/blog4/internal/search"
+	"github.com/tokuhirom/blog4/internal/templates"
 )
 
 type TopPageData struct {
@@ -30,6 +35,13 @@ type TopPageData struct {
 	Prev    int
 	Next    int
 	Query   string
+	// Tag is set on /tag/{name}; the pager and feed links then point there.
+	Tag string
+	// Archive is set on /archive/{yyyy} and /archive/{yyyy}/{mm}, e.g. "2026"
+	// or "2026-01". Months then lists the year's months that have entries.
+	Archive  string
+	Months   []ArchiveMonthData
+	PagePath string
 }
 
 type EntryViewData struct {
@@ -46,8 +58,8 @@ func summarizeEntry(body string, length int) string {
 	reURL := regexp.MustCompile(`https?://\S+`)
 	body = reURL.ReplaceAllString(body, "")
 
-	// Replace [[foobar]] with foobar
-	reBrackets := regexp.MustCompile(`\[\[(.*?)]]`)
+	// Replace [[foobar]] with foobar, and [[foobar|label]] with label
+	reBrackets := regexp.MustCompile(`!?\[\[(?:[^\]|]*\|)?(.*?)]]`)
 	body = reBrackets.ReplaceAllString(body, "$1")
 
 	// Trim to the specified length without cutting multibyte characters
@@ -59,11 +71,54 @@ func summarizeEntry(body string, length int) string {
 	return body
 }
 
-func RenderTopPage(c *gin.Context, queries *publicdb.Queries) {
+func RenderTopPage(c *gin.Context, queries *publicdb.Queries, views *templates.Registry) {
+	renderEntryList(c, views, TopPageData{PagePath: "/"}, func(ctx context.Context, limit, offset int32) ([]publicdb.SearchEntriesRow, error) {
+		return queries.SearchEntries(ctx, publicdb.SearchEntriesParams{
+			Limit:  limit,
+			Offset: offset,
+		})
+	})
+}
+
+// RenderTagPage lists the public entries tagged :name, like the top page.
+func RenderTagPage(c *gin.Context, queries *publicdb.Queries, views *templates.Registry) {
+	tag := c.Param("name")
+	renderEntryList(c, views, TopPageData{Tag: tag, PagePath: tagPath(tag)}, func(ctx context.Context, limit, offset int32) ([]publicdb.SearchEntriesRow, error) {
+		return searchEntriesByTag(ctx, queries, tag, limit, offset)
+	})
+}
+
+// tagPath returns the URL path of a tag's page.
+func tagPath(tag string) string {
+	return "/tag/" + url.PathEscape(tag)
+}
+
+// searchEntriesByTag returns the rows in the same shape as SearchEntries, so
+// that pages and feeds handle both alike.
+func searchEntriesByTag(ctx context.Context, queries *publicdb.Queries, tag string, limit, offset int32) ([]publicdb.SearchEntriesRow, error) {
+	rows, err := queries.SearchEntriesByTag(ctx, publicdb.SearchEntriesByTagParams{
+		Tag:    tag,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]publicdb.SearchEntriesRow, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, publicdb.SearchEntriesRow(row))
+	}
+	return entries, nil
+}
+
+// renderEntryList renders one page of entries with index.html. data carries
+// what identifies the list; the page and entries are filled in here. A list
+// other than the top page answers 404 when it has no entries.
+func renderEntryList(c *gin.Context, views *templates.Registry, data TopPageData, load func(ctx context.Context, limit, offset int32) ([]publicdb.SearchEntriesRow, error)) {
 	// Parse and execute the template
-	tmpl, err := template.ParseFiles("public/templates/index.html")
+	tmpl, err := views.Lookup("index.html")
 	if err != nil {
-		slog.Error("failed to parse template", slog.String("template", "index.html"), slog.Any("error", err))
+		slog.Error("failed to load template", slog.String("template", "index.html"), slog.Any("error", err))
 		c.String(http.StatusInternalServerError, "Internal Server Error")
 		return
 	}
@@ -82,15 +137,16 @@ func RenderTopPage(c *gin.Context, queries *publicdb.Queries) {
 	entriesPerPage := 60
 	offset := (page - 1) * entriesPerPage
 
-	entries, err := queries.SearchEntries(c.Request.Context(), publicdb.SearchEntriesParams{
-		Limit:  int32(entriesPerPage + 1),
-		Offset: int32(offset),
-	})
+	entries, err := load(c.Request.Context(), int32(entriesPerPage+1), int32(offset))
 	if err != nil {
-		slog.Error("failed to search entries", slog.Int("page", page), slog.Any("error", err))
+		slog.Error("failed to search entries", slog.Int("page", page), slog.String("path", data.PagePath), slog.Any("error", err))
 		c.String(http.StatusInternalServerError, "Internal Server Error")
 		return
 	}
+	if data.PagePath != "/" && len(entries) == 0 {
+		c.String(http.StatusNotFound, "Not Found")
+		return
+	}
 
 	// remove last entry if there are more entries
 	var hasNext = false
@@ -100,44 +156,95 @@ func RenderTopPage(c *gin.Context, queries *publicdb.Queries) {
 	}
 
 	// Prepare data for the template
+	viewData, err := toEntryViewData(entries)
+	if err != nil {
+		slog.Error("failed to prepare entries", slog.Any("error", err))
+		c.String(http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	data.Page = page
+	data.Prev = page - 1
+	data.Next = page + 1
+	data.HasPrev = page > 1
+	data.HasNext = hasNext
+	data.Entries = viewData
+	c.Status(http.StatusOK)
+	err = tmpl.Execute(c.Writer, data)
+	if err != nil {
+		slog.Error("failed to execute template", slog.String("template", "index.html"), slog.Int("page", page), slog.Any("error", err))
+		c.String(http.StatusInternalServerError, "Internal Server Error")
+	}
+}
+
+// toEntryViewData formats entries for the entry cards of index.html.
+func toEntryViewData(entries []publicdb.SearchEntriesRow) ([]EntryViewData, error) {
 	var viewData []EntryViewData
 	for _, entry := range entries {
 		// Format the PublishedAt date
-		var formattedDate string
-		if entry.PublishedAt.Valid {
-			formattedDate = entry.PublishedAt.Time.Format("2006-01-02(Mon)")
-		} else {
-			slog.Error("published_at is invalid", slog.String("path", entry.Path), slog.Any("published_at", entry.PublishedAt))
-			c.String(http.StatusInternalServerError, "Internal Server Error")
-			return
+		if !entry.PublishedAt.Valid {
+			return nil, fmt.Errorf("published_at of %s is invalid", entry.Path)
 		}
 
 		viewData = append(viewData, EntryViewData{
 			Path:        entry.Path,
 			Title:       entry.Title,
-			PublishedAt: formattedDate,
+			PublishedAt: entry.PublishedAt.Time.Format("2006-01-02(Mon)"),
 			TextPreview: summarizeEntry(entry.Body, 100),
 			ImageUrl:    entry.ImageUrl.String,
 		})
 	}
+	return viewData, nil
+}
 
-	c.Status(http.StatusOK)
-	err = tmpl.Execute(c.Writer, TopPageData{
-		Page:    page,
-		Prev:    page - 1,
-		Next:    page + 1,
-		HasPrev: page > 1,
-		HasNext: hasNext,
-		Entries: viewData,
-		Query:   "",
-	})
+// TagIndexData is the data for the /tags page.
+type TagIndexData struct {
+	Tags []TagViewData
+}
+
+// TagViewData is one tag on the /tags page.
+type TagViewData struct {
+	Name       string
+	Path       string
+	EntryCount int64
+}
+
+// RenderTagIndexPage lists every tag of public entries, most used first.
+func RenderTagIndexPage(c *gin.Context, queries *publicdb.Queries, views *templates.Registry) {
+	tmpl, err := views.Lookup("tags.html")
 	if err != nil {
-		slog.Error("failed to execute template", slog.String("template", "index.html"), slog.Int("page", page), slog.Any("error", err))
+		slog.Error("failed to load template", slog.String("template", "tags.html"), slog.Any("error", err))
+		c.String(http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	rows, err := queries.ListPublicTags(c.Request.Context())
+	if err != nil {
+		slog.Error("failed to list tags", slog.Any("error", err))
+		c.String(http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	data := TagIndexData{Tags: make([]TagViewData, 0, len(rows))}
+	for _, row := range rows {
+		data.Tags = append(data.Tags, TagViewData{
+			Name:       row.Tag,
+			Path:       tagPath(row.Tag),
+			EntryCount: row.EntryCount,
+		})
+	}
+
+	c.Status(http.StatusOK)
+	if err := tmpl.Execute(c.Writer, data); err != nil {
+		slog.Error("failed to execute template", slog.String("template", "tags.html"), slog.Any("error", err))
 		c.String(http.StatusInternalServerError, "Internal Server Error")
 	}
 }
 
-func RenderEntryPage(c *gin.Context, queries *publicdb.Queries, cfg *internal.Config) {
+// RenderEntryPage renders a public entry. With a ?preview= token minted in the
+// admin, it renders the entry the token was minted for whatever its
+// visibility, marked as a draft and kept out of search engines and caches.
+func RenderEntryPage(c *gin.Context, queries *publicdb.Queries, cfg *internal.Config, previews *preview.Signer, views *templates.Registry) {
 	extractedPath := c.Param("filepath")
 	// Strip leading slash from wildcard parameter
 	extractedPath = strings.TrimPrefix(extractedPath, "/")
@@ -145,26 +252,51 @@ func RenderEntryPage(c *gin.Context, queries *publicdb.Queries, cfg *internal.Co
 	md := markdown.NewMarkdown(c.Request.Context(), queries)
 
 	slog.Info("rendering entry page", slog.String("path", extractedPath))
-	entryRow, err := queries.GetEntryByPath(c.Request.Context(), extractedPath)
-	if err != nil {
-		slog.Error("failed to get entry by path", slog.String("path", extractedPath), slog.Any("error", err))
-		c.Status(http.StatusNotFound)
-		return
+	var entryRow publicdb.GetEntryByPathRow
+	var err error
+	isPreview := false
+	if token := c.Query(preview.QueryParam); token != "" {
+		// The token in the URL must not reach search engines, WebAccel's cache
+		// or the sites the draft links to
+		c.Header("X-Robots-Tag", "noindex, nofollow")
+		c.Header("Cache-Control", "private, no-store")
+		c.Header("Referrer-Policy", "no-referrer")
+
+		entryRow, err = getPreviewEntry(c.Request.Context(), queries, previews, extractedPath, token)
+		if err != nil {
+			slog.Info("rejected preview token", slog.String("path", extractedPath), slog.Any("error", err))
+		}
+		isPreview = err == nil && entryRow.Visibility != publicdb.EntryVisibilityPublic
+		if !isPreview {
+			// Once the entry is out, old preview links lead to the public page
+			if _, err := queries.GetEntryByPath(c.Request.Context(), extractedPath); err == nil {
+				c.Redirect(http.StatusFound, "/entry/"+extractedPath)
+				return
+			}
+			c.Status(http.StatusNotFound)
+			return
+		}
+	} else {
+		entryRow, err = queries.GetEntryByPath(c.Request.Context(), extractedPath)
+		if err != nil {
+			slog.Error("failed to get entry by path", slog.String("path", extractedPath), slog.Any("error", err))
+			c.Status(http.StatusNotFound)
+			return
+		}
 	}
-
 	// Data to pass to the template
 	var formattedDate string
 	var publishedAtISO string
 	if entryRow.PublishedAt.Valid {
 		formattedDate = utils.FormatDateTime(entryRow.PublishedAt.Time)
 		publishedAtISO = entryRow.PublishedAt.Time.Format(time.RFC3339)
-	} else {
+	} else if !isPreview {
 		slog.Error("published_at is invalid", slog.String("path", entryRow.Path), slog.Any("published_at", entryRow.PublishedAt))
 		c.String(http.StatusInternalServerError, "Internal Server Error")
 		return
 	}
 
-	body, err := md.Render(entryRow.Body)
+	body, err := md.RenderEntry(entryRow.Title, entryRow.Body)
 	if err != nil {
 		slog.Error("failed to render markdown", slog.String("path", entryRow.Path), slog.Any("error", err))
 		c.String(http.StatusInternalServerError, "Internal Server Error")
@@ -176,6 +308,31 @@ func RenderEntryPage(c *gin.Context, queries *publicdb.Queries, cfg *internal.Co
 		slog.Error("failed to get related entries", slog.String("path", entryRow.Path), slog.Any("error", err))
 	}
 
+	// A draft has no place in the timeline yet
+	var prev, next *AdjacentEntryData
+	if !isPreview {
+		prev, next, err = getAdjacentEntries(c.Request.Context(), queries, entryRow.Path, entryRow.PublishedAt)
+		if err != nil {
+			slog.Error("failed to get adjacent entries", slog.String("path", entryRow.Path), slog.Any("error", err))
+		}
+	}
+	baseURL := strings.TrimSuffix(cfg.SiteBaseUrl, "/")
+	if prev != nil {
+		c.Writer.Header().Add("Link", fmt.Sprintf(`<%s/entry/%s>; rel="prev"`, baseURL, prev.Path))
+	}
+	if next != nil {
+		c.Writer.Header().Add("Link", fmt.Sprintf(`<%s/entry/%s>; rel="next"`, baseURL, next.Path))
+	}
+
+	tags, err := queries.GetPublicEntryTags(c.Request.Context(), entryRow.Path)
+	if err != nil {
+		slog.Error("failed to get entry tags", slog.String("path", entryRow.Path), slog.Any("error", err))
+	}
+	tagViews := make([]TagViewData, 0, len(tags))
+	for _, tag := range tags {
+		tagViews = append(tagViews, TagViewData{Name: tag, Path: tagPath(tag)})
+	}
+
 	// Prepare OGP image URL
 	var imageUrl string
 	if entryRow.ImageUrl.Valid {
@@ -188,28 +345,38 @@ func RenderEntryPage(c *gin.Context, queries *publicdb.Queries, cfg *internal.Co
 		PublishedAt       string
 		HasRelatedEntries bool
 		RelatedEntries    []publicdb.Entry
+		Tags              []TagViewData
+		Prev              *AdjacentEntryData
+		Next              *AdjacentEntryData
 		Path              string
 		Description       string
 		ImageUrl          string
 		SiteBaseUrl       string
 		PublishedAtISO    string
+		Preview           bool
+		Scheduled         bool
 	}{
 		Title:             entryRow.Title,
 		Body:              body,
 		PublishedAt:       formattedDate,
 		HasRelatedEntries: len(relatedEntries) > 0,
 		RelatedEntries:    relatedEntries,
+		Tags:              tagViews,
+		Prev:              prev,
+		Next:              next,
 		Path:              entryRow.Path,
 		Description:       summarizeEntry(entryRow.Body, 200),
 		ImageUrl:          imageUrl,
 		SiteBaseUrl:       cfg.SiteBaseUrl,
 		PublishedAtISO:    publishedAtISO,
+		Preview:           isPreview,
+		Scheduled:         entryRow.Visibility == publicdb.EntryVisibilityScheduled,
 	}
 
 	// Parse and execute the template
-	tmpl, err := template.ParseFiles("public/templates/entry.html")
+	tmpl, err := views.Lookup("entry.html")
 	if err != nil {
-		slog.Error("failed to parse template", slog.String("template", "entry.html"), slog.String("path", extractedPath), slog.Any("error", err))
+		slog.Error("failed to load template", slog.String("template", "entry.html"), slog.String("path", extractedPath), slog.Any("error", err))
 		c.String(http.StatusInternalServerError, "Internal Server Error")
 		return
 	}
@@ -221,6 +388,54 @@ func RenderEntryPage(c *gin.Context, queries *publicdb.Queries, cfg *internal.Co
 	}
 }
 
+// getPreviewEntry returns the entry at path for a preview link token. The
+// token must be signed for path, not expired, and its preview_token row must
+// not have been deleted.
+func getPreviewEntry(ctx context.Context, queries *publicdb.Queries, previews *preview.Signer, path string, token string) (publicdb.GetEntryByPathRow, error) {
+	if previews == nil {
+		return publicdb.GetEntryByPathRow{}, errors.New("preview links are disabled")
+	}
+	id, err := previews.Verify(token, path)
+	if err != nil {
+		return publicdb.GetEntryByPathRow{}, err
+	}
+	row, err := queries.GetPreviewEntry(ctx, publicdb.GetPreviewEntryParams{ID: id, Path: path})
+	if err != nil {
+		return publicdb.GetEntryByPathRow{}, fmt.Errorf("failed to get preview entry: %w", err)
+	}
+	return publicdb.GetEntryByPathRow(row), nil
+}
+
+// AdjacentEntryData is the older or newer public entry next to the one shown.
+type AdjacentEntryData struct {
+	Path  string
+	Title string
+}
+
+// getAdjacentEntries returns the public entries published just before and
+// just after publishedAt, ordering entries published at the same time by
+// path. Either is nil at the end of the timeline.
+func getAdjacentEntries(ctx context.Context, queries *publicdb.Queries, path string, publishedAt sql.NullTime) (*AdjacentEntryData, *AdjacentEntryData, error) {
+	prevRow, err := queries.GetPrevPublicEntry(ctx, publicdb.GetPrevPublicEntryParams{PublishedAt: publishedAt, Path: path})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, fmt.Errorf("failed to get previous entry of %s: %w", path, err)
+	}
+	var prev *AdjacentEntryData
+	if err == nil {
+		prev = &AdjacentEntryData{Path: prevRow.Path, Title: prevRow.Title}
+	}
+
+	nextRow, err := queries.GetNextPublicEntry(ctx, publicdb.GetNextPublicEntryParams{PublishedAt: publishedAt, Path: path})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, fmt.Errorf("failed to get next entry of %s: %w", path, err)
+	}
+	var next *AdjacentEntryData
+	if err == nil {
+		next = &AdjacentEntryData{Path: nextRow.Path, Title: nextRow.Title}
+	}
+	return prev, next, nil
+}
+
 func getRelatedEntries(context context.Context, queries *publicdb.Queries, path string, title string) ([]publicdb.Entry, error) {
 	// 現在表示しているエントリがリンクしているページ
 	entries1, err := queries.GetRelatedEntries1(context, path)
@@ -268,63 +483,15 @@ func getRelatedEntries(context context.Context, queries *publicdb.Queries, path
 	return uniqueEntries, nil
 }
 
-func RenderFeed(c *gin.Context, queries *publicdb.Queries) {
-	entries, err := queries.SearchEntries(c.Request.Context(), publicdb.SearchEntriesParams{
-		Limit:  10,
-		Offset: 0,
-	})
+func RenderSearchPage(c *gin.Context, views *templates.Registry) {
+	tmpl, err := views.Lookup("search.html")
 	if err != nil {
-		slog.Error("failed to search entries for feed", slog.Any("error", err))
+		slog.Error("failed to load template", slog.String("template", "search.html"), slog.Any("error", err))
 		c.String(http.StatusInternalServerError, "Internal Server Error")
 		return
 	}
 
-	now := time.Now()
-	feed := &feeds.Feed{
-		Title:       "tokuhirom's blog",
-		Link:        &feeds.Link{Href: "https://blog.64p.org/"},
-		Description: "tokuhirom's thoughts",
-		Author:      &feeds.Author{Name: "Tokuhiro Matsuno", Email: "tokuhirom+blog-gmail.com"},
-		Created:     now,
-	}
-	md := markdown.NewMarkdown(c.Request.Context(), queries)
-	for _, entry := range entries {
-		render, err := md.Render(entry.Body)
-		if err != nil {
-			slog.Error("failed to render markdown for feed", slog.String("path", entry.Path), slog.Any("error", err))
-			// skip this entry
-			continue
-		}
-
-		feed.Items = append(feed.Items, &feeds.Item{
-			Title:       entry.Title,
-			Link:        &feeds.Link{Href: "https://blog.64p.org/entry/" + entry.Path},
-			Description: entry.Body,
-			Content:     string(render),
-			Created:     entry.PublishedAt.Time,
-		})
-	}
-
-	rss, err := feed.ToRss()
-	if err != nil {
-		slog.Error("failed to generate RSS", slog.Any("error", err))
-		c.String(http.StatusInternalServerError, "Internal Server Error")
-		return
-	}
-
-	c.Header("Content-Type", "application/rss+xml; charset=utf-8")
-	c.String(http.StatusOK, rss)
-}
-
-func RenderSearchPage(c *gin.Context) {
-	tmpl, err := template.ParseFiles("public/templates/search.html")
-	if err != nil {
-		slog.Error("failed to parse template", slog.String("template", "search.html"), slog.Any("error", err))
-		c.String(http.StatusInternalServerError, "Internal Server Error")
-		return
-	}
-
-	// 検索はクライアント側 (search.js) が /search-index.json を使って行う。
+	// 検索結果は search.js が /api/search から取得して描画する。
 	// サーバはクエリ初期値を渡す静的シェルを返すだけ。
 	c.Status(http.StatusOK)
 	if err := tmpl.Execute(c.Writer, struct{ Query string }{Query: c.Query("q")}); err != nil {
@@ -333,38 +500,76 @@ func RenderSearchPage(c *gin.Context) {
 	}
 }
 
-// SearchIndexEntry は /search-index.json で配信する 1 エントリ分のデータ。
-type SearchIndexEntry struct {
+const (
+	searchPerPage    = 20
+	maxSearchPerPage = 50
+)
+
+// SearchResult は /api/search が返す 1 エントリ分のデータ。
+// Snippet はエスケープ済みの HTML で、ヒット箇所が <mark> で囲まれている。
+type SearchResult struct {
 	Path        string `json:"path"`
 	Title       string `json:"title"`
-	Body        string `json:"body"`
+	Snippet     string `json:"snippet"`
 	PublishedAt string `json:"published_at"`
 	ImageURL    string `json:"image_url"`
 }
 
-// RenderSearchIndex は公開エントリ全件を JSON で返す。クライアント側の全文検索が使う。
-func RenderSearchIndex(c *gin.Context, queries *publicdb.Queries) {
-	entries, err := queries.ListAllPublicEntries(c.Request.Context())
+// SearchResponse は /api/search のレスポンス。
+type SearchResponse struct {
+	Query   string `json:"query"`
+	Page    int    `json:"page"`
+	PerPage int    `json:"per_page"`
+	Total   int    `json:"total"`
+	HasNext bool   `json:"has_next"`
+	// Truncated は候補が多すぎて新しいエントリだけを順位付けしたことを示す。
+	// このとき Total は下限になる。
+	Truncated bool           `json:"truncated"`
+	Results   []SearchResult `json:"results"`
+}
+
+// RenderSearchAPI は公開エントリを全文検索して JSON で返す。
+func RenderSearchAPI(c *gin.Context, queries *publicdb.Queries) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid page"})
+		return
+	}
+	perPage, err := strconv.Atoi(c.DefaultQuery("per_page", strconv.Itoa(searchPerPage)))
+	if err != nil || perPage < 1 || perPage > maxSearchPerPage {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid per_page"})
+		return
+	}
+
+	q := strings.TrimSpace(c.Query("q"))
+	result, err := search.Search(c.Request.Context(), queries, q, page, perPage)
 	if err != nil {
-		slog.Error("failed to list public entries", slog.Any("error", err))
-		c.String(http.StatusInternalServerError, "Internal Server Error")
+		slog.Error("failed to search entries", slog.String("q", q), slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
 		return
 	}
 
-	index := make([]SearchIndexEntry, 0, len(entries))
-	for _, entry := range entries {
+	resp := SearchResponse{
+		Query:     q,
+		Page:      page,
+		PerPage:   perPage,
+		Total:     result.Total,
+		HasNext:   result.HasNext,
+		Truncated: result.Truncated,
+		Results:   make([]SearchResult, 0, len(result.Results)),
+	}
+	for _, r := range result.Results {
 		var publishedAt string
-		if entry.PublishedAt.Valid {
-			publishedAt = entry.PublishedAt.Time.Format("2006-01-02(Mon)")
+		if !r.PublishedAt.IsZero() {
+			publishedAt = r.PublishedAt.Format("2006-01-02(Mon)")
 		}
-		index = append(index, SearchIndexEntry{
-			Path:        entry.Path,
-			Title:       entry.Title,
-			Body:        entry.Body,
+		resp.Results = append(resp.Results, SearchResult{
+			Path:        r.Path,
+			Title:       r.Title,
+			Snippet:     r.Snippet,
 			PublishedAt: publishedAt,
-			ImageURL:    entry.ImageUrl.String,
+			ImageURL:    r.ImageURL,
 		})
 	}
-
-	c.JSON(http.StatusOK, index)
+	c.JSON(http.StatusOK, resp)
 }
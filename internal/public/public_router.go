@@ -1,35 +1,95 @@
 package public
 
 import (
+	"fmt"
+	"io/fs"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/tokuhirom/blog4/db/public/publicdb"
 	"github.com/tokuhirom/blog4/internal"
+	"github.com/tokuhirom/blog4/internal/templates"
 )
 
-func SetupPublicRoutes(r *gin.Engine, queries *publicdb.Queries, cfg *internal.Config) {
+// NewTemplates parses the public pages' templates from assets, the tree
+// returned by public.FS.
+func NewTemplates(assets fs.FS, reload bool) (*templates.Registry, error) {
+	return templates.New(assets, map[string][]string{
+		"index.html":   {"templates/index.html"},
+		"entry.html":   {"templates/entry.html"},
+		"tags.html":    {"templates/tags.html"},
+		"archive.html": {"templates/archive.html"},
+		"search.html":  {"templates/search.html"},
+	}, reload)
+}
+
+// SetupPublicRoutes registers the public site. assets is the tree returned by
+// public.FS; its templates are reloaded on change in local development.
+func SetupPublicRoutes(r *gin.Engine, queries *publicdb.Queries, cfg *internal.Config, assets fs.FS) error {
+	views, err := NewTemplates(assets, cfg.LocalDev)
+	if err != nil {
+		return fmt.Errorf("failed to load public templates: %w", err)
+	}
+	previews := cfg.PreviewSigner()
+
 	r.GET("/", func(c *gin.Context) {
-		RenderTopPage(c, queries)
+		RenderTopPage(c, queries, views)
 	})
 	r.GET("/feed", func(c *gin.Context) {
-		RenderFeed(c, queries)
+		RenderFeed(c, queries, cfg)
+	})
+	r.GET("/feed.atom", func(c *gin.Context) {
+		RenderAtomFeed(c, queries, cfg)
+	})
+	r.GET("/feed.json", func(c *gin.Context) {
+		RenderJSONFeed(c, queries, cfg)
 	})
 	// index.rss was the feed path in the old system; redirect readers to the new one.
 	r.GET("/index.rss", func(c *gin.Context) {
 		c.Redirect(http.StatusMovedPermanently, "/feed")
 	})
+	r.GET("/sitemap.xml", func(c *gin.Context) {
+		RenderSitemap(c, queries, cfg)
+	})
+	r.GET("/sitemap/:page", func(c *gin.Context) {
+		RenderSitemapPage(c, queries, cfg)
+	})
+	r.GET("/archive", func(c *gin.Context) {
+		RenderArchiveIndexPage(c, queries, cfg, views)
+	})
+	r.GET("/archive/:year", func(c *gin.Context) {
+		RenderYearArchivePage(c, queries, cfg, views)
+	})
+	r.GET("/archive/:year/:month", func(c *gin.Context) {
+		RenderMonthArchivePage(c, queries, cfg, views)
+	})
+	r.GET("/tags", func(c *gin.Context) {
+		RenderTagIndexPage(c, queries, views)
+	})
+	r.GET("/tag/:name", func(c *gin.Context) {
+		RenderTagPage(c, queries, views)
+	})
+	r.GET("/tag/:name/feed", func(c *gin.Context) {
+		RenderTagFeed(c, queries, cfg)
+	})
+	r.GET("/tag/:name/feed.atom", func(c *gin.Context) {
+		RenderTagAtomFeed(c, queries, cfg)
+	})
+	r.GET("/tag/:name/feed.json", func(c *gin.Context) {
+		RenderTagJSONFeed(c, queries, cfg)
+	})
 	r.GET("/entry/*filepath", func(c *gin.Context) {
-		RenderEntryPage(c, queries, cfg)
+		RenderEntryPage(c, queries, cfg, previews, views)
 	})
 	r.GET("/search", func(c *gin.Context) {
-		RenderSearchPage(c)
+		RenderSearchPage(c, views)
 	})
-	r.GET("/search-index.json", func(c *gin.Context) {
-		RenderSearchIndex(c, queries)
+	r.GET("/api/search", func(c *gin.Context) {
+		RenderSearchAPI(c, queries)
 	})
-	r.StaticFile("/static/main.css", "public/static/main.css")
-	r.StaticFile("/static/search.js", "public/static/search.js")
+	r.StaticFileFS("/static/main.css", "static/main.css", http.FS(assets))
+	r.StaticFileFS("/static/search.js", "static/search.js", http.FS(assets))
 	r.StaticFile("/build-info.json", "build-info.json")
+	return nil
 }
@@ -106,3 +106,103 @@ func TestGinEntryRouteMatching(t *testing.T) {
 		})
 	}
 }
+
+// TestTagPathRoundTrip checks that a tag survives tagPath and gin's /tag/:name
+// routing, including the feed routes below it.
+func TestTagPathRoundTrip(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var got string
+	r := gin.New()
+	r.GET("/tag/:name", func(c *gin.Context) {
+		got = c.Param("name")
+	})
+	r.GET("/tag/:name/feed", func(c *gin.Context) {
+		got = c.Param("name") + " feed"
+	})
+
+	for _, tag := range []string{"Go", "日本語", "C#", "what?", "100%"} {
+		got = ""
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", tagPath(tag), nil))
+		if got != tag {
+			t.Errorf("tagPath(%q): routed as %q", tag, got)
+		}
+
+		got = ""
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", tagPath(tag)+"/feed", nil))
+		if got != tag+" feed" {
+			t.Errorf("tagPath(%q)/feed: routed as %q", tag, got)
+		}
+	}
+}
+
+// TestEntryTemplateAdjacentLinks checks the <link rel> elements and the pager
+// for the older and newer entries.
+func TestEntryTemplateAdjacentLinks(t *testing.T) {
+	tmpl, err := newTestTemplates(t).Lookup("entry.html")
+	if err != nil {
+		t.Fatalf("failed to load entry.html: %v", err)
+	}
+
+	var out strings.Builder
+	err = tmpl.Execute(&out, map[string]any{
+		"Title":       "current",
+		"SiteBaseUrl": "https://blog.example.com",
+		"Path":        "2026/01/02/120000",
+		"Prev":        &AdjacentEntryData{Path: "2026/01/01/120000", Title: "older"},
+	})
+	if err != nil {
+		t.Fatalf("failed to execute entry.html: %v", err)
+	}
+
+	html := out.String()
+	for _, want := range []string{
+		`<link rel="prev" href="https://blog.example.com/entry/2026/01/01/120000">`,
+		`<a href="/entry/2026/01/01/120000" rel="prev">&larr; older</a>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("entry.html does not contain %q", want)
+		}
+	}
+	if strings.Contains(html, `rel="next"`) {
+		t.Error("entry.html links to a next entry that does not exist")
+	}
+}
+
+// TestEntryTemplateDraftPreview checks that a preview link renders the draft
+// banner and stays out of search engines, analytics and share buttons.
+func TestEntryTemplateDraftPreview(t *testing.T) {
+	tmpl, err := newTestTemplates(t).Lookup("entry.html")
+	if err != nil {
+		t.Fatalf("failed to load entry.html: %v", err)
+	}
+
+	render := func(data map[string]any) string {
+		var out strings.Builder
+		if err := tmpl.Execute(&out, data); err != nil {
+			t.Fatalf("failed to execute entry.html: %v", err)
+		}
+		return out.String()
+	}
+
+	html := render(map[string]any{"Title": "draft", "Path": "draft", "Preview": true, "Scheduled": true, "PublishedAt": "2026-02-01 09:00"})
+	for _, want := range []string{
+		`<meta name="robots" content="noindex, nofollow">`,
+		`class="draft-banner"`,
+		`Scheduled: 2026-02-01 09:00`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("preview does not contain %q", want)
+		}
+	}
+	for _, unwanted := range []string{"googletagmanager", `class="share-buttons"`, "Published:"} {
+		if strings.Contains(html, unwanted) {
+			t.Errorf("preview contains %q", unwanted)
+		}
+	}
+
+	html = render(map[string]any{"Title": "public", "Path": "public", "PublishedAt": "2026-01-01 09:00"})
+	if strings.Contains(html, "draft-banner") || strings.Contains(html, "noindex") {
+		t.Error("public entry is rendered as a draft")
+	}
+}
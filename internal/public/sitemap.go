@@ -0,0 +1,184 @@
+package public
+
+import (
+	"encoding/xml"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/tokuhirom/blog4/db/public/publicdb"
+	"github.com/tokuhirom/blog4/internal"
+)
+
+const (
+	// maxSitemapURLs is the protocol's limit on URLs per sitemap file. Beyond
+	// it /sitemap.xml becomes a sitemap index of /sitemap/{n}.xml files.
+	maxSitemapURLs = 50000
+
+	sitemapNamespace      = "http://www.sitemaps.org/schemas/sitemap/0.9"
+	imageSitemapNamespace = "http://www.google.com/schemas/sitemap-image/1.1"
+
+	// Crawlers fetch sitemaps rarely; an hour keeps proxies from hammering
+	// the query while new entries still show up the same day.
+	sitemapCacheControl = "public, max-age=3600"
+)
+
+type sitemapURLSet struct {
+	XMLName        xml.Name     `xml:"urlset"`
+	Namespace      string       `xml:"xmlns,attr"`
+	ImageNamespace string       `xml:"xmlns:image,attr"`
+	URLs           []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string         `xml:"loc"`
+	LastMod string         `xml:"lastmod,omitempty"`
+	Images  []sitemapImage `xml:"image:image"`
+}
+
+type sitemapImage struct {
+	Loc string `xml:"image:loc"`
+}
+
+type sitemapIndex struct {
+	XMLName   xml.Name     `xml:"sitemapindex"`
+	Namespace string       `xml:"xmlns,attr"`
+	Sitemaps  []sitemapRef `xml:"sitemap"`
+}
+
+type sitemapRef struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// RenderSitemap serves /sitemap.xml: every public entry, or a sitemap index
+// once there are more than maxSitemapURLs of them.
+func RenderSitemap(c *gin.Context, queries *publicdb.Queries, cfg *internal.Config) {
+	entries, err := queries.ListAllPublicEntries(c.Request.Context())
+	if err != nil {
+		slog.Error("failed to list entries for sitemap", slog.Any("error", err))
+		c.String(http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	baseURL := strings.TrimSuffix(cfg.SiteBaseUrl, "/")
+	chunks := sitemapChunks(entries, maxSitemapURLs)
+	if len(chunks) <= 1 {
+		writeSitemap(c, "/sitemap.xml", entries, buildURLSet(baseURL, entries))
+		return
+	}
+	writeSitemap(c, "/sitemap.xml", entries, buildSitemapIndex(baseURL, chunks))
+}
+
+// RenderSitemapPage serves /sitemap/{n}.xml, the n-th file of the sitemap
+// index counting from 1.
+func RenderSitemapPage(c *gin.Context, queries *publicdb.Queries, cfg *internal.Config) {
+	page, ok := strings.CutSuffix(c.Param("page"), ".xml")
+	n, err := strconv.Atoi(page)
+	if !ok || err != nil || n < 1 {
+		c.String(http.StatusNotFound, "Not Found")
+		return
+	}
+
+	entries, err := queries.ListAllPublicEntries(c.Request.Context())
+	if err != nil {
+		slog.Error("failed to list entries for sitemap", slog.Int("page", n), slog.Any("error", err))
+		c.String(http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	chunks := sitemapChunks(entries, maxSitemapURLs)
+	if n > len(chunks) {
+		c.String(http.StatusNotFound, "Not Found")
+		return
+	}
+	chunk := chunks[n-1]
+	baseURL := strings.TrimSuffix(cfg.SiteBaseUrl, "/")
+	writeSitemap(c, sitemapPagePath(n), chunk, buildURLSet(baseURL, chunk))
+}
+
+// writeSitemap answers conditional requests from the validators of the
+// entries doc was built from, and otherwise serves doc.
+func writeSitemap(c *gin.Context, path string, entries []publicdb.ListAllPublicEntriesRow, doc any) {
+	c.Header("Cache-Control", sitemapCacheControl)
+	etag, lastModified := sitemapValidators(path, entries)
+	if writeNotModified(c, etag, lastModified) {
+		return
+	}
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		slog.Error("failed to marshal sitemap", slog.String("path", path), slog.Any("error", err))
+		c.String(http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", append([]byte(xml.Header), data...))
+}
+
+// sitemapValidators is feedValidators for sitemaps. The image URL is part of
+// the version because regenerating an entry's image does not touch the entry.
+func sitemapValidators(path string, entries []publicdb.ListAllPublicEntriesRow) (string, time.Time) {
+	return listValidators(path, entries, func(entry publicdb.ListAllPublicEntriesRow) (string, time.Time) {
+		return entry.Path + "\t" + entry.ImageUrl.String, entry.UpdatedAt.Time
+	})
+}
+
+// sitemapChunks splits entries into sitemap files of at most size URLs. The
+// entries are listed oldest first, so publishing only ever changes the last
+// file.
+func sitemapChunks(entries []publicdb.ListAllPublicEntriesRow, size int) [][]publicdb.ListAllPublicEntriesRow {
+	var chunks [][]publicdb.ListAllPublicEntriesRow
+	for start := 0; start < len(entries); start += size {
+		chunks = append(chunks, entries[start:min(start+size, len(entries))])
+	}
+	return chunks
+}
+
+func sitemapPagePath(n int) string {
+	return fmt.Sprintf("/sitemap/%d.xml", n)
+}
+
+func buildURLSet(baseURL string, entries []publicdb.ListAllPublicEntriesRow) sitemapURLSet {
+	urls := make([]sitemapURL, 0, len(entries))
+	for _, entry := range entries {
+		u := sitemapURL{Loc: baseURL + "/entry/" + entry.Path}
+		if entry.LastEditedAt.Valid {
+			u.LastMod = entry.LastEditedAt.Time.UTC().Format(time.RFC3339)
+		}
+		if entry.ImageUrl.Valid && entry.ImageUrl.String != "" {
+			u.Images = []sitemapImage{{Loc: entry.ImageUrl.String}}
+		}
+		urls = append(urls, u)
+	}
+	return sitemapURLSet{
+		Namespace:      sitemapNamespace,
+		ImageNamespace: imageSitemapNamespace,
+		URLs:           urls,
+	}
+}
+
+func buildSitemapIndex(baseURL string, chunks [][]publicdb.ListAllPublicEntriesRow) sitemapIndex {
+	refs := make([]sitemapRef, 0, len(chunks))
+	for i, chunk := range chunks {
+		ref := sitemapRef{Loc: baseURL + sitemapPagePath(i+1)}
+		var lastMod time.Time
+		for _, entry := range chunk {
+			if entry.LastEditedAt.Valid && entry.LastEditedAt.Time.After(lastMod) {
+				lastMod = entry.LastEditedAt.Time
+			}
+		}
+		if !lastMod.IsZero() {
+			ref.LastMod = lastMod.UTC().Format(time.RFC3339)
+		}
+		refs = append(refs, ref)
+	}
+	return sitemapIndex{
+		Namespace: sitemapNamespace,
+		Sitemaps:  refs,
+	}
+}
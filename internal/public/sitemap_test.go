@@ -0,0 +1,93 @@
+package public
+
+import (
+	"database/sql"
+	"encoding/xml"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/tokuhirom/blog4/db/public/publicdb"
+)
+
+func sitemapEntry(path string, lastEdited time.Time, imageURL string) publicdb.ListAllPublicEntriesRow {
+	return publicdb.ListAllPublicEntriesRow{
+		Path:         path,
+		LastEditedAt: sql.NullTime{Time: lastEdited, Valid: true},
+		UpdatedAt:    sql.NullTime{Time: lastEdited, Valid: true},
+		ImageUrl:     sql.NullString{String: imageURL, Valid: imageURL != ""},
+	}
+}
+
+func TestBuildURLSet(t *testing.T) {
+	entries := []publicdb.ListAllPublicEntriesRow{
+		sitemapEntry("2026/01/01/120000", time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), "https://img.example.com/a.png?w=1&h=2"),
+		sitemapEntry("no-image", time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC), ""),
+	}
+
+	data, err := xml.MarshalIndent(buildURLSet("https://blog.example.com", entries), "", "  ")
+	if err != nil {
+		t.Fatalf("xml.MarshalIndent() error = %v", err)
+	}
+
+	assertContainsAll(t, string(data),
+		`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9" xmlns:image="http://www.google.com/schemas/sitemap-image/1.1">`,
+		`<loc>https://blog.example.com/entry/2026/01/01/120000</loc>`,
+		`<lastmod>2026-01-02T03:04:05Z</lastmod>`,
+		`<image:loc>https://img.example.com/a.png?w=1&amp;h=2</image:loc>`,
+		`<loc>https://blog.example.com/entry/no-image</loc>`,
+	)
+	var got sitemapURLSet
+	if err := xml.Unmarshal(data, &got); err != nil {
+		t.Fatalf("invalid XML: %v", err)
+	}
+	if len(got.URLs) != 2 || len(got.URLs[1].Images) != 0 {
+		t.Errorf("urls = %+v", got.URLs)
+	}
+}
+
+func TestSitemapChunks(t *testing.T) {
+	var entries []publicdb.ListAllPublicEntriesRow
+	for i := range 5 {
+		entries = append(entries, sitemapEntry(fmt.Sprintf("e%d", i), time.Date(2026, 1, i+1, 0, 0, 0, 0, time.UTC), ""))
+	}
+
+	chunks := sitemapChunks(entries, 2)
+	if len(chunks) != 3 || len(chunks[0]) != 2 || len(chunks[2]) != 1 || chunks[2][0].Path != "e4" {
+		t.Fatalf("sitemapChunks() = %v", chunks)
+	}
+	if got := sitemapChunks(nil, 2); len(got) != 0 {
+		t.Errorf("sitemapChunks(nil) = %v, want none", got)
+	}
+
+	data, err := xml.MarshalIndent(buildSitemapIndex("https://blog.example.com", chunks), "", "  ")
+	if err != nil {
+		t.Fatalf("xml.MarshalIndent() error = %v", err)
+	}
+	assertContainsAll(t, string(data),
+		`<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`,
+		`<loc>https://blog.example.com/sitemap/1.xml</loc>`,
+		`<lastmod>2026-01-02T00:00:00Z</lastmod>`,
+		`<loc>https://blog.example.com/sitemap/3.xml</loc>`,
+		`<lastmod>2026-01-05T00:00:00Z</lastmod>`,
+	)
+}
+
+func TestSitemapValidators(t *testing.T) {
+	entries := []publicdb.ListAllPublicEntriesRow{
+		sitemapEntry("a", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), "https://img.example.com/a.png"),
+	}
+
+	etag, lastModified := sitemapValidators("/sitemap.xml", entries)
+	if !lastModified.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("lastModified = %v", lastModified)
+	}
+	if pageETag, _ := sitemapValidators(sitemapPagePath(1), entries); pageETag == etag {
+		t.Error("sitemap files must not share an ETag")
+	}
+
+	entries[0].ImageUrl.String = "https://img.example.com/b.png"
+	if changed, _ := sitemapValidators("/sitemap.xml", entries); changed == etag {
+		t.Error("ETag did not change after the entry image was regenerated")
+	}
+}
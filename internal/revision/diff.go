@@ -0,0 +1,116 @@
+package revision
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cubicdaiya/gonp"
+)
+
+// DiffContext is the number of unchanged lines shown around each change.
+const DiffContext = 3
+
+// lineOp is one step of a line-based edit script: a line kept from a, deleted
+// from a, or added from b. aLine and bLine are the 0-based positions in a and
+// b before the step.
+type lineOp struct {
+	kind  gonp.SesType
+	text  string
+	aLine int
+	bLine int
+}
+
+// splitLines splits text into lines. A trailing newline does not start an
+// extra empty line.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffLines returns the shortest edit script turning a into b.
+func diffLines(a, b []string) []lineOp {
+	d := gonp.New(a, b)
+	d.Compose()
+
+	ops := make([]lineOp, 0, len(a)+len(b))
+	ai, bi := 0, 0
+	for _, e := range d.Ses() {
+		op := lineOp{kind: e.GetType(), text: e.GetElem(), aLine: ai, bLine: bi}
+		switch op.kind {
+		case gonp.SesCommon:
+			ai++
+			bi++
+		case gonp.SesDelete:
+			ai++
+		case gonp.SesAdd:
+			bi++
+		}
+		ops = append(ops, op)
+	}
+	return ops
+}
+
+// Unified returns the unified diff turning a into b, labelled with fromName
+// and toName, or "" when they have the same lines.
+func Unified(fromName, toName, a, b string) string {
+	ops := diffLines(splitLines(a), splitLines(b))
+
+	var changes []int
+	for i, op := range ops {
+		if op.kind != gonp.SesCommon {
+			changes = append(changes, i)
+		}
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+	for start := 0; start < len(changes); {
+		// Changes closer than twice the context share a hunk
+		end := start
+		for end+1 < len(changes) && changes[end+1]-changes[end] <= 2*DiffContext+1 {
+			end++
+		}
+		writeHunk(&sb, ops, max(changes[start]-DiffContext, 0), min(changes[end]+DiffContext+1, len(ops)))
+		start = end + 1
+	}
+	return sb.String()
+}
+
+func writeHunk(sb *strings.Builder, ops []lineOp, from, to int) {
+	aLen, bLen := 0, 0
+	for _, op := range ops[from:to] {
+		if op.kind != gonp.SesAdd {
+			aLen++
+		}
+		if op.kind != gonp.SesDelete {
+			bLen++
+		}
+	}
+	// An empty range is written as the line before it, as diff -u does
+	aStart, bStart := ops[from].aLine+1, ops[from].bLine+1
+	if aLen == 0 {
+		aStart--
+	}
+	if bLen == 0 {
+		bStart--
+	}
+
+	fmt.Fprintf(sb, "@@ -%d,%d +%d,%d @@\n", aStart, aLen, bStart, bLen)
+	for _, op := range ops[from:to] {
+		switch op.kind {
+		case gonp.SesCommon:
+			sb.WriteString(" ")
+		case gonp.SesDelete:
+			sb.WriteString("-")
+		case gonp.SesAdd:
+			sb.WriteString("+")
+		}
+		sb.WriteString(op.text)
+		sb.WriteString("\n")
+	}
+}
@@ -0,0 +1,53 @@
+package revision
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnified(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n"
+	b := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n15\n16\n"
+
+	want := strings.Join([]string{
+		"--- a",
+		"+++ b",
+		"@@ -1,6 +1,6 @@",
+		" 1",
+		" 2",
+		"-3",
+		"+three",
+		" 4",
+		" 5",
+		" 6",
+		"@@ -11,5 +11,5 @@",
+		" 11",
+		" 12",
+		" 13",
+		"-14",
+		" 15",
+		"+16",
+		"",
+	}, "\n")
+	assert.Equal(t, want, Unified("a", "b", a, b))
+}
+
+func TestUnified_MergesNearbyChanges(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n"
+	b := "one\n2\n3\n4\n5\n6\n7\neight\n9\n"
+
+	got := Unified("a", "b", a, b)
+	assert.Equal(t, 1, strings.Count(got, "@@ -"), got)
+	assert.Contains(t, got, "@@ -1,9 +1,9 @@\n")
+}
+
+func TestUnified_EmptySides(t *testing.T) {
+	assert.Equal(t, "--- a\n+++ b\n@@ -0,0 +1,2 @@\n+x\n+y\n", Unified("a", "b", "", "x\ny"))
+	assert.Equal(t, "--- a\n+++ b\n@@ -1,1 +0,0 @@\n-x\n", Unified("a", "b", "x\n", ""))
+}
+
+func TestUnified_Same(t *testing.T) {
+	assert.Equal(t, "", Unified("a", "b", "x\ny\n", "x\ny"))
+}
@@ -0,0 +1,144 @@
+package revision
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/cubicdaiya/gonp"
+)
+
+// Chunk is a run of lines in the result of a three-way merge. A conflict
+// keeps the lines of all three sides for someone to choose from; any other
+// chunk holds its merged lines in Lines.
+type Chunk struct {
+	Lines    []string
+	Conflict bool
+	Base     []string
+	Current  []string
+	Incoming []string
+}
+
+// MergeResult is the outcome of Merge
+type MergeResult struct {
+	Chunks []Chunk
+	// TrailingNewline tells whether the merged text ends with a newline
+	TrailingNewline bool
+}
+
+// Merge combines the changes that current and incoming each made to base, as
+// diff3 does. Regions changed on one side only, or changed the same way on
+// both, merge cleanly; regions changed differently on both sides become
+// conflicts.
+func Merge(base, current, incoming string) MergeResult {
+	o, a, b := splitLines(base), splitLines(current), splitLines(incoming)
+	matchA, matchB := matchLines(o, a), matchLines(o, b)
+
+	var r MergeResult
+	i, j, k := 0, 0, 0
+	for i < len(o) || j < len(a) || k < len(b) {
+		// Lines kept in place on both sides
+		n := 0
+		for i+n < len(o) && matchA[i+n] == j+n && matchB[i+n] == k+n {
+			n++
+		}
+		if n > 0 {
+			r.add(Chunk{Lines: o[i : i+n]})
+			i, j, k = i+n, j+n, k+n
+			continue
+		}
+
+		// Changes up to the next base line that both sides kept
+		l := i
+		for l < len(o) && (matchA[l] < 0 || matchB[l] < 0) {
+			l++
+		}
+		nextA, nextB := len(a), len(b)
+		if l < len(o) {
+			nextA, nextB = matchA[l], matchB[l]
+		}
+		r.addChange(o[i:l], a[j:nextA], b[k:nextB])
+		i, j, k = l, nextA, nextB
+	}
+
+	r.TrailingNewline = strings.HasSuffix(current, "\n")
+	if strings.HasSuffix(incoming, "\n") != strings.HasSuffix(base, "\n") {
+		r.TrailingNewline = strings.HasSuffix(incoming, "\n")
+	}
+	return r
+}
+
+// matchLines maps each line of o to the line of x it was kept as, or -1 when
+// x dropped it.
+func matchLines(o, x []string) []int {
+	match := make([]int, len(o))
+	for i := range match {
+		match[i] = -1
+	}
+	for _, op := range diffLines(o, x) {
+		if op.kind == gonp.SesCommon {
+			match[op.aLine] = op.bLine
+		}
+	}
+	return match
+}
+
+func (r *MergeResult) addChange(base, current, incoming []string) {
+	switch {
+	case slices.Equal(current, base):
+		r.add(Chunk{Lines: incoming})
+	case slices.Equal(incoming, base), slices.Equal(current, incoming):
+		r.add(Chunk{Lines: current})
+	default:
+		r.Chunks = append(r.Chunks, Chunk{Conflict: true, Base: base, Current: current, Incoming: incoming})
+	}
+}
+
+// add appends a merged chunk, joining it to the previous one when that is
+// merged too.
+func (r *MergeResult) add(c Chunk) {
+	if len(c.Lines) == 0 {
+		return
+	}
+	if n := len(r.Chunks); n > 0 && !r.Chunks[n-1].Conflict {
+		last := &r.Chunks[n-1]
+		last.Lines = append(slices.Clip(last.Lines), c.Lines...)
+		return
+	}
+	r.Chunks = append(r.Chunks, c)
+}
+
+// Conflicts returns the number of conflicting chunks
+func (r MergeResult) Conflicts() int {
+	n := 0
+	for _, c := range r.Chunks {
+		if c.Conflict {
+			n++
+		}
+	}
+	return n
+}
+
+// Text returns the merged text. Conflicts are written between git-style
+// <<<<<<< / ======= / >>>>>>> markers, current first.
+func (r MergeResult) Text() string {
+	var lines []string
+	for _, c := range r.Chunks {
+		if !c.Conflict {
+			lines = append(lines, c.Lines...)
+			continue
+		}
+		lines = append(lines, "<<<<<<< current")
+		lines = append(lines, c.Current...)
+		lines = append(lines, "=======")
+		lines = append(lines, c.Incoming...)
+		lines = append(lines, ">>>>>>> incoming")
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	text := strings.Join(lines, "\n")
+	if r.TrailingNewline {
+		text += "\n"
+	}
+	return text
+}
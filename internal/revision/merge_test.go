@@ -0,0 +1,81 @@
+package revision
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge_Clean(t *testing.T) {
+	base := "1\n2\n3\n4\n5\n6\n7\n8\n"
+
+	tests := []struct {
+		name     string
+		current  string
+		incoming string
+		want     string
+	}{
+		{
+			name:     "separate hunks",
+			current:  "one\n2\n3\n4\n5\n6\n7\n8\n",
+			incoming: "1\n2\n3\n4\n5\n6\n7\neight\n",
+			want:     "one\n2\n3\n4\n5\n6\n7\neight\n",
+		},
+		{
+			name:     "same change on both sides",
+			current:  "1\n2\nthree\n4\n5\n6\n7\n8\n",
+			incoming: "1\n2\nthree\n4\n5\n6\n7\n8\nnine\n",
+			want:     "1\n2\nthree\n4\n5\n6\n7\n8\nnine\n",
+		},
+		{
+			name:     "insert and delete",
+			current:  "1\n2\n3\n4\n5\n6\n8\n",
+			incoming: "0\n1\n2\n3\n4\n5\n6\n7\n8\n",
+			want:     "0\n1\n2\n3\n4\n5\n6\n8\n",
+		},
+		{
+			name:     "incoming unchanged",
+			current:  "1\n2\n3\nfour\n5\n6\n7\n8\n",
+			incoming: base,
+			want:     "1\n2\n3\nfour\n5\n6\n7\n8\n",
+		},
+		{
+			name:     "trailing newline dropped",
+			current:  "one\n2\n3\n4\n5\n6\n7\n8\n",
+			incoming: "1\n2\n3\n4\n5\n6\n7\n8",
+			want:     "one\n2\n3\n4\n5\n6\n7\n8",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Merge(base, tt.current, tt.incoming)
+			assert.Zero(t, r.Conflicts())
+			assert.Equal(t, tt.want, r.Text())
+		})
+	}
+}
+
+func TestMerge_Conflict(t *testing.T) {
+	r := Merge(
+		"1\n2\n3\n4\n5\n",
+		"1\n2\nthree\n4\n5\n",
+		"1\n2\nTHREE\n4\nfive\n",
+	)
+
+	assert.Equal(t, 1, r.Conflicts())
+	assert.Equal(t, []Chunk{
+		{Lines: []string{"1", "2"}},
+		{Conflict: true, Base: []string{"3"}, Current: []string{"three"}, Incoming: []string{"THREE"}},
+		{Lines: []string{"4", "five"}},
+	}, r.Chunks)
+	assert.Equal(t, "1\n2\n<<<<<<< current\nthree\n=======\nTHREE\n>>>>>>> incoming\n4\nfive\n", r.Text())
+}
+
+func TestMerge_ConflictingInserts(t *testing.T) {
+	r := Merge("1\n", "1\na\n", "1\nb\n")
+
+	assert.Equal(t, []Chunk{
+		{Lines: []string{"1"}},
+		{Conflict: true, Base: []string{}, Current: []string{"a"}, Incoming: []string{"b"}},
+	}, r.Chunks)
+}
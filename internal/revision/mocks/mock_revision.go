@@ -0,0 +1,115 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: revision.go
+//
+// Generated by this command:
+//
+//	mockgen -source=revision.go -destination=mocks/mock_revision.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	admindb "github.com/tokuhirom/blog4/db/admin/admindb"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockStore is a mock of Store interface.
+type MockStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockStoreMockRecorder is the mock recorder for MockStore.
+type MockStoreMockRecorder struct {
+	mock *MockStore
+}
+
+// NewMockStore creates a new mock instance.
+func NewMockStore(ctrl *gomock.Controller) *MockStore {
+	mock := &MockStore{ctrl: ctrl}
+	mock.recorder = &MockStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStore) EXPECT() *MockStoreMockRecorder {
+	return m.recorder
+}
+
+// AdminGetEntryByPath mocks base method.
+func (m *MockStore) AdminGetEntryByPath(ctx context.Context, path string) (admindb.AdminGetEntryByPathRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminGetEntryByPath", ctx, path)
+	ret0, _ := ret[0].(admindb.AdminGetEntryByPathRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdminGetEntryByPath indicates an expected call of AdminGetEntryByPath.
+func (mr *MockStoreMockRecorder) AdminGetEntryByPath(ctx, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminGetEntryByPath", reflect.TypeOf((*MockStore)(nil).AdminGetEntryByPath), ctx, path)
+}
+
+// GetLatestEntryRevision mocks base method.
+func (m *MockStore) GetLatestEntryRevision(ctx context.Context, path string) (admindb.EntryRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestEntryRevision", ctx, path)
+	ret0, _ := ret[0].(admindb.EntryRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestEntryRevision indicates an expected call of GetLatestEntryRevision.
+func (mr *MockStoreMockRecorder) GetLatestEntryRevision(ctx, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestEntryRevision", reflect.TypeOf((*MockStore)(nil).GetLatestEntryRevision), ctx, path)
+}
+
+// InsertEntryRevision mocks base method.
+func (m *MockStore) InsertEntryRevision(ctx context.Context, arg admindb.InsertEntryRevisionParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertEntryRevision", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertEntryRevision indicates an expected call of InsertEntryRevision.
+func (mr *MockStoreMockRecorder) InsertEntryRevision(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertEntryRevision", reflect.TypeOf((*MockStore)(nil).InsertEntryRevision), ctx, arg)
+}
+
+// SealEntryRevisions mocks base method.
+func (m *MockStore) SealEntryRevisions(ctx context.Context, path string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SealEntryRevisions", ctx, path)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SealEntryRevisions indicates an expected call of SealEntryRevisions.
+func (mr *MockStoreMockRecorder) SealEntryRevisions(ctx, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SealEntryRevisions", reflect.TypeOf((*MockStore)(nil).SealEntryRevisions), ctx, path)
+}
+
+// UpdateEntryRevision mocks base method.
+func (m *MockStore) UpdateEntryRevision(ctx context.Context, arg admindb.UpdateEntryRevisionParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEntryRevision", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateEntryRevision indicates an expected call of UpdateEntryRevision.
+func (mr *MockStoreMockRecorder) UpdateEntryRevision(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEntryRevision", reflect.TypeOf((*MockStore)(nil).UpdateEntryRevision), ctx, arg)
+}
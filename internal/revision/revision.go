@@ -0,0 +1,160 @@
+// Package revision keeps the history of entry titles and bodies in
+// entry_revision, so that an edit can be compared with and rolled back to an
+// earlier state.
+package revision
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tokuhirom/blog4/db/admin/admindb"
+)
+
+//go:generate go run go.uber.org/mock/mockgen -source=revision.go -destination=mocks/mock_revision.go -package=mocks
+
+const (
+	// CoalesceIdle is how soon after the previous save a save is folded into
+	// the latest revision instead of starting a new one.
+	CoalesceIdle = 10 * time.Minute
+	// CoalesceMax bounds how long one revision keeps absorbing saves, so a
+	// long writing session still leaves a trail.
+	CoalesceMax = time.Hour
+)
+
+// Store defines the database operations needed to record revisions
+type Store interface {
+	AdminGetEntryByPath(ctx context.Context, path string) (admindb.AdminGetEntryByPathRow, error)
+	GetLatestEntryRevision(ctx context.Context, path string) (admindb.EntryRevision, error)
+	InsertEntryRevision(ctx context.Context, arg admindb.InsertEntryRevisionParams) (int64, error)
+	UpdateEntryRevision(ctx context.Context, arg admindb.UpdateEntryRevisionParams) error
+	SealEntryRevisions(ctx context.Context, path string) error
+}
+
+// NewEditSession returns a random id for one open editor page. Saves carry it
+// so that only that page's saves are coalesced into the revisions it wrote.
+func NewEditSession() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate edit session: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Record saves the current title and body of path as its latest revision and
+// returns its id. A save from editSession within CoalesceIdle of its previous
+// one overwrites the latest revision, so autosave produces one revision per
+// editing session. Revisions of other sessions are never overwritten: an
+// editor merges concurrent edits against the revision it last saw, so that
+// revision must stay as it was. Call it with a store bound to the transaction
+// that changed the entry.
+func Record(ctx context.Context, store Store, path, editSession string, now time.Time) (int64, error) {
+	return record(ctx, store, path, editSession, now, true)
+}
+
+// Checkpoint is Record without coalescing: the current state always gets a
+// revision of its own, which no session will change afterwards. Use it for
+// changes that should stay undoable on their own, such as a restore, and for
+// a revision to merge against.
+func Checkpoint(ctx context.Context, store Store, path string, now time.Time) (int64, error) {
+	return record(ctx, store, path, "", now, false)
+}
+
+// MergeBase returns the revision an editor opened now on entry may merge its
+// saves against, without writing anything: the latest revision, when it holds
+// the current state and no session can coalesce saves into it any more. It
+// returns 0 otherwise, and the editor gets a base from its first save.
+func MergeBase(ctx context.Context, store Store, entry admindb.AdminGetEntryByPathRow, now time.Time) (int64, error) {
+	latest, err := store.GetLatestEntryRevision(ctx, entry.Path)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest revision of %s: %w", entry.Path, err)
+	}
+	if latest.Title != entry.Title || latest.Body != entry.Body {
+		return 0, nil
+	}
+	if latest.EditSession.Valid && coalescing(latest, now) {
+		return 0, nil
+	}
+	return latest.ID, nil
+}
+
+// coalescing reports whether saves of the session that wrote rev at now
+// would still be folded into it
+func coalescing(rev admindb.EntryRevision, now time.Time) bool {
+	return now.Sub(rev.SavedAt) < CoalesceIdle && now.Sub(rev.CreatedAt) < CoalesceMax
+}
+
+func record(ctx context.Context, store Store, path, editSession string, now time.Time, coalesce bool) (int64, error) {
+	entry, err := store.AdminGetEntryByPath(ctx, path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get entry %s: %w", path, err)
+	}
+
+	latest, err := store.GetLatestEntryRevision(ctx, path)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return 0, fmt.Errorf("failed to get latest revision of %s: %w", path, err)
+	case latest.Title == entry.Title && latest.Body == entry.Body:
+		if latest.EditSession.Valid && latest.EditSession.String != editSession {
+			// The caller may merge against it from now on, so its session
+			// must not change it any more
+			if err := store.SealEntryRevisions(ctx, path); err != nil {
+				return 0, fmt.Errorf("failed to seal revisions of %s: %w", path, err)
+			}
+		}
+		return latest.ID, nil
+	case coalesce && editSession != "" && latest.EditSession.String == editSession && coalescing(latest, now):
+		err := store.UpdateEntryRevision(ctx, admindb.UpdateEntryRevisionParams{
+			Title:   entry.Title,
+			Body:    entry.Body,
+			SavedAt: now,
+			ID:      latest.ID,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to update revision %d of %s: %w", latest.ID, path, err)
+		}
+		return latest.ID, nil
+	}
+
+	id, err := store.InsertEntryRevision(ctx, admindb.InsertEntryRevisionParams{
+		Path:        path,
+		Title:       entry.Title,
+		Body:        entry.Body,
+		CreatedAt:   now,
+		SavedAt:     now,
+		EditSession: sql.NullString{String: editSession, Valid: editSession != ""},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert revision of %s: %w", path, err)
+	}
+	return id, nil
+}
+
+// Service records revisions for entries that already exist
+type Service struct {
+	queries *admindb.Queries
+}
+
+// NewService creates a new Service
+func NewService(queries *admindb.Queries) *Service {
+	return &Service{queries: queries}
+}
+
+// RecordMissing gives every entry without revisions, such as those written
+// before revisions were kept or loaded from a dump, a revision of its current
+// state. It returns the number of revisions written.
+func (s *Service) RecordMissing(ctx context.Context) (int64, error) {
+	n, err := s.queries.InsertMissingEntryRevisions(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert missing revisions: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,200 @@
+package revision
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/tokuhirom/blog4/db/admin/admindb"
+	"github.com/tokuhirom/blog4/internal/revision/mocks"
+)
+
+var testNow = time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+
+func expectEntry(store *mocks.MockStore, title, body string) {
+	store.EXPECT().
+		AdminGetEntryByPath(gomock.Any(), "a").
+		Return(admindb.AdminGetEntryByPathRow{Path: "a", Title: title, Body: body}, nil)
+}
+
+func session(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func TestRecord_First(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mocks.NewMockStore(ctrl)
+	expectEntry(store, "T", "new")
+	store.EXPECT().GetLatestEntryRevision(gomock.Any(), "a").Return(admindb.EntryRevision{}, sql.ErrNoRows)
+	store.EXPECT().InsertEntryRevision(gomock.Any(), admindb.InsertEntryRevisionParams{
+		Path: "a", Title: "T", Body: "new", CreatedAt: testNow, SavedAt: testNow, EditSession: session("s1"),
+	}).Return(int64(1), nil)
+
+	id, err := Record(context.Background(), store, "a", "s1", testNow)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), id)
+}
+
+func TestRecord_CoalescesRecentSaves(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mocks.NewMockStore(ctrl)
+	expectEntry(store, "T", "new")
+	store.EXPECT().GetLatestEntryRevision(gomock.Any(), "a").Return(admindb.EntryRevision{
+		ID: 7, Path: "a", Title: "T", Body: "old",
+		CreatedAt: testNow.Add(-30 * time.Minute), SavedAt: testNow.Add(-time.Minute), EditSession: session("s1"),
+	}, nil)
+	store.EXPECT().UpdateEntryRevision(gomock.Any(), admindb.UpdateEntryRevisionParams{
+		Title: "T", Body: "new", SavedAt: testNow, ID: 7,
+	}).Return(nil)
+
+	id, err := Record(context.Background(), store, "a", "s1", testNow)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), id)
+}
+
+func TestRecord_StartsNewRevision(t *testing.T) {
+	tests := []struct {
+		name        string
+		createdAt   time.Time
+		savedAt     time.Time
+		editSession string
+	}{
+		{name: "idle", createdAt: testNow.Add(-20 * time.Minute), savedAt: testNow.Add(-CoalesceIdle), editSession: "s1"},
+		{name: "long session", createdAt: testNow.Add(-CoalesceMax), savedAt: testNow.Add(-time.Minute), editSession: "s1"},
+		{name: "other session", createdAt: testNow.Add(-time.Minute), savedAt: testNow.Add(-time.Minute), editSession: "s2"},
+		{name: "sealed", createdAt: testNow.Add(-time.Minute), savedAt: testNow.Add(-time.Minute)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mocks.NewMockStore(ctrl)
+			expectEntry(store, "T", "new")
+			store.EXPECT().GetLatestEntryRevision(gomock.Any(), "a").Return(admindb.EntryRevision{
+				ID: 7, Path: "a", Title: "T", Body: "old",
+				CreatedAt: tt.createdAt, SavedAt: tt.savedAt, EditSession: session(tt.editSession),
+			}, nil)
+			store.EXPECT().InsertEntryRevision(gomock.Any(), admindb.InsertEntryRevisionParams{
+				Path: "a", Title: "T", Body: "new", CreatedAt: testNow, SavedAt: testNow, EditSession: session("s1"),
+			}).Return(int64(8), nil)
+
+			id, err := Record(context.Background(), store, "a", "s1", testNow)
+			require.NoError(t, err)
+			assert.Equal(t, int64(8), id)
+		})
+	}
+}
+
+func TestRecord_Unchanged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mocks.NewMockStore(ctrl)
+	expectEntry(store, "T", "same")
+	store.EXPECT().GetLatestEntryRevision(gomock.Any(), "a").Return(admindb.EntryRevision{
+		ID: 7, Path: "a", Title: "T", Body: "same",
+		CreatedAt: testNow.Add(-time.Hour), SavedAt: testNow.Add(-time.Hour), EditSession: session("s1"),
+	}, nil)
+
+	id, err := Record(context.Background(), store, "a", "s1", testNow)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), id)
+}
+
+func TestCheckpoint_SealsRevisionOfOtherSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mocks.NewMockStore(ctrl)
+	expectEntry(store, "T", "same")
+	store.EXPECT().GetLatestEntryRevision(gomock.Any(), "a").Return(admindb.EntryRevision{
+		ID: 7, Path: "a", Title: "T", Body: "same",
+		CreatedAt: testNow.Add(-time.Minute), SavedAt: testNow.Add(-time.Minute), EditSession: session("s2"),
+	}, nil)
+	store.EXPECT().SealEntryRevisions(gomock.Any(), "a").Return(nil)
+
+	id, err := Checkpoint(context.Background(), store, "a", testNow)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), id)
+}
+
+func TestCheckpoint_NeverCoalesces(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mocks.NewMockStore(ctrl)
+	expectEntry(store, "T", "restored")
+	store.EXPECT().GetLatestEntryRevision(gomock.Any(), "a").Return(admindb.EntryRevision{
+		ID: 7, Path: "a", Title: "T", Body: "current", CreatedAt: testNow.Add(-time.Minute), SavedAt: testNow.Add(-time.Minute),
+	}, nil)
+	store.EXPECT().InsertEntryRevision(gomock.Any(), admindb.InsertEntryRevisionParams{
+		Path: "a", Title: "T", Body: "restored", CreatedAt: testNow, SavedAt: testNow,
+	}).Return(int64(8), nil)
+
+	_, err := Checkpoint(context.Background(), store, "a", testNow)
+	require.NoError(t, err)
+}
+
+func TestMergeBase(t *testing.T) {
+	entry := admindb.AdminGetEntryByPathRow{Path: "a", Title: "T", Body: "same"}
+	tests := []struct {
+		name   string
+		latest admindb.EntryRevision
+		want   int64
+	}{
+		{
+			name:   "sealed revision of the current state",
+			latest: admindb.EntryRevision{ID: 7, Title: "T", Body: "same", CreatedAt: testNow.Add(-time.Minute), SavedAt: testNow.Add(-time.Minute)},
+			want:   7,
+		},
+		{
+			name: "session past the coalescing window",
+			latest: admindb.EntryRevision{ID: 7, Title: "T", Body: "same", EditSession: session("s2"),
+				CreatedAt: testNow.Add(-CoalesceIdle), SavedAt: testNow.Add(-CoalesceIdle)},
+			want: 7,
+		},
+		{
+			name: "session still coalescing",
+			latest: admindb.EntryRevision{ID: 7, Title: "T", Body: "same", EditSession: session("s2"),
+				CreatedAt: testNow.Add(-time.Minute), SavedAt: testNow.Add(-time.Minute)},
+			want: 0,
+		},
+		{
+			name:   "entry changed since",
+			latest: admindb.EntryRevision{ID: 7, Title: "T", Body: "older", CreatedAt: testNow.Add(-time.Hour), SavedAt: testNow.Add(-time.Hour)},
+			want:   0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// Only reads are expected: the mock fails on any write
+			store := mocks.NewMockStore(ctrl)
+			store.EXPECT().GetLatestEntryRevision(gomock.Any(), "a").Return(tt.latest, nil)
+
+			id, err := MergeBase(context.Background(), store, entry, testNow)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, id)
+		})
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mocks.NewMockStore(ctrl)
+	store.EXPECT().GetLatestEntryRevision(gomock.Any(), "a").Return(admindb.EntryRevision{}, sql.ErrNoRows)
+	id, err := MergeBase(context.Background(), store, entry, testNow)
+	require.NoError(t, err)
+	assert.Zero(t, id)
+}
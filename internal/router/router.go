@@ -2,13 +2,15 @@ package router
 
 import (
 	"database/sql"
-	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
 
 	"github.com/gin-gonic/gin"
 
+	adminassets "github.com/tokuhirom/blog4/admin"
+	publicassets "github.com/tokuhirom/blog4/public"
+
 	"github.com/tokuhirom/blog4/internal"
 	"github.com/tokuhirom/blog4/internal/public"
 	"github.com/tokuhirom/blog4/internal/sobs"
@@ -21,15 +23,6 @@ import (
 )
 
 func BuildRouter(cfg internal.Config, sqlDB *sql.DB, sobsClient *sobs.SobsClient) (*gin.Engine, error) {
-	// Validate admin config
-	if cfg.AdminUser == "" {
-		slog.Warn("AdminUser is not set")
-	}
-	if cfg.AdminPassword == "" {
-		slog.Warn("AdminPassword is not set")
-		return nil, fmt.Errorf("AdminPassword is not set")
-	}
-
 	// Set gin mode
 	if !cfg.LocalDev {
 		gin.SetMode(gin.ReleaseMode)
@@ -63,11 +56,15 @@ func BuildRouter(cfg internal.Config, sqlDB *sql.DB, sobsClient *sobs.SobsClient
 	// Setup admin routes
 	adminQueries := admindb.New(sqlDB)
 	adminGroup := r.Group("/admin")
-	admin.SetupAdminRoutes(adminGroup, adminQueries, sobsClient, cfg)
+	if err := admin.SetupAdminRoutes(adminGroup, sqlDB, adminQueries, sobsClient, cfg, adminassets.FS(cfg.LocalDev)); err != nil {
+		return nil, err
+	}
 
 	// Setup public routes
 	publicQueries := publicdb.New(sqlDB)
-	public.SetupPublicRoutes(r, publicQueries, &cfg)
+	if err := public.SetupPublicRoutes(r, publicQueries, &cfg, publicassets.FS(cfg.LocalDev)); err != nil {
+		return nil, err
+	}
 
 	return r, nil
 }
@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: scheduler.go
+//
+// Generated by this command:
+//
+//	mockgen -source=scheduler.go -destination=mocks/mock_scheduler.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	sql "database/sql"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockStore is a mock of Store interface.
+type MockStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockStoreMockRecorder is the mock recorder for MockStore.
+type MockStoreMockRecorder struct {
+	mock *MockStore
+}
+
+// NewMockStore creates a new mock instance.
+func NewMockStore(ctrl *gomock.Controller) *MockStore {
+	mock := &MockStore{ctrl: ctrl}
+	mock.recorder = &MockStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStore) EXPECT() *MockStoreMockRecorder {
+	return m.recorder
+}
+
+// ListDueScheduledEntries mocks base method.
+func (m *MockStore) ListDueScheduledEntries(ctx context.Context, now sql.NullTime) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDueScheduledEntries", ctx, now)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDueScheduledEntries indicates an expected call of ListDueScheduledEntries.
+func (mr *MockStoreMockRecorder) ListDueScheduledEntries(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDueScheduledEntries", reflect.TypeOf((*MockStore)(nil).ListDueScheduledEntries), ctx, now)
+}
+
+// PublishScheduledEntry mocks base method.
+func (m *MockStore) PublishScheduledEntry(ctx context.Context, path string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishScheduledEntry", ctx, path)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishScheduledEntry indicates an expected call of PublishScheduledEntry.
+func (mr *MockStoreMockRecorder) PublishScheduledEntry(ctx, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishScheduledEntry", reflect.TypeOf((*MockStore)(nil).PublishScheduledEntry), ctx, path)
+}
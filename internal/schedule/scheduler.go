@@ -0,0 +1,86 @@
+// Package schedule publishes entries whose visibility is 'scheduled' once
+// their published_at has been reached.
+package schedule
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+//go:generate go run go.uber.org/mock/mockgen -source=scheduler.go -destination=mocks/mock_scheduler.go -package=mocks
+
+// Store defines the database operations needed to publish scheduled entries
+type Store interface {
+	ListDueScheduledEntries(ctx context.Context, now sql.NullTime) ([]string, error)
+	PublishScheduledEntry(ctx context.Context, path string) (int64, error)
+}
+
+// Scheduler flips due scheduled entries to public and reports each one to
+// onPublish, which fires the same side effects as publishing by hand.
+type Scheduler struct {
+	store     Store
+	onPublish func(path string)
+	// Interval is how often Run looks for due entries.
+	Interval time.Duration
+	now      func() time.Time
+}
+
+// NewScheduler creates a Scheduler that checks for due entries every minute.
+func NewScheduler(store Store, onPublish func(path string)) *Scheduler {
+	return &Scheduler{
+		store:     store,
+		onPublish: onPublish,
+		Interval:  1 * time.Minute,
+		now:       time.Now,
+	}
+}
+
+// PublishDue publishes every scheduled entry whose published_at is not in the
+// future and returns how many were published. An entry that was unscheduled
+// or published by hand since it was listed is skipped.
+func (s *Scheduler) PublishDue(ctx context.Context) (int, error) {
+	paths, err := s.store.ListDueScheduledEntries(ctx, sql.NullTime{Time: s.now(), Valid: true})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list due scheduled entries: %w", err)
+	}
+
+	published := 0
+	for _, path := range paths {
+		n, err := s.store.PublishScheduledEntry(ctx, path)
+		if err != nil {
+			return published, fmt.Errorf("failed to publish scheduled entry %s: %w", path, err)
+		}
+		if n == 0 {
+			continue
+		}
+		slog.Info("published scheduled entry", slog.String("path", path))
+		published++
+		s.onPublish(path)
+	}
+	return published, nil
+}
+
+// Run calls PublishDue once at startup and then every Interval until ctx is
+// done.
+func (s *Scheduler) Run(ctx context.Context) {
+	publish := func() {
+		if _, err := s.PublishDue(ctx); err != nil {
+			slog.Error("failed to publish scheduled entries", slog.Any("error", err))
+		}
+	}
+
+	publish()
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			publish()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
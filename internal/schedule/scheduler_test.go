@@ -0,0 +1,90 @@
+package schedule
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/tokuhirom/blog4/internal/schedule/mocks"
+)
+
+var testNow = time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+
+func newTestScheduler(store Store, published *[]string) *Scheduler {
+	s := NewScheduler(store, func(path string) { *published = append(*published, path) })
+	s.now = func() time.Time { return testNow }
+	return s
+}
+
+func TestPublishDue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mocks.NewMockStore(ctrl)
+	mockStore.EXPECT().
+		ListDueScheduledEntries(gomock.Any(), sql.NullTime{Time: testNow, Valid: true}).
+		Return([]string{"a", "b", "c"}, nil)
+	mockStore.EXPECT().PublishScheduledEntry(gomock.Any(), "a").Return(int64(1), nil)
+	// b was unscheduled after it was listed.
+	mockStore.EXPECT().PublishScheduledEntry(gomock.Any(), "b").Return(int64(0), nil)
+	mockStore.EXPECT().PublishScheduledEntry(gomock.Any(), "c").Return(int64(1), nil)
+
+	var published []string
+	n, err := newTestScheduler(mockStore, &published).PublishDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []string{"a", "c"}, published)
+}
+
+func TestPublishDue_StopsOnError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mocks.NewMockStore(ctrl)
+	mockStore.EXPECT().
+		ListDueScheduledEntries(gomock.Any(), gomock.Any()).
+		Return([]string{"a", "b"}, nil)
+	mockStore.EXPECT().PublishScheduledEntry(gomock.Any(), "a").Return(int64(0), errors.New("connection lost"))
+
+	var published []string
+	n, err := newTestScheduler(mockStore, &published).PublishDue(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "connection lost")
+	assert.Equal(t, 0, n)
+	assert.Empty(t, published)
+}
+
+func TestRun_StopsWhenCancelled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mockStore := mocks.NewMockStore(ctrl)
+	mockStore.EXPECT().
+		ListDueScheduledEntries(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(context.Context, sql.NullTime) ([]string, error) {
+			cancel()
+			return nil, nil
+		})
+
+	var published []string
+	s := newTestScheduler(mockStore, &published)
+	s.Interval = time.Hour
+
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after the context was cancelled")
+	}
+}
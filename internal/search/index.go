@@ -0,0 +1,108 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"github.com/tokuhirom/blog4/db/admin/admindb"
+)
+
+//go:generate go run go.uber.org/mock/mockgen -source=index.go -destination=mocks/mock_index.go -package=mocks
+
+// IndexStore defines the database operations needed to maintain entry_search_token
+type IndexStore interface {
+	AdminGetEntryByPath(ctx context.Context, path string) (admindb.AdminGetEntryByPathRow, error)
+	DeleteSearchTokensByPath(ctx context.Context, path string) (int64, error)
+	InsertSearchTokens(ctx context.Context, path string, tokens []string) (int64, error)
+}
+
+// ReindexEntry replaces the search tokens of path with those of its current
+// title and body. Call it with a store bound to the transaction that changed
+// the entry, so the index never lags behind a committed edit.
+func ReindexEntry(ctx context.Context, store IndexStore, path string) error {
+	entry, err := store.AdminGetEntryByPath(ctx, path)
+	if err != nil {
+		return fmt.Errorf("failed to get entry %s: %w", path, err)
+	}
+	if _, err := store.DeleteSearchTokensByPath(ctx, path); err != nil {
+		return fmt.Errorf("failed to delete search tokens for %s: %w", path, err)
+	}
+
+	tokens := IndexTokens(entry.Title, entry.Body)
+	if len(tokens) == 0 {
+		return nil
+	}
+	if _, err := store.InsertSearchTokens(ctx, path, tokens); err != nil {
+		return fmt.Errorf("failed to insert search tokens for %s: %w", path, err)
+	}
+	return nil
+}
+
+// Service builds the search index for entries that already exist
+type Service struct {
+	db      *sql.DB
+	queries *admindb.Queries
+}
+
+// NewService creates a new Service
+func NewService(db *sql.DB, queries *admindb.Queries) *Service {
+	return &Service{
+		db:      db,
+		queries: queries,
+	}
+}
+
+// IndexMissing indexes every entry that has no search tokens yet, such as
+// entries written before the index existed or loaded from a dump. It returns
+// the number of entries indexed.
+func (s *Service) IndexMissing(ctx context.Context) (int, error) {
+	paths, err := s.queries.ListEntryPathsWithoutSearchTokens(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list unindexed entries: %w", err)
+	}
+	return s.reindexAll(ctx, paths), nil
+}
+
+// RebuildAll re-indexes every entry, one transaction per entry. A failure on
+// one entry is logged and does not stop the rest.
+func (s *Service) RebuildAll(ctx context.Context) (int, error) {
+	entries, err := s.queries.AdminListAllEntries(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list entries: %w", err)
+	}
+	paths := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		paths = append(paths, entry.Path)
+	}
+	return s.reindexAll(ctx, paths), nil
+}
+
+func (s *Service) reindexAll(ctx context.Context, paths []string) int {
+	processed := 0
+	for _, path := range paths {
+		if err := s.reindexEntry(ctx, path); err != nil {
+			slog.Error("failed to index entry", slog.String("path", path), slog.Any("error", err))
+			continue
+		}
+		processed++
+	}
+	return processed
+}
+
+func (s *Service) reindexEntry(ctx context.Context, path string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := ReindexEntry(ctx, s.queries.WithTx(tx), path); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/tokuhirom/blog4/db/admin/admindb"
+	"github.com/tokuhirom/blog4/internal/search/mocks"
+)
+
+func TestReindexEntry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mocks.NewMockIndexStore(ctrl)
+	gomock.InOrder(
+		mockStore.EXPECT().
+			AdminGetEntryByPath(gomock.Any(), "2026/01/01/120000").
+			Return(admindb.AdminGetEntryByPathRow{Path: "2026/01/01/120000", Title: "Go", Body: "日本"}, nil),
+		mockStore.EXPECT().
+			DeleteSearchTokensByPath(gomock.Any(), "2026/01/01/120000").
+			Return(int64(10), nil),
+		mockStore.EXPECT().
+			InsertSearchTokens(gomock.Any(), "2026/01/01/120000", []string{"g", "go", "o", "日", "日本", "本"}).
+			Return(int64(6), nil),
+	)
+
+	err := ReindexEntry(context.Background(), mockStore, "2026/01/01/120000")
+	require.NoError(t, err)
+}
+
+func TestReindexEntry_EmptyOnlyDeletes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mocks.NewMockIndexStore(ctrl)
+	mockStore.EXPECT().
+		AdminGetEntryByPath(gomock.Any(), "empty").
+		Return(admindb.AdminGetEntryByPathRow{Path: "empty"}, nil)
+	mockStore.EXPECT().
+		DeleteSearchTokensByPath(gomock.Any(), "empty").
+		Return(int64(0), nil)
+
+	err := ReindexEntry(context.Background(), mockStore, "empty")
+	require.NoError(t, err)
+}
+
+func TestReindexEntry_InsertError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mocks.NewMockIndexStore(ctrl)
+	mockStore.EXPECT().
+		AdminGetEntryByPath(gomock.Any(), "a").
+		Return(admindb.AdminGetEntryByPathRow{Path: "a", Title: "x"}, nil)
+	mockStore.EXPECT().
+		DeleteSearchTokensByPath(gomock.Any(), "a").
+		Return(int64(0), nil)
+	mockStore.EXPECT().
+		InsertSearchTokens(gomock.Any(), "a", []string{"x"}).
+		Return(int64(0), errors.New("db down"))
+
+	err := ReindexEntry(context.Background(), mockStore, "a")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to insert search tokens")
+}
@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: index.go
+//
+// Generated by this command:
+//
+//	mockgen -source=index.go -destination=mocks/mock_index.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	admindb "github.com/tokuhirom/blog4/db/admin/admindb"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIndexStore is a mock of IndexStore interface.
+type MockIndexStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockIndexStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockIndexStoreMockRecorder is the mock recorder for MockIndexStore.
+type MockIndexStoreMockRecorder struct {
+	mock *MockIndexStore
+}
+
+// NewMockIndexStore creates a new mock instance.
+func NewMockIndexStore(ctrl *gomock.Controller) *MockIndexStore {
+	mock := &MockIndexStore{ctrl: ctrl}
+	mock.recorder = &MockIndexStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIndexStore) EXPECT() *MockIndexStoreMockRecorder {
+	return m.recorder
+}
+
+// AdminGetEntryByPath mocks base method.
+func (m *MockIndexStore) AdminGetEntryByPath(ctx context.Context, path string) (admindb.AdminGetEntryByPathRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminGetEntryByPath", ctx, path)
+	ret0, _ := ret[0].(admindb.AdminGetEntryByPathRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdminGetEntryByPath indicates an expected call of AdminGetEntryByPath.
+func (mr *MockIndexStoreMockRecorder) AdminGetEntryByPath(ctx, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminGetEntryByPath", reflect.TypeOf((*MockIndexStore)(nil).AdminGetEntryByPath), ctx, path)
+}
+
+// DeleteSearchTokensByPath mocks base method.
+func (m *MockIndexStore) DeleteSearchTokensByPath(ctx context.Context, path string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSearchTokensByPath", ctx, path)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSearchTokensByPath indicates an expected call of DeleteSearchTokensByPath.
+func (mr *MockIndexStoreMockRecorder) DeleteSearchTokensByPath(ctx, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSearchTokensByPath", reflect.TypeOf((*MockIndexStore)(nil).DeleteSearchTokensByPath), ctx, path)
+}
+
+// InsertSearchTokens mocks base method.
+func (m *MockIndexStore) InsertSearchTokens(ctx context.Context, path string, tokens []string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertSearchTokens", ctx, path, tokens)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertSearchTokens indicates an expected call of InsertSearchTokens.
+func (mr *MockIndexStoreMockRecorder) InsertSearchTokens(ctx, path, tokens any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertSearchTokens", reflect.TypeOf((*MockIndexStore)(nil).InsertSearchTokens), ctx, path, tokens)
+}
@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: search.go
+//
+// Generated by this command:
+//
+//	mockgen -source=search.go -destination=mocks/mock_search.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	publicdb "github.com/tokuhirom/blog4/db/public/publicdb"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockStore is a mock of Store interface.
+type MockStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockStoreMockRecorder is the mock recorder for MockStore.
+type MockStoreMockRecorder struct {
+	mock *MockStore
+}
+
+// NewMockStore creates a new mock instance.
+func NewMockStore(ctrl *gomock.Controller) *MockStore {
+	mock := &MockStore{ctrl: ctrl}
+	mock.recorder = &MockStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStore) EXPECT() *MockStoreMockRecorder {
+	return m.recorder
+}
+
+// GetPublicEntriesByPaths mocks base method.
+func (m *MockStore) GetPublicEntriesByPaths(ctx context.Context, paths []string) ([]publicdb.GetPublicEntriesByPathsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPublicEntriesByPaths", ctx, paths)
+	ret0, _ := ret[0].([]publicdb.GetPublicEntriesByPathsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPublicEntriesByPaths indicates an expected call of GetPublicEntriesByPaths.
+func (mr *MockStoreMockRecorder) GetPublicEntriesByPaths(ctx, paths any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublicEntriesByPaths", reflect.TypeOf((*MockStore)(nil).GetPublicEntriesByPaths), ctx, paths)
+}
+
+// SearchEntryPathsByTokens mocks base method.
+func (m *MockStore) SearchEntryPathsByTokens(ctx context.Context, arg publicdb.SearchEntryPathsByTokensParams) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchEntryPathsByTokens", ctx, arg)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchEntryPathsByTokens indicates an expected call of SearchEntryPathsByTokens.
+func (mr *MockStoreMockRecorder) SearchEntryPathsByTokens(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchEntryPathsByTokens", reflect.TypeOf((*MockStore)(nil).SearchEntryPathsByTokens), ctx, arg)
+}
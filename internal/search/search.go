@@ -0,0 +1,207 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/tokuhirom/blog4/db/public/publicdb"
+)
+
+//go:generate go run go.uber.org/mock/mockgen -source=search.go -destination=mocks/mock_search.go -package=mocks
+
+// Store defines the database operations needed to search public entries
+type Store interface {
+	SearchEntryPathsByTokens(ctx context.Context, arg publicdb.SearchEntryPathsByTokensParams) ([]string, error)
+	GetPublicEntriesByPaths(ctx context.Context, paths []string) ([]publicdb.GetPublicEntriesByPathsRow, error)
+}
+
+const (
+	// maxCandidates bounds how many entries are loaded and ranked per query.
+	// Very common n-grams match most of the blog; the newest ones win, and
+	// the page is marked Truncated.
+	maxCandidates = 1000
+
+	titleScore    = 10
+	maxBodyScore  = 5
+	snippetLength = 160
+	// snippetLead is how much text is kept before the first match.
+	snippetLead = 40
+)
+
+// Result is a matching entry.
+type Result struct {
+	Path  string
+	Title string
+	// Snippet is HTML: escaped text with matches wrapped in <mark>.
+	Snippet     string
+	PublishedAt time.Time
+	ImageURL    string
+}
+
+// Page is one page of results.
+type Page struct {
+	Total   int
+	HasNext bool
+	// Truncated is set when more than maxCandidates entries may match, so
+	// that only the newest ones were ranked and Total is a lower bound.
+	Truncated bool
+	Results   []Result
+}
+
+type scoredEntry struct {
+	entry publicdb.GetPublicEntriesByPathsRow
+	text  []rune
+	score int
+}
+
+// Search returns the public entries that contain every whitespace-separated
+// term of q, in the title or the body. Entries matching in the title rank
+// first, then by how often the terms occur; ties keep newest first.
+func Search(ctx context.Context, store Store, q string, page int, perPage int) (*Page, error) {
+	terms := queryTerms(q)
+	tokens := queryTokens(terms)
+	if len(tokens) == 0 {
+		return &Page{}, nil
+	}
+
+	paths, err := store.SearchEntryPathsByTokens(ctx, publicdb.SearchEntryPathsByTokensParams{
+		Tokens:     tokens,
+		TokenCount: int64(len(tokens)),
+		// One more than is ranked tells whether older entries were left out
+		Limit: maxCandidates + 1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search tokens: %w", err)
+	}
+	if len(paths) == 0 {
+		return &Page{}, nil
+	}
+	truncated := len(paths) > maxCandidates
+	if truncated {
+		paths = paths[:maxCandidates]
+	}
+	entries, err := store.GetPublicEntriesByPaths(ctx, paths)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entries: %w", err)
+	}
+
+	// The n-grams only say a term may occur; confirm it really does.
+	var hits []scoredEntry
+	for _, entry := range entries {
+		text := []rune(plainText(entry.Body))
+		score, ok := scoreEntry(normalize(entry.Title), normalize(string(text)), terms)
+		if ok {
+			hits = append(hits, scoredEntry{entry: entry, text: text, score: score})
+		}
+	}
+	slices.SortStableFunc(hits, func(a, b scoredEntry) int {
+		return b.score - a.score
+	})
+
+	result := &Page{Total: len(hits), Truncated: truncated}
+	start := min((page-1)*perPage, len(hits))
+	end := min(start+perPage, len(hits))
+	result.HasNext = end < len(hits)
+	for _, hit := range hits[start:end] {
+		result.Results = append(result.Results, Result{
+			Path:        hit.entry.Path,
+			Title:       hit.entry.Title,
+			Snippet:     snippet(hit.text, terms),
+			PublishedAt: hit.entry.PublishedAt.Time,
+			ImageURL:    hit.entry.ImageUrl.String,
+		})
+	}
+	return result, nil
+}
+
+// scoreEntry reports whether every term occurs in title or body, and scores
+// the entry.
+func scoreEntry(title []rune, body []rune, terms [][]rune) (int, bool) {
+	score := 0
+	for _, term := range terms {
+		inTitle := indexRunes(title, term, 0) >= 0
+		count := countRunes(body, term, maxBodyScore)
+		if !inTitle && count == 0 {
+			return 0, false
+		}
+		if inTitle {
+			score += titleScore
+		}
+		score += count
+	}
+	return score, true
+}
+
+// snippet cuts about snippetLength runes of text around the first match and
+// highlights every match within it.
+func snippet(text []rune, terms [][]rune) string {
+	normalized := normalize(string(text))
+
+	start := 0
+	if first, _ := nextMatch(normalized, terms, 0); first >= 0 {
+		start = max(0, first-snippetLead)
+	}
+	end := min(start+snippetLength, len(text))
+	// Keep the window full when the match is near the end.
+	start = max(0, min(start, end-snippetLength))
+
+	var sb strings.Builder
+	if start > 0 {
+		sb.WriteString("…")
+	}
+	window := normalized[start:end]
+	for pos := 0; pos < len(window); {
+		at, length := nextMatch(window, terms, pos)
+		if at < 0 {
+			sb.WriteString(html.EscapeString(string(text[start+pos : end])))
+			break
+		}
+		sb.WriteString(html.EscapeString(string(text[start+pos : start+at])))
+		sb.WriteString("<mark>")
+		sb.WriteString(html.EscapeString(string(text[start+at : start+at+length])))
+		sb.WriteString("</mark>")
+		pos = at + length
+	}
+	if end < len(text) {
+		sb.WriteString("…")
+	}
+	return sb.String()
+}
+
+// nextMatch finds the earliest occurrence of any term at or after from,
+// preferring the longest term at the same position.
+func nextMatch(text []rune, terms [][]rune, from int) (int, int) {
+	at, length := -1, 0
+	for _, term := range terms {
+		i := indexRunes(text, term, from)
+		if i < 0 {
+			continue
+		}
+		if at < 0 || i < at || (i == at && len(term) > length) {
+			at, length = i, len(term)
+		}
+	}
+	return at, length
+}
+
+func indexRunes(text []rune, term []rune, from int) int {
+	for i := from; i+len(term) <= len(text); i++ {
+		if slices.Equal(text[i:i+len(term)], term) {
+			return i
+		}
+	}
+	return -1
+}
+
+// countRunes counts non-overlapping occurrences of term, up to limit.
+func countRunes(text []rune, term []rune, limit int) int {
+	count := 0
+	for i := indexRunes(text, term, 0); i >= 0 && count < limit; i = indexRunes(text, term, i+len(term)) {
+		count++
+	}
+	return count
+}
@@ -0,0 +1,129 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/tokuhirom/blog4/db/public/publicdb"
+	"github.com/tokuhirom/blog4/internal/search/mocks"
+)
+
+func publicEntry(path string, title string, body string) publicdb.GetPublicEntriesByPathsRow {
+	return publicdb.GetPublicEntriesByPathsRow{
+		Path:        path,
+		Title:       title,
+		Body:        body,
+		Visibility:  publicdb.EntryVisibilityPublic,
+		PublishedAt: sql.NullTime{Time: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+	}
+}
+
+func TestSearch_RanksAndPaginates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mocks.NewMockStore(ctrl)
+	mockStore.EXPECT().
+		SearchEntryPathsByTokens(gomock.Any(), publicdb.SearchEntryPathsByTokensParams{
+			Tokens:     []string{"go"},
+			TokenCount: 1,
+			Limit:      maxCandidates + 1,
+		}).
+		Return([]string{"newest", "body-only", "title", "false-positive"}, nil)
+	mockStore.EXPECT().
+		GetPublicEntriesByPaths(gomock.Any(), []string{"newest", "body-only", "title", "false-positive"}).
+		Return([]publicdb.GetPublicEntriesByPathsRow{
+			publicEntry("newest", "Rust", "go once"),
+			publicEntry("body-only", "Rust", "go go go"),
+			publicEntry("title", "Go入門", "nothing"),
+			// "g" and "o" apart: the n-gram matched but the term does not occur.
+			publicEntry("false-positive", "Rust", "[[go|g o]]"),
+		}, nil)
+
+	page, err := Search(context.Background(), mockStore, "ＧＯ", 1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 3, page.Total)
+	assert.True(t, page.HasNext)
+	assert.False(t, page.Truncated)
+	require.Len(t, page.Results, 2)
+	assert.Equal(t, "title", page.Results[0].Path)
+	assert.Equal(t, "body-only", page.Results[1].Path)
+	assert.Equal(t, "<mark>go</mark> <mark>go</mark> <mark>go</mark>", page.Results[1].Snippet)
+}
+
+func TestSearch_TooManyCandidates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	paths := make([]string, maxCandidates+1)
+	for i := range paths {
+		paths[i] = fmt.Sprintf("%04d", i)
+	}
+	mockStore := mocks.NewMockStore(ctrl)
+	mockStore.EXPECT().
+		SearchEntryPathsByTokens(gomock.Any(), gomock.Any()).
+		Return(paths, nil)
+	// The oldest candidate is not loaded
+	mockStore.EXPECT().
+		GetPublicEntriesByPaths(gomock.Any(), paths[:maxCandidates]).
+		Return([]publicdb.GetPublicEntriesByPathsRow{publicEntry("0000", "Go", "")}, nil)
+
+	page, err := Search(context.Background(), mockStore, "go", 1, 10)
+	require.NoError(t, err)
+	assert.True(t, page.Truncated)
+	assert.Equal(t, 1, page.Total)
+}
+
+func TestSearch_SecondPage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mocks.NewMockStore(ctrl)
+	mockStore.EXPECT().
+		SearchEntryPathsByTokens(gomock.Any(), gomock.Any()).
+		Return([]string{"a", "b"}, nil)
+	mockStore.EXPECT().
+		GetPublicEntriesByPaths(gomock.Any(), []string{"a", "b"}).
+		Return([]publicdb.GetPublicEntriesByPathsRow{
+			publicEntry("a", "日本語", ""),
+			publicEntry("b", "日本語", ""),
+		}, nil)
+
+	page, err := Search(context.Background(), mockStore, "日本語", 2, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, page.Total)
+	assert.False(t, page.HasNext)
+	require.Len(t, page.Results, 1)
+	assert.Equal(t, "b", page.Results[0].Path)
+}
+
+func TestSearch_NoTokensSkipsDatabase(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	page, err := Search(context.Background(), mocks.NewMockStore(ctrl), " !! ", 1, 20)
+	require.NoError(t, err)
+	assert.Equal(t, 0, page.Total)
+	assert.Empty(t, page.Results)
+}
+
+func TestSnippet(t *testing.T) {
+	text := []rune(strings.Repeat("あ", 100) + "<Docker>と日本語" + strings.Repeat("い", 200))
+	got := snippet(text, queryTerms("docker 日本"))
+
+	assert.True(t, strings.HasPrefix(got, "…"+strings.Repeat("あ", snippetLead-1)+"&lt;<mark>Docker</mark>&gt;と<mark>日本</mark>語"), got)
+	assert.True(t, strings.HasSuffix(got, "い…"), got)
+}
+
+func TestSnippet_ShortTextIsNotTruncated(t *testing.T) {
+	assert.Equal(t, "Go と <mark>Rust</mark>", snippet([]rune("Go と Rust"), queryTerms("rust")))
+	assert.Equal(t, "no match", snippet([]rune("no match"), queryTerms("zzz")))
+}
@@ -0,0 +1,138 @@
+// Package search implements the public full-text search. TiDB has no FULLTEXT
+// index, so entries are split into unigrams and bigrams stored in
+// entry_search_token; a query narrows candidates with the n-grams of its
+// terms, and the candidates are then checked and ranked in Go.
+package search
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+var (
+	reMarkdownImage = regexp.MustCompile(`!\[[^\]]*\]\([^)]*\)`)
+	reMarkdownLink  = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	reWikiLink      = regexp.MustCompile(`!?\[\[(?:[^\]|]*\|)?(.*?)\]\]`)
+	reURL           = regexp.MustCompile(`https?://\S+`)
+	reSpaces        = regexp.MustCompile(`\s+`)
+)
+
+// plainText reduces a markdown body to the text a reader sees, which is what
+// gets indexed and what snippets are cut from.
+func plainText(body string) string {
+	body = reMarkdownImage.ReplaceAllString(body, "")
+	body = reMarkdownLink.ReplaceAllString(body, "$1")
+	body = reWikiLink.ReplaceAllString(body, "$1")
+	body = reURL.ReplaceAllString(body, "")
+	return strings.TrimSpace(reSpaces.ReplaceAllString(body, " "))
+}
+
+// normalize folds full-width ASCII to half-width and lower-cases. It maps rune
+// to rune, so positions in the result are positions in the input as well.
+func normalize(s string) []rune {
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case r >= 0xFF01 && r <= 0xFF5E:
+			r -= 0xFEE0
+		case r == 0x3000:
+			r = ' '
+		}
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// wordRuns splits normalized text into runs of letters and digits. Japanese
+// has no spaces, so a run is often a whole sentence.
+func wordRuns(runes []rune) [][]rune {
+	var runs [][]rune
+	start := -1
+	for i, r := range runes {
+		if isWordRune(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			runs = append(runs, runes[start:i])
+			start = -1
+		}
+	}
+	if start >= 0 {
+		runs = append(runs, runes[start:])
+	}
+	return runs
+}
+
+// IndexTokens returns the distinct unigrams and bigrams of an entry, sorted.
+func IndexTokens(title string, body string) []string {
+	seen := map[string]struct{}{}
+	for _, run := range wordRuns(normalize(title + " " + plainText(body))) {
+		for i := range run {
+			seen[string(run[i:i+1])] = struct{}{}
+			if i+1 < len(run) {
+				seen[string(run[i:i+2])] = struct{}{}
+			}
+		}
+	}
+
+	tokens := make([]string, 0, len(seen))
+	for token := range seen {
+		tokens = append(tokens, token)
+	}
+	slices.Sort(tokens)
+	return tokens
+}
+
+// maxQueryRunes bounds the work a single query can cause.
+const maxQueryRunes = 100
+
+// queryTerms splits a query into normalized, whitespace-separated terms.
+func queryTerms(q string) [][]rune {
+	runes := normalize(q)
+	if len(runes) > maxQueryRunes {
+		runes = runes[:maxQueryRunes]
+	}
+
+	var terms [][]rune
+	for _, field := range strings.Fields(string(runes)) {
+		term := []rune(field)
+		if !slices.ContainsFunc(terms, func(t []rune) bool { return slices.Equal(t, term) }) {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// queryTokens returns the distinct n-grams every matching entry must contain:
+// the bigrams of each word run, or the run itself when it is a single rune.
+func queryTokens(terms [][]rune) []string {
+	seen := map[string]struct{}{}
+	var tokens []string
+	add := func(token string) {
+		if _, ok := seen[token]; !ok {
+			seen[token] = struct{}{}
+			tokens = append(tokens, token)
+		}
+	}
+	for _, term := range terms {
+		for _, run := range wordRuns(term) {
+			if len(run) == 1 {
+				add(string(run))
+				continue
+			}
+			for i := 0; i+1 < len(run); i++ {
+				add(string(run[i : i+2]))
+			}
+		}
+	}
+	return tokens
+}
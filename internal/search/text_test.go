@@ -0,0 +1,50 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlainText(t *testing.T) {
+	body := "## 見出し\n\n[[Go|ゴー]] と ![[Embed]] と [リンク](https://example.com/a) ![img](https://example.com/b.png)\nhttps://example.com/c\n"
+	assert.Equal(t, "## 見出し ゴー と Embed と リンク", plainText(body))
+}
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "go 言語 abc", string(normalize("Ｇｏ　言語 ABC")))
+	// Positions must line up with the input.
+	assert.Len(t, normalize("Ｇｏ　言語"), 5)
+}
+
+func TestIndexTokens(t *testing.T) {
+	assert.Equal(t,
+		[]string{"a", "ab", "b", "日", "日本", "本"},
+		IndexTokens("AB", "日本 [[Ab]]"))
+	assert.Empty(t, IndexTokens("", "!!! https://example.com/"))
+}
+
+func TestQueryTokens(t *testing.T) {
+	terms := queryTerms("  Docker  日本語 c++ docker ")
+	assert.Equal(t, []string{"docker", "日本語", "c++"}, runesToStrings(terms))
+	assert.Equal(t,
+		[]string{"do", "oc", "ck", "ke", "er", "日本", "本語", "c"},
+		queryTokens(terms))
+}
+
+func TestQueryTerms_Truncates(t *testing.T) {
+	long := make([]rune, maxQueryRunes+10)
+	for i := range long {
+		long[i] = 'a'
+	}
+	terms := queryTerms(string(long))
+	assert.Len(t, terms[0], maxQueryRunes)
+}
+
+func runesToStrings(terms [][]rune) []string {
+	var s []string
+	for _, term := range terms {
+		s = append(s, string(term))
+	}
+	return s
+}
@@ -0,0 +1,76 @@
+// Package sweep deletes expired admin sessions, and the short-lived login
+// state that is otherwise only cleaned up when someone signs in.
+package sweep
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+//go:generate go run go.uber.org/mock/mockgen -source=sweeper.go -destination=mocks/mock_sweeper.go -package=mocks
+
+// Store defines the database operations needed to delete expired rows
+type Store interface {
+	DeleteExpiredSessions(ctx context.Context) (int64, error)
+	DeleteExpiredPendingLogins(ctx context.Context) error
+	DeleteExpiredWebauthnChallenges(ctx context.Context) error
+}
+
+// Sweeper periodically deletes expired rows
+type Sweeper struct {
+	store Store
+	// Interval is how often Run sweeps.
+	Interval time.Duration
+}
+
+// NewSweeper creates a Sweeper that sweeps every ten minutes.
+func NewSweeper(store Store) *Sweeper {
+	return &Sweeper{
+		store:    store,
+		Interval: 10 * time.Minute,
+	}
+}
+
+// Sweep deletes expired sessions, pending two-factor logins and passkey
+// challenges, and returns how many sessions were deleted.
+func (s *Sweeper) Sweep(ctx context.Context) (int64, error) {
+	n, err := s.store.DeleteExpiredSessions(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired sessions: %w", err)
+	}
+	if err := s.store.DeleteExpiredPendingLogins(ctx); err != nil {
+		return n, fmt.Errorf("failed to delete expired pending logins: %w", err)
+	}
+	if err := s.store.DeleteExpiredWebauthnChallenges(ctx); err != nil {
+		return n, fmt.Errorf("failed to delete expired passkey challenges: %w", err)
+	}
+	return n, nil
+}
+
+// Run calls Sweep once at startup and then every Interval until ctx is done.
+func (s *Sweeper) Run(ctx context.Context) {
+	sweep := func() {
+		n, err := s.Sweep(ctx)
+		if err != nil {
+			slog.Error("failed to sweep expired rows", slog.Any("error", err))
+			return
+		}
+		if n > 0 {
+			slog.Info("deleted expired sessions", slog.Int64("count", n))
+		}
+	}
+
+	sweep()
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			sweep()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
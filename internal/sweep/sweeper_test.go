@@ -0,0 +1,70 @@
+package sweep
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/tokuhirom/blog4/internal/sweep/mocks"
+)
+
+func TestSweep(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mocks.NewMockStore(ctrl)
+	mockStore.EXPECT().DeleteExpiredSessions(gomock.Any()).Return(int64(3), nil)
+	mockStore.EXPECT().DeleteExpiredPendingLogins(gomock.Any()).Return(nil)
+	mockStore.EXPECT().DeleteExpiredWebauthnChallenges(gomock.Any()).Return(nil)
+
+	n, err := NewSweeper(mockStore).Sweep(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+}
+
+func TestSweep_StopsOnError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mocks.NewMockStore(ctrl)
+	mockStore.EXPECT().DeleteExpiredSessions(gomock.Any()).Return(int64(0), errors.New("connection lost"))
+
+	_, err := NewSweeper(mockStore).Sweep(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "connection lost")
+}
+
+func TestRun_StopsWhenCancelled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mockStore := mocks.NewMockStore(ctrl)
+	mockStore.EXPECT().
+		DeleteExpiredSessions(gomock.Any()).
+		DoAndReturn(func(context.Context) (int64, error) {
+			cancel()
+			return 0, nil
+		})
+	mockStore.EXPECT().DeleteExpiredPendingLogins(gomock.Any()).Return(nil)
+	mockStore.EXPECT().DeleteExpiredWebauthnChallenges(gomock.Any()).Return(nil)
+
+	s := NewSweeper(mockStore)
+	s.Interval = time.Hour
+
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after the context was cancelled")
+	}
+}
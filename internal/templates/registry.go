@@ -0,0 +1,98 @@
+// Package templates parses the HTML templates once and hands them out to
+// request handlers.
+package templates
+
+import (
+	"fmt"
+	"html/template"
+	"io/fs"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Registry holds named templates, each parsed from one or more files of fsys.
+//
+// With reload set, Lookup re-parses a template whenever one of its files has
+// a newer modification time than when it was last parsed. That is meant for
+// local development against the files on disk; embedded files never change.
+type Registry struct {
+	fsys   fs.FS
+	files  map[string][]string
+	reload bool
+
+	mu       sync.Mutex
+	parsed   map[string]*template.Template
+	modTimes map[string]time.Time
+}
+
+// New parses every template in files, which maps a template name to the
+// files it is parsed from. It fails if any of them does not parse, so that a
+// broken template stops the server at startup rather than on first request.
+func New(fsys fs.FS, files map[string][]string, reload bool) (*Registry, error) {
+	r := &Registry{
+		fsys:     fsys,
+		files:    files,
+		reload:   reload,
+		parsed:   make(map[string]*template.Template, len(files)),
+		modTimes: make(map[string]time.Time, len(files)),
+	}
+	for name := range files {
+		if err := r.parse(name); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Lookup returns the template registered as name.
+func (r *Registry) Lookup(name string) (*template.Template, error) {
+	if _, ok := r.files[name]; !ok {
+		return nil, fmt.Errorf("unknown template %q", name)
+	}
+	if !r.reload {
+		return r.parsed[name], nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	modTime, err := r.latestModTime(name)
+	if err != nil {
+		return nil, err
+	}
+	if modTime.After(r.modTimes[name]) {
+		slog.Info("reloading template", slog.String("template", name))
+		if err := r.parse(name); err != nil {
+			return nil, err
+		}
+	}
+	return r.parsed[name], nil
+}
+
+func (r *Registry) parse(name string) error {
+	modTime, err := r.latestModTime(name)
+	if err != nil {
+		return err
+	}
+	tmpl, err := template.ParseFS(r.fsys, r.files[name]...)
+	if err != nil {
+		return fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+	r.parsed[name] = tmpl
+	r.modTimes[name] = modTime
+	return nil
+}
+
+func (r *Registry) latestModTime(name string) (time.Time, error) {
+	var latest time.Time
+	for _, file := range r.files[name] {
+		info, err := fs.Stat(r.fsys, file)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to stat template file %s: %w", file, err)
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
@@ -0,0 +1,59 @@
+package templates
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func execute(t *testing.T, r *Registry, name string) string {
+	t.Helper()
+	tmpl, err := r.Lookup(name)
+	require.NoError(t, err)
+	var out strings.Builder
+	require.NoError(t, tmpl.ExecuteTemplate(&out, "page.html", "x"))
+	return out.String()
+}
+
+func TestRegistry(t *testing.T) {
+	fsys := fstest.MapFS{
+		"layout.html": {Data: []byte(`{{define "layout"}}[{{template "content" .}}]{{end}}`)},
+		"page.html":   {Data: []byte(`{{template "layout" .}}{{define "content"}}hello {{.}}{{end}}`)},
+	}
+
+	r, err := New(fsys, map[string][]string{"page": {"layout.html", "page.html"}}, false)
+	require.NoError(t, err)
+	assert.Equal(t, "[hello x]", execute(t, r, "page"))
+
+	_, err = r.Lookup("missing")
+	assert.Error(t, err)
+}
+
+func TestRegistry_ParseErrorFailsAtStartup(t *testing.T) {
+	fsys := fstest.MapFS{"page.html": {Data: []byte(`{{if}}`)}}
+	_, err := New(fsys, map[string][]string{"page": {"page.html"}}, false)
+	assert.Error(t, err)
+}
+
+func TestRegistry_Reload(t *testing.T) {
+	modTime := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	fsys := fstest.MapFS{"page.html": {Data: []byte(`v1 {{.}}`), ModTime: modTime}}
+
+	reloading, err := New(fsys, map[string][]string{"page": {"page.html"}}, true)
+	require.NoError(t, err)
+	fixed, err := New(fsys, map[string][]string{"page": {"page.html"}}, false)
+	require.NoError(t, err)
+
+	fsys["page.html"] = &fstest.MapFile{Data: []byte(`v2 {{.}}`), ModTime: modTime.Add(time.Second)}
+	assert.Equal(t, "v2 x", execute(t, reloading, "page"))
+	assert.Equal(t, "v1 x", execute(t, fixed, "page"))
+
+	// A template that no longer parses is reported, not served stale.
+	fsys["page.html"] = &fstest.MapFile{Data: []byte(`{{if}}`), ModTime: modTime.Add(2 * time.Second)}
+	_, err = reloading.Lookup("page")
+	assert.Error(t, err)
+}
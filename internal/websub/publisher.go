@@ -0,0 +1,138 @@
+// Package websub notifies WebSub (PubSubHubbub) hubs that the blog's feeds
+// have changed, so that subscribers pick up new entries without polling.
+package websub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Publisher pings hubs with hub.mode=publish for every topic URL.
+type Publisher struct {
+	hubs   []string
+	topics []string
+
+	HTTPClient *http.Client
+	// MaxAttempts is how many times a failing hub is tried before giving up.
+	MaxAttempts int
+	// Backoff is the wait before the first retry; it doubles on each retry.
+	Backoff time.Duration
+	// Delay is how long Schedule waits for further changes before pinging, so
+	// that a burst of auto-saves results in a single notification.
+	Delay time.Duration
+
+	mu    sync.Mutex
+	timer *time.Timer
+}
+
+// NewPublisher creates a Publisher that announces topics to hubs.
+func NewPublisher(hubs []string, topics ...string) *Publisher {
+	return &Publisher{
+		hubs:        hubs,
+		topics:      topics,
+		HTTPClient:  &http.Client{Timeout: 10 * time.Second},
+		MaxAttempts: 4,
+		Backoff:     2 * time.Second,
+		Delay:       30 * time.Second,
+	}
+}
+
+// Hubs returns the hub URLs, for advertising them in feeds.
+func (p *Publisher) Hubs() []string {
+	return p.hubs
+}
+
+// Schedule pings the hubs in the background once no further Schedule call
+// has arrived for Delay.
+func (p *Publisher) Schedule() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.timer != nil {
+		p.timer.Stop()
+	}
+	p.timer = time.AfterFunc(p.Delay, func() {
+		if err := p.Publish(context.Background()); err != nil {
+			slog.Error("failed to notify WebSub hubs", slog.Any("error", err))
+		}
+	})
+}
+
+// Publish pings every hub for every topic, retrying each failed ping with
+// exponential backoff. Hubs are independent: the error lists every hub that
+// still failed after the last attempt.
+func (p *Publisher) Publish(ctx context.Context) error {
+	var wg sync.WaitGroup
+	errs := make([]error, len(p.hubs))
+	for i, hub := range p.hubs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = p.publishToHub(ctx, hub)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+func (p *Publisher) publishToHub(ctx context.Context, hub string) error {
+	backoff := p.Backoff
+	var err error
+	for attempt := 1; attempt <= p.MaxAttempts; attempt++ {
+		var retry bool
+		retry, err = p.ping(ctx, hub)
+		if err == nil {
+			slog.Info("notified WebSub hub", slog.String("hub", hub), slog.Int("attempt", attempt))
+			return nil
+		}
+		if !retry || attempt == p.MaxAttempts {
+			break
+		}
+
+		slog.Warn("failed to notify WebSub hub, retrying",
+			slog.String("hub", hub), slog.Int("attempt", attempt), slog.Duration("backoff", backoff), slog.Any("error", err))
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return fmt.Errorf("hub %s: %w", hub, ctx.Err())
+		}
+		backoff *= 2
+	}
+	return fmt.Errorf("hub %s: %w", hub, err)
+}
+
+// ping sends one publish request. It reports whether a failure is worth
+// retrying: network errors, 429 and 5xx are, other 4xx responses are not.
+func (p *Publisher) ping(ctx context.Context, hub string) (bool, error) {
+	form := url.Values{"hub.mode": {"publish"}}
+	for _, topic := range p.topics {
+		form.Add("hub.url", topic)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hub, strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.HTTPClient.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	err = fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, err
+}
@@ -0,0 +1,102 @@
+package websub
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPublisher(hubs ...string) *Publisher {
+	p := NewPublisher(hubs, "https://blog.example.com/feed")
+	p.Backoff = time.Millisecond
+	p.Delay = time.Millisecond
+	return p
+}
+
+func TestPublish(t *testing.T) {
+	var received atomic.Int32
+	hub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "publish", r.PostForm.Get("hub.mode"))
+		assert.Equal(t, []string{"https://blog.example.com/feed"}, r.PostForm["hub.url"])
+		received.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer hub.Close()
+
+	err := newTestPublisher(hub.URL).Publish(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), received.Load())
+}
+
+func TestPublish_RetriesServerErrors(t *testing.T) {
+	var attempts atomic.Int32
+	hub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer hub.Close()
+
+	err := newTestPublisher(hub.URL).Publish(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), attempts.Load())
+}
+
+func TestPublish_GivesUp(t *testing.T) {
+	var badAttempts, failingAttempts atomic.Int32
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		badAttempts.Add(1)
+		http.Error(w, "unknown topic", http.StatusBadRequest)
+	}))
+	defer bad.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		failingAttempts.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	p := newTestPublisher(bad.URL, failing.URL)
+	err := p.Publish(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown topic")
+	assert.Contains(t, err.Error(), "status 500")
+
+	// Client errors are not retried; server errors are retried up to MaxAttempts.
+	assert.Equal(t, int32(1), badAttempts.Load())
+	assert.Equal(t, int32(p.MaxAttempts), failingAttempts.Load())
+}
+
+func TestSchedule_Debounces(t *testing.T) {
+	received := make(chan struct{}, 10)
+	hub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer hub.Close()
+
+	p := newTestPublisher(hub.URL)
+	p.Delay = 50 * time.Millisecond
+	for range 5 {
+		p.Schedule()
+	}
+
+	select {
+	case <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("hub was not notified")
+	}
+	select {
+	case <-received:
+		t.Fatal("hub was notified more than once")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
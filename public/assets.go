@@ -0,0 +1,21 @@
+// Package public embeds the public site's templates and static files.
+package public
+
+import (
+	"embed"
+	"io/fs"
+	"os"
+)
+
+//go:embed templates static
+var embedded embed.FS
+
+// FS returns the templates/ and static/ trees. They are embedded in the
+// binary, except in local development, where they are read from ./public so
+// that edits show up without a rebuild.
+func FS(localDev bool) fs.FS {
+	if localDev {
+		return os.DirFS("public")
+	}
+	return embedded
+}
@@ -3,34 +3,7 @@
     const inputEl = document.querySelector('.search-input');
     const formEl = document.querySelector('.search-form');
 
-    let entries = [];
-
-    // サーバの summarizeEntry 相当の軽量版: URL と [[...]] を整理して先頭 length 文字。
-    function summarize(body, length) {
-        const s = body.replace(/https?:\/\/\S+/g, '').replace(/\[\[(.*?)\]\]/g, '$1');
-        return [...s].slice(0, length).join('');
-    }
-
-    // 軽いスコアリング: 全語必須 (AND)、title ヒットを加点。
-    function scoreEntry(entry, terms) {
-        const title = entry.title.toLowerCase();
-        const body = entry.body.toLowerCase();
-        let score = 0;
-        for (const term of terms) {
-            const inTitle = title.includes(term);
-            const inBody = body.includes(term);
-            if (!inTitle && !inBody) {
-                return -1;
-            }
-            if (inTitle) {
-                score += 10;
-            }
-            if (inBody) {
-                score += 1;
-            }
-        }
-        return score;
-    }
+    let controller = null;
 
     function renderInfo(message) {
         const div = document.createElement('div');
@@ -39,105 +12,149 @@
         resultsEl.replaceChildren(div);
     }
 
-    function renderResults(list) {
-        if (list.length === 0) {
-            renderInfo('No results found. Try different keywords.');
-            return;
+    function renderCard(entry) {
+        const li = document.createElement('li');
+        li.className = 'card';
+
+        const a = document.createElement('a');
+        a.href = `/entry/${entry.path}`;
+        a.className = 'card-link';
+
+        const head = document.createElement('div');
+        head.className = 'entry-head';
+        const title = document.createElement('span');
+        title.className = 'entry-title';
+        title.textContent = entry.title;
+        head.appendChild(title);
+        a.appendChild(head);
+
+        if (entry.image_url) {
+            const wrap = document.createElement('div');
+            const img = document.createElement('img');
+            img.src = entry.image_url;
+            img.className = 'entry-image';
+            img.alt = entry.title;
+            wrap.appendChild(img);
+            a.appendChild(wrap);
         }
 
-        const ul = document.createElement('ul');
-        ul.className = 'card-container';
-        for (const entry of list) {
-            const li = document.createElement('li');
-            li.className = 'card';
-
-            const a = document.createElement('a');
-            a.href = `/entry/${entry.path}`;
-            a.className = 'card-link';
-
-            const head = document.createElement('div');
-            head.className = 'entry-head';
-            const title = document.createElement('span');
-            title.className = 'entry-title';
-            title.textContent = entry.title;
-            head.appendChild(title);
-            a.appendChild(head);
-
-            if (entry.image_url) {
-                const wrap = document.createElement('div');
-                const img = document.createElement('img');
-                img.src = entry.image_url;
-                img.className = 'entry-image';
-                img.alt = entry.title;
-                wrap.appendChild(img);
-                a.appendChild(wrap);
+        // snippet はサーバでエスケープ済み。ヒット箇所だけ <mark> が入っている。
+        const preview = document.createElement('div');
+        preview.className = 'entry-text-preview';
+        preview.innerHTML = entry.snippet;
+        a.appendChild(preview);
+
+        const date = document.createElement('span');
+        date.className = 'published-date';
+        date.textContent = entry.published_at;
+        a.appendChild(date);
+
+        li.appendChild(a);
+        return li;
+    }
+
+    function renderPager(data) {
+        const pager = document.createElement('div');
+        pager.className = 'pager';
+        const links = [
+            ['prev', 'Prev', data.page > 1, data.page - 1],
+            ['next', 'Next', data.has_next, data.page + 1],
+        ];
+        for (const [className, label, enabled, page] of links) {
+            const div = document.createElement('div');
+            div.className = className;
+            if (enabled) {
+                const a = document.createElement('a');
+                a.href = `/search?q=${encodeURIComponent(data.query)}&page=${page}`;
+                a.textContent = label;
+                a.addEventListener('click', (e) => {
+                    e.preventDefault();
+                    doSearch(page);
+                    window.scrollTo(0, 0);
+                });
+                div.appendChild(a);
             } else {
-                const preview = document.createElement('div');
-                preview.className = 'entry-text-preview';
-                preview.textContent = summarize(entry.body, 100);
-                a.appendChild(preview);
+                div.textContent = label;
             }
+            pager.appendChild(div);
+        }
+        return pager;
+    }
 
-            const date = document.createElement('span');
-            date.className = 'published-date';
-            date.textContent = entry.published_at;
-            a.appendChild(date);
+    function renderResults(data) {
+        if (data.results.length === 0) {
+            renderInfo('No results found. Try different keywords.');
+            return;
+        }
 
-            li.appendChild(a);
-            ul.appendChild(li);
+        const info = document.createElement('div');
+        info.className = 'search-result-info';
+        info.textContent = data.truncated
+            ? `${data.total}+ results (only the newest matches are shown; add keywords to narrow down)`
+            : `${data.total} results`;
+
+        const ul = document.createElement('ul');
+        ul.className = 'card-container';
+        for (const entry of data.results) {
+            ul.appendChild(renderCard(entry));
         }
-        resultsEl.replaceChildren(ul);
+        resultsEl.replaceChildren(info, ul, renderPager(data));
     }
 
-    function doSearch() {
+    async function doSearch(page = 1) {
         const q = inputEl.value.trim();
+        // 再読み込みや共有で同じ結果に戻れるよう URL を追従させる。
+        const url = new URL(window.location.href);
+        url.searchParams.delete('page');
+        if (q) {
+            url.searchParams.set('q', q);
+            if (page > 1) {
+                url.searchParams.set('page', page);
+            }
+        } else {
+            url.searchParams.delete('q');
+        }
+        history.replaceState(null, '', url);
+
+        if (controller) {
+            controller.abort();
+        }
         if (!q) {
             renderInfo('Enter keywords to search entries.');
             return;
         }
 
-        const terms = q.toLowerCase().split(/\s+/).filter(Boolean);
-        const scored = [];
-        for (const entry of entries) {
-            const score = scoreEntry(entry, terms);
-            if (score >= 0) {
-                scored.push({ entry, score });
-            }
-        }
-        // 高スコア順。Array.sort は stable なので同点は元の published_at DESC を保つ。
-        scored.sort((a, b) => b.score - a.score);
-        renderResults(scored.map((x) => x.entry));
-    }
-
-    let timer = null;
-    function scheduleSearch() {
-        clearTimeout(timer);
-        timer = setTimeout(doSearch, 200);
-    }
-
-    async function init() {
-        renderInfo('Loading...');
+        controller = new AbortController();
         try {
-            const res = await fetch('/search-index.json');
+            const params = new URLSearchParams({ q, page });
+            const res = await fetch(`/api/search?${params}`, { signal: controller.signal });
             if (!res.ok) {
                 throw new Error(`HTTP ${res.status}`);
             }
-            entries = await res.json();
+            renderResults(await res.json());
         } catch (err) {
-            renderInfo('Failed to load search index.');
+            if (err.name === 'AbortError') {
+                return;
+            }
+            renderInfo('Search failed. Please try again.');
             console.error(err);
-            return;
         }
+    }
 
-        inputEl.addEventListener('input', scheduleSearch);
-        formEl.addEventListener('submit', (e) => {
-            e.preventDefault();
-            doSearch();
-        });
+    let timer = null;
+    function scheduleSearch() {
+        clearTimeout(timer);
+        timer = setTimeout(() => doSearch(), 300);
+    }
 
-        // ?q= に初期値があれば即検索 (input の value はサーバが埋めている)。
+    inputEl.addEventListener('input', scheduleSearch);
+    formEl.addEventListener('submit', (e) => {
+        e.preventDefault();
+        clearTimeout(timer);
         doSearch();
-    }
+    });
 
-    init();
+    // ?q= に初期値があれば即検索 (input の value はサーバが埋めている)。
+    const initialPage = Number.parseInt(new URLSearchParams(window.location.search).get('page'), 10);
+    doSearch(initialPage > 0 ? initialPage : 1);
 })();